@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 const (
 	Activated = "ACTIVATED"
 	Standby   = "STANDBY"
@@ -7,19 +9,48 @@ const (
 )
 
 const (
-	PluginARP              = "arp"
-	PluginWebhook          = "webhook"
-	PluginOutscalePublicIP = "outscale_public_ip"
+	PluginARP                  = "arp"
+	PluginWebhook              = "webhook"
+	PluginOutscalePublicIP     = "outscale_public_ip"
+	PluginRoute                = "route"
+	PluginBGP                  = "bgp"
+	PluginVRRP                 = "vrrp"
+	PluginIPVS                 = "ipvs"
+	PluginNftables             = "nftables"
+	PluginOutscalePrivateIP    = "outscale_private_ip"
+	PluginOutscaleRouteTable   = "outscale_route_table"
+	PluginOutscaleLoadBalancer = "outscale_load_balancer"
+	PluginAWSEIP               = "aws_eip"
+	PluginHetznerFloatingIP    = "hetzner_floating_ip"
+	PluginScalewayFlexibleIP   = "scaleway_flexible_ip"
+	PluginOpenStackNeutron     = "openstack_neutron"
+	PluginDNSRFC2136           = "dns_rfc2136"
+	PluginDNSAPI               = "dns_api"
+)
+
+const (
+	// EndpointConditionAddressConflict is reported when another host uses an address of the endpoint
+	EndpointConditionAddressConflict = "AddressConflict"
 )
 
 type Endpoint struct {
 	ID string `json:"id"`
 
-	Status              string        `json:"status,omitempty"`
-	Checks              []HealthCheck `json:"checks,omitempty"`
-	HealthCheckInterval int           `json:"healthcheck_interval"`
-	Plugin              string        `json:"plugin,omitempty"`
-	ElectionKey         string        `json:"election_key,omitempty"`
+	Status              string              `json:"status,omitempty"`
+	Checks              []HealthCheck       `json:"checks,omitempty"`
+	HealthCheckInterval int                 `json:"healthcheck_interval"`
+	Plugin              string              `json:"plugin,omitempty"`
+	ElectionKey         string              `json:"election_key,omitempty"`
+	Conditions          []EndpointCondition `json:"conditions,omitempty"`
+}
+
+// EndpointCondition is an abnormal condition of an endpoint reported by its plugin
+type EndpointCondition struct {
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	Since   time.Time `json:"since"`
+	// Failing conditions are considered as failed health checks and push the endpoint to FAILING
+	Failing bool `json:"failing"`
 }
 
 type HealthCheckType string
@@ -62,7 +93,39 @@ type Host struct {
 }
 
 type ARPPluginConfig struct {
-	IP string `json:"ip"`
+	// IP is the address managed by the endpoint. It can be combined with IPs.
+	IP string `json:"ip,omitempty"`
+	// IPs are additional addresses managed by the endpoint (e.g. an IPv4 and IPv6 pair)
+	IPs []string `json:"ips,omitempty"`
+	// Interface overrides the interface configured on the host for this endpoint
+	Interface string `json:"interface,omitempty"`
+	// Netns overrides the network namespace configured on the host for this endpoint: a name under
+	// /var/run/netns or a path
+	Netns string `json:"netns,omitempty"`
+	// GARP overrides the gratuitous ARP settings of the host for this endpoint
+	GARP *ARPGratuitousConfig `json:"garp,omitempty"`
+}
+
+// ARPGratuitousConfig configures how the IPs of an endpoint are announced. Empty fields use the
+// settings of the host.
+type ARPGratuitousConfig struct {
+	// BurstCount is the number of gratuitous ARP packets sent on activation and on each refresh
+	BurstCount int `json:"burst_count,omitempty"`
+	// BurstInterval is the duration between two packets of a burst (e.g. "500ms")
+	BurstInterval string `json:"burst_interval,omitempty"`
+	// RefreshInterval is the duration between two bursts while the endpoint is activated (e.g. "5m"). "0s" disables the refresh.
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+	// Mode is the kind of packets sent: "request", "reply" or "both"
+	Mode string `json:"mode,omitempty"`
+}
+
+// Addresses returns all the addresses managed by the endpoint
+func (c ARPPluginConfig) Addresses() []string {
+	addresses := make([]string, 0, len(c.IPs)+1)
+	if c.IP != "" {
+		addresses = append(addresses, c.IP)
+	}
+	return append(addresses, c.IPs...)
 }
 
 type WebhookPluginConfig struct {
@@ -72,13 +135,299 @@ type WebhookPluginConfig struct {
 	ResourceID string            `json:"resource_id"`
 }
 
+type RoutePluginConfig struct {
+	// Routes installed when the endpoint is activated, at least one route is required
+	Routes []RouteConfig `json:"routes"`
+	// Rules are optional policy routing rules installed with the routes
+	Rules []RuleConfig `json:"rules,omitempty"`
+	// Netns is the network namespace of the routes: a name under /var/run/netns or a path
+	Netns string `json:"netns,omitempty"`
+}
+
+type RouteConfig struct {
+	// Destination is the destination prefix using CIDR notation (e.g. "203.0.113.0/24", "0.0.0.0/0")
+	Destination string `json:"destination"`
+	// Gateway is the next hop of the route. Gateway and/or Device must be set.
+	Gateway string `json:"gateway,omitempty"`
+	// Device is the output interface of the route
+	Device string `json:"device,omitempty"`
+	Metric int    `json:"metric,omitempty"`
+	// Table defaults to the main table (254)
+	Table int `json:"table,omitempty"`
+	// Protocol is the originator of the route (see /etc/iproute2/rt_protos), defaults to static (4)
+	Protocol int `json:"protocol,omitempty"`
+}
+
+type RuleConfig struct {
+	// Priority of the rule, it must be unique on the host
+	Priority int `json:"priority"`
+	// From is the source prefix matched by the rule
+	From string `json:"from,omitempty"`
+	// To is the destination prefix matched by the rule
+	To string `json:"to,omitempty"`
+	// IIF is the input interface matched by the rule
+	IIF string `json:"iif,omitempty"`
+	// FwMark is the firewall mark matched by the rule
+	FwMark uint32 `json:"fwmark,omitempty"`
+	// Table is the routing table looked up if the rule matches
+	Table int `json:"table"`
+	// IPv6 makes a rule without From nor To match IPv6 packets instead of IPv4 packets
+	IPv6 bool `json:"ipv6,omitempty"`
+}
+
+type BGPPluginConfig struct {
+	// IP is the prefix announced by the endpoint, using CIDR notation or a bare IP for a host
+	// prefix. It can be combined with IPs.
+	IP string `json:"ip,omitempty"`
+	// IPs are additional prefixes announced by the endpoint
+	IPs []string `json:"ips,omitempty"`
+	// NextHop overrides the next hop configured on the host. Defaults to the local address of the
+	// BGP session.
+	NextHop string `json:"next_hop,omitempty"`
+	// Communities attached to the prefixes: "<ASN>:<value>" or a well-known community (e.g. "no-export")
+	Communities []string `json:"communities,omitempty"`
+	// LocalASN overrides the AS number configured on the host
+	LocalASN uint32 `json:"local_asn,omitempty"`
+	// Peers override the peers configured on the host
+	Peers []BGPPeerConfig `json:"peers,omitempty"`
+}
+
+type BGPPeerConfig struct {
+	Address string `json:"address"`
+	// Port defaults to 179
+	Port int    `json:"port,omitempty"`
+	ASN  uint32 `json:"asn"`
+}
+
+// Prefixes returns all the prefixes announced by the endpoint
+func (c BGPPluginConfig) Prefixes() []string {
+	prefixes := make([]string, 0, len(c.IPs)+1)
+	if c.IP != "" {
+		prefixes = append(prefixes, c.IP)
+	}
+	return append(prefixes, c.IPs...)
+}
+
+type VRRPPluginConfig struct {
+	// VRID is the virtual router ID, between 1 and 255
+	VRID int `json:"vrid"`
+	// Priority sent in the advertisements, between 1 and 255. Defaults to 100.
+	Priority int `json:"priority,omitempty"`
+	// VirtualIPs are the addresses of the virtual router, all of the same IP family
+	VirtualIPs []string `json:"virtual_ips"`
+	// AdvertInterval is the duration between two advertisements (e.g. "1s"), up to 40.95s
+	AdvertInterval string `json:"advert_interval,omitempty"`
+	// VirtualMAC adds a macvlan interface owning the virtual MAC address 00-00-5E-00-01-{VRID}
+	// (00-00-5E-00-02-{VRID} for IPv6) while the endpoint is activated
+	VirtualMAC bool `json:"virtual_mac,omitempty"`
+	// Interface overrides the interface configured on the host for this endpoint
+	Interface string `json:"interface,omitempty"`
+	// Netns overrides the network namespace configured on the host for this endpoint: a name under
+	// /var/run/netns or a path
+	Netns string `json:"netns,omitempty"`
+}
+
+type IPVSPluginConfig struct {
+	// Address is the virtual IP of the service
+	Address string `json:"address"`
+	// Protocol is tcp, udp or sctp. Defaults to tcp.
+	Protocol string `json:"protocol,omitempty"`
+	Port     int    `json:"port"`
+	// Scheduler is the IPVS scheduling algorithm (e.g. "rr", "wlc"). Defaults to rr.
+	Scheduler string `json:"scheduler,omitempty"`
+	// PersistenceTimeout sends the connections of a client to the same real server during this
+	// duration (e.g. "5m"). Disabled by default.
+	PersistenceTimeout string                 `json:"persistence_timeout,omitempty"`
+	RealServers        []IPVSRealServerConfig `json:"real_servers"`
+	// Netns is the network namespace of the service: a name under /var/run/netns or a path
+	Netns string `json:"netns,omitempty"`
+}
+
+type IPVSRealServerConfig struct {
+	Address string `json:"address"`
+	// Port defaults to the port of the service
+	Port int `json:"port,omitempty"`
+	// Weight defaults to 1. A weight of 0 stops sending new connections to the real server.
+	Weight *int `json:"weight,omitempty"`
+	// Forwarding is the forwarding method: dr (direct routing), nat or tunnel. Defaults to dr.
+	Forwarding string `json:"forwarding,omitempty"`
+}
+
 type OutscalePublicIPPluginConfig struct {
 	AccessKey string `json:"access_key"`
 	SecretKey string `json:"secret_key"`
 	Region    string `json:"region"`
 
 	PublicIPID string `json:"public_ip_id"`
-	NICID      string `json:"nic_id"`
+	// NICID is the NIC the public IP is linked to. If it is empty, the NIC is discovered with the
+	// metadata service of the VM: the NIC attached with NICDeviceIndex, or the primary NIC.
+	NICID          string `json:"nic_id,omitempty"`
+	NICDeviceIndex *int   `json:"nic_device_index,omitempty"`
+}
+
+type OutscalePrivateIPPluginConfig struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	Region    string `json:"region"`
+
+	// PrivateIP is the secondary private IP moved to the NIC of the active host
+	PrivateIP string `json:"private_ip"`
+	NICID     string `json:"nic_id"`
+	// Interface is the local interface where the private IP is added while the endpoint is
+	// activated. The IP is not configured on the host if it is empty.
+	Interface string `json:"interface,omitempty"`
+}
+
+type OutscaleRouteTablePluginConfig struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	Region    string `json:"region"`
+
+	// NICID is the NIC of the active host, set as the target of the routes
+	NICID  string                    `json:"nic_id"`
+	Routes []OutscaleRouteTableRoute `json:"routes"`
+}
+
+// OutscaleRouteTableRoute is a route of a route table targeting the NIC of the active host. The
+// route must already exist in the route table.
+type OutscaleRouteTableRoute struct {
+	RouteTableID       string `json:"route_table_id"`
+	DestinationIPRange string `json:"destination_ip_range"`
+}
+
+type OutscaleLoadBalancerPluginConfig struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	Region    string `json:"region"`
+
+	LoadBalancerName string `json:"load_balancer_name"`
+	// VMID is the VM of the current host, registered as a backend of the load balancer while the
+	// endpoint is activated
+	VMID string `json:"vm_id"`
+}
+
+const (
+	AWSEIPModeElasticIP = "elastic_ip"
+	AWSEIPModePrivateIP = "private_ip"
+)
+
+type AWSEIPPluginConfig struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	Region    string `json:"region"`
+
+	// Mode is elastic_ip to associate an Elastic IP with the ENI of the active host, or private_ip
+	// to assign a secondary private IP to it. Defaults to elastic_ip.
+	Mode string `json:"mode,omitempty"`
+	// AllocationID is the Elastic IP associated with the ENI, only used with the elastic_ip mode
+	AllocationID       string `json:"allocation_id,omitempty"`
+	NetworkInterfaceID string `json:"network_interface_id"`
+	// PrivateIP is the secondary private IP moved to the ENI with the private_ip mode. With the
+	// elastic_ip mode, it is the private IP of the ENI associated with the Elastic IP, the primary
+	// private IP if it is empty.
+	PrivateIP string `json:"private_ip,omitempty"`
+	// AllowReassociation lets the Elastic IP or the private IP be moved when it is still associated
+	// with another ENI. Defaults to true.
+	AllowReassociation *bool `json:"allow_reassociation,omitempty"`
+	// Interface is the local interface where the private IP is added while the endpoint is
+	// activated, only used with the private_ip mode. The IP is not configured on the host if it is
+	// empty.
+	Interface string `json:"interface,omitempty"`
+}
+
+type HetznerFloatingIPPluginConfig struct {
+	APIToken string `json:"api_token"`
+
+	FloatingIPID int64 `json:"floating_ip_id"`
+	// ServerID is the server of the current host, the floating IP is assigned to it while the
+	// endpoint is activated
+	ServerID int64 `json:"server_id"`
+}
+
+type ScalewayFlexibleIPPluginConfig struct {
+	SecretKey string `json:"secret_key"`
+	Zone      string `json:"zone"`
+
+	IPID string `json:"ip_id"`
+	// ServerID is the Instance of the current host, the flexible IP is attached to it while the
+	// endpoint is activated
+	ServerID string `json:"server_id"`
+}
+
+const (
+	OpenStackNeutronModeFloatingIP         = "floating_ip"
+	OpenStackNeutronModeAllowedAddressPair = "allowed_address_pair"
+)
+
+type OpenStackNeutronPluginConfig struct {
+	// AuthURL is the URL of the Keystone v3 API (e.g. https://keystone.example.com:5000/v3)
+	AuthURL                     string `json:"auth_url"`
+	ApplicationCredentialID     string `json:"application_credential_id"`
+	ApplicationCredentialSecret string `json:"application_credential_secret"`
+	// Region is used to find the network endpoint in the service catalog
+	Region string `json:"region,omitempty"`
+
+	// Mode is floating_ip to associate a floating IP with the port of the active host, or
+	// allowed_address_pair to add the VIP to the allowed address pairs of this port. Defaults to
+	// floating_ip.
+	Mode   string `json:"mode,omitempty"`
+	PortID string `json:"port_id"`
+	// FloatingIPID is the floating IP associated with the port, only used with the floating_ip mode
+	FloatingIPID string `json:"floating_ip_id,omitempty"`
+	// FixedIP is the IP of the port associated with the floating IP, required by Neutron if the port
+	// has several IPv4 addresses. Only used with the floating_ip mode.
+	FixedIP string `json:"fixed_ip,omitempty"`
+	// VIP is the IP, or the prefix, added to the allowed address pairs of the port with the
+	// allowed_address_pair mode
+	VIP string `json:"vip,omitempty"`
+}
+
+type DNSRFC2136PluginConfig struct {
+	// Server is the primary server of the zone receiving the dynamic updates, as host or host:port
+	Server string `json:"server"`
+	Zone   string `json:"zone"`
+	// Name of the record, fully qualified or relative to the zone
+	Name string `json:"name"`
+	// Type is A, AAAA or CNAME
+	Type string `json:"type"`
+	// Value is the IP of the current host for an A or AAAA record, the target of a CNAME record
+	Value string `json:"value"`
+	// TTL of the record in seconds, defaults to 60
+	TTL uint32 `json:"ttl,omitempty"`
+
+	TSIGKeyName string `json:"tsig_key_name"`
+	// TSIGAlgorithm defaults to hmac-sha256
+	TSIGAlgorithm string `json:"tsig_algorithm,omitempty"`
+	// TSIGSecret is the base64 encoded secret of the TSIG key
+	TSIGSecret string `json:"tsig_secret"`
+
+	// RemoveOnDeactivate removes the record of the current host when the endpoint is deactivated
+	RemoveOnDeactivate bool `json:"remove_on_deactivate,omitempty"`
+}
+
+const (
+	DNSAPIProviderCloudflare = "cloudflare"
+	DNSAPIProviderPowerDNS   = "powerdns"
+)
+
+type DNSAPIPluginConfig struct {
+	// Provider is cloudflare or powerdns
+	Provider string `json:"provider"`
+	// APIToken is the Cloudflare API token or the PowerDNS API key
+	APIToken string `json:"api_token"`
+	// APIURL is the URL of the PowerDNS webserver (e.g. http://127.0.0.1:8081), only used with
+	// PowerDNS
+	APIURL string `json:"api_url,omitempty"`
+	// ServerID is the PowerDNS server, defaults to localhost
+	ServerID string `json:"server_id,omitempty"`
+
+	Zone string `json:"zone"`
+	// Name of the record, fully qualified or relative to the zone
+	Name string `json:"name"`
+	// IP of the current host, the type of the record is A for an IPv4 and AAAA for an IPv6
+	IP string `json:"ip"`
+	// TTL of the record in seconds, defaults to 60
+	TTL uint32 `json:"ttl,omitempty"`
 }
 
 type WebhookPluginStatusChangePayload struct {
@@ -87,3 +436,30 @@ type WebhookPluginStatusChangePayload struct {
 	Plugin     string `json:"plugin"`
 	Status     string `json:"status"`
 }
+
+type NftablesPluginConfig struct {
+	// Rules are the NAT rules installed while the endpoint is activated
+	Rules []NftablesRuleConfig `json:"rules"`
+	// Netns is the network namespace of the rules: a name under /var/run/netns or a path
+	Netns string `json:"netns,omitempty"`
+}
+
+type NftablesRuleConfig struct {
+	// Type is dnat, snat or masquerade
+	Type string `json:"type"`
+	// Protocol matched by the rule: tcp, udp or sctp. It is required to match or translate a port.
+	Protocol string `json:"protocol,omitempty"`
+	// Source is the source prefix matched by the rule, using CIDR notation or a bare IP
+	Source string `json:"source,omitempty"`
+	// Destination is the destination prefix matched by the rule, using CIDR notation or a bare IP
+	Destination string `json:"destination,omitempty"`
+	// Port is the destination port matched by the rule
+	Port int `json:"port,omitempty"`
+	// Interface is the input interface of a dnat rule or the output interface of a snat or
+	// masquerade rule
+	Interface string `json:"interface,omitempty"`
+	// ToAddress is the translated address, required for dnat and snat rules
+	ToAddress string `json:"to_address,omitempty"`
+	// ToPort is the translated port. Defaults to the original port.
+	ToPort int `json:"to_port,omitempty"`
+}
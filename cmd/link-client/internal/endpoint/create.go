@@ -3,6 +3,8 @@ package endpoint
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/logrusorgru/aurora/v3"
 	"github.com/urfave/cli/v3"
@@ -11,7 +13,21 @@ import (
 	"github.com/Scalingo/link/v3/api"
 	"github.com/Scalingo/link/v3/cmd/link-client/internal/utils"
 	"github.com/Scalingo/link/v3/plugin/arp"
+	awseip "github.com/Scalingo/link/v3/plugin/aws_eip"
+	"github.com/Scalingo/link/v3/plugin/bgp"
+	dnsapi "github.com/Scalingo/link/v3/plugin/dns_api"
+	dnsrfc2136 "github.com/Scalingo/link/v3/plugin/dns_rfc2136"
+	hetznerfloatingip "github.com/Scalingo/link/v3/plugin/hetzner_floating_ip"
+	"github.com/Scalingo/link/v3/plugin/ipvs"
+	"github.com/Scalingo/link/v3/plugin/nftables"
+	openstackneutron "github.com/Scalingo/link/v3/plugin/openstack_neutron"
+	outscaleloadbalancer "github.com/Scalingo/link/v3/plugin/outscale_load_balancer"
+	outscaleprivateip "github.com/Scalingo/link/v3/plugin/outscale_private_ip"
 	outscalepublicip "github.com/Scalingo/link/v3/plugin/outscale_public_ip"
+	outscaleroutetable "github.com/Scalingo/link/v3/plugin/outscale_route_table"
+	"github.com/Scalingo/link/v3/plugin/route"
+	scalewayflexibleip "github.com/Scalingo/link/v3/plugin/scaleway_flexible_ip"
+	"github.com/Scalingo/link/v3/plugin/vrrp"
 )
 
 func Create(ctx context.Context, c *cli.Command) error {
@@ -28,6 +44,34 @@ func Create(ctx context.Context, c *cli.Command) error {
 		pluginConfig, err = getArpPluginConfig(ctx, c)
 	case outscalepublicip.Name:
 		pluginConfig, err = getOutscalePublicIPPluginConfig(ctx, c)
+	case outscaleprivateip.Name:
+		pluginConfig, err = getOutscalePrivateIPPluginConfig(ctx, c)
+	case outscaleroutetable.Name:
+		pluginConfig, err = getOutscaleRouteTablePluginConfig(ctx, c)
+	case outscaleloadbalancer.Name:
+		pluginConfig, err = getOutscaleLoadBalancerPluginConfig(ctx, c)
+	case awseip.Name:
+		pluginConfig, err = getAWSEIPPluginConfig(ctx, c)
+	case hetznerfloatingip.Name:
+		pluginConfig, err = getHetznerFloatingIPPluginConfig(ctx, c)
+	case scalewayflexibleip.Name:
+		pluginConfig, err = getScalewayFlexibleIPPluginConfig(ctx, c)
+	case openstackneutron.Name:
+		pluginConfig, err = getOpenStackNeutronPluginConfig(ctx, c)
+	case dnsrfc2136.Name:
+		pluginConfig, err = getDNSRFC2136PluginConfig(ctx, c)
+	case dnsapi.Name:
+		pluginConfig, err = getDNSAPIPluginConfig(ctx, c)
+	case route.Name:
+		pluginConfig, err = getRoutePluginConfig(ctx, c)
+	case bgp.Name:
+		pluginConfig, err = getBGPPluginConfig(ctx, c)
+	case vrrp.Name:
+		pluginConfig, err = getVRRPPluginConfig(ctx, c)
+	case ipvs.Name:
+		pluginConfig, err = getIPVSPluginConfig(ctx, c)
+	case nftables.Name:
+		pluginConfig, err = getNftablesPluginConfig(ctx, c)
 	default:
 		err = fmt.Errorf("plugin %s not supported", params.Plugin)
 	}
@@ -54,13 +98,123 @@ func Create(ctx context.Context, c *cli.Command) error {
 }
 
 func getArpPluginConfig(ctx context.Context, c *cli.Command) (arp.PluginConfig, error) {
-	value := c.String("ip")
-	if value == "" {
+	values := c.StringSlice("ip")
+	if len(values) == 0 {
 		return arp.PluginConfig{}, errors.New(ctx, "ip is required for arp plugin")
 	}
 
-	return arp.PluginConfig{
-		IP: value,
+	cfg := arp.PluginConfig{
+		Interface: c.String("interface"),
+		Netns:     c.String("netns"),
+	}
+	if len(values) == 1 {
+		cfg.IP = values[0]
+	} else {
+		cfg.IPs = values
+	}
+	return cfg, nil
+}
+
+func getRoutePluginConfig(ctx context.Context, c *cli.Command) (route.PluginConfig, error) {
+	destination := c.String("destination")
+	if destination == "" {
+		return route.PluginConfig{}, errors.New(ctx, "destination is required for route plugin")
+	}
+
+	return route.PluginConfig{
+		Routes: []api.RouteConfig{{
+			Destination: destination,
+			Gateway:     c.String("gateway"),
+			Device:      c.String("device"),
+			Metric:      c.Int("metric"),
+			Table:       c.Int("table"),
+		}},
+		Netns: c.String("netns"),
+	}, nil
+}
+
+func getBGPPluginConfig(ctx context.Context, c *cli.Command) (bgp.PluginConfig, error) {
+	values := c.StringSlice("ip")
+	if len(values) == 0 {
+		return bgp.PluginConfig{}, errors.New(ctx, "ip is required for bgp plugin")
+	}
+
+	cfg := bgp.PluginConfig{
+		NextHop:     c.String("next-hop"),
+		Communities: c.StringSlice("community"),
+	}
+	if len(values) == 1 {
+		cfg.IP = values[0]
+	} else {
+		cfg.IPs = values
+	}
+	return cfg, nil
+}
+
+func getVRRPPluginConfig(ctx context.Context, c *cli.Command) (vrrp.PluginConfig, error) {
+	vrid := c.Int("vrid")
+	if vrid == 0 {
+		return vrrp.PluginConfig{}, errors.New(ctx, "vrid is required for vrrp plugin")
+	}
+	virtualIPs := c.StringSlice("ip")
+	if len(virtualIPs) == 0 {
+		return vrrp.PluginConfig{}, errors.New(ctx, "ip is required for vrrp plugin")
+	}
+
+	return vrrp.PluginConfig{
+		VRID:       vrid,
+		Priority:   c.Int("priority"),
+		VirtualIPs: virtualIPs,
+		VirtualMAC: c.Bool("virtual-mac"),
+		Interface:  c.String("interface"),
+		Netns:      c.String("netns"),
+	}, nil
+}
+
+func getIPVSPluginConfig(ctx context.Context, c *cli.Command) (ipvs.PluginConfig, error) {
+	ips := c.StringSlice("ip")
+	if len(ips) != 1 {
+		return ipvs.PluginConfig{}, errors.New(ctx, "a single ip is required for ipvs plugin")
+	}
+	port := c.Int("port")
+	if port == 0 {
+		return ipvs.PluginConfig{}, errors.New(ctx, "port is required for ipvs plugin")
+	}
+
+	cfg := ipvs.PluginConfig{
+		Address:   ips[0],
+		Protocol:  c.String("protocol"),
+		Port:      port,
+		Scheduler: c.String("scheduler"),
+		Netns:     c.String("netns"),
+	}
+	for _, realServer := range c.StringSlice("real-server") {
+		cfg.RealServers = append(cfg.RealServers, api.IPVSRealServerConfig{
+			Address:    realServer,
+			Forwarding: c.String("forwarding"),
+		})
+	}
+	return cfg, nil
+}
+
+func getNftablesPluginConfig(ctx context.Context, c *cli.Command) (nftables.PluginConfig, error) {
+	natType := c.String("nat-type")
+	if natType == "" {
+		return nftables.PluginConfig{}, errors.New(ctx, "nat-type is required for nftables plugin")
+	}
+
+	return nftables.PluginConfig{
+		Rules: []api.NftablesRuleConfig{{
+			Type:        natType,
+			Protocol:    c.String("protocol"),
+			Source:      c.String("source"),
+			Destination: c.String("destination"),
+			Port:        c.Int("port"),
+			Interface:   c.String("interface"),
+			ToAddress:   c.String("to-address"),
+			ToPort:      c.Int("to-port"),
+		}},
+		Netns: c.String("netns"),
 	}, nil
 }
 
@@ -70,10 +224,6 @@ func getOutscalePublicIPPluginConfig(ctx context.Context, c *cli.Command) (outsc
 		return outscalepublicip.PluginConfig{}, errors.New(ctx, "public-ip-id is required for outscale public ip plugin")
 	}
 
-	nicID := c.String("nic-id")
-	if nicID == "" {
-		return outscalepublicip.PluginConfig{}, errors.New(ctx, "nic-id is required for outscale public ip plugin")
-	}
 	region := c.String("region")
 	if region == "" {
 		return outscalepublicip.PluginConfig{}, errors.New(ctx, "region is required for outscale public ip plugin")
@@ -87,11 +237,355 @@ func getOutscalePublicIPPluginConfig(ctx context.Context, c *cli.Command) (outsc
 		return outscalepublicip.PluginConfig{}, errors.New(ctx, "secret-key is required for outscale public ip plugin")
 	}
 
-	return outscalepublicip.PluginConfig{
+	cfg := outscalepublicip.PluginConfig{
 		PublicIPID: publicIPID,
-		NICID:      nicID,
+		NICID:      c.String("nic-id"),
 		Region:     region,
 		AccessKey:  accessKey,
 		SecretKey:  secretKey,
+	}
+	if c.IsSet("nic-device-index") {
+		nicDeviceIndex := c.Int("nic-device-index")
+		cfg.NICDeviceIndex = &nicDeviceIndex
+	}
+	return cfg, nil
+}
+
+func getOutscalePrivateIPPluginConfig(ctx context.Context, c *cli.Command) (outscaleprivateip.PluginConfig, error) {
+	privateIP := c.String("private-ip")
+	if privateIP == "" {
+		return outscaleprivateip.PluginConfig{}, errors.New(ctx, "private-ip is required for outscale private ip plugin")
+	}
+
+	nicID := c.String("nic-id")
+	if nicID == "" {
+		return outscaleprivateip.PluginConfig{}, errors.New(ctx, "nic-id is required for outscale private ip plugin")
+	}
+	region := c.String("region")
+	if region == "" {
+		return outscaleprivateip.PluginConfig{}, errors.New(ctx, "region is required for outscale private ip plugin")
+	}
+	accessKey := c.String("access-key")
+	if accessKey == "" {
+		return outscaleprivateip.PluginConfig{}, errors.New(ctx, "access-key is required for outscale private ip plugin")
+	}
+	secretKey := c.String("secret-key")
+	if secretKey == "" {
+		return outscaleprivateip.PluginConfig{}, errors.New(ctx, "secret-key is required for outscale private ip plugin")
+	}
+
+	return outscaleprivateip.PluginConfig{
+		PrivateIP: privateIP,
+		NICID:     nicID,
+		Interface: c.String("interface"),
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}, nil
+}
+
+func getOutscaleRouteTablePluginConfig(ctx context.Context, c *cli.Command) (outscaleroutetable.PluginConfig, error) {
+	routes := c.StringSlice("route-table-route")
+	if len(routes) == 0 {
+		return outscaleroutetable.PluginConfig{}, errors.New(ctx, "route-table-route is required for outscale route table plugin")
+	}
+
+	nicID := c.String("nic-id")
+	if nicID == "" {
+		return outscaleroutetable.PluginConfig{}, errors.New(ctx, "nic-id is required for outscale route table plugin")
+	}
+	region := c.String("region")
+	if region == "" {
+		return outscaleroutetable.PluginConfig{}, errors.New(ctx, "region is required for outscale route table plugin")
+	}
+	accessKey := c.String("access-key")
+	if accessKey == "" {
+		return outscaleroutetable.PluginConfig{}, errors.New(ctx, "access-key is required for outscale route table plugin")
+	}
+	secretKey := c.String("secret-key")
+	if secretKey == "" {
+		return outscaleroutetable.PluginConfig{}, errors.New(ctx, "secret-key is required for outscale route table plugin")
+	}
+
+	cfg := outscaleroutetable.PluginConfig{
+		NICID:     nicID,
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+	for _, r := range routes {
+		routeTableID, destination, ok := strings.Cut(r, ":")
+		if !ok {
+			return outscaleroutetable.PluginConfig{}, errors.Newf(ctx, "invalid route %s, format must be ROUTE_TABLE_ID:DESTINATION", r)
+		}
+		cfg.Routes = append(cfg.Routes, api.OutscaleRouteTableRoute{
+			RouteTableID:       routeTableID,
+			DestinationIPRange: destination,
+		})
+	}
+	return cfg, nil
+}
+
+func getOutscaleLoadBalancerPluginConfig(ctx context.Context, c *cli.Command) (outscaleloadbalancer.PluginConfig, error) {
+	loadBalancerName := c.String("load-balancer-name")
+	if loadBalancerName == "" {
+		return outscaleloadbalancer.PluginConfig{}, errors.New(ctx, "load-balancer-name is required for outscale load balancer plugin")
+	}
+
+	vmID := c.String("vm-id")
+	if vmID == "" {
+		return outscaleloadbalancer.PluginConfig{}, errors.New(ctx, "vm-id is required for outscale load balancer plugin")
+	}
+	region := c.String("region")
+	if region == "" {
+		return outscaleloadbalancer.PluginConfig{}, errors.New(ctx, "region is required for outscale load balancer plugin")
+	}
+	accessKey := c.String("access-key")
+	if accessKey == "" {
+		return outscaleloadbalancer.PluginConfig{}, errors.New(ctx, "access-key is required for outscale load balancer plugin")
+	}
+	secretKey := c.String("secret-key")
+	if secretKey == "" {
+		return outscaleloadbalancer.PluginConfig{}, errors.New(ctx, "secret-key is required for outscale load balancer plugin")
+	}
+
+	return outscaleloadbalancer.PluginConfig{
+		LoadBalancerName: loadBalancerName,
+		VMID:             vmID,
+		Region:           region,
+		AccessKey:        accessKey,
+		SecretKey:        secretKey,
+	}, nil
+}
+
+func getAWSEIPPluginConfig(ctx context.Context, c *cli.Command) (awseip.PluginConfig, error) {
+	mode := c.String("aws-eip-mode")
+	allocationID := c.String("allocation-id")
+	if allocationID == "" && mode != api.AWSEIPModePrivateIP {
+		return awseip.PluginConfig{}, errors.New(ctx, "allocation-id is required for aws eip plugin")
+	}
+	privateIP := c.String("private-ip")
+	if privateIP == "" && mode == api.AWSEIPModePrivateIP {
+		return awseip.PluginConfig{}, errors.New(ctx, "private-ip is required for aws eip plugin with the private_ip mode")
+	}
+
+	networkInterfaceID := c.String("network-interface-id")
+	if networkInterfaceID == "" {
+		return awseip.PluginConfig{}, errors.New(ctx, "network-interface-id is required for aws eip plugin")
+	}
+	region := c.String("region")
+	if region == "" {
+		return awseip.PluginConfig{}, errors.New(ctx, "region is required for aws eip plugin")
+	}
+	accessKey := c.String("access-key")
+	if accessKey == "" {
+		return awseip.PluginConfig{}, errors.New(ctx, "access-key is required for aws eip plugin")
+	}
+	secretKey := c.String("secret-key")
+	if secretKey == "" {
+		return awseip.PluginConfig{}, errors.New(ctx, "secret-key is required for aws eip plugin")
+	}
+
+	cfg := awseip.PluginConfig{
+		Mode:               mode,
+		AllocationID:       allocationID,
+		NetworkInterfaceID: networkInterfaceID,
+		PrivateIP:          privateIP,
+		Interface:          c.String("interface"),
+		Region:             region,
+		AccessKey:          accessKey,
+		SecretKey:          secretKey,
+	}
+	if c.IsSet("allow-reassociation") {
+		allowReassociation := c.Bool("allow-reassociation")
+		cfg.AllowReassociation = &allowReassociation
+	}
+	return cfg, nil
+}
+
+func getHetznerFloatingIPPluginConfig(ctx context.Context, c *cli.Command) (hetznerfloatingip.PluginConfig, error) {
+	floatingIPID := c.Int("floating-ip-id")
+	if floatingIPID == 0 {
+		return hetznerfloatingip.PluginConfig{}, errors.New(ctx, "floating-ip-id is required for hetzner floating ip plugin")
+	}
+
+	if c.String("server-id") == "" {
+		return hetznerfloatingip.PluginConfig{}, errors.New(ctx, "server-id is required for hetzner floating ip plugin")
+	}
+	serverID, err := strconv.ParseInt(c.String("server-id"), 10, 64)
+	if err != nil {
+		return hetznerfloatingip.PluginConfig{}, errors.Wrap(ctx, err, "invalid server-id for hetzner floating ip plugin")
+	}
+	apiToken := c.String("api-token")
+	if apiToken == "" {
+		return hetznerfloatingip.PluginConfig{}, errors.New(ctx, "api-token is required for hetzner floating ip plugin")
+	}
+
+	return hetznerfloatingip.PluginConfig{
+		FloatingIPID: int64(floatingIPID),
+		ServerID:     serverID,
+		APIToken:     apiToken,
+	}, nil
+}
+
+func getScalewayFlexibleIPPluginConfig(ctx context.Context, c *cli.Command) (scalewayflexibleip.PluginConfig, error) {
+	ipID := c.String("ip-id")
+	if ipID == "" {
+		return scalewayflexibleip.PluginConfig{}, errors.New(ctx, "ip-id is required for scaleway flexible ip plugin")
+	}
+
+	serverID := c.String("server-id")
+	if serverID == "" {
+		return scalewayflexibleip.PluginConfig{}, errors.New(ctx, "server-id is required for scaleway flexible ip plugin")
+	}
+	zone := c.String("zone")
+	if zone == "" {
+		return scalewayflexibleip.PluginConfig{}, errors.New(ctx, "zone is required for scaleway flexible ip plugin")
+	}
+	secretKey := c.String("secret-key")
+	if secretKey == "" {
+		return scalewayflexibleip.PluginConfig{}, errors.New(ctx, "secret-key is required for scaleway flexible ip plugin")
+	}
+
+	return scalewayflexibleip.PluginConfig{
+		IPID:      ipID,
+		ServerID:  serverID,
+		Zone:      zone,
+		SecretKey: secretKey,
+	}, nil
+}
+
+func getOpenStackNeutronPluginConfig(ctx context.Context, c *cli.Command) (openstackneutron.PluginConfig, error) {
+	portID := c.String("port-id")
+	if portID == "" {
+		return openstackneutron.PluginConfig{}, errors.New(ctx, "port-id is required for openstack neutron plugin")
+	}
+
+	mode := c.String("openstack-mode")
+	floatingIPID := c.String("neutron-floating-ip-id")
+	vip := c.String("vip")
+	if mode == api.OpenStackNeutronModeAllowedAddressPair {
+		if vip == "" {
+			return openstackneutron.PluginConfig{}, errors.New(ctx, "vip is required for openstack neutron plugin with the allowed_address_pair mode")
+		}
+	} else if floatingIPID == "" {
+		return openstackneutron.PluginConfig{}, errors.New(ctx, "neutron-floating-ip-id is required for openstack neutron plugin with the floating_ip mode")
+	}
+
+	authURL := c.String("auth-url")
+	if authURL == "" {
+		return openstackneutron.PluginConfig{}, errors.New(ctx, "auth-url is required for openstack neutron plugin")
+	}
+	applicationCredentialID := c.String("application-credential-id")
+	if applicationCredentialID == "" {
+		return openstackneutron.PluginConfig{}, errors.New(ctx, "application-credential-id is required for openstack neutron plugin")
+	}
+	applicationCredentialSecret := c.String("application-credential-secret")
+	if applicationCredentialSecret == "" {
+		return openstackneutron.PluginConfig{}, errors.New(ctx, "application-credential-secret is required for openstack neutron plugin")
+	}
+
+	return openstackneutron.PluginConfig{
+		AuthURL:                     authURL,
+		ApplicationCredentialID:     applicationCredentialID,
+		ApplicationCredentialSecret: applicationCredentialSecret,
+		Region:                      c.String("region"),
+		Mode:                        mode,
+		PortID:                      portID,
+		FloatingIPID:                floatingIPID,
+		FixedIP:                     c.String("fixed-ip"),
+		VIP:                         vip,
+	}, nil
+}
+
+func getDNSRFC2136PluginConfig(ctx context.Context, c *cli.Command) (dnsrfc2136.PluginConfig, error) {
+	server := c.String("dns-server")
+	if server == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "dns-server is required for dns rfc2136 plugin")
+	}
+	zone := c.String("zone")
+	if zone == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "zone is required for dns rfc2136 plugin")
+	}
+	name := c.String("record-name")
+	if name == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "record-name is required for dns rfc2136 plugin")
+	}
+	recordType := c.String("record-type")
+	if recordType == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "record-type is required for dns rfc2136 plugin")
+	}
+	value := c.String("record-value")
+	if value == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "record-value is required for dns rfc2136 plugin")
+	}
+	ttl := c.Int("record-ttl")
+	if ttl < 0 {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "record-ttl must be positive for dns rfc2136 plugin")
+	}
+
+	tsigKeyName := c.String("tsig-key-name")
+	if tsigKeyName == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "tsig-key-name is required for dns rfc2136 plugin")
+	}
+	tsigSecret := c.String("tsig-secret")
+	if tsigSecret == "" {
+		return dnsrfc2136.PluginConfig{}, errors.New(ctx, "tsig-secret is required for dns rfc2136 plugin")
+	}
+
+	return dnsrfc2136.PluginConfig{
+		Server:             server,
+		Zone:               zone,
+		Name:               name,
+		Type:               recordType,
+		Value:              value,
+		TTL:                uint32(ttl),
+		TSIGKeyName:        tsigKeyName,
+		TSIGAlgorithm:      c.String("tsig-algorithm"),
+		TSIGSecret:         tsigSecret,
+		RemoveOnDeactivate: c.Bool("remove-on-deactivate"),
+	}, nil
+}
+
+func getDNSAPIPluginConfig(ctx context.Context, c *cli.Command) (dnsapi.PluginConfig, error) {
+	provider := c.String("dns-provider")
+	if provider == "" {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "dns-provider is required for dns api plugin")
+	}
+	apiURL := c.String("api-url")
+	if provider == api.DNSAPIProviderPowerDNS && apiURL == "" {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "api-url is required for dns api plugin with the powerdns provider")
+	}
+	apiToken := c.String("api-token")
+	if apiToken == "" {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "api-token is required for dns api plugin")
+	}
+
+	zone := c.String("zone")
+	if zone == "" {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "zone is required for dns api plugin")
+	}
+	name := c.String("record-name")
+	if name == "" {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "record-name is required for dns api plugin")
+	}
+	ip := c.String("record-value")
+	if ip == "" {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "record-value is required for dns api plugin")
+	}
+	ttl := c.Int("record-ttl")
+	if ttl < 0 {
+		return dnsapi.PluginConfig{}, errors.New(ctx, "record-ttl must be positive for dns api plugin")
+	}
+
+	return dnsapi.PluginConfig{
+		Provider: provider,
+		APIToken: apiToken,
+		APIURL:   apiURL,
+		ServerID: c.String("powerdns-server-id"),
+		Zone:     zone,
+		Name:     name,
+		IP:       ip,
+		TTL:      uint32(ttl),
 	}, nil
 }
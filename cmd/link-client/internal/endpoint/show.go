@@ -3,7 +3,9 @@ package endpoint
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/logrusorgru/aurora/v3"
 	"github.com/urfave/cli/v3"
 
 	"github.com/Scalingo/go-utils/errors/v2"
@@ -34,6 +36,12 @@ func Show(ctx context.Context, c *cli.Command) error {
 			fmt.Printf(" - Type: %s, Host: %s, Port: %v\n", check.Type, check.Host, check.Port)
 		}
 	}
+	if len(endpoint.Conditions) > 0 {
+		fmt.Println("Conditions:")
+		for _, condition := range endpoint.Conditions {
+			fmt.Printf(" - %s (since %s): %s\n", aurora.Red(condition.Type), condition.Since.Format(time.RFC3339), condition.Message)
+		}
+	}
 
 	hosts, err := client.GetEndpointHosts(ctx, endpointID)
 	if err != nil {
@@ -87,9 +87,98 @@ func main() {
 					Required: true,
 				},
 				// ARP Plugin
-				&cli.StringFlag{
+				&cli.StringSliceFlag{
 					Name:  "ip",
-					Usage: "For ARP Plugin: IP to add",
+					Usage: "For ARP, BGP, VRRP and IPVS Plugins: IP to add, prefix to announce or virtual IP, can be repeated to manage several IPs with the same endpoint",
+				},
+				&cli.StringFlag{
+					Name:  "interface",
+					Usage: "For ARP and VRRP Plugins: Interface where the IPs are added or the advertisements are sent, defaults to the interface configured on the host. For nftables Plugin: Input interface of a dnat rule or output interface of a snat or masquerade rule. For Outscale Private IP and AWS EIP Plugins: Local interface where the private IP is added",
+				},
+				&cli.StringFlag{
+					Name:  "netns",
+					Usage: "For ARP, Route, VRRP, IPVS and nftables Plugins: Network namespace of the interface or the service, a name under /var/run/netns or a path",
+				},
+				// Route Plugin
+				&cli.StringFlag{
+					Name:  "destination",
+					Usage: "For Route and nftables Plugins: Destination of the route or destination matched by the rule using CIDR notation",
+				},
+				&cli.StringFlag{
+					Name:  "gateway",
+					Usage: "For Route Plugin: Gateway of the route",
+				},
+				&cli.StringFlag{
+					Name:  "device",
+					Usage: "For Route Plugin: Output interface of the route",
+				},
+				&cli.IntFlag{
+					Name:  "metric",
+					Usage: "For Route Plugin: Metric of the route",
+				},
+				&cli.IntFlag{
+					Name:  "table",
+					Usage: "For Route Plugin: Routing table of the route, defaults to the main table",
+				},
+				// BGP Plugin
+				&cli.StringFlag{
+					Name:  "next-hop",
+					Usage: "For BGP Plugin: Next hop of the prefixes, defaults to the next hop configured on the host",
+				},
+				&cli.StringSliceFlag{
+					Name:  "community",
+					Usage: "For BGP Plugin: Community attached to the prefixes (e.g. 65000:100 or no-export), can be repeated",
+				},
+				// VRRP Plugin
+				&cli.IntFlag{
+					Name:  "vrid",
+					Usage: "For VRRP Plugin: Virtual router ID, between 1 and 255",
+				},
+				&cli.IntFlag{
+					Name:  "priority",
+					Usage: "For VRRP Plugin: Priority sent in the advertisements, defaults to 100",
+				},
+				&cli.BoolFlag{
+					Name:  "virtual-mac",
+					Usage: "For VRRP Plugin: Own the virtual MAC address of the virtual router through a macvlan interface",
+				},
+				// IPVS Plugin
+				&cli.StringFlag{
+					Name:  "protocol",
+					Usage: "For IPVS and nftables Plugins: Protocol of the virtual service or protocol matched by the rule: tcp, udp or sctp, defaults to tcp for IPVS",
+				},
+				&cli.IntFlag{
+					Name:  "port",
+					Usage: "For IPVS and nftables Plugins: Port of the virtual service or destination port matched by the rule",
+				},
+				&cli.StringFlag{
+					Name:  "scheduler",
+					Usage: "For IPVS Plugin: Scheduling algorithm of the virtual service, defaults to rr",
+				},
+				&cli.StringSliceFlag{
+					Name:  "real-server",
+					Usage: "For IPVS Plugin: Address of a real server, can be repeated",
+				},
+				&cli.StringFlag{
+					Name:  "forwarding",
+					Usage: "For IPVS Plugin: Forwarding method of the real servers: dr, nat or tunnel, defaults to dr",
+				},
+				// nftables Plugin
+				&cli.StringFlag{
+					Name:  "nat-type",
+					Usage: "For nftables Plugin: Type of the NAT rule: dnat, snat or masquerade",
+				},
+				&cli.StringFlag{
+					Name:  "source",
+					Usage: "For nftables Plugin: Source matched by the rule using CIDR notation",
+				},
+				&cli.StringFlag{
+					Name:  "to-address",
+					Usage: "For nftables Plugin: Translated address of a dnat or snat rule",
+				},
+				&cli.IntFlag{
+					Name:  "to-port",
+					Usage: "For nftables Plugin: Translated port, defaults to the original port",
 				},
 				// Outscale Public IP Plugin
 				&cli.StringFlag{
@@ -98,19 +187,165 @@ func main() {
 				},
 				&cli.StringFlag{
 					Name:  "nic-id",
-					Usage: "For Outscale Public IP Plugin: ID of the NIC to add the public IP to",
+					Usage: "For Outscale Public IP, Private IP and Route Table Plugins: ID of the NIC to add the public IP, to link the private IP to or to set as target of the routes. Optional for Outscale Public IP Plugin, the NIC is then discovered with the metadata service of the VM",
+				},
+				&cli.IntFlag{
+					Name:  "nic-device-index",
+					Usage: "For Outscale Public IP Plugin: Device index of the NIC discovered with the metadata service of the VM, defaults to the primary NIC",
 				},
 				&cli.StringFlag{
 					Name:  "region",
-					Usage: "For Outscale Public IP Plugin: Region of the public IP",
+					Usage: "For Outscale, AWS and OpenStack Neutron Plugins: Region of the Outscale or AWS resources, or of the network endpoint in the OpenStack service catalog",
 				},
 				&cli.StringFlag{
 					Name:  "access-key",
-					Usage: "For Outscale Public IP Plugin: Access key for the Outscale API",
+					Usage: "For Outscale and AWS Plugins: Access key for the Outscale or AWS API",
 				},
 				&cli.StringFlag{
 					Name:  "secret-key",
-					Usage: "For Outscale Public IP Plugin: Secret key for the Outscale API",
+					Usage: "For Outscale, AWS and Scaleway Plugins: Secret key for the Outscale, AWS or Scaleway API",
+				},
+				// Outscale Private IP Plugin
+				&cli.StringFlag{
+					Name:  "private-ip",
+					Usage: "For Outscale Private IP and AWS EIP Plugins: Secondary private IP to link to the NIC or to assign to the ENI. For AWS EIP Plugin with the elastic_ip mode: Private IP of the ENI associated with the Elastic IP, defaults to the primary private IP",
+				},
+				// Outscale Route Table Plugin
+				&cli.StringSliceFlag{
+					Name:  "route-table-route",
+					Usage: "For Outscale Route Table Plugin: Route targeting the NIC, format: ROUTE_TABLE_ID:DESTINATION (e.g. rtb-12345678:0.0.0.0/0), can be repeated",
+				},
+				// Outscale Load Balancer Plugin
+				&cli.StringFlag{
+					Name:  "load-balancer-name",
+					Usage: "For Outscale Load Balancer Plugin: Name of the load balancer",
+				},
+				&cli.StringFlag{
+					Name:  "vm-id",
+					Usage: "For Outscale Load Balancer Plugin: ID of the VM to register in the load balancer",
+				},
+				// AWS EIP Plugin
+				&cli.StringFlag{
+					Name:  "aws-eip-mode",
+					Usage: "For AWS EIP Plugin: elastic_ip to associate an Elastic IP with the ENI or private_ip to assign a secondary private IP to it, defaults to elastic_ip",
+				},
+				&cli.StringFlag{
+					Name:  "allocation-id",
+					Usage: "For AWS EIP Plugin: Allocation ID of the Elastic IP",
+				},
+				&cli.StringFlag{
+					Name:  "network-interface-id",
+					Usage: "For AWS EIP Plugin: ID of the ENI to associate the Elastic IP with or to assign the private IP to",
+				},
+				&cli.BoolFlag{
+					Name:  "allow-reassociation",
+					Value: true,
+					Usage: "For AWS EIP Plugin: Move the Elastic IP or the private IP when it is still associated with another ENI",
+				},
+				// Hetzner Floating IP Plugin
+				&cli.StringFlag{
+					Name:  "api-token",
+					Usage: "For Hetzner Floating IP and DNS API Plugins: Token for the Hetzner Cloud API, the Cloudflare API or the PowerDNS API",
+				},
+				&cli.IntFlag{
+					Name:  "floating-ip-id",
+					Usage: "For Hetzner Floating IP Plugin: ID of the floating IP",
+				},
+				&cli.StringFlag{
+					Name:  "server-id",
+					Usage: "For Hetzner Floating IP and Scaleway Flexible IP Plugins: ID of the server to assign the IP to",
+				},
+				// Scaleway Flexible IP Plugin
+				&cli.StringFlag{
+					Name:  "zone",
+					Usage: "For Scaleway Flexible IP Plugin: Zone of the Scaleway resources. For DNS RFC 2136 and DNS API Plugins: DNS zone of the record",
+				},
+				&cli.StringFlag{
+					Name:  "ip-id",
+					Usage: "For Scaleway Flexible IP Plugin: ID of the flexible IP",
+				},
+				// OpenStack Neutron Plugin
+				&cli.StringFlag{
+					Name:  "auth-url",
+					Usage: "For OpenStack Neutron Plugin: URL of the Keystone v3 API",
+				},
+				&cli.StringFlag{
+					Name:  "application-credential-id",
+					Usage: "For OpenStack Neutron Plugin: ID of the application credential",
+				},
+				&cli.StringFlag{
+					Name:  "application-credential-secret",
+					Usage: "For OpenStack Neutron Plugin: Secret of the application credential",
+				},
+				&cli.StringFlag{
+					Name:  "openstack-mode",
+					Usage: "For OpenStack Neutron Plugin: floating_ip to associate a floating IP with the port or allowed_address_pair to add the VIP to its allowed address pairs, defaults to floating_ip",
+				},
+				&cli.StringFlag{
+					Name:  "port-id",
+					Usage: "For OpenStack Neutron Plugin: ID of the port of the host",
+				},
+				&cli.StringFlag{
+					Name:  "neutron-floating-ip-id",
+					Usage: "For OpenStack Neutron Plugin with the floating_ip mode: ID of the floating IP",
+				},
+				&cli.StringFlag{
+					Name:  "fixed-ip",
+					Usage: "For OpenStack Neutron Plugin with the floating_ip mode: IP of the port associated with the floating IP, required if the port has several IPv4 addresses",
+				},
+				&cli.StringFlag{
+					Name:  "vip",
+					Usage: "For OpenStack Neutron Plugin with the allowed_address_pair mode: IP or prefix added to the allowed address pairs of the port",
+				},
+				// DNS RFC 2136 Plugin
+				&cli.StringFlag{
+					Name:  "dns-server",
+					Usage: "For DNS RFC 2136 Plugin: Primary server of the zone receiving the updates, as host or host:port",
+				},
+				&cli.StringFlag{
+					Name:  "record-name",
+					Usage: "For DNS RFC 2136 and DNS API Plugins: Name of the record, fully qualified or relative to the zone",
+				},
+				&cli.StringFlag{
+					Name:  "record-type",
+					Usage: "For DNS RFC 2136 Plugin: Type of the record: A, AAAA or CNAME",
+				},
+				&cli.StringFlag{
+					Name:  "record-value",
+					Usage: "For DNS RFC 2136 and DNS API Plugins: IP of the host for an A or AAAA record. For DNS RFC 2136 Plugin: Target of a CNAME record",
+				},
+				&cli.IntFlag{
+					Name:  "record-ttl",
+					Usage: "For DNS RFC 2136 and DNS API Plugins: TTL of the record in seconds, defaults to 60",
+				},
+				&cli.StringFlag{
+					Name:  "tsig-key-name",
+					Usage: "For DNS RFC 2136 Plugin: Name of the TSIG key",
+				},
+				&cli.StringFlag{
+					Name:  "tsig-algorithm",
+					Usage: "For DNS RFC 2136 Plugin: Algorithm of the TSIG key, defaults to hmac-sha256",
+				},
+				&cli.StringFlag{
+					Name:  "tsig-secret",
+					Usage: "For DNS RFC 2136 Plugin: Base64 encoded secret of the TSIG key",
+				},
+				&cli.BoolFlag{
+					Name:  "remove-on-deactivate",
+					Usage: "For DNS RFC 2136 Plugin: Remove the record of the host when the endpoint is deactivated",
+				},
+				// DNS API Plugin
+				&cli.StringFlag{
+					Name:  "dns-provider",
+					Usage: "For DNS API Plugin: Provider hosting the zone: cloudflare or powerdns",
+				},
+				&cli.StringFlag{
+					Name:  "api-url",
+					Usage: "For DNS API Plugin with the powerdns provider: URL of the PowerDNS webserver",
+				},
+				&cli.StringFlag{
+					Name:  "powerdns-server-id",
+					Usage: "For DNS API Plugin with the powerdns provider: ID of the PowerDNS server, defaults to localhost",
 				},
 				&cli.IntFlag{
 					Name:  "health-check-interval",
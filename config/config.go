@@ -24,6 +24,9 @@ type Config struct {
 
 	PluginEnsureInterval           time.Duration `envconfig:"PLUGIN_ENSURE_INTERVAL" default:"1s"`
 	PluginEnsureMaxBackoffInterval time.Duration `envconfig:"PLUGIN_ENSURE_MAX_BACKOFF_INTERVAL" default:"10m"`
+	PluginActivateTimeout          time.Duration `envconfig:"PLUGIN_ACTIVATE_TIMEOUT" default:"30s"`
+	PluginDeactivateTimeout        time.Duration `envconfig:"PLUGIN_DEACTIVATE_TIMEOUT" default:"30s"`
+	PluginEnsureTimeout            time.Duration `envconfig:"PLUGIN_ENSURE_TIMEOUT" default:"30s"`
 
 	ARPGratuitousInterval   time.Duration `envconfig:"ARP_GRATUITOUS_INTERVAL" default:"1s"` // Deprecated: Use PluginEnsureInterval
 	FailCountBeforeFailover int           `envconfig:"FAIL_COUNT_BEFORE_FAILOVER" default:"3"`
@@ -32,6 +35,18 @@ type Config struct {
 	SecretStorageAlternateKeys []string `envconfig:"SECRET_STORAGE_ALTERNATE_KEYS" default:""`
 
 	MaxNumberOfEndpoints int `envconfig:"MAX_NUMBER_OF_ENDPOINTS" default:"1000"`
+
+	ShutdownTimeout           time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"30s"`
+	ShutdownKeepPluginsActive bool          `envconfig:"SHUTDOWN_KEEP_PLUGINS_ACTIVE" default:"false"`
+
+	LocalStatePath           string        `envconfig:"LOCAL_STATE_PATH" default:""`
+	LocalStateSyncInterval   time.Duration `envconfig:"LOCAL_STATE_SYNC_INTERVAL" default:"1m"`
+	LocalStateIncludeSecrets bool          `envconfig:"LOCAL_STATE_INCLUDE_SECRETS" default:"false"`
+
+	OutscaleAPIRateLimit   float64       `envconfig:"OUTSCALE_API_RATE_LIMIT" default:"5"`
+	OutscaleAPIRateBurst   int           `envconfig:"OUTSCALE_API_RATE_BURST" default:"10"`
+	OutscaleAPIMaxAttempts int           `envconfig:"OUTSCALE_API_MAX_ATTEMPTS" default:"5"`
+	OutscaleAPIRetryWait   time.Duration `envconfig:"OUTSCALE_API_RETRY_WAIT" default:"1s"`
 }
 
 // LeaseTime is 5 * the global keepalive interval
@@ -13,13 +13,16 @@ require (
 	github.com/Scalingo/go-utils/logger v1.12.2
 	github.com/Scalingo/go-utils/retry v1.4.1
 	github.com/Scalingo/link/v2 v2.0.7
+	github.com/aws/aws-sdk-go v1.55.8
 	github.com/cenkalti/backoff/v5 v5.0.3
 	github.com/gofrs/uuid/v5 v5.5.0
+	github.com/google/nftables v0.3.0
 	github.com/gorilla/mux v1.8.1
-	github.com/j-keck/arping v1.0.3
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/logrusorgru/aurora/v3 v3.0.0
 	github.com/looplab/fsm v1.0.3
+	github.com/miekg/dns v1.1.59
+	github.com/moby/ipvs v1.1.0
 	github.com/olekukonko/tablewriter v1.1.4
 	github.com/outscale/osc-sdk-go/v2 v2.34.0
 	github.com/pkg/errors v0.9.1
@@ -27,16 +30,18 @@ require (
 	github.com/stretchr/testify v1.11.1
 	github.com/urfave/cli/v3 v3.10.1
 	github.com/vishvananda/netlink v1.3.1
+	github.com/vishvananda/netns v0.0.5
 	go.etcd.io/etcd/api/v3 v3.7.1
 	go.etcd.io/etcd/client/v3 v3.7.1
 	go.uber.org/mock v0.6.0
+	golang.org/x/sys v0.47.0
+	golang.org/x/time v0.15.0
 )
 
 require (
 	github.com/Scalingo/errgo-rollbar v0.2.1 // indirect
 	github.com/Scalingo/go-utils/security v1.2.2 // indirect
 	github.com/Scalingo/logrus-rollbar v1.4.5 // indirect
-	github.com/aws/aws-sdk-go v1.55.8 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/clipperhouse/displaywidth v0.11.0 // indirect
 	github.com/clipperhouse/uax29/v2 v2.7.0 // indirect
@@ -49,18 +54,20 @@ require (
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/goccy/go-json v0.10.6 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.29.0 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/mattn/go-colorable v0.1.15 // indirect
 	github.com/mattn/go-isatty v0.0.24 // indirect
 	github.com/mattn/go-runewidth v0.0.27 // indirect
+	github.com/mdlayher/netlink v1.7.3-0.20250113171957-fbb4dce95f42 // indirect
+	github.com/mdlayher/socket v0.5.0 // indirect
 	github.com/olekukonko/cat v0.0.0-20250911104152-50322a0618f6 // indirect
 	github.com/olekukonko/errors v1.3.0 // indirect
 	github.com/olekukonko/ll v0.1.8 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/rollbar/rollbar-go v1.4.8 // indirect
 	github.com/urfave/negroni/v3 v3.1.1 // indirect
-	github.com/vishvananda/netns v0.0.5 // indirect
 	go.etcd.io/etcd/client/pkg/v3 v3.7.1 // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux v0.69.0 // indirect
@@ -71,7 +78,7 @@ require (
 	go.uber.org/zap v1.28.0 // indirect
 	golang.org/x/net v0.57.0 // indirect
 	golang.org/x/oauth2 v0.36.0 // indirect
-	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/sync v0.6.0 // indirect
 	golang.org/x/text v0.40.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20260526163538-3dc84a4a5aaa // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260526163538-3dc84a4a5aaa // indirect
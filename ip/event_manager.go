@@ -20,12 +20,27 @@ import (
 	2.2. If we are the owner of the lock on the endpoint remove it
 	2.3. Remove us from the list of potential hosts for this endpoint (UnlinkEndpointFromCurrentHost). This will trigger other hosts to try to get the endpoint
 	2.4. Send a demoted event so that the state machine is in a state to remote the endpoint
+  3. Wait for the plugin worker to apply the last transition
+
+  If the KeepPluginActive option is set, steps 2.2 to 2.4 are skipped: the lock is kept until our lease
+  expires and the plugin is left in its current state. A LinK process restarted before the lease
+  expiration can then resume the endpoint without any failover.
 */
 
-func (m *EndpointManager) Stop(ctx context.Context) error {
+// StopOpts are the options of the EndpointManager Stop method
+type StopOpts struct {
+	// KeepPluginActive leaves the plugin in its current state and does not hand over the endpoint to another host.
+	KeepPluginActive bool
+}
+
+func (m *EndpointManager) Stop(ctx context.Context, opts StopOpts) error {
 	log := logger.Get(ctx).WithField("process", "stop")
 	ctx = logger.ToCtx(ctx, log)
 
+	if opts.KeepPluginActive {
+		return m.stopKeepingPluginActive(ctx)
+	}
+
 	log.Info("Stops the endpoint manager")
 	hosts, err := m.storage.GetEndpointHosts(ctx, m.plugin.ElectionKey(ctx))
 	if err != nil {
@@ -79,10 +94,49 @@ func (m *EndpointManager) Stop(ctx context.Context) error {
 	// We can stop the FSM we do not need it anymore
 	close(m.eventChan)
 
+	err = m.waitForPluginWorker(ctx)
+	if err != nil {
+		return errors.Wrap(err, "fail to wait for the plugin worker")
+	}
+
+	log.Info("Stop process ended!")
+	return nil
+}
+
+func (m *EndpointManager) stopKeepingPluginActive(ctx context.Context) error {
+	log := logger.Get(ctx)
+	log.Info("Stops the endpoint manager, keeping the plugin in its current state")
+
+	m.stopMutex.Lock()
+	defer m.stopMutex.Unlock()
+
+	m.stopped = true
+
+	log.Info("Stop the watcher")
+	m.watcher.Stop(ctx)
+
+	close(m.eventChan)
+
+	err := m.waitForPluginWorker(ctx)
+	if err != nil {
+		return errors.Wrap(err, "fail to wait for the plugin worker")
+	}
+
 	log.Info("Stop process ended!")
 	return nil
 }
 
+// waitForPluginWorker waits for the Start method to return. It returns once the plugin worker
+// applied the last transition.
+func (m *EndpointManager) waitForPluginWorker(ctx context.Context) error {
+	select {
+	case <-m.doneChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // endpointCheckLoop tries to get the endpoint at regular intervals. It is useful to get the endpoint if the current primary crashed.
 func (m *EndpointManager) endpointCheckLoop(ctx context.Context) {
 	for {
@@ -190,16 +190,21 @@ func TestManager_Stop(t *testing.T) {
 			eventChan := make(chan string, 2)
 			events := make([]string, 0)
 
+			// The manager Start method is not running in this test
+			doneChan := make(chan struct{})
+			close(doneChan)
+
 			manager := &EndpointManager{
 				stateMachine: fsm.NewFSM(example.CurrentState, fsm.Events{}, fsm.Callbacks{}),
 				locker:       lockerMock,
 				storage:      storageMock,
 				watcher:      watcherMock,
 				eventChan:    eventChan,
+				doneChan:     doneChan,
 				plugin:       pluginMock,
 			}
 
-			err := manager.Stop(context.Background())
+			err := manager.Stop(context.Background(), StopOpts{})
 			require.NoError(t, err)
 
 			timer := time.NewTimer(1 * time.Second)
@@ -223,3 +228,34 @@ func TestManager_Stop(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_Stop_KeepPluginActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The locker and the storage must not be called: the lock is kept and the endpoint is not handed over
+	lockerMock := lockermock.NewMockLocker(ctrl)
+	storageMock := models.NewMockStorage(ctrl)
+	watcherMock := watchermock.NewMockWatcher(ctrl)
+	watcherMock.EXPECT().Stop(gomock.Any())
+
+	doneChan := make(chan struct{})
+	close(doneChan)
+	eventChan := make(chan string, 2)
+
+	manager := &EndpointManager{
+		stateMachine: fsm.NewFSM(ACTIVATED, fsm.Events{}, fsm.Callbacks{}),
+		locker:       lockerMock,
+		storage:      storageMock,
+		watcher:      watcherMock,
+		eventChan:    eventChan,
+		doneChan:     doneChan,
+	}
+
+	err := manager.Stop(context.Background(), StopOpts{KeepPluginActive: true})
+	require.NoError(t, err)
+
+	_, ok := <-eventChan
+	assert.False(t, ok, "no event should be sent and the event channel should be closed")
+	assert.True(t, manager.stopped)
+}
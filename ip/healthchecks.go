@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/Scalingo/go-utils/errors/v2"
 	"github.com/Scalingo/go-utils/logger"
 )
 
@@ -18,6 +19,12 @@ func (m *EndpointManager) healthChecker(ctx context.Context) {
 		healthy, err := m.checker.IsHealthy(ctx)
 		m.checkerMutex.RUnlock()
 
+		if healthy {
+			// Failing conditions reported by the plugin (e.g. an address conflict) are considered as failed health checks
+			err = m.failingConditionsError(ctx)
+			healthy = err == nil
+		}
+
 		// The eventManager closes the channel `eventChan` when we receive the Stop order. We do not want to send anything on a closed channel.
 		// Since the checker can take up to 5s to run the checks, we need to check the manager stopped status between the health check and sending the results.
 		if m.isStopped() {
@@ -30,6 +37,16 @@ func (m *EndpointManager) healthChecker(ctx context.Context) {
 	}
 }
 
+// failingConditionsError returns an error if the plugin reported a failing condition
+func (m *EndpointManager) failingConditionsError(ctx context.Context) error {
+	for _, condition := range m.Conditions(ctx) {
+		if condition.Failing {
+			return errors.Newf(ctx, "failing condition %s: %s", condition.Type, condition.Message)
+		}
+	}
+	return nil
+}
+
 func (m *EndpointManager) sendHealthCheckResults(ctx context.Context, healthy bool, err error) {
 	log := logger.Get(ctx)
 	if healthy {
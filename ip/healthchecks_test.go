@@ -10,8 +10,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"github.com/Scalingo/link/v3/api"
 	"github.com/Scalingo/link/v3/config"
 	"github.com/Scalingo/link/v3/healthcheck/healthcheckmock"
+	"github.com/Scalingo/link/v3/plugin/pluginmock"
 )
 
 func TestManager_HealthChecker(t *testing.T) {
@@ -111,3 +113,49 @@ func TestManager_HealthChecker(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_FailingConditionsError(t *testing.T) {
+	examples := []struct {
+		Name          string
+		Conditions    []api.EndpointCondition
+		ExpectedError string
+	}{
+		{
+			Name: "Without conditions",
+		}, {
+			Name:       "With a condition which is not failing",
+			Conditions: []api.EndpointCondition{{Type: api.EndpointConditionAddressConflict, Message: "conflict"}},
+		}, {
+			Name:          "With a failing condition",
+			Conditions:    []api.EndpointCondition{{Type: api.EndpointConditionAddressConflict, Message: "conflict", Failing: true}},
+			ExpectedError: "failing condition AddressConflict: conflict",
+		},
+	}
+
+	for _, example := range examples {
+		t.Run(example.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			reporter := pluginmock.NewMockConditionReporter(ctrl)
+			reporter.EXPECT().Conditions(gomock.Any()).Return(example.Conditions)
+
+			manager := &EndpointManager{
+				plugin: conditionReporterPlugin{
+					MockPlugin:            pluginmock.NewMockPlugin(ctrl),
+					MockConditionReporter: reporter,
+				},
+			}
+
+			err := manager.failingConditionsError(context.Background())
+			if example.ExpectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, example.ExpectedError)
+		})
+	}
+}
+
+type conditionReporterPlugin struct {
+	*pluginmock.MockPlugin
+	*pluginmock.MockConditionReporter
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Scalingo/go-utils/logger"
 	"github.com/Scalingo/go-utils/retry"
+	"github.com/Scalingo/link/v3/api"
 	"github.com/Scalingo/link/v3/config"
 	"github.com/Scalingo/link/v3/healthcheck"
 	"github.com/Scalingo/link/v3/locker"
@@ -31,12 +32,14 @@ const (
 
 type Manager interface {
 	Start(ctx context.Context)
-	Stop(ctx context.Context) error
+	Stop(ctx context.Context, opts StopOpts) error
 	Failover(ctx context.Context) error
 	Status() string
 	Endpoint() models.Endpoint
 	ElectionKey(ctx context.Context) string
 	SetHealthChecks(ctx context.Context, config config.Config, checks []models.HealthCheck)
+	Conditions(ctx context.Context) []api.EndpointCondition
+	IsMaster(ctx context.Context) (bool, error)
 }
 
 type EndpointManager struct {
@@ -51,8 +54,10 @@ type EndpointManager struct {
 	watcher                 watcher.Watcher
 	retry                   retry.Retry
 	plugin                  plugin.Plugin
+	pluginWorker            *pluginWorker
 	ensureBackoff           Backoff
 	eventChan               chan string
+	doneChan                chan struct{} // Closed when the Start method returns
 	keepaliveRetry          int
 	healthCheckFailingCount int
 	stopped                 bool
@@ -68,9 +73,11 @@ func NewManager(ctx context.Context, cfg config.Config, endpoint models.Endpoint
 		config:                  cfg,
 		storage:                 storage,
 		eventChan:               make(chan string),
+		doneChan:                make(chan struct{}),
 		healthCheckFailingCount: 0,
 		retry:                   retry.New(retry.WithWaitDuration(10*time.Second), retry.WithMaxAttempts(5)),
 		plugin:                  plugin,
+		pluginWorker:            newPluginWorker(cfg, plugin),
 		ensureBackoff: &backoff.ExponentialBackOff{
 			InitialInterval: cfg.PluginEnsureInterval,
 			Multiplier:      pluginEnsureBackoffMultiplier,
@@ -93,6 +100,7 @@ func NewManager(ctx context.Context, cfg config.Config, endpoint models.Endpoint
 func (m *EndpointManager) Start(ctx context.Context) {
 	ctx, log := logger.WithStructToCtx(ctx, "endpoint", m.endpoint)
 	log.Info("Starting manager")
+	defer close(m.doneChan)
 
 	err := m.retry.Do(ctx, func(ctx context.Context) error {
 		return m.storage.LinkEndpointWithCurrentHost(ctx, m.plugin.ElectionKey(ctx))
@@ -102,8 +110,11 @@ func (m *EndpointManager) Start(ctx context.Context) {
 	}
 
 	ctx = logger.ToCtx(ctx, log)
+	m.resumePreviousState(ctx)
+
 	go m.endpointCheckLoop(ctx) // Will continuously try to get the endpoint
 	go m.healthChecker(ctx)     // HealthChecker
+	go m.pluginWorker.run(ctx)  // Applies the plugin actions asynchronously
 	go m.startPluginEnsureLoop(ctx)
 	go m.watcher.Start(ctx) // Start a watcher that will notify us if other hosts are joining or leaving this endpoint
 
@@ -117,6 +128,9 @@ func (m *EndpointManager) Start(ctx context.Context) {
 			}
 		}
 	}
+
+	// Wait for the plugin worker to apply the last transition
+	m.pluginWorker.stop(ctx)
 	log.Info("Manager stopped")
 }
 
@@ -134,6 +148,20 @@ func (m *EndpointManager) ElectionKey(ctx context.Context) string {
 	return m.plugin.ElectionKey(ctx)
 }
 
+// Conditions returns the conditions reported by the plugin, if it supports it
+func (m *EndpointManager) Conditions(ctx context.Context) []api.EndpointCondition {
+	reporter, ok := m.plugin.(plugin.ConditionReporter)
+	if !ok {
+		return nil
+	}
+	return reporter.Conditions(ctx)
+}
+
+// IsMaster returns true if this host currently owns the lock of the endpoint
+func (m *EndpointManager) IsMaster(ctx context.Context) (bool, error) {
+	return m.locker.IsMaster(ctx)
+}
+
 // sendEvent sends an event to the state machine
 func (m *EndpointManager) sendEvent(status string) {
 	if m.isStopped() {
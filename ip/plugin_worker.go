@@ -0,0 +1,292 @@
+package ip
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/config"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+type pluginState string
+
+const (
+	pluginStateUnknown     pluginState = "unknown"
+	pluginStateActivated   pluginState = "activated"
+	pluginStateDeactivated pluginState = "deactivated"
+)
+
+// pluginWorker runs the plugin actions of an endpoint outside of the state machine callbacks.
+//
+// The state machine callbacks only set the desired state of the plugin. The worker then calls
+// Activate or Deactivate in its own goroutine with a timeout, so that a slow plugin never blocks
+// the event loop. If a newer transition arrives while an action is running, the context of the
+// running action is canceled and the worker converges to the new desired state.
+//
+// The worker also keeps track of the actual state of the plugin (the state of the last action
+// which succeeded). The plugin control loop uses it to retry failed actions.
+type pluginWorker struct {
+	plugin plugin.Plugin
+	config config.Config
+
+	// stateMutex protects desired, actual, cancelAction and onDeactivated
+	stateMutex   sync.Mutex
+	desired      pluginState
+	actual       pluginState
+	cancelAction context.CancelFunc
+	// onDeactivated is called once the worker tried to deactivate the plugin, see deactivateThen
+	onDeactivated func(context.Context)
+
+	// callMutex ensures that the plugin is never called concurrently. Plugins are not thread safe.
+	callMutex sync.Mutex
+
+	notifier chan struct{}
+	stopper  chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newPluginWorker(cfg config.Config, plugin plugin.Plugin) *pluginWorker {
+	return &pluginWorker{
+		plugin:   plugin,
+		config:   cfg,
+		desired:  pluginStateUnknown,
+		actual:   pluginStateUnknown,
+		notifier: make(chan struct{}, 1),
+		stopper:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// setDesiredState records the state the plugin should converge to. It never calls the plugin
+// and can be safely called from the state machine callbacks.
+func (w *pluginWorker) setDesiredState(ctx context.Context, state pluginState) {
+	log := logger.Get(ctx)
+
+	w.stateMutex.Lock()
+	if state != pluginStateDeactivated {
+		// The hook only applies to the pending deactivation
+		w.onDeactivated = nil
+	}
+	if w.desired == state {
+		w.stateMutex.Unlock()
+		return
+	}
+	w.desired = state
+	if w.cancelAction != nil {
+		log.WithField("desired_plugin_state", state).Info("Cancel the running plugin action, a newer transition arrived")
+		w.cancelAction()
+	}
+	w.stateMutex.Unlock()
+
+	w.notify()
+}
+
+func (w *pluginWorker) notify() {
+	select {
+	case w.notifier <- struct{}{}:
+	default:
+		// A notification is already pending, the worker will read the latest desired state anyway
+	}
+}
+
+// deactivateThen sets the deactivated desired state. The worker calls onDeactivated once it tried
+// to deactivate the plugin, even if the deactivation failed or timed out. The hook is dropped if
+// another desired state is set in the meantime.
+func (w *pluginWorker) deactivateThen(ctx context.Context, onDeactivated func(context.Context)) {
+	w.stateMutex.Lock()
+	w.onDeactivated = onDeactivated
+	w.stateMutex.Unlock()
+
+	w.setDesiredState(ctx, pluginStateDeactivated)
+
+	// The plugin may already be deactivated, the worker must run the hook anyway
+	w.notify()
+}
+
+func (w *pluginWorker) desiredState() pluginState {
+	w.stateMutex.Lock()
+	defer w.stateMutex.Unlock()
+	return w.desired
+}
+
+func (w *pluginWorker) actualState() pluginState {
+	w.stateMutex.Lock()
+	defer w.stateMutex.Unlock()
+	return w.actual
+}
+
+// markActivated records that the plugin is already activated, without calling it. It is used when
+// an endpoint is resumed after a restart.
+func (w *pluginWorker) markActivated() {
+	w.stateMutex.Lock()
+	defer w.stateMutex.Unlock()
+	w.desired = pluginStateActivated
+	w.actual = pluginStateActivated
+}
+
+// run applies the desired state changes until the worker is stopped. Before returning, it makes a
+// last attempt to converge so that the last transition (usually a demotion) is applied.
+func (w *pluginWorker) run(ctx context.Context) {
+	log := logger.Get(ctx).WithField("process", "plugin_worker")
+	ctx = logger.ToCtx(ctx, log)
+	defer close(w.done)
+
+	for {
+		select {
+		case <-w.notifier:
+			err := w.converge(ctx)
+			if err != nil {
+				log.WithError(err).Error("Fail to apply the plugin state, it will be retried by the plugin control loop")
+			}
+		case <-w.stopper:
+			err := w.converge(ctx)
+			if err != nil {
+				log.WithError(err).Error("Fail to apply the plugin state before stopping")
+			}
+			log.Info("Plugin worker stopped")
+			return
+		}
+	}
+}
+
+// stop stops the worker and waits for the last action to finish
+func (w *pluginWorker) stop(_ context.Context) {
+	w.stopOnce.Do(func() {
+		close(w.stopper)
+	})
+	<-w.done
+}
+
+// converge calls the plugin if the actual state of the plugin is not the desired state. Then it
+// runs the onDeactivated hook if the plugin should be deactivated.
+func (w *pluginWorker) converge(ctx context.Context) error {
+	w.callMutex.Lock()
+	defer w.callMutex.Unlock()
+
+	err := w.applyDesiredState(ctx)
+
+	w.stateMutex.Lock()
+	onDeactivated := w.onDeactivated
+	if w.desired != pluginStateDeactivated {
+		onDeactivated = nil
+	}
+	if onDeactivated != nil {
+		w.onDeactivated = nil
+	}
+	w.stateMutex.Unlock()
+
+	// The hook is run while holding callMutex, so that the plugin is not activated again before it
+	// returns
+	if onDeactivated != nil {
+		onDeactivated(ctx)
+	}
+	return err
+}
+
+func (w *pluginWorker) applyDesiredState(ctx context.Context) error {
+	w.stateMutex.Lock()
+	desired := w.desired
+	if desired == pluginStateUnknown || desired == w.actual {
+		w.stateMutex.Unlock()
+		return nil
+	}
+	actionCtx, cancel := w.actionContext(ctx, w.timeoutFor(desired))
+	w.cancelAction = cancel
+	w.stateMutex.Unlock()
+
+	var err error
+	switch desired {
+	case pluginStateActivated:
+		err = w.plugin.Activate(actionCtx)
+		if err != nil {
+			err = errors.Wrap(ctx, err, "activate endpoint")
+		}
+	case pluginStateDeactivated:
+		err = w.plugin.Deactivate(actionCtx)
+		if err != nil {
+			err = errors.Wrap(ctx, err, "deactivate endpoint")
+		}
+	}
+	cancel()
+
+	w.stateMutex.Lock()
+	defer w.stateMutex.Unlock()
+	w.cancelAction = nil
+	if err != nil {
+		// We do not know in which state the plugin has been left
+		w.actual = pluginStateUnknown
+		return err
+	}
+	w.actual = desired
+	return nil
+}
+
+// ensure is called by the plugin control loop. It first retries any action which did not succeed.
+// Then it runs the plugin Ensure method if the endpoint is activated, or the plugin
+// EnsureDeactivated method if the endpoint is deactivated and the plugin implements it.
+func (w *pluginWorker) ensure(ctx context.Context) error {
+	err := w.converge(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "converge to the desired plugin state")
+	}
+
+	w.callMutex.Lock()
+	defer w.callMutex.Unlock()
+
+	w.stateMutex.Lock()
+	if w.desired != w.actual {
+		// A newer transition arrived in the meantime, the worker will take care of it
+		w.stateMutex.Unlock()
+		return nil
+	}
+
+	var ensureFunc func(context.Context) error
+	switch w.actual {
+	case pluginStateActivated:
+		ensureFunc = w.plugin.Ensure
+	case pluginStateDeactivated:
+		ensurer, ok := w.plugin.(plugin.DeactivationEnsurer)
+		if ok {
+			ensureFunc = ensurer.EnsureDeactivated
+		}
+	}
+	if ensureFunc == nil {
+		w.stateMutex.Unlock()
+		return nil
+	}
+
+	ensureCtx, cancel := w.actionContext(ctx, w.config.PluginEnsureTimeout)
+	w.cancelAction = cancel
+	w.stateMutex.Unlock()
+
+	err = ensureFunc(ensureCtx)
+	cancel()
+
+	w.stateMutex.Lock()
+	w.cancelAction = nil
+	w.stateMutex.Unlock()
+
+	if err != nil {
+		return errors.Wrap(ctx, err, "ensure endpoint")
+	}
+	return nil
+}
+
+func (w *pluginWorker) timeoutFor(state pluginState) time.Duration {
+	if state == pluginStateActivated {
+		return w.config.PluginActivateTimeout
+	}
+	return w.config.PluginDeactivateTimeout
+}
+
+// actionContext returns a context canceled after the given timeout. A zero timeout disables it.
+func (w *pluginWorker) actionContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
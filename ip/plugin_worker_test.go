@@ -0,0 +1,161 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/config"
+	"github.com/Scalingo/link/v3/plugin/pluginmock"
+)
+
+func TestPluginWorker_Converge(t *testing.T) {
+	t.Run("it does nothing if the desired state is unknown", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		worker := newPluginWorker(config.Config{}, pluginMock)
+
+		err := worker.converge(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, pluginStateUnknown, worker.actualState())
+	})
+
+	t.Run("it does not call the plugin again if the actual state is the desired state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		pluginMock.EXPECT().Activate(gomock.Any()).Return(nil).Times(1)
+		worker := newPluginWorker(config.Config{}, pluginMock)
+
+		worker.setDesiredState(t.Context(), pluginStateActivated)
+		require.NoError(t, worker.converge(t.Context()))
+		require.NoError(t, worker.converge(t.Context()))
+		assert.Equal(t, pluginStateActivated, worker.actualState())
+	})
+
+	t.Run("it marks the actual state as unknown if the action fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		pluginMock.EXPECT().Deactivate(gomock.Any()).Return(errors.New("unlink error"))
+		worker := newPluginWorker(config.Config{}, pluginMock)
+		worker.actual = pluginStateActivated
+
+		worker.setDesiredState(t.Context(), pluginStateDeactivated)
+		err := worker.converge(t.Context())
+		require.Error(t, err)
+		assert.Equal(t, pluginStateUnknown, worker.actualState())
+	})
+
+	t.Run("it cancels the action after the configured timeout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		pluginMock.EXPECT().Activate(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		worker := newPluginWorker(config.Config{PluginActivateTimeout: 10 * time.Millisecond}, pluginMock)
+
+		worker.setDesiredState(t.Context(), pluginStateActivated)
+		err := worker.converge(t.Context())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestPluginWorker_Run(t *testing.T) {
+	t.Run("a newer transition cancels the running action", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		worker := newPluginWorker(config.Config{}, pluginMock)
+
+		activateStarted := make(chan struct{})
+		deactivated := make(chan struct{})
+		gomock.InOrder(
+			pluginMock.EXPECT().Activate(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+				close(activateStarted)
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+			pluginMock.EXPECT().Deactivate(gomock.Any()).DoAndReturn(func(_ context.Context) error {
+				close(deactivated)
+				return nil
+			}),
+		)
+
+		go worker.run(t.Context())
+
+		worker.setDesiredState(t.Context(), pluginStateActivated)
+		select {
+		case <-activateStarted:
+		case <-time.After(time.Second):
+			t.Fatal("Activate was never called")
+		}
+
+		worker.setDesiredState(t.Context(), pluginStateDeactivated)
+		select {
+		case <-deactivated:
+		case <-time.After(time.Second):
+			t.Fatal("Deactivate was never called")
+		}
+
+		worker.stop(t.Context())
+		assert.Equal(t, pluginStateDeactivated, worker.actualState())
+	})
+}
+
+func TestPluginWorker_Ensure(t *testing.T) {
+	t.Run("it retries the activation if the previous one failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		gomock.InOrder(
+			pluginMock.EXPECT().Activate(gomock.Any()).Return(errors.New("link error")),
+			pluginMock.EXPECT().Activate(gomock.Any()).Return(nil),
+			pluginMock.EXPECT().Ensure(gomock.Any()).Return(nil),
+		)
+		worker := newPluginWorker(config.Config{}, pluginMock)
+
+		worker.setDesiredState(t.Context(), pluginStateActivated)
+		require.Error(t, worker.converge(t.Context()))
+
+		err := worker.ensure(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, pluginStateActivated, worker.actualState())
+	})
+
+	t.Run("it does not call Ensure if the endpoint is not activated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
+		pluginMock.EXPECT().Deactivate(gomock.Any()).Return(nil)
+		worker := newPluginWorker(config.Config{}, pluginMock)
+
+		worker.setDesiredState(t.Context(), pluginStateDeactivated)
+		err := worker.ensure(t.Context())
+		require.NoError(t, err)
+	})
+
+	t.Run("it calls EnsureDeactivated if the endpoint is deactivated and the plugin implements it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		p := deactivationEnsurerPlugin{
+			MockPlugin:              pluginmock.NewMockPlugin(ctrl),
+			MockDeactivationEnsurer: pluginmock.NewMockDeactivationEnsurer(ctrl),
+		}
+		gomock.InOrder(
+			p.MockPlugin.EXPECT().Deactivate(gomock.Any()).Return(nil),
+			p.MockDeactivationEnsurer.EXPECT().EnsureDeactivated(gomock.Any()).Return(errors.New("unlink error")),
+			p.MockDeactivationEnsurer.EXPECT().EnsureDeactivated(gomock.Any()).Return(nil),
+		)
+		worker := newPluginWorker(config.Config{}, p)
+
+		worker.setDesiredState(t.Context(), pluginStateDeactivated)
+		require.Error(t, worker.ensure(t.Context()))
+		require.NoError(t, worker.ensure(t.Context()))
+	})
+}
+
+type deactivationEnsurerPlugin struct {
+	*pluginmock.MockPlugin
+	*pluginmock.MockDeactivationEnsurer
+}
@@ -11,37 +11,37 @@ import (
 	"github.com/Scalingo/link/v3/locker"
 )
 
+// The state machine callbacks do not call the plugin directly. They only set the desired state of
+// the plugin and the plugin worker applies it asynchronously. This prevents a slow plugin from
+// blocking the state machine event loop.
+
 func (m *EndpointManager) setActivated(ctx context.Context, _ *fsm.Event) {
 	log := logger.Get(ctx)
 	log.Info("New state: ACTIVATED")
-	err := m.plugin.Activate(ctx)
-	if err != nil {
-		log.WithError(err).Error("Fail to activate endpoint")
-	}
+	m.pluginWorker.setDesiredState(ctx, pluginStateActivated)
 }
 
 func (m *EndpointManager) setStandBy(ctx context.Context, _ *fsm.Event) {
 	log := logger.Get(ctx)
 	log.Info("New state: STANDBY")
-	err := m.plugin.Deactivate(ctx)
-	if err != nil {
-		log.WithError(err).Error("Fail to de-activate endpoint")
-	}
+	m.pluginWorker.setDesiredState(ctx, pluginStateDeactivated)
 }
 
 func (m *EndpointManager) setFailing(ctx context.Context, _ *fsm.Event) {
 	log := logger.Get(ctx)
 	log.Info("New state: FAILING")
 
-	err := m.plugin.Deactivate(ctx)
-	if err != nil {
-		log.WithError(err).Error("Fail to de-activate IP")
-	}
+	// The lock is only released once the worker tried to deactivate the plugin. Otherwise another
+	// host could activate the endpoint while it is still active on this host.
+	m.pluginWorker.deactivateThen(ctx, m.unlock)
+}
 
-	err = m.locker.Unlock(ctx)
+// unlock releases the lock of the endpoint so that another host can take it over
+func (m *EndpointManager) unlock(ctx context.Context) {
+	err := m.locker.Unlock(ctx)
 	if err != nil && err != locker.ErrNotMaster {
 		// If we are not master, we can safely ignore this error
-		log.WithError(err).Error("Fail to unlock the key")
+		logger.Get(ctx).WithError(err).Error("Fail to unlock the key")
 	}
 }
 
@@ -51,17 +51,13 @@ func (m *EndpointManager) startPluginEnsureLoop(ctx context.Context) {
 		if m.isStopped() {
 			return
 		}
-		currentState := m.Status()
-
 		hasFailed := false
 
-		if currentState == ACTIVATED {
-			log.Debug("Start plugin ensure")
-			err := m.plugin.Ensure(ctx)
-			if err != nil {
-				log.WithError(err).Error("Fail to run plugin ensure")
-				hasFailed = true
-			}
+		log.Debug("Start plugin ensure")
+		err := m.pluginWorker.ensure(ctx)
+		if err != nil {
+			log.WithError(err).Error("Fail to run plugin ensure")
+			hasFailed = true
 		}
 
 		timeToSleep := config.RandomDurationAround(m.config.PluginEnsureInterval, 0.25)
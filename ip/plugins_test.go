@@ -7,15 +7,25 @@ import (
 	"time"
 
 	"github.com/looplab/fsm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/Scalingo/link/v3/config"
 	"github.com/Scalingo/link/v3/ip/ipmock"
 	"github.com/Scalingo/link/v3/locker/lockermock"
 	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
 	"github.com/Scalingo/link/v3/plugin/pluginmock"
 )
 
+func newActivatedPluginWorker(cfg config.Config, p plugin.Plugin) *pluginWorker {
+	worker := newPluginWorker(cfg, p)
+	worker.desired = pluginStateActivated
+	worker.actual = pluginStateActivated
+	return worker
+}
+
 func TestSetActivated(t *testing.T) {
 	t.Run("It should call the plugin Activated Method", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -27,14 +37,20 @@ func TestSetActivated(t *testing.T) {
 			ID: "test-1234",
 		}
 
-		pluginMock.EXPECT().Activate(gomock.Any()).Return(nil)
-
 		manager := &EndpointManager{
-			plugin:   pluginMock,
-			endpoint: endpoint,
+			plugin:       pluginMock,
+			pluginWorker: newPluginWorker(config.Config{}, pluginMock),
+			endpoint:     endpoint,
 		}
 
+		// The state machine callback must not call the plugin itself
 		manager.setActivated(context.Background(), &fsm.Event{})
+		assert.Equal(t, pluginStateActivated, manager.pluginWorker.desiredState())
+
+		pluginMock.EXPECT().Activate(gomock.Any()).Return(nil)
+		err := manager.pluginWorker.converge(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, pluginStateActivated, manager.pluginWorker.actualState())
 	})
 }
 
@@ -48,39 +64,94 @@ func TestSetStandBy(t *testing.T) {
 		}
 
 		pluginMock := pluginmock.NewMockPlugin(ctrl)
-		pluginMock.EXPECT().Deactivate(gomock.Any()).Return(nil)
 
 		manager := &EndpointManager{
-			plugin:   pluginMock,
-			endpoint: endpoint,
+			plugin:       pluginMock,
+			pluginWorker: newPluginWorker(config.Config{}, pluginMock),
+			endpoint:     endpoint,
 		}
 
 		manager.setStandBy(context.Background(), &fsm.Event{})
+		assert.Equal(t, pluginStateDeactivated, manager.pluginWorker.desiredState())
+
+		pluginMock.EXPECT().Deactivate(gomock.Any()).Return(nil)
+		err := manager.pluginWorker.converge(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, pluginStateDeactivated, manager.pluginWorker.actualState())
 	})
 }
 
 func TestSetFailing(t *testing.T) {
-	t.Run("It remove the endpoint and stop the locker", func(t *testing.T) {
+	t.Run("It deactivates the plugin outside of the callback and then releases the lock", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		lockerMock := lockermock.NewMockLocker(ctrl)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
 
-		endpoint := models.Endpoint{
-			ID: "test-1234",
+		manager := &EndpointManager{
+			plugin:       pluginMock,
+			pluginWorker: newActivatedPluginWorker(config.Config{}, pluginMock),
+			locker:       lockerMock,
+			endpoint:     models.Endpoint{ID: "test-1234"},
 		}
 
+		// The mocks fail on any call made by the callback
+		manager.setFailing(context.Background(), &fsm.Event{})
+		assert.Equal(t, pluginStateDeactivated, manager.pluginWorker.desiredState())
+
+		gomock.InOrder(
+			pluginMock.EXPECT().Deactivate(gomock.Any()).Return(nil),
+			lockerMock.EXPECT().Unlock(gomock.Any()).Return(nil),
+		)
+		err := manager.pluginWorker.converge(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, pluginStateDeactivated, manager.pluginWorker.actualState())
+	})
+
+	t.Run("It releases the lock even if the deactivation fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		lockerMock := lockermock.NewMockLocker(ctrl)
 		pluginMock := pluginmock.NewMockPlugin(ctrl)
-		pluginMock.EXPECT().Deactivate(gomock.Any()).Return(nil)
-		lockerMock.EXPECT().Unlock(gomock.Any()).Return(nil)
 
 		manager := &EndpointManager{
-			plugin:   pluginMock,
-			locker:   lockerMock,
-			endpoint: endpoint,
+			plugin:       pluginMock,
+			pluginWorker: newActivatedPluginWorker(config.Config{}, pluginMock),
+			locker:       lockerMock,
+			endpoint:     models.Endpoint{ID: "test-1234"},
 		}
+		manager.setFailing(context.Background(), &fsm.Event{})
+
+		gomock.InOrder(
+			pluginMock.EXPECT().Deactivate(gomock.Any()).Return(fmt.Errorf("fail")),
+			lockerMock.EXPECT().Unlock(gomock.Any()).Return(nil),
+		)
+		err := manager.pluginWorker.converge(context.Background())
+		require.Error(t, err)
+		// The plugin control loop retries the deactivation
+		assert.Equal(t, pluginStateUnknown, manager.pluginWorker.actualState())
+	})
+
+	t.Run("It keeps the lock if the endpoint is activated again in the meantime", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		lockerMock := lockermock.NewMockLocker(ctrl)
+		pluginMock := pluginmock.NewMockPlugin(ctrl)
 
+		manager := &EndpointManager{
+			plugin:       pluginMock,
+			pluginWorker: newActivatedPluginWorker(config.Config{}, pluginMock),
+			locker:       lockerMock,
+			endpoint:     models.Endpoint{ID: "test-1234"},
+		}
 		manager.setFailing(context.Background(), &fsm.Event{})
+		manager.pluginWorker.setDesiredState(context.Background(), pluginStateActivated)
+
+		err := manager.pluginWorker.converge(context.Background())
+		require.NoError(t, err)
 	})
 }
 
@@ -110,6 +181,7 @@ func Test_startPluginEnsureLoop(t *testing.T) {
 			config:        config,
 			endpoint:      endpoint,
 			plugin:        pluginMock,
+			pluginWorker:  newActivatedPluginWorker(config, pluginMock),
 			ensureBackoff: backoffMock,
 		}
 
@@ -144,6 +216,7 @@ func Test_startPluginEnsureLoop(t *testing.T) {
 		sm.SetState(FAILING)
 		manager := &EndpointManager{
 			plugin:        pluginMock,
+			pluginWorker:  newPluginWorker(config, pluginMock),
 			stateMachine:  sm,
 			config:        config,
 			endpoint:      endpoint,
@@ -188,6 +261,7 @@ func Test_startPluginEnsureLoop(t *testing.T) {
 			config:        config,
 			endpoint:      endpoint,
 			plugin:        pluginMock,
+			pluginWorker:  newActivatedPluginWorker(config, pluginMock),
 			ensureBackoff: backoffMock,
 		}
 
@@ -0,0 +1,47 @@
+package ip
+
+import (
+	"context"
+
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+// resumePreviousState is called when the manager starts. If LinK restarted while its lease was
+// still alive, this host still owns the lock of the endpoint. In this case the endpoint is
+// directly resumed in the ACTIVATED state instead of BOOTING, so that the plugin is never
+// deactivated in the meantime.
+//
+// If the plugin is able to check its actual state, it is checked first to avoid calling Activate
+// again. Otherwise the plugin is activated by the worker, all plugin activations being idempotent.
+func (m *EndpointManager) resumePreviousState(ctx context.Context) {
+	log := logger.Get(ctx).WithField("process", "resume")
+
+	isMaster, err := m.locker.IsMaster(ctx)
+	if err != nil {
+		// The lock does not exist (or etcd is not reachable), fallback to the standard election
+		log.WithError(err).Debug("Fail to check if the lock is still owned by this host")
+		return
+	}
+	if !isMaster {
+		return
+	}
+
+	alreadyActivated := false
+	checker, ok := m.plugin.(plugin.ActivationChecker)
+	if ok {
+		alreadyActivated, err = checker.IsActivated(ctx)
+		if err != nil {
+			log.WithError(err).Info("Fail to check the actual state of the plugin, it will be activated again")
+			alreadyActivated = false
+		}
+	}
+
+	log.WithField("plugin_already_activated", alreadyActivated).Info("The lock is still owned by this host, resume the endpoint in ACTIVATED state")
+	m.stateMachine.SetState(ACTIVATED)
+	if alreadyActivated {
+		m.pluginWorker.markActivated()
+	} else {
+		m.pluginWorker.setDesiredState(ctx, pluginStateActivated)
+	}
+}
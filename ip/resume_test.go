@@ -0,0 +1,113 @@
+package ip
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/config"
+	"github.com/Scalingo/link/v3/locker/lockermock"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/plugin/pluginmock"
+)
+
+func TestManager_ResumePreviousState(t *testing.T) {
+	examples := []struct {
+		Name          string
+		Locker        func(*lockermock.MockLocker)
+		Checker       func(*pluginmock.MockActivationChecker)
+		ExpectedState string
+		DesiredState  pluginState
+		ActualState   pluginState
+	}{
+		{
+			Name: "When the lock does not exist, it boots normally",
+			Locker: func(l *lockermock.MockLocker) {
+				l.EXPECT().IsMaster(gomock.Any()).Return(false, errors.New("invalid etcd state"))
+			},
+			ExpectedState: BOOTING,
+			DesiredState:  pluginStateUnknown,
+			ActualState:   pluginStateUnknown,
+		}, {
+			Name: "When the lock is owned by another host, it boots normally",
+			Locker: func(l *lockermock.MockLocker) {
+				l.EXPECT().IsMaster(gomock.Any()).Return(false, nil)
+			},
+			ExpectedState: BOOTING,
+			DesiredState:  pluginStateUnknown,
+			ActualState:   pluginStateUnknown,
+		}, {
+			Name: "When the lock is still owned and the plugin is activated, it does not call the plugin again",
+			Locker: func(l *lockermock.MockLocker) {
+				l.EXPECT().IsMaster(gomock.Any()).Return(true, nil)
+			},
+			Checker: func(c *pluginmock.MockActivationChecker) {
+				c.EXPECT().IsActivated(gomock.Any()).Return(true, nil)
+			},
+			ExpectedState: ACTIVATED,
+			DesiredState:  pluginStateActivated,
+			ActualState:   pluginStateActivated,
+		}, {
+			Name: "When the lock is still owned but the plugin is not activated, it activates the plugin",
+			Locker: func(l *lockermock.MockLocker) {
+				l.EXPECT().IsMaster(gomock.Any()).Return(true, nil)
+			},
+			Checker: func(c *pluginmock.MockActivationChecker) {
+				c.EXPECT().IsActivated(gomock.Any()).Return(false, nil)
+			},
+			ExpectedState: ACTIVATED,
+			DesiredState:  pluginStateActivated,
+			ActualState:   pluginStateUnknown,
+		}, {
+			Name: "When the plugin state cannot be checked, it activates the plugin",
+			Locker: func(l *lockermock.MockLocker) {
+				l.EXPECT().IsMaster(gomock.Any()).Return(true, nil)
+			},
+			Checker: func(c *pluginmock.MockActivationChecker) {
+				c.EXPECT().IsActivated(gomock.Any()).Return(false, errors.New("API error"))
+			},
+			ExpectedState: ACTIVATED,
+			DesiredState:  pluginStateActivated,
+			ActualState:   pluginStateUnknown,
+		},
+	}
+
+	for _, example := range examples {
+		t.Run(example.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			lockerMock := lockermock.NewMockLocker(ctrl)
+			example.Locker(lockerMock)
+
+			p := activationCheckerPlugin{
+				MockPlugin:            pluginmock.NewMockPlugin(ctrl),
+				MockActivationChecker: pluginmock.NewMockActivationChecker(ctrl),
+			}
+			if example.Checker != nil {
+				example.Checker(p.MockActivationChecker)
+			}
+
+			manager := &EndpointManager{
+				locker:       lockerMock,
+				plugin:       p,
+				stateMachine: NewStateMachine(context.Background(), NewStateMachineOpts{}),
+				pluginWorker: newPluginWorker(config.Config{}, p),
+			}
+
+			manager.resumePreviousState(context.Background())
+
+			assert.Equal(t, example.ExpectedState, manager.Status())
+			assert.Equal(t, example.DesiredState, manager.pluginWorker.desiredState())
+			assert.Equal(t, example.ActualState, manager.pluginWorker.actualState())
+		})
+	}
+}
+
+type activationCheckerPlugin struct {
+	*pluginmock.MockPlugin
+	*pluginmock.MockActivationChecker
+}
+
+var _ plugin.ActivationChecker = activationCheckerPlugin{}
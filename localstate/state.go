@@ -0,0 +1,108 @@
+// Package localstate persists a snapshot of the host configuration on the local disk. This snapshot
+// is used to start the endpoints of the host if the etcd cluster is not reachable when LinK boots.
+package localstate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	stderrors "github.com/pkg/errors"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/models"
+)
+
+// ErrStateNotFound is returned by Load if no snapshot has been saved yet
+var ErrStateNotFound = stderrors.New("local state not found")
+
+// State is the snapshot of the host configuration saved on disk
+type State struct {
+	Host      models.Host      `json:"host"`
+	Endpoints models.Endpoints `json:"endpoints"`
+	// EncryptedData is only saved if the policy allows it. The data is kept encrypted with the
+	// encryption key of the encrypted storage.
+	EncryptedData []models.EncryptedData `json:"encrypted_data,omitempty"`
+	SavedAt       time.Time              `json:"saved_at"`
+}
+
+// Store reads and writes the local state
+type Store interface {
+	Load(ctx context.Context) (State, error)
+	Save(ctx context.Context, state State) error
+}
+
+type fileStore struct {
+	path string
+}
+
+// NewFileStore returns a Store saving the local state in the file at the given path
+func NewFileStore(path string) Store {
+	return fileStore{path: path}
+}
+
+func (s fileStore) Load(ctx context.Context) (State, error) {
+	var state State
+
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, ErrStateNotFound
+	}
+	if err != nil {
+		return state, errors.Wrap(ctx, err, "read local state file")
+	}
+
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		return state, errors.Wrap(ctx, err, "decode local state")
+	}
+
+	return state, nil
+}
+
+// Save writes the state in a temporary file which is then renamed, so that a crash during the
+// write never leaves a truncated snapshot.
+func (s fileStore) Save(ctx context.Context, state State) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrap(ctx, err, "encode local state")
+	}
+
+	dir := filepath.Dir(s.path)
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create local state directory")
+	}
+
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(ctx, err, "create temporary local state file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(content)
+	if err != nil {
+		tmpFile.Close()
+		return errors.Wrap(ctx, err, "write temporary local state file")
+	}
+
+	err = tmpFile.Sync()
+	if err != nil {
+		tmpFile.Close()
+		return errors.Wrap(ctx, err, "sync temporary local state file")
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return errors.Wrap(ctx, err, "close temporary local state file")
+	}
+
+	err = os.Rename(tmpFile.Name(), s.path)
+	if err != nil {
+		return errors.Wrap(ctx, err, "rename temporary local state file")
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package localstate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("it returns ErrStateNotFound if no state has been saved", func(t *testing.T) {
+		store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+
+		_, err := store.Load(context.Background())
+		require.ErrorIs(t, err, ErrStateNotFound)
+	})
+
+	t.Run("it loads the saved state", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "link", "state.json")
+		store := NewFileStore(path)
+		state := State{
+			Host:          models.Host{Hostname: "host-1", LeaseID: 42},
+			Endpoints:     models.Endpoints{{ID: "vip-1", Plugin: "arp"}},
+			EncryptedData: []models.EncryptedData{{ID: "data-1", EndpointID: "vip-1", Data: "encrypted"}},
+			SavedAt:       time.Now().Round(0).Truncate(time.Second),
+		}
+
+		err := store.Save(context.Background(), state)
+		require.NoError(t, err)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		loaded, err := store.Load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, state.Host, loaded.Host)
+		assert.Equal(t, state.Endpoints, loaded.Endpoints)
+		assert.Equal(t, state.EncryptedData, loaded.EncryptedData)
+		assert.True(t, state.SavedAt.Equal(loaded.SavedAt))
+	})
+}
@@ -0,0 +1,51 @@
+package localstate
+
+import (
+	"context"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+)
+
+// fallbackStorage is a models.Storage which reads the host configuration and the encrypted data
+// from the local state if the underlying storage fails. It lets LinK start the endpoints and their
+// plugins while etcd is not reachable. All the other methods are delegated to the underlying storage.
+type fallbackStorage struct {
+	models.Storage
+	state State
+}
+
+// NewFallbackStorage wraps the storage with a fallback on the given local state
+func NewFallbackStorage(storage models.Storage, state State) models.Storage {
+	return fallbackStorage{
+		Storage: storage,
+		state:   state,
+	}
+}
+
+func (s fallbackStorage) GetCurrentHost(ctx context.Context) (models.Host, error) {
+	host, err := s.Storage.GetCurrentHost(ctx)
+	if err == nil || errors.Is(err, models.ErrHostNotFound) || s.state.Host.Hostname == "" {
+		return host, err
+	}
+
+	logger.Get(ctx).WithError(err).Warn("Fail to get the host configuration, using the local state")
+	return s.state.Host, nil
+}
+
+func (s fallbackStorage) GetEncryptedData(ctx context.Context, endpointID string, encryptedDataID string) (models.EncryptedData, error) {
+	data, err := s.Storage.GetEncryptedData(ctx, endpointID, encryptedDataID)
+	if err == nil || errors.Is(err, models.ErrEncryptedDataNotFound) {
+		return data, err
+	}
+
+	for _, data := range s.state.EncryptedData {
+		if data.EndpointID == endpointID && data.ID == encryptedDataID {
+			logger.Get(ctx).WithError(err).Warn("Fail to get the encrypted data, using the local state")
+			return data, nil
+		}
+	}
+
+	return data, err
+}
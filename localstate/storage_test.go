@@ -0,0 +1,71 @@
+package localstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFallbackStorage_GetCurrentHost(t *testing.T) {
+	state := State{Host: models.Host{Hostname: "host-1", LeaseID: 42}}
+
+	t.Run("it returns the host from the storage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		storageMock := models.NewMockStorage(ctrl)
+		storageMock.EXPECT().GetCurrentHost(gomock.Any()).Return(models.Host{Hostname: "host-1", LeaseID: 43}, nil)
+
+		host, err := NewFallbackStorage(storageMock, state).GetCurrentHost(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(43), host.LeaseID)
+	})
+
+	t.Run("it returns the host from the local state if the storage fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		storageMock := models.NewMockStorage(ctrl)
+		storageMock.EXPECT().GetCurrentHost(gomock.Any()).Return(models.Host{}, errors.New("etcd unreachable"))
+
+		host, err := NewFallbackStorage(storageMock, state).GetCurrentHost(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, state.Host, host)
+	})
+
+	t.Run("it does not use the local state if the host does not exist", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		storageMock := models.NewMockStorage(ctrl)
+		storageMock.EXPECT().GetCurrentHost(gomock.Any()).Return(models.Host{}, models.ErrHostNotFound)
+
+		_, err := NewFallbackStorage(storageMock, state).GetCurrentHost(context.Background())
+		require.ErrorIs(t, err, models.ErrHostNotFound)
+	})
+}
+
+func TestFallbackStorage_GetEncryptedData(t *testing.T) {
+	state := State{
+		EncryptedData: []models.EncryptedData{{ID: "data-1", EndpointID: "vip-1", Data: "encrypted"}},
+	}
+
+	t.Run("it returns the encrypted data from the local state if the storage fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		storageMock := models.NewMockStorage(ctrl)
+		storageMock.EXPECT().GetEncryptedData(gomock.Any(), "vip-1", "data-1").Return(models.EncryptedData{}, errors.New("etcd unreachable"))
+
+		data, err := NewFallbackStorage(storageMock, state).GetEncryptedData(context.Background(), "vip-1", "data-1")
+		require.NoError(t, err)
+		assert.Equal(t, "encrypted", data.Data)
+	})
+
+	t.Run("it returns the storage error if the data is not in the local state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		storageMock := models.NewMockStorage(ctrl)
+		storageMock.EXPECT().GetEncryptedData(gomock.Any(), "vip-2", "data-2").Return(models.EncryptedData{}, errors.New("etcd unreachable"))
+
+		_, err := NewFallbackStorage(storageMock, state).GetEncryptedData(context.Background(), "vip-2", "data-2")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package localstate
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/config"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/scheduler"
+)
+
+// Syncer keeps the local state up to date with the storage
+type Syncer struct {
+	config    config.Config
+	storage   models.Storage
+	store     Store
+	scheduler scheduler.Scheduler
+}
+
+func NewSyncer(config config.Config, storage models.Storage, store Store, scheduler scheduler.Scheduler) Syncer {
+	return Syncer{
+		config:    config,
+		storage:   storage,
+		store:     store,
+		scheduler: scheduler,
+	}
+}
+
+// RunOpts are the options of the Syncer Run method
+type RunOpts struct {
+	// Reconcile the endpoints started by the scheduler with the storage before saving the first
+	// snapshot. It must be set if LinK booted from the local state.
+	Reconcile bool
+}
+
+// Run saves a snapshot of the host configuration at every LocalStateSyncInterval, until the
+// context is canceled.
+func (s Syncer) Run(ctx context.Context, opts RunOpts) {
+	log := logger.Get(ctx).WithField("process", "local_state_syncer")
+	ctx = logger.ToCtx(ctx, log)
+
+	ticker := time.NewTicker(s.config.LocalStateSyncInterval)
+	defer ticker.Stop()
+
+	reconciled := !opts.Reconcile
+	for {
+		if !reconciled {
+			err := s.Reconcile(ctx)
+			if err != nil {
+				log.WithError(err).Warn("Fail to reconcile the endpoints with the storage, it will be retried")
+			} else {
+				reconciled = true
+			}
+		}
+
+		if reconciled {
+			err := s.Snapshot(ctx)
+			if err != nil {
+				log.WithError(err).Warn("Fail to save the local state")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Snapshot fetches the host configuration from the storage and saves it in the local state
+func (s Syncer) Snapshot(ctx context.Context) error {
+	host, err := s.storage.GetCurrentHost(ctx)
+	if err != nil && !errors.Is(err, models.ErrHostNotFound) {
+		return errors.Wrap(ctx, err, "get current host")
+	}
+
+	endpoints, err := s.storage.GetEndpoints(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get endpoints")
+	}
+
+	state := State{
+		Host:      host,
+		Endpoints: endpoints,
+		SavedAt:   time.Now(),
+	}
+
+	if s.config.LocalStateIncludeSecrets {
+		state.EncryptedData, err = s.storage.ListEncryptedDataForHost(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "list encrypted data")
+		}
+	}
+
+	err = s.store.Save(ctx, state)
+	if err != nil {
+		return errors.Wrap(ctx, err, "save local state")
+	}
+	return nil
+}
+
+// Reconcile compares the endpoints started from the local state with the endpoints in the storage.
+// Endpoints missing from the scheduler are started, endpoints removed from the storage are
+// stopped, and endpoints which changed in the meantime are restarted.
+func (s Syncer) Reconcile(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	endpoints, err := s.storage.GetEndpoints(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get endpoints")
+	}
+	log.Info("Storage is reachable, reconciling the endpoints started from the local state")
+
+	storedEndpoints := make(map[string]models.Endpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		storedEndpoints[endpoint.ID] = endpoint
+	}
+
+	var failures int
+	for _, running := range s.scheduler.ConfiguredEndpoints(ctx) {
+		ctx, log := logger.WithStructToCtx(ctx, "endpoint", running.Endpoint)
+
+		stored, ok := storedEndpoints[running.ID]
+		if ok && sameEndpoint(running.Endpoint, stored) {
+			delete(storedEndpoints, running.ID)
+			continue
+		}
+
+		if ok {
+			log.Info("Endpoint changed while the storage was not reachable, restarting it")
+		} else {
+			log.Info("Endpoint removed while the storage was not reachable, stopping it")
+		}
+		err := s.scheduler.Stop(ctx, running.ID)
+		if err != nil {
+			log.WithError(err).Error("Fail to stop the endpoint")
+			failures++
+		}
+	}
+
+	for _, endpoint := range storedEndpoints {
+		ctx, log := logger.WithStructToCtx(ctx, "endpoint", endpoint)
+		log.Info("Starting an endpoint scheduler")
+		// The endpoint manager outlives the reconciliation and must not be canceled on shutdown, only the context values are kept
+		_, err := s.scheduler.Start(context.WithoutCancel(ctx), endpoint)
+		if err != nil {
+			log.WithError(err).Error("Fail to start the endpoint")
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return errors.Newf(ctx, "fail to reconcile %d endpoints", failures)
+	}
+	return nil
+}
+
+func sameEndpoint(a, b models.Endpoint) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,93 @@
+package localstate
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/config"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/scheduler"
+	"github.com/Scalingo/link/v3/scheduler/schedulermock"
+)
+
+func TestSyncer_Snapshot(t *testing.T) {
+	examples := []struct {
+		Name                  string
+		IncludeSecrets        bool
+		ExpectedEncryptedData []models.EncryptedData
+	}{
+		{
+			Name: "it does not save the encrypted data by default",
+		}, {
+			Name:                  "it saves the encrypted data if the policy allows it",
+			IncludeSecrets:        true,
+			ExpectedEncryptedData: []models.EncryptedData{{ID: "data-1", EndpointID: "vip-1"}},
+		},
+	}
+
+	for _, example := range examples {
+		t.Run(example.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			storageMock := models.NewMockStorage(ctrl)
+			storageMock.EXPECT().GetCurrentHost(gomock.Any()).Return(models.Host{Hostname: "host-1", LeaseID: 42}, nil)
+			storageMock.EXPECT().GetEndpoints(gomock.Any()).Return(models.Endpoints{{ID: "vip-1"}}, nil)
+			if example.IncludeSecrets {
+				storageMock.EXPECT().ListEncryptedDataForHost(gomock.Any()).Return([]models.EncryptedData{{ID: "data-1", EndpointID: "vip-1"}}, nil)
+			}
+			store := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+			syncer := NewSyncer(config.Config{LocalStateIncludeSecrets: example.IncludeSecrets}, storageMock, store, nil)
+
+			err := syncer.Snapshot(context.Background())
+			require.NoError(t, err)
+
+			state, err := store.Load(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), state.Host.LeaseID)
+			assert.Equal(t, models.Endpoints{{ID: "vip-1"}}, state.Endpoints)
+			assert.Equal(t, example.ExpectedEncryptedData, state.EncryptedData)
+		})
+	}
+}
+
+func TestSyncer_Reconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storageMock := models.NewMockStorage(ctrl)
+	schedulerMock := schedulermock.NewMockScheduler(ctrl)
+
+	unchanged := models.Endpoint{ID: "vip-unchanged", Plugin: "arp"}
+	changedBefore := models.Endpoint{ID: "vip-changed", Plugin: "arp", HealthCheckInterval: 5}
+	changedAfter := models.Endpoint{ID: "vip-changed", Plugin: "arp", HealthCheckInterval: 10}
+	removed := models.Endpoint{ID: "vip-removed", Plugin: "arp"}
+	added := models.Endpoint{ID: "vip-added", Plugin: "arp"}
+
+	storageMock.EXPECT().GetEndpoints(gomock.Any()).Return(models.Endpoints{unchanged, changedAfter, added}, nil)
+	schedulerMock.EXPECT().ConfiguredEndpoints(gomock.Any()).Return(scheduler.EndpointsWithStatus{
+		{Endpoint: unchanged}, {Endpoint: changedBefore}, {Endpoint: removed},
+	})
+	schedulerMock.EXPECT().Stop(gomock.Any(), "vip-changed").Return(nil)
+	schedulerMock.EXPECT().Stop(gomock.Any(), "vip-removed").Return(nil)
+	var schedulerCtxs []context.Context
+	startScheduler := func(ctx context.Context, endpoint models.Endpoint) (models.Endpoint, error) {
+		schedulerCtxs = append(schedulerCtxs, ctx)
+		return endpoint, nil
+	}
+	schedulerMock.EXPECT().Start(gomock.Any(), changedAfter).DoAndReturn(startScheduler)
+	schedulerMock.EXPECT().Start(gomock.Any(), added).DoAndReturn(startScheduler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	syncer := NewSyncer(config.Config{}, storageMock, nil, schedulerMock)
+	err := syncer.Reconcile(ctx)
+	require.NoError(t, err)
+
+	// Canceling the reconciliation context must not cancel the started endpoint managers
+	cancel()
+	require.Len(t, schedulerCtxs, 2)
+	for _, schedulerCtx := range schedulerCtxs {
+		assert.NoError(t, schedulerCtx.Err())
+	}
+}
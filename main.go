@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -16,14 +19,30 @@ import (
 	"github.com/Scalingo/go-utils/logger/plugins/rollbarplugin"
 	"github.com/Scalingo/link/v3/config"
 	"github.com/Scalingo/link/v3/endpoint"
+	"github.com/Scalingo/link/v3/localstate"
 	"github.com/Scalingo/link/v3/locker"
 	"github.com/Scalingo/link/v3/migrations"
 	"github.com/Scalingo/link/v3/models"
 	"github.com/Scalingo/link/v3/plugin"
 	"github.com/Scalingo/link/v3/plugin/arp"
+	awseip "github.com/Scalingo/link/v3/plugin/aws_eip"
+	"github.com/Scalingo/link/v3/plugin/bgp"
+	dnsapi "github.com/Scalingo/link/v3/plugin/dns_api"
+	dnsrfc2136 "github.com/Scalingo/link/v3/plugin/dns_rfc2136"
+	hetznerfloatingip "github.com/Scalingo/link/v3/plugin/hetzner_floating_ip"
+	"github.com/Scalingo/link/v3/plugin/ipvs"
+	"github.com/Scalingo/link/v3/plugin/nftables"
+	openstackneutron "github.com/Scalingo/link/v3/plugin/openstack_neutron"
+	outscaleloadbalancer "github.com/Scalingo/link/v3/plugin/outscale_load_balancer"
+	outscaleprivateip "github.com/Scalingo/link/v3/plugin/outscale_private_ip"
 	outscalepublicip "github.com/Scalingo/link/v3/plugin/outscale_public_ip"
+	outscaleroutetable "github.com/Scalingo/link/v3/plugin/outscale_route_table"
+	"github.com/Scalingo/link/v3/plugin/route"
+	scalewayflexibleip "github.com/Scalingo/link/v3/plugin/scaleway_flexible_ip"
+	"github.com/Scalingo/link/v3/plugin/vrrp"
 	"github.com/Scalingo/link/v3/plugin/webhook"
 	"github.com/Scalingo/link/v3/scheduler"
+	"github.com/Scalingo/link/v3/services/outscale"
 	"github.com/Scalingo/link/v3/web"
 )
 
@@ -35,6 +54,10 @@ func main() {
 	log := logger.Default()
 	ctx := logger.ToCtx(context.Background(), log)
 
+	// The signals are trapped as soon as possible so that the endpoints started below are always stopped gracefully
+	signalCtx, stopSignals := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stopSignals()
+
 	config, err := config.Build(ctx)
 	if err != nil {
 		log.WithError(err).Error("Fail to init config")
@@ -49,7 +72,19 @@ func main() {
 		panic(err)
 	}
 
-	storage := models.NewEtcdStorage(config)
+	var storage models.Storage = models.NewEtcdStorage(config)
+	var localState localstate.State
+	localStateStore := localstate.NewFileStore(config.LocalStatePath)
+	if config.LocalStatePath != "" {
+		localState, err = localStateStore.Load(ctx)
+		if err != nil && !errors.Is(err, localstate.ErrStateNotFound) {
+			log.WithError(err).Error("Fail to load the local state")
+			panic(err)
+		}
+		// The host configuration and the encrypted data are read from the local state if etcd is not reachable
+		storage = localstate.NewFallbackStorage(storage, localState)
+	}
+
 	leaseManager := locker.NewEtcdLeaseManager(ctx, config, storage, etcd)
 	encryptedStorage, err := models.NewEncryptedStorage(ctx, config, storage)
 	if err != nil {
@@ -58,7 +93,7 @@ func main() {
 	}
 
 	pluginRegistry := plugin.NewRegistry()
-	err = initPlugins(ctx, pluginRegistry, encryptedStorage)
+	err = initPlugins(ctx, config, pluginRegistry, encryptedStorage)
 	if err != nil {
 		log.WithError(err).Error("Fail to init plugins")
 		panic(err)
@@ -89,10 +124,17 @@ func main() {
 
 	scheduler := scheduler.NewEndpointScheduler(config, etcd, storage, leaseManager, pluginRegistry)
 
+	bootedFromLocalState := false
 	endpoints, err := storage.GetEndpoints(ctx)
 	if err != nil {
-		log.WithError(err).Error("Fail to list configured endpoints")
-		panic(err)
+		if config.LocalStatePath == "" || localState.SavedAt.IsZero() {
+			log.WithError(err).Error("Fail to list configured endpoints")
+			panic(err)
+		}
+		// The endpoints will be reconciled with etcd once it is reachable
+		log.WithError(err).WithField("local_state_saved_at", localState.SavedAt).Warn("Fail to list configured endpoints, booting from the local state")
+		endpoints = localState.Endpoints
+		bootedFromLocalState = true
 	}
 
 	if len(endpoints) > 0 {
@@ -100,13 +142,26 @@ func main() {
 		for _, endpoint := range endpoints {
 			ctx, log := logger.WithStructToCtx(ctx, "endpoint", endpoint)
 			log.Info("Starting an endpoint scheduler")
-			_, err := scheduler.Start(ctx, endpoint)
+			_, err := scheduler.Restore(ctx, endpoint)
 			if err != nil {
 				panic(err)
 			}
 		}
 	}
 
+	// Remove what a previous run of LinK left on the host, for the endpoints not owned anymore. This
+	// is done before the managers start, so that the cleanup never races with an activation.
+	err = pluginRegistry.ReconcileAtStartup(ctx, endpoints, scheduler.IsOwned)
+	if err != nil {
+		log.WithError(err).Error("Fail to clean up the leftovers of the previous run")
+	}
+	scheduler.StartRestored()
+
+	if config.LocalStatePath != "" {
+		syncer := localstate.NewSyncer(config, storage, localStateStore, scheduler)
+		go syncer.Run(signalCtx, localstate.RunOpts{Reconcile: bootedFromLocalState})
+	}
+
 	endpointCreator := endpoint.NewCreator(config, storage, scheduler, pluginRegistry)
 	ipController := web.NewIPController(scheduler, storage, endpointCreator)
 	endpointController := web.NewEndpointController(scheduler, storage, endpointCreator, encryptedStorage)
@@ -163,28 +218,162 @@ func main() {
 	}
 	globalRouter.Handle("/{any:.+}", r)
 
-	log.Infof("Listening on %v", config.Port)
-	err = http.ListenAndServe(fmt.Sprintf(":%v", config.Port), globalRouter)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%v", config.Port),
+		Handler:           globalRouter,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Infof("Listening on %v", config.Port)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	<-signalCtx.Done()
+	stopSignals()
+	log.Info("Termination signal received, shutting down LinK")
+
+	// The shutdown is bounded by a deadline. If the endpoints did not stop in time, we exit anyway
+	// and let the other hosts take the endpoints once our lease expired.
+	shutdownCtx, cancel := context.WithTimeout(ctx, config.ShutdownTimeout)
+	defer cancel()
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		shutdown(shutdownCtx, config, server, scheduler, leaseManager)
+		close(shutdownDone)
+	}()
+
+	select {
+	case <-shutdownDone:
+		log.Info("LinK stopped")
+	case <-shutdownCtx.Done():
+		log.WithField("shutdown_timeout", config.ShutdownTimeout).Error("Shutdown deadline exceeded, exiting")
+		os.Exit(1)
+	}
+}
+
+func shutdown(ctx context.Context, config config.Config, server *http.Server, endpointScheduler *scheduler.EndpointScheduler, leaseManager locker.EtcdLeaseManager) {
+	log := logger.Get(ctx)
+
+	log.Info("Stopping the HTTP server")
+	err := server.Shutdown(ctx)
 	if err != nil {
-		panic(err)
+		log.WithError(err).Error("Fail to stop the HTTP server")
+	}
+
+	if config.ShutdownKeepPluginsActive {
+		log.Info("Stopping the endpoint schedulers, the plugins are left active and the endpoints are not handed over")
+	} else {
+		log.Info("Stopping the endpoint schedulers and handing over the endpoints")
+	}
+	err = endpointScheduler.Shutdown(ctx, scheduler.ShutdownOpts{
+		KeepPluginsActive: config.ShutdownKeepPluginsActive,
+	})
+	if err != nil {
+		log.WithError(err).Error("Fail to stop the endpoint schedulers")
 	}
+
+	log.Info("Stopping the lease manager")
+	leaseManager.Stop(ctx)
 }
 
-func initPlugins(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+func initPlugins(ctx context.Context, cfg config.Config, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
 	err := arp.Register(ctx, registry)
 	if err != nil {
 		return errors.Wrap(ctx, err, "register arp plugin")
 	}
 
-	err = outscalepublicip.Register(ctx, registry, encryptedStorage)
+	// The Outscale plugins share the API clients of the endpoints using the same credentials
+	oscClients := outscale.NewClients(outscale.ClientConfig{
+		RateLimit:   cfg.OutscaleAPIRateLimit,
+		RateBurst:   cfg.OutscaleAPIRateBurst,
+		MaxAttempts: cfg.OutscaleAPIMaxAttempts,
+		RetryWait:   cfg.OutscaleAPIRetryWait,
+	})
+
+	err = outscalepublicip.Register(ctx, registry, encryptedStorage, oscClients)
 	if err != nil {
 		return errors.Wrap(ctx, err, "register outscale public ip plugin")
 	}
 
+	err = outscaleprivateip.Register(ctx, registry, encryptedStorage, oscClients)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register outscale private ip plugin")
+	}
+
+	err = outscaleroutetable.Register(ctx, registry, encryptedStorage, oscClients)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register outscale route table plugin")
+	}
+
+	err = outscaleloadbalancer.Register(ctx, registry, encryptedStorage, oscClients)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register outscale load balancer plugin")
+	}
+
+	err = awseip.Register(ctx, registry, encryptedStorage)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register aws eip plugin")
+	}
+
+	err = hetznerfloatingip.Register(ctx, registry, encryptedStorage)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register hetzner floating ip plugin")
+	}
+
+	err = scalewayflexibleip.Register(ctx, registry, encryptedStorage)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register scaleway flexible ip plugin")
+	}
+
+	err = openstackneutron.Register(ctx, registry, encryptedStorage)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register openstack neutron plugin")
+	}
+
+	err = dnsrfc2136.Register(ctx, registry, encryptedStorage)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register dns rfc2136 plugin")
+	}
+
+	err = dnsapi.Register(ctx, registry, encryptedStorage)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register dns api plugin")
+	}
+
 	err = webhook.Register(ctx, registry, encryptedStorage)
 	if err != nil {
 		return errors.Wrap(ctx, err, "register webhook plugin")
 	}
 
+	err = route.Register(ctx, registry)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register route plugin")
+	}
+
+	err = bgp.Register(ctx, registry)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register bgp plugin")
+	}
+
+	err = vrrp.Register(ctx, registry)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register vrrp plugin")
+	}
+
+	err = ipvs.Register(ctx, registry)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register ipvs plugin")
+	}
+
+	err = nftables.Register(ctx, registry)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register nftables plugin")
+	}
+
 	return nil
 }
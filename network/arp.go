@@ -2,27 +2,25 @@ package network
 
 import (
 	"net"
-	"sync"
 
-	"github.com/j-keck/arping"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
 )
 
-/*
- * The library used to make arp request is not thread safe. See issue https://github.com/Scalingo/link/issues/15
- * Here the idea is to have a singleton that ensure only one goroutine will use this library to make ARP requests.
- *
- * With this library if you want to call:
- * err = arping.GratuitousArpOverIfaceByName(addr.IP, i.cardName)
- *
- * Just do:
- * 	err = network.GetARP().GratuitousArpRequest(GratuitousArpRequest{
- * 		IP:        addr.IP,
- * 		Interface: i.cardName,
- * 	})
- */
+// ARPMode is the kind of gratuitous ARP packets sent to announce an IP. Some switches and routers
+// only update their cache with gratuitous ARP replies.
+type ARPMode string
 
-var arpInstance *arp
-var arpOnce sync.Once
+const (
+	ARPModeRequest ARPMode = "request"
+	ARPModeReply   ARPMode = "reply"
+	ARPModeBoth    ARPMode = "both"
+)
+
+// IsValid returns true if the mode is a known mode
+func (m ARPMode) IsValid() bool {
+	return m == ARPModeRequest || m == ARPModeReply || m == ARPModeBoth
+}
 
 type ARP interface {
 	GratuitousArp(req GratuitousArpRequest) error
@@ -31,45 +29,71 @@ type ARP interface {
 type GratuitousArpRequest struct {
 	IP        net.IP
 	Interface *net.Interface
+	// Mode defaults to ARPModeRequest
+	Mode ARPMode
 }
 
-type gratuitousArpRequest struct {
-	request  GratuitousArpRequest
-	response chan error
-}
+type arpSender struct{}
 
-type arp struct {
-	requests chan gratuitousArpRequest
+// NewARP returns an ARP sender. Each request uses its own packet socket, so the sender can be used
+// concurrently by many endpoints.
+func NewARP() ARP {
+	return arpSender{}
 }
 
-func GetArp() ARP {
-	arpOnce.Do(func() {
-		arpInstance = &arp{
-			requests: make(chan gratuitousArpRequest),
-		}
-		go arpInstance.start()
-	})
+func (arpSender) GratuitousArp(req GratuitousArpRequest) error {
+	ip := req.IP.To4()
+	if ip == nil {
+		return errors.Errorf("not an IPv4 address: %s", req.IP)
+	}
 
-	return arpInstance
-}
+	var operations []uint16
+	switch req.Mode {
+	case ARPModeRequest, "":
+		operations = []uint16{arpOperationRequest}
+	case ARPModeReply:
+		operations = []uint16{arpOperationReply}
+	case ARPModeBoth:
+		operations = []uint16{arpOperationRequest, arpOperationReply}
+	default:
+		return errors.Errorf("invalid gratuitous ARP mode: %s", req.Mode)
+	}
 
-func (a *arp) GratuitousArp(request GratuitousArpRequest) error {
-	errChan := make(chan error)
-	a.requests <- gratuitousArpRequest{
-		request:  request,
-		response: errChan,
+	fd, err := openARPSocket(req.Interface)
+	if err != nil {
+		return err
 	}
+	defer unix.Close(fd)
 
-	return <-errChan
-}
+	dst := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  req.Interface.Index,
+		Halen:    6,
+	}
+	copy(dst.Addr[:], ethernetBroadcast)
 
-func (a *arp) Stop() {
-	close(a.requests)
+	for _, operation := range operations {
+		err := unix.Sendto(fd, gratuitousARP(operation, ip, req.Interface.HardwareAddr).marshal(), 0, dst)
+		if err != nil {
+			return errors.Wrap(err, "fail to send gratuitous ARP")
+		}
+	}
+	return nil
 }
 
-func (a *arp) start() {
-	for req := range a.requests {
-		err := arping.GratuitousArpOverIface(req.request.IP, *req.request.Interface)
-		req.response <- err
+// gratuitousARP builds a gratuitous ARP packet: both the sender and the target IPs are the
+// announced IP. The target hardware address is left empty for requests and set to our own
+// address for replies, as done by arping.
+func gratuitousARP(operation uint16, ip net.IP, hardwareAddr net.HardwareAddr) arpPacket {
+	packet := arpPacket{
+		Operation:          operation,
+		SenderHardwareAddr: hardwareAddr,
+		SenderIP:           ip,
+		TargetHardwareAddr: make(net.HardwareAddr, 6),
+		TargetIP:           ip,
+	}
+	if operation == arpOperationReply {
+		packet.TargetHardwareAddr = hardwareAddr
 	}
+	return packet
 }
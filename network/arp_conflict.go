@@ -0,0 +1,263 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	arpPacketLen        = 28
+	arpOperationRequest = 1
+	arpOperationReply   = 2
+	// arpReadTimeout is the maximum duration of a blocking read on the ARP socket. It defines how
+	// often the context is checked while waiting for packets.
+	arpReadTimeout = 200 * time.Millisecond
+)
+
+var ethernetBroadcast = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// ARPProbeOpts configures the ARP probes sent before using an IPv4 address (RFC 5227)
+type ARPProbeOpts struct {
+	// Count is the number of probes sent
+	Count int
+	// Interval is the duration between two probes
+	Interval time.Duration
+	// Wait is the duration to wait for a conflicting packet after the last probe
+	Wait time.Duration
+}
+
+type arpPacket struct {
+	Operation          uint16
+	SenderHardwareAddr net.HardwareAddr
+	SenderIP           net.IP
+	TargetHardwareAddr net.HardwareAddr
+	TargetIP           net.IP
+}
+
+func (p arpPacket) marshal() []byte {
+	b := make([]byte, arpPacketLen)
+	binary.BigEndian.PutUint16(b[0:2], 1)      // Hardware type: Ethernet
+	binary.BigEndian.PutUint16(b[2:4], 0x0800) // Protocol type: IPv4
+	b[4] = 6                                   // Hardware address length
+	b[5] = 4                                   // Protocol address length
+	binary.BigEndian.PutUint16(b[6:8], p.Operation)
+	copy(b[8:14], p.SenderHardwareAddr)
+	copy(b[14:18], p.SenderIP.To4())
+	copy(b[18:24], p.TargetHardwareAddr)
+	copy(b[24:28], p.TargetIP.To4())
+	return b
+}
+
+func parseARPPacket(b []byte) (arpPacket, error) {
+	if len(b) < arpPacketLen {
+		return arpPacket{}, errors.New("ARP packet too short")
+	}
+	if binary.BigEndian.Uint16(b[0:2]) != 1 || binary.BigEndian.Uint16(b[2:4]) != 0x0800 || b[4] != 6 || b[5] != 4 {
+		return arpPacket{}, errors.New("not an Ethernet/IPv4 ARP packet")
+	}
+
+	return arpPacket{
+		Operation:          binary.BigEndian.Uint16(b[6:8]),
+		SenderHardwareAddr: net.HardwareAddr(bytes.Clone(b[8:14])),
+		SenderIP:           net.IP(bytes.Clone(b[14:18])),
+		TargetHardwareAddr: net.HardwareAddr(bytes.Clone(b[18:24])),
+		TargetIP:           net.IP(bytes.Clone(b[24:28])),
+	}, nil
+}
+
+// isProbeConflict returns true if the packet, received while probing ip, shows that another host
+// uses the address: either it claims it as its sender address, or it is probing it at the same
+// time (RFC 5227 section 2.1.1).
+func isProbeConflict(packet arpPacket, ip net.IP, ourHardwareAddr net.HardwareAddr) bool {
+	if bytes.Equal(packet.SenderHardwareAddr, ourHardwareAddr) {
+		return false
+	}
+	if packet.SenderIP.Equal(ip) {
+		return true
+	}
+	return packet.Operation == arpOperationRequest && packet.SenderIP.Equal(net.IPv4zero) && packet.TargetIP.Equal(ip)
+}
+
+// isActiveConflict returns true if the packet, received while ip is in use, has been sent by
+// another host claiming the address
+func isActiveConflict(packet arpPacket, ip net.IP, ourHardwareAddr net.HardwareAddr) bool {
+	return packet.SenderIP.Equal(ip) && !bytes.Equal(packet.SenderHardwareAddr, ourHardwareAddr)
+}
+
+// ProbeARP sends ARP probes for an IPv4 address which is not yet configured on the interface. It
+// returns the hardware address of the host using this IP if a conflict is detected, nil otherwise.
+func (i NetInterface) ProbeARP(ctx context.Context, ip string, opts ARPProbeOpts) (net.HardwareAddr, error) {
+	addr, err := parseIPv4(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err = i.refreshed()
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := i.openARPSocket()
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(fd)
+
+	probe := arpPacket{
+		Operation:          arpOperationRequest,
+		SenderHardwareAddr: i.card.HardwareAddr,
+		SenderIP:           net.IPv4zero,
+		TargetHardwareAddr: make(net.HardwareAddr, 6),
+		TargetIP:           addr,
+	}.marshal()
+	dst := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  i.card.Index,
+		Halen:    6,
+	}
+	copy(dst.Addr[:], ethernetBroadcast)
+
+	for n := 0; n < opts.Count; n++ {
+		err := unix.Sendto(fd, probe, 0, dst)
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to send ARP probe")
+		}
+
+		wait := opts.Interval
+		if n == opts.Count-1 {
+			wait = opts.Wait
+		}
+		mac, err := i.readARPUntil(ctx, fd, time.Now().Add(wait), func(packet arpPacket) bool {
+			return isProbeConflict(packet, addr, i.card.HardwareAddr)
+		})
+		if err != nil || mac != nil {
+			return mac, err
+		}
+	}
+
+	return nil, nil
+}
+
+// WatchARPConflicts listens for ARP packets sent by other hosts claiming the IPv4 address, until the
+// context is canceled. onConflict is called with the hardware address of the other host for each
+// conflicting packet.
+func (i NetInterface) WatchARPConflicts(ctx context.Context, ip string, onConflict func(net.HardwareAddr)) error {
+	addr, err := parseIPv4(ip)
+	if err != nil {
+		return err
+	}
+
+	i, err = i.refreshed()
+	if err != nil {
+		return err
+	}
+
+	fd, err := i.openARPSocket()
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	for {
+		mac, err := i.readARPUntil(ctx, fd, time.Time{}, func(packet arpPacket) bool {
+			return isActiveConflict(packet, addr, i.card.HardwareAddr)
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		onConflict(mac)
+	}
+}
+
+// readARPUntil reads the ARP packets received on the socket until one of them matches, the
+// deadline is exceeded (a zero deadline never expires) or the context is canceled. It returns the
+// sender hardware address of the matching packet.
+func (i NetInterface) readARPUntil(ctx context.Context, fd int, deadline time.Time, match func(arpPacket) bool) (net.HardwareAddr, error) {
+	buf := make([]byte, 1500)
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, nil
+		}
+
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to read ARP packet")
+		}
+
+		packet, err := parseARPPacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		if match(packet) {
+			return packet.SenderHardwareAddr, nil
+		}
+	}
+}
+
+// openARPSocket opens an ARP socket in the network namespace of the interface
+func (i NetInterface) openARPSocket() (int, error) {
+	fd := -1
+	err := i.ns.run(func() error {
+		var err error
+		fd, err = openARPSocket(i.card)
+		return err
+	})
+	return fd, err
+}
+
+// openARPSocket opens a packet socket receiving and sending ARP packets on the interface. The
+// Ethernet header is handled by the kernel.
+func openARPSocket(card *net.Interface) (int, error) {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, int(htons(unix.ETH_P_ARP)))
+	if err != nil {
+		return -1, errors.Wrap(err, "fail to open ARP socket")
+	}
+
+	err = unix.Bind(fd, &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  card.Index,
+	})
+	if err != nil {
+		unix.Close(fd)
+		return -1, errors.Wrap(err, "fail to bind ARP socket to the interface")
+	}
+
+	timeout := unix.NsecToTimeval(arpReadTimeout.Nanoseconds())
+	err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout)
+	if err != nil {
+		unix.Close(fd)
+		return -1, errors.Wrap(err, "fail to set ARP socket read timeout")
+	}
+
+	return fd, nil
+}
+
+func parseIPv4(ip string) (net.IP, error) {
+	addr, _, err := net.ParseCIDR(ip)
+	if err != nil {
+		addr = net.ParseIP(ip)
+	}
+	if addr == nil || addr.To4() == nil {
+		return nil, errors.Errorf("not an IPv4 address: %s", ip)
+	}
+	return addr.To4(), nil
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
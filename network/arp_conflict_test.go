@@ -0,0 +1,88 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestARPPacket(t *testing.T) {
+	ourMAC, _ := net.ParseMAC("02:00:00:00:00:01")
+	otherMAC, _ := net.ParseMAC("02:00:00:00:00:02")
+	vip := net.ParseIP("10.0.0.10").To4()
+
+	t.Run("it parses a marshaled packet", func(t *testing.T) {
+		packet := arpPacket{
+			Operation:          arpOperationReply,
+			SenderHardwareAddr: otherMAC,
+			SenderIP:           vip,
+			TargetHardwareAddr: ourMAC,
+			TargetIP:           net.ParseIP("10.0.0.1").To4(),
+		}
+
+		parsed, err := parseARPPacket(packet.marshal())
+		require.NoError(t, err)
+		assert.Equal(t, packet, parsed)
+	})
+
+	t.Run("it refuses a truncated packet", func(t *testing.T) {
+		_, err := parseARPPacket(make([]byte, 10))
+		require.Error(t, err)
+	})
+
+	examples := []struct {
+		Name             string
+		Packet           arpPacket
+		IsProbeConflict  bool
+		IsActiveConflict bool
+	}{
+		{
+			Name:             "a reply from another host for the IP",
+			Packet:           arpPacket{Operation: arpOperationReply, SenderHardwareAddr: otherMAC, SenderIP: vip},
+			IsProbeConflict:  true,
+			IsActiveConflict: true,
+		}, {
+			Name:             "a packet sent by this host",
+			Packet:           arpPacket{Operation: arpOperationReply, SenderHardwareAddr: ourMAC, SenderIP: vip},
+			IsProbeConflict:  false,
+			IsActiveConflict: false,
+		}, {
+			Name:             "a probe from another host for the same IP",
+			Packet:           arpPacket{Operation: arpOperationRequest, SenderHardwareAddr: otherMAC, SenderIP: net.IPv4zero, TargetIP: vip},
+			IsProbeConflict:  true,
+			IsActiveConflict: false,
+		}, {
+			Name:             "a request from another host for the IP",
+			Packet:           arpPacket{Operation: arpOperationRequest, SenderHardwareAddr: otherMAC, SenderIP: net.ParseIP("10.0.0.2").To4(), TargetIP: vip},
+			IsProbeConflict:  false,
+			IsActiveConflict: false,
+		},
+	}
+
+	for _, example := range examples {
+		t.Run(example.Name, func(t *testing.T) {
+			assert.Equal(t, example.IsProbeConflict, isProbeConflict(example.Packet, vip, ourMAC))
+			assert.Equal(t, example.IsActiveConflict, isActiveConflict(example.Packet, vip, ourMAC))
+		})
+	}
+}
+
+func TestGratuitousARP(t *testing.T) {
+	mac, _ := net.ParseMAC("02:00:00:00:00:01")
+	vip := net.ParseIP("10.0.0.10").To4()
+
+	t.Run("a request has an empty target hardware address", func(t *testing.T) {
+		packet := gratuitousARP(arpOperationRequest, vip, mac)
+		assert.Equal(t, vip, packet.SenderIP)
+		assert.Equal(t, vip, packet.TargetIP)
+		assert.Equal(t, net.HardwareAddr{0, 0, 0, 0, 0, 0}, packet.TargetHardwareAddr)
+	})
+
+	t.Run("a reply targets our own hardware address", func(t *testing.T) {
+		packet := gratuitousARP(arpOperationReply, vip, mac)
+		assert.Equal(t, uint16(arpOperationReply), packet.Operation)
+		assert.Equal(t, mac, packet.TargetHardwareAddr)
+	})
+}
@@ -0,0 +1,75 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/network/bgp (interfaces: Speaker)
+
+// Package bgpmock is a generated GoMock package.
+package bgpmock
+
+import (
+	netip "net/netip"
+	reflect "reflect"
+
+	bgp "github.com/Scalingo/link/v3/network/bgp"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSpeaker is a mock of Speaker interface.
+type MockSpeaker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpeakerMockRecorder
+	isgomock struct{}
+}
+
+// MockSpeakerMockRecorder is the mock recorder for MockSpeaker.
+type MockSpeakerMockRecorder struct {
+	mock *MockSpeaker
+}
+
+// NewMockSpeaker creates a new mock instance.
+func NewMockSpeaker(ctrl *gomock.Controller) *MockSpeaker {
+	mock := &MockSpeaker{ctrl: ctrl}
+	mock.recorder = &MockSpeakerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpeaker) EXPECT() *MockSpeakerMockRecorder {
+	return m.recorder
+}
+
+// Announce mocks base method.
+func (m *MockSpeaker) Announce(path bgp.Path) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Announce", path)
+}
+
+// Announce indicates an expected call of Announce.
+func (mr *MockSpeakerMockRecorder) Announce(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Announce", reflect.TypeOf((*MockSpeaker)(nil).Announce), path)
+}
+
+// Established mocks base method.
+func (m *MockSpeaker) Established() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Established")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Established indicates an expected call of Established.
+func (mr *MockSpeakerMockRecorder) Established() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Established", reflect.TypeOf((*MockSpeaker)(nil).Established))
+}
+
+// Withdraw mocks base method.
+func (m *MockSpeaker) Withdraw(prefix netip.Prefix) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Withdraw", prefix)
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockSpeakerMockRecorder) Withdraw(prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockSpeaker)(nil).Withdraw), prefix)
+}
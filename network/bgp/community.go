@@ -0,0 +1,39 @@
+package bgp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Well-known communities (RFC 1997, RFC 7999)
+var wellKnownCommunities = map[string]uint32{
+	"no-export":           0xffffff01,
+	"no-advertise":        0xffffff02,
+	"no-export-subconfed": 0xffffff03,
+	"blackhole":           0xffff029a,
+}
+
+// ParseCommunity parses a community written as "<ASN>:<value>" (e.g. "65000:100") or the name of
+// a well-known community (e.g. "no-export")
+func ParseCommunity(s string) (uint32, error) {
+	community, ok := wellKnownCommunities[strings.ToLower(s)]
+	if ok {
+		return community, nil
+	}
+
+	asn, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, errors.Errorf("invalid community %s, expected <ASN>:<value> or a well-known community", s)
+	}
+	high, err := strconv.ParseUint(asn, 10, 16)
+	if err != nil {
+		return 0, errors.Errorf("invalid community %s, the ASN must be between 0 and 65535", s)
+	}
+	low, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return 0, errors.Errorf("invalid community %s, the value must be between 0 and 65535", s)
+	}
+	return uint32(high)<<16 | uint32(low), nil
+}
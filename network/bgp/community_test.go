@@ -0,0 +1,49 @@
+package bgp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommunity(t *testing.T) {
+	tests := map[string]struct {
+		community     string
+		expected      uint32
+		expectedError string
+	}{
+		"with an ASN and a value": {
+			community: "65000:100",
+			expected:  0xfde80064,
+		},
+		"with a well-known community": {
+			community: "NO-EXPORT",
+			expected:  0xffffff01,
+		},
+		"without value": {
+			community:     "65000",
+			expectedError: "expected <ASN>:<value>",
+		},
+		"with a 4-octet ASN": {
+			community:     "4200000000:100",
+			expectedError: "the ASN must be between 0 and 65535",
+		},
+		"with an invalid value": {
+			community:     "65000:abc",
+			expectedError: "the value must be between 0 and 65535",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			community, err := ParseCommunity(test.community)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, community)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+
+	"github.com/pkg/errors"
+)
+
+// Message types (RFC 4271)
+const (
+	msgTypeOpen         = 1
+	msgTypeUpdate       = 2
+	msgTypeNotification = 3
+	msgTypeKeepalive    = 4
+)
+
+const (
+	headerLength     = 19
+	maxMessageLength = 4096
+	bgpVersion       = 4
+	// asTrans is the 2-octet AS number used in the OPEN message by speakers with a 4-octet AS number (RFC 6793)
+	asTrans = 23456
+)
+
+// Path attribute types and flags
+const (
+	attrTypeOrigin        = 1
+	attrTypeASPath        = 2
+	attrTypeNextHop       = 3
+	attrTypeLocalPref     = 5
+	attrTypeCommunities   = 8
+	attrTypeMPReachNLRI   = 14
+	attrTypeMPUnreachNLRI = 15
+	attrTypeAS4Path       = 17
+
+	attrFlagOptional       = 0x80
+	attrFlagTransitive     = 0x40
+	attrFlagExtendedLength = 0x10
+
+	originIGP        = 0
+	asPathSequence   = 2
+	defaultLocalPref = 100
+)
+
+// Capabilities advertised in the OPEN message
+const (
+	optParamCapabilities    = 2
+	capabilityMultiprotocol = 1
+	capabilityFourOctetAS   = 65
+
+	afiIPv4     = 1
+	afiIPv6     = 2
+	safiUnicast = 1
+)
+
+// NOTIFICATION error codes
+const (
+	notificationHoldTimerExpired = 4
+	notificationCease            = 6
+)
+
+// openMessage is the content of an OPEN message used by the session
+type openMessage struct {
+	ASN      uint32
+	HoldTime uint16
+	RouterID netip.Addr
+	// FourOctetAS is true if the speaker supports 4-octet AS numbers
+	FourOctetAS bool
+}
+
+// notification is a NOTIFICATION message, it closes the session
+type notification struct {
+	Code    uint8
+	Subcode uint8
+	Data    []byte
+}
+
+func (n notification) Error() string {
+	return fmt.Sprintf("BGP notification with code %d and subcode %d", n.Code, n.Subcode)
+}
+
+// message prepends the BGP header to the body of a message
+func message(msgType uint8, body []byte) []byte {
+	msg := make([]byte, headerLength, headerLength+len(body))
+	for i := 0; i < 16; i++ {
+		msg[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(msg[16:18], uint16(headerLength+len(body)))
+	msg[18] = msgType
+	return append(msg, body...)
+}
+
+func (o openMessage) marshal() []byte {
+	myAS := uint16(asTrans)
+	if o.ASN <= 0xffff {
+		myAS = uint16(o.ASN)
+	}
+
+	var capabilities []byte
+	for _, afi := range []uint16{afiIPv4, afiIPv6} {
+		capabilities = append(capabilities, capabilityMultiprotocol, 4)
+		capabilities = binary.BigEndian.AppendUint16(capabilities, afi)
+		capabilities = append(capabilities, 0, safiUnicast)
+	}
+	capabilities = append(capabilities, capabilityFourOctetAS, 4)
+	capabilities = binary.BigEndian.AppendUint32(capabilities, o.ASN)
+
+	routerID := o.RouterID.As4()
+	body := []byte{bgpVersion}
+	body = binary.BigEndian.AppendUint16(body, myAS)
+	body = binary.BigEndian.AppendUint16(body, o.HoldTime)
+	body = append(body, routerID[:]...)
+	body = append(body, byte(len(capabilities)+2), optParamCapabilities, byte(len(capabilities)))
+	body = append(body, capabilities...)
+	return message(msgTypeOpen, body)
+}
+
+func parseOpen(body []byte) (openMessage, error) {
+	if len(body) < 10 {
+		return openMessage{}, errors.New("OPEN message too short")
+	}
+	if body[0] != bgpVersion {
+		return openMessage{}, errors.Errorf("unsupported BGP version %d", body[0])
+	}
+
+	open := openMessage{
+		ASN:      uint32(binary.BigEndian.Uint16(body[1:3])),
+		HoldTime: binary.BigEndian.Uint16(body[3:5]),
+		RouterID: netip.AddrFrom4([4]byte(body[5:9])),
+	}
+
+	params := body[10:]
+	if len(params) != int(body[9]) {
+		return openMessage{}, errors.New("invalid OPEN optional parameters length")
+	}
+	for len(params) >= 2 {
+		paramType, paramLength := params[0], int(params[1])
+		if len(params) < 2+paramLength {
+			return openMessage{}, errors.New("invalid OPEN optional parameter")
+		}
+		value := params[2 : 2+paramLength]
+		params = params[2+paramLength:]
+		if paramType != optParamCapabilities {
+			continue
+		}
+
+		for len(value) >= 2 {
+			capCode, capLength := value[0], int(value[1])
+			if len(value) < 2+capLength {
+				return openMessage{}, errors.New("invalid capability")
+			}
+			if capCode == capabilityFourOctetAS && capLength == 4 {
+				open.FourOctetAS = true
+				open.ASN = binary.BigEndian.Uint32(value[2:6])
+			}
+			value = value[2+capLength:]
+		}
+	}
+	return open, nil
+}
+
+func keepaliveMessage() []byte {
+	return message(msgTypeKeepalive, nil)
+}
+
+func (n notification) marshal() []byte {
+	return message(msgTypeNotification, append([]byte{n.Code, n.Subcode}, n.Data...))
+}
+
+func parseNotification(body []byte) (notification, error) {
+	if len(body) < 2 {
+		return notification{}, errors.New("NOTIFICATION message too short")
+	}
+	return notification{Code: body[0], Subcode: body[1], Data: body[2:]}, nil
+}
+
+// readMessage reads a BGP message and returns its type and body
+func readMessage(r io.Reader) (uint8, []byte, error) {
+	header := make([]byte, headerLength)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i := 0; i < 16; i++ {
+		if header[i] != 0xff {
+			return 0, nil, errors.New("invalid BGP message marker")
+		}
+	}
+
+	length := int(binary.BigEndian.Uint16(header[16:18]))
+	if length < headerLength || length > maxMessageLength {
+		return 0, nil, errors.Errorf("invalid BGP message length %d", length)
+	}
+
+	body := make([]byte, length-headerLength)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return header[18], body, nil
+}
+
+// appendPrefix encodes a prefix in the NLRI format: the length in bits followed by the significant octets
+func appendPrefix(b []byte, prefix netip.Prefix) []byte {
+	bits := prefix.Bits()
+	addr := prefix.Addr().AsSlice()
+	return append(append(b, byte(bits)), addr[:(bits+7)/8]...)
+}
+
+// appendAttribute encodes a path attribute, using the extended length if needed
+func appendAttribute(b []byte, flags, attrType uint8, value []byte) []byte {
+	if len(value) > 0xff {
+		b = append(b, flags|attrFlagExtendedLength, attrType)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(value)))
+	} else {
+		b = append(b, flags, attrType, byte(len(value)))
+	}
+	return append(b, value...)
+}
+
+// updateOpts are the parameters of the session used to encode the UPDATE messages
+type updateOpts struct {
+	LocalASN uint32
+	// IBGP is true if the peer is in the same AS
+	IBGP bool
+	// FourOctetAS is true if both speakers support 4-octet AS numbers
+	FourOctetAS bool
+}
+
+// announceMessage encodes an UPDATE message announcing the path
+func announceMessage(path Path, opts updateOpts) []byte {
+	var attrs []byte
+	attrs = appendAttribute(attrs, attrFlagTransitive, attrTypeOrigin, []byte{originIGP})
+
+	var asPath []byte
+	var as4Path []byte
+	if !opts.IBGP {
+		asPath = []byte{asPathSequence, 1}
+		if opts.FourOctetAS {
+			asPath = binary.BigEndian.AppendUint32(asPath, opts.LocalASN)
+		} else if opts.LocalASN <= 0xffff {
+			asPath = binary.BigEndian.AppendUint16(asPath, uint16(opts.LocalASN))
+		} else {
+			// The peer only supports 2-octet AS numbers: AS_PATH contains AS_TRANS and the real AS
+			// number is sent in AS4_PATH (RFC 6793)
+			asPath = binary.BigEndian.AppendUint16(asPath, asTrans)
+			as4Path = []byte{asPathSequence, 1}
+			as4Path = binary.BigEndian.AppendUint32(as4Path, opts.LocalASN)
+		}
+	}
+	attrs = appendAttribute(attrs, attrFlagTransitive, attrTypeASPath, asPath)
+	if as4Path != nil {
+		attrs = appendAttribute(attrs, attrFlagOptional|attrFlagTransitive, attrTypeAS4Path, as4Path)
+	}
+
+	if opts.IBGP {
+		attrs = appendAttribute(attrs, attrFlagTransitive, attrTypeLocalPref, binary.BigEndian.AppendUint32(nil, defaultLocalPref))
+	}
+	if len(path.Communities) > 0 {
+		var communities []byte
+		for _, community := range path.Communities {
+			communities = binary.BigEndian.AppendUint32(communities, community)
+		}
+		attrs = appendAttribute(attrs, attrFlagOptional|attrFlagTransitive, attrTypeCommunities, communities)
+	}
+
+	var nlri []byte
+	if path.Prefix.Addr().Is4() {
+		attrs = appendAttribute(attrs, attrFlagTransitive, attrTypeNextHop, path.NextHop.AsSlice())
+		nlri = appendPrefix(nil, path.Prefix)
+	} else {
+		nextHop := path.NextHop.AsSlice()
+		mpReach := binary.BigEndian.AppendUint16(nil, afiIPv6)
+		mpReach = append(mpReach, safiUnicast, byte(len(nextHop)))
+		mpReach = append(mpReach, nextHop...)
+		mpReach = append(mpReach, 0)
+		mpReach = appendPrefix(mpReach, path.Prefix)
+		attrs = appendAttribute(attrs, attrFlagOptional, attrTypeMPReachNLRI, mpReach)
+	}
+
+	body := binary.BigEndian.AppendUint16(nil, 0) // No withdrawn routes
+	body = binary.BigEndian.AppendUint16(body, uint16(len(attrs)))
+	body = append(body, attrs...)
+	body = append(body, nlri...)
+	return message(msgTypeUpdate, body)
+}
+
+// withdrawMessage encodes an UPDATE message withdrawing the prefix
+func withdrawMessage(prefix netip.Prefix) []byte {
+	if prefix.Addr().Is4() {
+		withdrawn := appendPrefix(nil, prefix)
+		body := binary.BigEndian.AppendUint16(nil, uint16(len(withdrawn)))
+		body = append(body, withdrawn...)
+		body = binary.BigEndian.AppendUint16(body, 0) // No path attributes
+		return message(msgTypeUpdate, body)
+	}
+
+	mpUnreach := binary.BigEndian.AppendUint16(nil, afiIPv6)
+	mpUnreach = append(mpUnreach, safiUnicast)
+	mpUnreach = appendPrefix(mpUnreach, prefix)
+	attrs := appendAttribute(nil, attrFlagOptional, attrTypeMPUnreachNLRI, mpUnreach)
+
+	body := binary.BigEndian.AppendUint16(nil, 0) // No withdrawn routes
+	body = binary.BigEndian.AppendUint16(body, uint16(len(attrs)))
+	body = append(body, attrs...)
+	return message(msgTypeUpdate, body)
+}
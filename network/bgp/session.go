@@ -0,0 +1,383 @@
+package bgp
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/logger"
+)
+
+// DefaultPort is the TCP port of the BGP protocol
+const DefaultPort = 179
+
+// PeerConfig configures a BGP session with a peer
+type PeerConfig struct {
+	// Address is the IP address of the peer
+	Address netip.Addr
+	// Port defaults to 179
+	Port     int
+	ASN      uint32
+	LocalASN uint32
+	// RouterID is the BGP identifier of the speaker, an IPv4 address
+	RouterID netip.Addr
+	// HoldTime is proposed to the peer, the smallest hold time of both speakers is used
+	HoldTime time.Duration
+	// ConnectRetryInterval is the duration between two connection attempts
+	ConnectRetryInterval time.Duration
+}
+
+// Path is a route announced to the peers
+type Path struct {
+	Prefix netip.Prefix
+	// NextHop defaults to the local address of the session if it is of the same family as the prefix
+	NextHop     netip.Addr
+	Communities []uint32
+}
+
+func (p Path) equal(other Path) bool {
+	if p.Prefix != other.Prefix || p.NextHop != other.NextHop || len(p.Communities) != len(other.Communities) {
+		return false
+	}
+	for i := range p.Communities {
+		if p.Communities[i] != other.Communities[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Speaker announces paths to a BGP peer
+type Speaker interface {
+	// Announce adds or updates the path announced to the peer
+	Announce(path Path)
+	// Withdraw withdraws the path of the prefix from the peer
+	Withdraw(prefix netip.Prefix)
+	// Established returns true if the session is established with the peer
+	Established() bool
+}
+
+// Session is a BGP session initiated with a peer. The paths announced on the session are kept and
+// sent again each time the session is established. The session only advertises routes, the routes
+// received from the peer are ignored.
+type Session struct {
+	config PeerConfig
+
+	mutex       sync.Mutex
+	paths       map[netip.Prefix]Path
+	established bool
+	changed     chan struct{}
+}
+
+// NewSession returns a session with the peer, the connection is initiated by Run
+func NewSession(config PeerConfig) *Session {
+	if config.Port == 0 {
+		config.Port = DefaultPort
+	}
+	return &Session{
+		config:  config,
+		paths:   make(map[netip.Prefix]Path),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// Announce adds or updates the path. It is sent to the peer as soon as the session is established.
+func (s *Session) Announce(path Path) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, ok := s.paths[path.Prefix]
+	if ok && current.equal(path) {
+		return
+	}
+	s.paths[path.Prefix] = path
+	s.notifyChange()
+}
+
+// Withdraw removes the path of the prefix. It is withdrawn from the peer if it has been sent.
+func (s *Session) Withdraw(prefix netip.Prefix) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.paths[prefix]
+	if !ok {
+		return
+	}
+	delete(s.paths, prefix)
+	s.notifyChange()
+}
+
+// Established returns true if the session is established with the peer
+func (s *Session) Established() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.established
+}
+
+func (s *Session) notifyChange() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Session) setEstablished(established bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.established = established
+}
+
+// announcedPaths returns a copy of the paths to announce
+func (s *Session) announcedPaths() map[netip.Prefix]Path {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	paths := make(map[netip.Prefix]Path, len(s.paths))
+	for prefix, path := range s.paths {
+		paths[prefix] = path
+	}
+	return paths
+}
+
+// Run connects to the peer and keeps the session established until the context is canceled. When
+// the context is canceled, a Cease notification is sent to the peer which withdraws our routes.
+func (s *Session) Run(ctx context.Context) {
+	ctx, log := logger.WithFieldToCtx(ctx, "bgp_peer", s.address())
+
+	for {
+		err := s.connect(ctx)
+		s.setEstablished(false)
+		if ctx.Err() != nil {
+			return
+		}
+		log.WithError(err).Info("BGP session closed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.config.ConnectRetryInterval):
+		}
+	}
+}
+
+func (s *Session) address() string {
+	return net.JoinHostPort(s.config.Address.String(), strconv.Itoa(s.config.Port))
+}
+
+// connect opens a connection with the peer and runs the session until it is closed
+func (s *Session) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: s.config.ConnectRetryInterval}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address())
+	if err != nil {
+		return errors.Wrap(err, "fail to connect to the peer")
+	}
+	defer conn.Close()
+
+	peerOpen, err := s.openSession(conn)
+	if err != nil {
+		return errors.Wrap(err, "fail to open the session")
+	}
+
+	return s.runEstablished(ctx, conn, peerOpen)
+}
+
+// openSession exchanges the OPEN and KEEPALIVE messages with the peer
+func (s *Session) openSession(conn net.Conn) (openMessage, error) {
+	// The hold time is sent in seconds, the values 1 and 2 are not allowed
+	holdTime := uint16(s.config.HoldTime / time.Second)
+	if holdTime > 0 && holdTime < 3 {
+		holdTime = 3
+	}
+	err := conn.SetDeadline(time.Now().Add(s.config.ConnectRetryInterval + s.config.HoldTime))
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "fail to set the deadline of the connection")
+	}
+
+	open := openMessage{ASN: s.config.LocalASN, HoldTime: holdTime, RouterID: s.config.RouterID}
+	_, err = conn.Write(open.marshal())
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "fail to send OPEN message")
+	}
+
+	msgType, body, err := readMessage(conn)
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "fail to read OPEN message")
+	}
+	if msgType == msgTypeNotification {
+		n, _ := parseNotification(body)
+		return openMessage{}, errors.Wrap(n, "session refused by the peer")
+	}
+	if msgType != msgTypeOpen {
+		return openMessage{}, errors.Errorf("unexpected message of type %d instead of OPEN", msgType)
+	}
+	peerOpen, err := parseOpen(body)
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "invalid OPEN message")
+	}
+	if peerOpen.ASN != s.config.ASN {
+		return openMessage{}, errors.Errorf("unexpected peer AS %d, expected %d", peerOpen.ASN, s.config.ASN)
+	}
+	if peerOpen.HoldTime < holdTime {
+		holdTime = peerOpen.HoldTime
+	}
+	peerOpen.HoldTime = holdTime
+
+	_, err = conn.Write(keepaliveMessage())
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "fail to send KEEPALIVE message")
+	}
+
+	msgType, body, err = readMessage(conn)
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "fail to read KEEPALIVE message")
+	}
+	if msgType == msgTypeNotification {
+		n, _ := parseNotification(body)
+		return openMessage{}, errors.Wrap(n, "session refused by the peer")
+	}
+	if msgType != msgTypeKeepalive {
+		return openMessage{}, errors.Errorf("unexpected message of type %d instead of KEEPALIVE", msgType)
+	}
+
+	err = conn.SetDeadline(time.Time{})
+	if err != nil {
+		return openMessage{}, errors.Wrap(err, "fail to reset the deadline of the connection")
+	}
+	return peerOpen, nil
+}
+
+// runEstablished sends the paths to the peer and keeps the session alive until it is closed
+func (s *Session) runEstablished(ctx context.Context, conn net.Conn, peerOpen openMessage) error {
+	log := logger.Get(ctx)
+	holdTime := time.Duration(peerOpen.HoldTime) * time.Second
+	log.WithFields(logrus.Fields{
+		"bgp_peer_router_id": peerOpen.RouterID,
+		"bgp_hold_time":      holdTime,
+	}).Info("BGP session established")
+	s.setEstablished(true)
+
+	localAddr, _ := netip.ParseAddrPort(conn.LocalAddr().String())
+	opts := updateOpts{
+		LocalASN:    s.config.LocalASN,
+		IBGP:        s.config.LocalASN == s.config.ASN,
+		FourOctetAS: peerOpen.FourOctetAS,
+	}
+
+	// Messages received from the peer
+	received := make(chan error, 1)
+	receivedAt := make(chan struct{}, 1)
+	go func() {
+		for {
+			msgType, body, err := readMessage(conn)
+			if err != nil {
+				received <- errors.Wrap(err, "fail to read message")
+				return
+			}
+			if msgType == msgTypeNotification {
+				n, _ := parseNotification(body)
+				received <- errors.Wrap(n, "session closed by the peer")
+				return
+			}
+			select {
+			case receivedAt <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	// The hold time 0 disables the keepalives
+	var keepaliveTicker <-chan time.Time
+	var holdTimer <-chan time.Time
+	if holdTime > 0 {
+		ticker := time.NewTicker(holdTime / 3)
+		defer ticker.Stop()
+		keepaliveTicker = ticker.C
+		holdTimer = time.After(holdTime)
+	}
+
+	sent := make(map[netip.Prefix]Path)
+	err := s.sendChanges(ctx, conn, sent, localAddr.Addr().Unmap(), opts)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			_, _ = conn.Write(notification{Code: notificationCease}.marshal())
+			return ctx.Err()
+		case err := <-received:
+			return err
+		case <-receivedAt:
+			if holdTime > 0 {
+				holdTimer = time.After(holdTime)
+			}
+		case <-holdTimer:
+			_, _ = conn.Write(notification{Code: notificationHoldTimerExpired}.marshal())
+			return errors.New("hold timer expired")
+		case <-keepaliveTicker:
+			_, err := conn.Write(keepaliveMessage())
+			if err != nil {
+				return errors.Wrap(err, "fail to send KEEPALIVE message")
+			}
+		case <-s.changed:
+			err := s.sendChanges(ctx, conn, sent, localAddr.Addr().Unmap(), opts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendChanges sends the UPDATE messages needed for the peer to receive the current paths. sent
+// contains the paths already sent on the connection, it is updated with the new paths.
+func (s *Session) sendChanges(ctx context.Context, conn net.Conn, sent map[netip.Prefix]Path, localAddr netip.Addr, opts updateOpts) error {
+	log := logger.Get(ctx)
+	paths := s.announcedPaths()
+
+	for prefix := range sent {
+		_, ok := paths[prefix]
+		if ok {
+			continue
+		}
+		_, err := conn.Write(withdrawMessage(prefix))
+		if err != nil {
+			return errors.Wrapf(err, "fail to withdraw %s", prefix)
+		}
+		delete(sent, prefix)
+		log.WithField("bgp_prefix", prefix).Info("Prefix withdrawn")
+	}
+
+	for prefix, path := range paths {
+		sentPath, ok := sent[prefix]
+		if ok && sentPath.equal(path) {
+			continue
+		}
+
+		announcedPath := path
+		if !announcedPath.NextHop.IsValid() {
+			announcedPath.NextHop = localAddr
+		}
+		if announcedPath.NextHop.Is4() != prefix.Addr().Is4() {
+			log.WithField("bgp_prefix", prefix).Error("No next hop of the prefix family, the prefix is not announced")
+			continue
+		}
+
+		_, err := conn.Write(announceMessage(announcedPath, opts))
+		if err != nil {
+			return errors.Wrapf(err, "fail to announce %s", prefix)
+		}
+		sent[prefix] = path
+		log.WithFields(logrus.Fields{
+			"bgp_prefix":   prefix,
+			"bgp_next_hop": announcedPath.NextHop,
+		}).Info("Prefix announced")
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package bgp
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLocalASN = 4200000000
+	testPeerASN  = 65001
+)
+
+// testPeer is a BGP speaker listening on the loopback, it records the UPDATE messages it receives
+type testPeer struct {
+	t        *testing.T
+	listener net.Listener
+	conn     net.Conn
+	asn      uint32
+}
+
+func newTestPeer(t *testing.T, asn uint32) *testPeer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	return &testPeer{t: t, listener: listener, asn: asn}
+}
+
+func (p *testPeer) port() int {
+	return p.listener.Addr().(*net.TCPAddr).Port
+}
+
+// accept accepts the connection of the session and establishes it
+func (p *testPeer) accept() openMessage {
+	conn, err := p.listener.Accept()
+	require.NoError(p.t, err)
+	p.t.Cleanup(func() { conn.Close() })
+	p.conn = conn
+	require.NoError(p.t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	msgType, body := p.read()
+	require.Equal(p.t, uint8(msgTypeOpen), msgType)
+	open, err := parseOpen(body)
+	require.NoError(p.t, err)
+
+	_, err = conn.Write(openMessage{ASN: p.asn, HoldTime: 30, RouterID: netip.MustParseAddr("192.0.2.1")}.marshal())
+	require.NoError(p.t, err)
+	_, err = conn.Write(keepaliveMessage())
+	require.NoError(p.t, err)
+
+	msgType, _ = p.read()
+	require.Equal(p.t, uint8(msgTypeKeepalive), msgType)
+	return open
+}
+
+func (p *testPeer) read() (uint8, []byte) {
+	msgType, body, err := readMessage(p.conn)
+	require.NoError(p.t, err)
+	return msgType, body
+}
+
+// readUpdate returns the next UPDATE message, skipping the KEEPALIVE messages
+func (p *testPeer) readUpdate() testUpdate {
+	for {
+		msgType, body := p.read()
+		if msgType == msgTypeKeepalive {
+			continue
+		}
+		require.Equal(p.t, uint8(msgTypeUpdate), msgType)
+		return parseTestUpdate(p.t, body)
+	}
+}
+
+type testUpdate struct {
+	Withdrawn  []netip.Prefix
+	Attributes map[uint8][]byte
+	NLRI       []netip.Prefix
+}
+
+func parseTestPrefixes(t *testing.T, b []byte, ipv6 bool) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for len(b) > 0 {
+		bits := int(b[0])
+		n := (bits + 7) / 8
+		addr := make([]byte, 4)
+		if ipv6 {
+			addr = make([]byte, 16)
+		}
+		copy(addr, b[1:1+n])
+		ip, ok := netip.AddrFromSlice(addr)
+		require.True(t, ok)
+		prefixes = append(prefixes, netip.PrefixFrom(ip, bits))
+		b = b[1+n:]
+	}
+	return prefixes
+}
+
+func parseTestUpdate(t *testing.T, body []byte) testUpdate {
+	withdrawnLength := int(binary.BigEndian.Uint16(body[0:2]))
+	update := testUpdate{
+		Withdrawn:  parseTestPrefixes(t, body[2:2+withdrawnLength], false),
+		Attributes: make(map[uint8][]byte),
+	}
+	body = body[2+withdrawnLength:]
+
+	attrsLength := int(binary.BigEndian.Uint16(body[0:2]))
+	attrs := body[2 : 2+attrsLength]
+	for len(attrs) > 0 {
+		flags, attrType := attrs[0], attrs[1]
+		var length, offset int
+		if flags&attrFlagExtendedLength != 0 {
+			length, offset = int(binary.BigEndian.Uint16(attrs[2:4])), 4
+		} else {
+			length, offset = int(attrs[2]), 3
+		}
+		update.Attributes[attrType] = attrs[offset : offset+length]
+		attrs = attrs[offset+length:]
+	}
+	update.NLRI = parseTestPrefixes(t, body[2+attrsLength:], false)
+	return update
+}
+
+func newTestSession(peer *testPeer, peerASN uint32) *Session {
+	return NewSession(PeerConfig{
+		Address:              netip.MustParseAddr("127.0.0.1"),
+		Port:                 peer.port(),
+		ASN:                  peerASN,
+		LocalASN:             testLocalASN,
+		RouterID:             netip.MustParseAddr("192.0.2.10"),
+		HoldTime:             9 * time.Second,
+		ConnectRetryInterval: 100 * time.Millisecond,
+	})
+}
+
+func TestSession(t *testing.T) {
+	t.Run("it announces and withdraws an IPv4 prefix", func(t *testing.T) {
+		peer := newTestPeer(t, testPeerASN)
+		session := newTestSession(peer, testPeerASN)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go session.Run(ctx)
+
+		open := peer.accept()
+		assert.Equal(t, uint32(testLocalASN), open.ASN)
+		assert.True(t, open.FourOctetAS)
+		assert.Equal(t, uint16(9), open.HoldTime)
+		assert.Eventually(t, session.Established, time.Second, 10*time.Millisecond)
+
+		prefix := netip.MustParsePrefix("10.0.0.10/32")
+		session.Announce(Path{Prefix: prefix, Communities: []uint32{65001<<16 | 100}})
+		update := peer.readUpdate()
+		assert.Equal(t, []netip.Prefix{prefix}, update.NLRI)
+		// The next hop defaults to the local address of the session
+		assert.Equal(t, []byte{127, 0, 0, 1}, update.Attributes[attrTypeNextHop])
+		assert.Equal(t, []byte{asPathSequence, 1, 0xfa, 0x56, 0xea, 0x00}, update.Attributes[attrTypeASPath])
+		assert.Equal(t, []byte{0xfd, 0xe9, 0x00, 0x64}, update.Attributes[attrTypeCommunities])
+		assert.NotContains(t, update.Attributes, uint8(attrTypeLocalPref))
+
+		session.Withdraw(prefix)
+		update = peer.readUpdate()
+		assert.Equal(t, []netip.Prefix{prefix}, update.Withdrawn)
+		assert.Empty(t, update.NLRI)
+
+		cancel()
+		msgType, body := peer.read()
+		for msgType == msgTypeKeepalive {
+			msgType, body = peer.read()
+		}
+		require.Equal(t, uint8(msgTypeNotification), msgType)
+		assert.Equal(t, uint8(notificationCease), body[0])
+	})
+
+	t.Run("it sends the paths announced before the session is established", func(t *testing.T) {
+		peer := newTestPeer(t, testLocalASN)
+		session := newTestSession(peer, testLocalASN)
+		prefix := netip.MustParsePrefix("10.0.0.20/32")
+		session.Announce(Path{Prefix: prefix, NextHop: netip.MustParseAddr("10.0.0.1")})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go session.Run(ctx)
+		peer.accept()
+
+		update := peer.readUpdate()
+		assert.Equal(t, []netip.Prefix{prefix}, update.NLRI)
+		assert.Equal(t, []byte{10, 0, 0, 1}, update.Attributes[attrTypeNextHop])
+		// iBGP: empty AS path and local preference
+		assert.Empty(t, update.Attributes[attrTypeASPath])
+		assert.Equal(t, []byte{0, 0, 0, defaultLocalPref}, update.Attributes[attrTypeLocalPref])
+	})
+
+	t.Run("it announces and withdraws an IPv6 prefix with the multiprotocol extensions", func(t *testing.T) {
+		peer := newTestPeer(t, testPeerASN)
+		session := newTestSession(peer, testPeerASN)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go session.Run(ctx)
+		peer.accept()
+
+		prefix := netip.MustParsePrefix("2001:db8::10/128")
+		nextHop := netip.MustParseAddr("2001:db8::1")
+		session.Announce(Path{Prefix: prefix, NextHop: nextHop})
+		update := peer.readUpdate()
+		assert.Empty(t, update.NLRI)
+		assert.NotContains(t, update.Attributes, uint8(attrTypeNextHop))
+		mpReach := update.Attributes[attrTypeMPReachNLRI]
+		require.NotEmpty(t, mpReach)
+		assert.Equal(t, []byte{0, afiIPv6, safiUnicast, 16}, mpReach[0:4])
+		assert.Equal(t, nextHop.AsSlice(), mpReach[4:20])
+		assert.Equal(t, []netip.Prefix{prefix}, parseTestPrefixes(t, mpReach[21:], true))
+
+		session.Withdraw(prefix)
+		update = peer.readUpdate()
+		mpUnreach := update.Attributes[attrTypeMPUnreachNLRI]
+		require.NotEmpty(t, mpUnreach)
+		assert.Equal(t, []netip.Prefix{prefix}, parseTestPrefixes(t, mpUnreach[3:], true))
+	})
+
+	t.Run("it does not establish the session with an unexpected peer AS", func(t *testing.T) {
+		peer := newTestPeer(t, 65002)
+		session := newTestSession(peer, testPeerASN)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go session.Run(ctx)
+
+		conn, err := peer.listener.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _, err = readMessage(conn)
+		require.NoError(t, err)
+		_, err = conn.Write(openMessage{ASN: 65002, HoldTime: 30, RouterID: netip.MustParseAddr("192.0.2.1")}.marshal())
+		require.NoError(t, err)
+
+		// The session closes the connection
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, _, err = readMessage(conn)
+		require.Error(t, err)
+		assert.False(t, session.Established())
+	})
+}
+
+func TestOpenMessage(t *testing.T) {
+	open := openMessage{ASN: testLocalASN, HoldTime: 90, RouterID: netip.MustParseAddr("192.0.2.10")}
+	msg := open.marshal()
+
+	// The 2-octet AS field contains AS_TRANS if the AS number does not fit
+	assert.Equal(t, []byte{0x5b, 0xa0}, msg[headerLength+1:headerLength+3])
+
+	parsed, err := parseOpen(msg[headerLength:])
+	require.NoError(t, err)
+	assert.Equal(t, openMessage{ASN: testLocalASN, HoldTime: 90, RouterID: open.RouterID, FourOctetAS: true}, parsed)
+}
+
+func TestAnnounceMessage(t *testing.T) {
+	path := Path{Prefix: netip.MustParsePrefix("192.0.2.1/32"), NextHop: netip.MustParseAddr("192.0.2.10")}
+	// Flags, type and length of the attribute, followed by a sequence of one AS number
+	as4Path := []byte{attrFlagOptional | attrFlagTransitive, attrTypeAS4Path, 6, asPathSequence, 1, 0xfa, 0x56, 0xea, 0x00}
+
+	t.Run("it sends AS_TRANS and AS4_PATH to a peer without 4-octet AS support", func(t *testing.T) {
+		msg := announceMessage(path, updateOpts{LocalASN: testLocalASN})
+
+		assert.Contains(t, string(msg), string([]byte{attrFlagTransitive, attrTypeASPath, 4, asPathSequence, 1, 0x5b, 0xa0}))
+		assert.Contains(t, string(msg), string(as4Path))
+	})
+
+	t.Run("it does not send AS4_PATH to a peer with 4-octet AS support", func(t *testing.T) {
+		msg := announceMessage(path, updateOpts{LocalASN: testLocalASN, FourOctetAS: true})
+
+		assert.Contains(t, string(msg), string([]byte{attrFlagTransitive, attrTypeASPath, 6, asPathSequence, 1, 0xfa, 0x56, 0xea, 0x00}))
+		assert.NotContains(t, string(msg), string(as4Path))
+	})
+
+	t.Run("it does not send AS4_PATH if the AS number fits in 2 octets", func(t *testing.T) {
+		msg := announceMessage(path, updateOpts{LocalASN: testPeerASN})
+
+		assert.Contains(t, string(msg), string([]byte{attrFlagTransitive, attrTypeASPath, 4, asPathSequence, 1, 0xfd, 0xe9}))
+		assert.NotContains(t, string(msg), string([]byte{attrFlagOptional | attrFlagTransitive, attrTypeAS4Path}))
+	})
+}
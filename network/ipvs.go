@@ -0,0 +1,337 @@
+package network
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/moby/ipvs"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// ipvsServicePersistentFlag is the IP_VS_SVC_F_PERSISTENT flag of the IPVS services
+const ipvsServicePersistentFlag = 0x0001
+
+// IPVSForwarding is the forwarding method of a real server
+type IPVSForwarding string
+
+const (
+	IPVSForwardingDirectRoute IPVSForwarding = "dr"
+	IPVSForwardingNAT         IPVSForwarding = "nat"
+	IPVSForwardingTunnel      IPVSForwarding = "tunnel"
+)
+
+// IsValid returns true if the forwarding method is a known method
+func (f IPVSForwarding) IsValid() bool {
+	return f == IPVSForwardingDirectRoute || f == IPVSForwardingNAT || f == IPVSForwardingTunnel
+}
+
+// IPVSService is an IPVS virtual service, identified by its address, protocol and port
+type IPVSService struct {
+	Address net.IP
+	// Protocol is tcp, udp or sctp
+	Protocol  string
+	Port      uint16
+	Scheduler string
+	// PersistenceTimeout makes the connections of a client go to the same real server, 0 disables
+	// the persistence
+	PersistenceTimeout time.Duration
+}
+
+// String returns the service as displayed by ipvsadm (e.g. tcp 192.0.2.10:80)
+func (s IPVSService) String() string {
+	return s.Protocol + " " + net.JoinHostPort(s.Address.String(), strconv.Itoa(int(s.Port)))
+}
+
+// IPVSRealServer is a real server of an IPVS virtual service, identified by its address and port
+type IPVSRealServer struct {
+	Address    net.IP
+	Port       uint16
+	Weight     int
+	Forwarding IPVSForwarding
+}
+
+// String returns the address and port of the real server
+func (s IPVSRealServer) String() string {
+	return net.JoinHostPort(s.Address.String(), strconv.Itoa(int(s.Port)))
+}
+
+// IPVSManager manages the IPVS virtual services of a network namespace
+type IPVSManager interface {
+	HasService(service IPVSService) (bool, error)
+	// EnsureService adds the service, or updates its scheduler and persistence if it exists
+	EnsureService(service IPVSService) error
+	// RemoveService removes the service and its real servers
+	RemoveService(service IPVSService) error
+
+	RealServers(service IPVSService) ([]IPVSRealServer, error)
+	// EnsureRealServer adds the real server, or updates its weight and forwarding method if it exists
+	EnsureRealServer(service IPVSService, realServer IPVSRealServer) error
+	RemoveRealServer(service IPVSService, realServer IPVSRealServer) error
+}
+
+type ipvsManager struct {
+	namespace string
+}
+
+// NewIPVSManager returns an IPVSManager managing the IPVS services of the network namespace: a
+// name under /var/run/netns or a path. An empty namespace is the namespace of LinK.
+//
+// The managers of a namespace share the same netlink socket.
+func NewIPVSManager(namespace string) (IPVSManager, error) {
+	m := ipvsManager{namespace: namespace}
+	// The socket is opened right away so that an error is reported early
+	_, unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	unlock()
+	return m, nil
+}
+
+// lock returns the IPVS handle of the namespace, opening it if needed. The handle must not be used
+// once unlock is called.
+func (m ipvsManager) lock() (*ipvs.Handle, func(), error) {
+	ns, err := getNamespace(m.namespace)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fail to get network namespace")
+	}
+
+	ns.ipvsMutex.Lock()
+	if ns.ipvs == nil {
+		ns.ipvs, err = ipvs.New(ns.path)
+		if err != nil {
+			ns.ipvsMutex.Unlock()
+			return nil, nil, errors.Wrap(err, "fail to open IPVS netlink socket")
+		}
+	}
+	return ns.ipvs, ns.ipvsMutex.Unlock, nil
+}
+
+// IPVSProtocol returns the IP protocol number of an IPVS protocol name
+func IPVSProtocol(protocol string) (uint16, error) {
+	switch protocol {
+	case "tcp":
+		return unix.IPPROTO_TCP, nil
+	case "udp":
+		return unix.IPPROTO_UDP, nil
+	case "sctp":
+		return unix.IPPROTO_SCTP, nil
+	}
+	return 0, errors.Errorf("unknown protocol %s, must be tcp, udp or sctp", protocol)
+}
+
+func toIPVSService(service IPVSService) (*ipvs.Service, error) {
+	protocol, err := IPVSProtocol(service.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ipvs.Service{
+		Address:       service.Address,
+		Protocol:      protocol,
+		Port:          service.Port,
+		SchedName:     service.Scheduler,
+		AddressFamily: unix.AF_INET,
+		Netmask:       0xffffffff,
+	}
+	if service.Address.To4() == nil {
+		s.AddressFamily = unix.AF_INET6
+		s.Netmask = 128
+	}
+	if service.PersistenceTimeout > 0 {
+		s.Flags = ipvsServicePersistentFlag
+		s.Timeout = uint32(service.PersistenceTimeout / time.Second)
+	}
+	return s, nil
+}
+
+func toIPVSDestination(realServer IPVSRealServer) *ipvs.Destination {
+	d := &ipvs.Destination{
+		Address:       realServer.Address,
+		Port:          realServer.Port,
+		Weight:        realServer.Weight,
+		AddressFamily: unix.AF_INET,
+	}
+	if realServer.Address.To4() == nil {
+		d.AddressFamily = unix.AF_INET6
+	}
+	switch realServer.Forwarding {
+	case IPVSForwardingNAT:
+		d.ConnectionFlags = ipvs.ConnFwdMasq
+	case IPVSForwardingTunnel:
+		d.ConnectionFlags = ipvs.ConnFwdTunnel
+	default:
+		d.ConnectionFlags = ipvs.ConnFwdDirectRoute
+	}
+	return d
+}
+
+func fromIPVSDestination(d *ipvs.Destination) IPVSRealServer {
+	realServer := IPVSRealServer{
+		Address:    d.Address,
+		Port:       d.Port,
+		Weight:     d.Weight,
+		Forwarding: IPVSForwardingDirectRoute,
+	}
+	switch d.ConnectionFlags & ipvs.ConnFwdMask {
+	case ipvs.ConnFwdMasq:
+		realServer.Forwarding = IPVSForwardingNAT
+	case ipvs.ConnFwdTunnel:
+		realServer.Forwarding = IPVSForwardingTunnel
+	}
+	return realServer
+}
+
+func (m ipvsManager) HasService(service IPVSService) (bool, error) {
+	s, err := toIPVSService(service)
+	if err != nil {
+		return false, err
+	}
+
+	handle, unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	return handle.IsServicePresent(s), nil
+}
+
+func (m ipvsManager) EnsureService(service IPVSService) error {
+	s, err := toIPVSService(service)
+	if err != nil {
+		return err
+	}
+
+	handle, unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !handle.IsServicePresent(s) {
+		err = handle.NewService(s)
+		if err != nil {
+			return errors.Wrapf(err, "fail to add IPVS service %s", service)
+		}
+		return nil
+	}
+
+	current, err := handle.GetService(s)
+	if err != nil {
+		return errors.Wrapf(err, "fail to get IPVS service %s", service)
+	}
+	if current.SchedName == s.SchedName &&
+		current.Flags&ipvsServicePersistentFlag == s.Flags&ipvsServicePersistentFlag &&
+		(s.Flags&ipvsServicePersistentFlag == 0 || current.Timeout == s.Timeout) {
+		return nil
+	}
+	err = handle.UpdateService(s)
+	if err != nil {
+		return errors.Wrapf(err, "fail to update IPVS service %s", service)
+	}
+	return nil
+}
+
+func (m ipvsManager) RemoveService(service IPVSService) error {
+	s, err := toIPVSService(service)
+	if err != nil {
+		return err
+	}
+
+	handle, unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !handle.IsServicePresent(s) {
+		return nil
+	}
+	err = handle.DelService(s)
+	if err != nil {
+		return errors.Wrapf(err, "fail to remove IPVS service %s", service)
+	}
+	return nil
+}
+
+func (m ipvsManager) RealServers(service IPVSService) ([]IPVSRealServer, error) {
+	s, err := toIPVSService(service)
+	if err != nil {
+		return nil, err
+	}
+
+	handle, unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	destinations, err := handle.GetDestinations(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to list real servers of IPVS service %s", service)
+	}
+	realServers := make([]IPVSRealServer, 0, len(destinations))
+	for _, d := range destinations {
+		realServers = append(realServers, fromIPVSDestination(d))
+	}
+	return realServers, nil
+}
+
+func (m ipvsManager) EnsureRealServer(service IPVSService, realServer IPVSRealServer) error {
+	s, err := toIPVSService(service)
+	if err != nil {
+		return err
+	}
+	d := toIPVSDestination(realServer)
+
+	handle, unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	destinations, err := handle.GetDestinations(s)
+	if err != nil {
+		return errors.Wrapf(err, "fail to list real servers of IPVS service %s", service)
+	}
+	for _, current := range destinations {
+		if !current.Address.Equal(d.Address) || current.Port != d.Port {
+			continue
+		}
+		if current.Weight == d.Weight && current.ConnectionFlags&ipvs.ConnFwdMask == d.ConnectionFlags {
+			return nil
+		}
+		err = handle.UpdateDestination(s, d)
+		if err != nil {
+			return errors.Wrapf(err, "fail to update real server %s", realServer)
+		}
+		return nil
+	}
+
+	err = handle.NewDestination(s, d)
+	if err != nil {
+		return errors.Wrapf(err, "fail to add real server %s", realServer)
+	}
+	return nil
+}
+
+func (m ipvsManager) RemoveRealServer(service IPVSService, realServer IPVSRealServer) error {
+	s, err := toIPVSService(service)
+	if err != nil {
+		return err
+	}
+
+	handle, unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = handle.DelDestination(s, toIPVSDestination(realServer))
+	if err != nil {
+		return errors.Wrapf(err, "fail to remove real server %s", realServer)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/moby/ipvs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestToIPVSService(t *testing.T) {
+	t.Run("with an IPv4 service with persistence", func(t *testing.T) {
+		service, err := toIPVSService(IPVSService{
+			Address:            net.ParseIP("192.0.2.10"),
+			Protocol:           "tcp",
+			Port:               443,
+			Scheduler:          "wlc",
+			PersistenceTimeout: 5 * time.Minute,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &ipvs.Service{
+			Address:       net.ParseIP("192.0.2.10"),
+			Protocol:      unix.IPPROTO_TCP,
+			Port:          443,
+			SchedName:     "wlc",
+			Flags:         ipvsServicePersistentFlag,
+			Timeout:       300,
+			AddressFamily: unix.AF_INET,
+			Netmask:       0xffffffff,
+		}, service)
+	})
+
+	t.Run("with an IPv6 service", func(t *testing.T) {
+		service, err := toIPVSService(IPVSService{Address: net.ParseIP("2001:db8::10"), Protocol: "udp", Port: 53, Scheduler: "rr"})
+		require.NoError(t, err)
+		assert.Equal(t, uint16(unix.AF_INET6), service.AddressFamily)
+		assert.Equal(t, uint32(128), service.Netmask)
+		assert.Equal(t, uint16(unix.IPPROTO_UDP), service.Protocol)
+		assert.Zero(t, service.Flags)
+	})
+
+	t.Run("with an unknown protocol", func(t *testing.T) {
+		_, err := toIPVSService(IPVSService{Address: net.ParseIP("192.0.2.10"), Protocol: "icmp"})
+		require.ErrorContains(t, err, "unknown protocol icmp")
+	})
+}
+
+func TestIPVSDestination(t *testing.T) {
+	for _, forwarding := range []IPVSForwarding{IPVSForwardingDirectRoute, IPVSForwardingNAT, IPVSForwardingTunnel} {
+		t.Run(string(forwarding), func(t *testing.T) {
+			realServer := IPVSRealServer{Address: net.ParseIP("10.0.0.1"), Port: 8080, Weight: 2, Forwarding: forwarding}
+			assert.Equal(t, realServer, fromIPVSDestination(toIPVSDestination(realServer)))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package network
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	icmpv6TypeNeighborAdvertisement = 136
+	ndpOptionTargetLinkLayerAddress = 2
+	// neighborAdvertisementOverrideFlag is the O flag: receivers update their cache entry with the
+	// link-layer address of the advertisement, even if they already know another one.
+	neighborAdvertisementOverrideFlag = 1 << 29
+)
+
+// allNodesMulticast is the IPv6 all-nodes link-local multicast address (ff02::1)
+var allNodesMulticast = net.ParseIP("ff02::1")
+
+// NDP sends IPv6 Neighbor Discovery Protocol messages. This is the IPv6 equivalent of the gratuitous ARP.
+type NDP interface {
+	UnsolicitedNeighborAdvertisement(req NeighborAdvertisementRequest) error
+}
+
+type NeighborAdvertisementRequest struct {
+	IP        net.IP
+	Interface *net.Interface
+}
+
+type ndp struct{}
+
+// NewNDP returns an NDP implementation using raw ICMPv6 sockets. Like the ARP sender, a socket is
+// opened per request so it is safe to use it concurrently.
+func NewNDP() NDP {
+	return ndp{}
+}
+
+// UnsolicitedNeighborAdvertisement sends a Neighbor Advertisement to all the nodes of the link
+// with the override flag set, so that the neighbors update their cache with our link-layer address.
+func (ndp) UnsolicitedNeighborAdvertisement(req NeighborAdvertisementRequest) error {
+	if req.IP.To4() != nil || req.IP.To16() == nil {
+		return errors.Errorf("not an IPv6 address: %s", req.IP)
+	}
+
+	fd, err := unix.Socket(unix.AF_INET6, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.IPPROTO_ICMPV6)
+	if err != nil {
+		return errors.Wrap(err, "fail to open ICMPv6 socket")
+	}
+	defer unix.Close(fd)
+
+	// RFC 4861: Neighbor Discovery messages must be sent with a hop limit of 255
+	err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, 255)
+	if err != nil {
+		return errors.Wrap(err, "fail to set the hop limit")
+	}
+	err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_IF, req.Interface.Index)
+	if err != nil {
+		return errors.Wrap(err, "fail to set the multicast interface")
+	}
+
+	dst := &unix.SockaddrInet6{ZoneId: uint32(req.Interface.Index)}
+	copy(dst.Addr[:], allNodesMulticast.To16())
+
+	// The checksum is computed by the kernel for ICMPv6 raw sockets
+	err = unix.Sendto(fd, neighborAdvertisement(req.IP, req.Interface.HardwareAddr), 0, dst)
+	if err != nil {
+		return errors.Wrap(err, "fail to send the neighbor advertisement")
+	}
+	return nil
+}
+
+// neighborAdvertisement builds an ICMPv6 Neighbor Advertisement message (RFC 4861 section 4.4)
+// with the Target Link-Layer Address option.
+func neighborAdvertisement(target net.IP, hardwareAddr net.HardwareAddr) []byte {
+	// The option length is expressed in units of 8 bytes
+	optionLen := (2 + len(hardwareAddr) + 7) / 8 * 8
+
+	msg := make([]byte, 24+optionLen)
+	msg[0] = icmpv6TypeNeighborAdvertisement
+	// msg[1] is the code and msg[2:4] the checksum, both left to 0
+	binary.BigEndian.PutUint32(msg[4:8], neighborAdvertisementOverrideFlag)
+	copy(msg[8:24], target.To16())
+
+	msg[24] = ndpOptionTargetLinkLayerAddress
+	msg[25] = byte(optionLen / 8)
+	copy(msg[26:], hardwareAddr)
+
+	return msg
+}
@@ -0,0 +1,23 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeighborAdvertisement(t *testing.T) {
+	mac, err := net.ParseMAC("02:42:ac:11:00:02")
+	assert.NoError(t, err)
+
+	msg := neighborAdvertisement(net.ParseIP("2001:db8::10"), mac)
+
+	expected := []byte{
+		136, 0, 0, 0, // Type, code and checksum
+		0x20, 0, 0, 0, // Override flag
+		0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10, // Target address
+		2, 1, 0x02, 0x42, 0xac, 0x11, 0x00, 0x02, // Target link-layer address option
+	}
+	assert.Equal(t, expected, msg)
+}
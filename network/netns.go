@@ -0,0 +1,173 @@
+package network
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/moby/ipvs"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// NetnsDir is the directory of the named network namespaces, as created by `ip netns add`
+const NetnsDir = "/var/run/netns"
+
+// namespace is a network namespace where the interfaces are managed
+type namespace struct {
+	// path is empty for the namespace of LinK
+	path string
+	// dev and ino identify the namespace. A namespace deleted and created again with the same name
+	// is another namespace, with another inode.
+	dev    uint64
+	ino    uint64
+	ns     netns.NsHandle
+	handle *netlink.Handle
+
+	// ipvs is opened on first use and shared by the IPVS managers of the namespace. Its netlink
+	// socket must not be used concurrently.
+	ipvsMutex sync.Mutex
+	ipvs      *ipvs.Handle
+	// nftablesMutex serializes the read-modify-write cycles of the NAT managers of the namespace
+	nftablesMutex sync.Mutex
+}
+
+var (
+	currentNamespace = &namespace{handle: &netlink.Handle{}}
+
+	// The namespaces are opened once and shared by all the interfaces, netlink handles being safe
+	// for concurrent use
+	namespacesMutex sync.Mutex
+	namespaces      = make(map[string]*namespace)
+
+	// openNamespace is replaced in the tests, opening a namespace requires privileges
+	openNamespace = openNamespaceFromPath
+)
+
+// NamespacePath returns the path of a network namespace, given its name under /var/run/netns or its path
+func NamespacePath(name string) string {
+	if name == "" || strings.Contains(name, "/") {
+		return name
+	}
+	return filepath.Join(NetnsDir, name)
+}
+
+// getNamespace returns the network namespace with the given name or path. An empty name is the
+// namespace of LinK.
+//
+// The namespaces are cached. The cached namespace is opened again if the file of the namespace does
+// not match it anymore, for instance after `ip netns del` and `ip netns add`.
+func getNamespace(name string) (*namespace, error) {
+	path := NamespacePath(name)
+	if path == "" {
+		return currentNamespace, nil
+	}
+
+	namespacesMutex.Lock()
+	defer namespacesMutex.Unlock()
+
+	n, ok := namespaces[path]
+	var stat unix.Stat_t
+	err := unix.Stat(path, &stat)
+	if err != nil {
+		if ok {
+			delete(namespaces, path)
+			n.close()
+		}
+		return nil, errors.Wrapf(err, "fail to stat network namespace %s", path)
+	}
+	if ok {
+		if n.dev == uint64(stat.Dev) && n.ino == uint64(stat.Ino) {
+			return n, nil
+		}
+		delete(namespaces, path)
+		n.close()
+	}
+
+	n, err = openNamespace(path)
+	if err != nil {
+		return nil, err
+	}
+	// The identity is read from the opened namespace, the file may have changed since the stat
+	err = unix.Fstat(int(n.ns), &stat)
+	if err != nil {
+		n.close()
+		return nil, errors.Wrapf(err, "fail to stat network namespace %s", path)
+	}
+	n.dev, n.ino = uint64(stat.Dev), uint64(stat.Ino)
+
+	namespaces[path] = n
+	return n, nil
+}
+
+func openNamespaceFromPath(path string) (*namespace, error) {
+	ns, err := netns.GetFromPath(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open network namespace %s", path)
+	}
+	handle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		ns.Close()
+		return nil, errors.Wrapf(err, "fail to open netlink handle in network namespace %s", path)
+	}
+	return &namespace{path: path, ns: ns, handle: handle}, nil
+}
+
+// close releases the namespace. The interfaces of the namespace can not be managed anymore.
+func (n *namespace) close() {
+	// Wait for the IPVS and nftables operations in progress
+	n.ipvsMutex.Lock()
+	defer n.ipvsMutex.Unlock()
+	n.nftablesMutex.Lock()
+	defer n.nftablesMutex.Unlock()
+
+	if n.ipvs != nil {
+		n.ipvs.Close()
+		n.ipvs = nil
+	}
+	n.handle.Close()
+	n.ns.Close()
+}
+
+// run calls fn from a goroutine locked on an OS thread switched to the namespace. This is required
+// to open sockets in the namespace, a socket staying in the namespace where it has been created.
+// fn must not start goroutines: they would not run in the namespace.
+func (n *namespace) run(fn func() error) error {
+	if n.path == "" {
+		return fn()
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		origin, err := netns.Get()
+		if err != nil {
+			runtime.UnlockOSThread()
+			errChan <- errors.Wrap(err, "fail to get the current network namespace")
+			return
+		}
+		defer origin.Close()
+
+		err = netns.Set(n.ns)
+		if err != nil {
+			runtime.UnlockOSThread()
+			errChan <- errors.Wrapf(err, "fail to switch to network namespace %s", n.path)
+			return
+		}
+
+		fnErr := fn()
+
+		// If the thread cannot be switched back, it is kept locked so that it is terminated when the
+		// goroutine exits instead of being reused by another goroutine
+		err = netns.Set(origin)
+		if err == nil {
+			runtime.UnlockOSThread()
+		}
+		errChan <- fnErr
+	}()
+	return <-errChan
+}
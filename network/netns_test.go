@@ -0,0 +1,69 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+func TestGetNamespace(t *testing.T) {
+	// Regular files stand for the namespaces, opening a network namespace requires privileges
+	opened := 0
+	openNamespace = func(path string) (*namespace, error) {
+		opened++
+		ns, err := netns.GetFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		return &namespace{path: path, ns: ns, handle: &netlink.Handle{}}, nil
+	}
+	path := filepath.Join(t.TempDir(), "tenant")
+	t.Cleanup(func() {
+		openNamespace = openNamespaceFromPath
+		n, ok := namespaces[path]
+		if ok {
+			n.close()
+			delete(namespaces, path)
+		}
+	})
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	n, err := getNamespace(path)
+	require.NoError(t, err)
+	cached, err := getNamespace(path)
+	require.NoError(t, err)
+	assert.Same(t, n, cached)
+	assert.Equal(t, 1, opened)
+
+	t.Run("it opens the namespace again if it has been recreated", func(t *testing.T) {
+		// The old file is kept so that its inode is not reused
+		require.NoError(t, os.Rename(path, path+".old"))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		recreated, err := getNamespace(path)
+		require.NoError(t, err)
+		assert.NotSame(t, n, recreated)
+		assert.Equal(t, 2, opened)
+		// The handle of the old namespace is closed
+		assert.Equal(t, netns.None(), n.ns)
+
+		cached, err := getNamespace(path)
+		require.NoError(t, err)
+		assert.Same(t, recreated, cached)
+		n = recreated
+	})
+
+	t.Run("it forgets the namespace if it has been deleted", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+
+		_, err := getNamespace(path)
+		require.Error(t, err)
+		assert.NotContains(t, namespaces, path)
+		assert.Equal(t, netns.None(), n.ns)
+	})
+}
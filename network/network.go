@@ -1,30 +1,78 @@
 package network
 
 import (
+	"context"
 	"net"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	dadPollInterval = 100 * time.Millisecond
+
+	// addressLabelSuffix is appended to the interface name to label the IPv4 addresses added by
+	// LinK, so that they can be found after a restart. IPv6 addresses do not support labels.
+	addressLabelSuffix = ":link"
+	// maxAddressLabelLength is the maximum length of an address label (IFNAMSIZ without the trailing NUL)
+	maxAddressLabelLength = 15
 )
 
 type Interface interface {
+	// EnsureIP adds the IP to the interface if it is not present yet
 	EnsureIP(ip string) error
 	RemoveIP(ip string) error
+	HasIP(ip string) (bool, error)
+	// ManagedIPs lists the IPv4 addresses labelled as added by LinK on the interface
+	ManagedIPs() ([]string, error)
+	// Announce announces the IP on the network with a gratuitous ARP packet for IPv4 addresses, or
+	// an unsolicited neighbor advertisement for IPv6 addresses
+	Announce(ip string, mode ARPMode) error
+
+	// ARP conflict detection (RFC 5227), only available for IPv4 addresses
+	ProbeARP(ctx context.Context, ip string, opts ARPProbeOpts) (net.HardwareAddr, error)
+	WatchARPConflicts(ctx context.Context, ip string, onConflict func(net.HardwareAddr)) error
 }
 
 type NetInterface struct {
 	card *net.Interface
 	link netlink.Link
+	ns   *namespace
 	arp  ARP
+	ndp  NDP
+	opts NetInterfaceOpts
+}
+
+type NetInterfaceOpts struct {
+	// IPv6DAD enables the Duplicate Address Detection when an IPv6 address is added. The address is
+	// only announced once the DAD succeeded. If disabled, IPv6 addresses are added with the nodad flag.
+	IPv6DAD bool
+	// IPv6DADTimeout is the maximum duration to wait for the DAD to complete
+	IPv6DADTimeout time.Duration
+	// Namespace is the network namespace of the interface: a name under /var/run/netns or a path.
+	// Defaults to the namespace of LinK.
+	Namespace string
 }
 
-func NewNetworkInterfaceFromName(name string) (NetInterface, error) {
-	link, err := netlink.LinkByName(name)
+func NewNetworkInterfaceFromName(name string, opts NetInterfaceOpts) (NetInterface, error) {
+	ns, err := getNamespace(opts.Namespace)
+	if err != nil {
+		return NetInterface{}, errors.Wrap(err, "fail to get network namespace")
+	}
+
+	link, err := ns.handle.LinkByName(name)
 	if err != nil {
 		return NetInterface{}, errors.Wrapf(err, "fail to open interface %s", name)
 	}
 
-	card, err := net.InterfaceByName(name)
+	var card *net.Interface
+	err = ns.run(func() error {
+		var err error
+		card, err = net.InterfaceByName(name)
+		return err
+	})
 	if err != nil {
 		return NetInterface{}, errors.Wrapf(err, "fail to find interface %s", name)
 	}
@@ -32,12 +80,34 @@ func NewNetworkInterfaceFromName(name string) (NetInterface, error) {
 	return NetInterface{
 		card: card,
 		link: link,
-		arp:  GetArp(),
+		ns:   ns,
+		arp:  NewARP(),
+		ndp:  NewNDP(),
+		opts: opts,
 	}, nil
 }
 
+// refreshed returns the interface opened again if its network namespace has been recreated since
+// the interface was opened. Otherwise the interface is returned unchanged.
+func (i NetInterface) refreshed() (NetInterface, error) {
+	ns, err := getNamespace(i.opts.Namespace)
+	if err != nil {
+		return NetInterface{}, errors.Wrap(err, "fail to get network namespace")
+	}
+	if ns == i.ns {
+		return i, nil
+	}
+
+	refreshed, err := NewNetworkInterfaceFromName(i.link.Attrs().Name, i.opts)
+	if err != nil {
+		return NetInterface{}, errors.Wrap(err, "fail to open the interface in the recreated network namespace")
+	}
+	refreshed.arp, refreshed.ndp = i.arp, i.ndp
+	return refreshed, nil
+}
+
 func (i NetInterface) hasIP(addr *netlink.Addr) (bool, error) {
-	addrs, err := netlink.AddrList(i.link, netlink.FAMILY_ALL)
+	addrs, err := i.ns.handle.AddrList(i.link, netlink.FAMILY_ALL)
 	if err != nil {
 		return false, errors.Wrap(err, "fail to list interface IPs")
 	}
@@ -50,12 +120,37 @@ func (i NetInterface) hasIP(addr *netlink.Addr) (bool, error) {
 	return false, nil
 }
 
+// isIPv6 returns true if the address is an IPv6 address
+func isIPv6(addr *netlink.Addr) bool {
+	return addr.IP.To4() == nil
+}
+
+// HasIP returns true if the IP is configured on the interface
+func (i NetInterface) HasIP(ip string) (bool, error) {
+	addr, err := netlink.ParseAddr(ip)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid IP: %s", ip)
+	}
+
+	i, err = i.refreshed()
+	if err != nil {
+		return false, err
+	}
+
+	return i.hasIP(addr)
+}
+
 func (i NetInterface) EnsureIP(ip string) error {
 	addr, err := netlink.ParseAddr(ip)
 	if err != nil {
 		return errors.Wrapf(err, "invalid IP: %s", ip)
 	}
 
+	i, err = i.refreshed()
+	if err != nil {
+		return err
+	}
+
 	has, err := i.hasIP(addr)
 	if err != nil {
 		return errors.Wrap(err, "fail to check if the IP is present")
@@ -69,10 +164,41 @@ func (i NetInterface) EnsureIP(ip string) error {
 			return errors.Wrap(err, "fail to add IP address")
 		}
 	}
-	// Send the gratuitous ARP request (it wont hurt anyone)
-	err = i.arp.GratuitousArp(GratuitousArpRequest{
-		IP:        addr.IP,
-		Interface: i.card,
+
+	if isIPv6(addr) && i.opts.IPv6DAD {
+		err := i.waitForDAD(addr)
+		if err != nil {
+			return errors.Wrap(err, "fail to wait for the duplicate address detection")
+		}
+	}
+	return nil
+}
+
+// Announce sends gratuitous ARP packets for IPv4 addresses and an unsolicited neighbor
+// advertisement for IPv6 addresses. The mode is ignored for IPv6 addresses.
+func (i NetInterface) Announce(ip string, mode ARPMode) error {
+	addr, err := netlink.ParseAddr(ip)
+	if err != nil {
+		return errors.Wrapf(err, "invalid IP: %s", ip)
+	}
+
+	i, err = i.refreshed()
+	if err != nil {
+		return err
+	}
+
+	err = i.ns.run(func() error {
+		if isIPv6(addr) {
+			return i.ndp.UnsolicitedNeighborAdvertisement(NeighborAdvertisementRequest{
+				IP:        addr.IP,
+				Interface: i.card,
+			})
+		}
+		return i.arp.GratuitousArp(GratuitousArpRequest{
+			IP:        addr.IP,
+			Interface: i.card,
+			Mode:      mode,
+		})
 	})
 	if err != nil {
 		return errors.Wrapf(err, "fail to announce our IP")
@@ -80,8 +206,52 @@ func (i NetInterface) EnsureIP(ip string) error {
 	return nil
 }
 
+// addressLabel returns the label of the IPv4 addresses added by LinK on the interface. The label of
+// an address must start with the name of the interface, if the name is too long no label is used.
+func addressLabel(linkName string) string {
+	label := linkName + addressLabelSuffix
+	if len(label) > maxAddressLabelLength {
+		return ""
+	}
+	return label
+}
+
+// ManagedIPs returns the IPv4 addresses of the interface carrying the LinK label, using the CIDR notation
+func (i NetInterface) ManagedIPs() ([]string, error) {
+	i, err := i.refreshed()
+	if err != nil {
+		return nil, err
+	}
+
+	label := addressLabel(i.link.Attrs().Name)
+	if label == "" {
+		return nil, nil
+	}
+
+	addrs, err := i.ns.handle.AddrList(i.link, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to list interface IPs")
+	}
+
+	var ips []string
+	for _, a := range addrs {
+		if a.Label == label {
+			ips = append(ips, a.IPNet.String())
+		}
+	}
+	return ips, nil
+}
+
 func (i NetInterface) addIP(addr *netlink.Addr) error {
-	err := netlink.AddrAdd(i.link, addr)
+	if isIPv6(addr) && !i.opts.IPv6DAD {
+		// Without DAD, the address is usable (and can be announced) immediately
+		addr.Flags |= unix.IFA_F_NODAD
+	}
+	if !isIPv6(addr) {
+		addr.Label = addressLabel(i.link.Attrs().Name)
+	}
+
+	err := i.ns.handle.AddrAdd(i.link, addr)
 	if err != nil {
 		return errors.Wrap(err, "fail to add address to the interface")
 	}
@@ -89,12 +259,56 @@ func (i NetInterface) addIP(addr *netlink.Addr) error {
 	return nil
 }
 
+// waitForDAD waits for the kernel to complete the duplicate address detection of an IPv6 address.
+// If another host on the link already uses this address, the address is removed from the interface.
+func (i NetInterface) waitForDAD(addr *netlink.Addr) error {
+	deadline := time.Now().Add(i.opts.IPv6DADTimeout)
+	for {
+		addrs, err := i.ns.handle.AddrList(i.link, netlink.FAMILY_V6)
+		if err != nil {
+			return errors.Wrap(err, "fail to list interface IPs")
+		}
+
+		var current *netlink.Addr
+		for _, a := range addrs {
+			if a.IP.Equal(addr.IP) {
+				current = &a
+				break
+			}
+		}
+		if current == nil {
+			return errors.New("address not found on the interface")
+		}
+
+		if current.Flags&unix.IFA_F_DADFAILED != 0 {
+			err := i.ns.handle.AddrDel(i.link, current)
+			if err != nil {
+				return errors.Wrap(err, "duplicate address detected, fail to remove it from the interface")
+			}
+			return errors.New("duplicate address detected on the link")
+		}
+		if current.Flags&unix.IFA_F_TENTATIVE == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("timeout waiting for the duplicate address detection")
+		}
+		time.Sleep(dadPollInterval)
+	}
+}
+
 func (i NetInterface) RemoveIP(ip string) error {
 	addr, err := netlink.ParseAddr(ip)
 	if err != nil {
 		return errors.Wrapf(err, "invalid IP: %s", ip)
 	}
 
+	i, err = i.refreshed()
+	if err != nil {
+		return err
+	}
+
 	has, err := i.hasIP(addr)
 	if err != nil {
 		return errors.WithMessage(err, "fail to check if the IP is already present")
@@ -104,10 +318,24 @@ func (i NetInterface) RemoveIP(ip string) error {
 		return nil
 	}
 
-	err = netlink.AddrDel(i.link, addr)
+	err = i.ns.handle.AddrDel(i.link, addr)
 	if err != nil {
 		return errors.Wrap(err, "fail to remove address from the interface")
 	}
 
 	return nil
 }
+
+// InterfaceExists returns an error if the interface does not exist in the network namespace
+func InterfaceExists(name string, namespace string) error {
+	ns, err := getNamespace(namespace)
+	if err != nil {
+		return errors.Wrap(err, "fail to get network namespace")
+	}
+
+	_, err = ns.handle.LinkByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "fail to open interface %s", name)
+	}
+	return nil
+}
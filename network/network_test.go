@@ -0,0 +1,20 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressLabel(t *testing.T) {
+	assert.Equal(t, "eth0:link", addressLabel("eth0"))
+	assert.Equal(t, "vlan1234:link", addressLabel("vlan1234"))
+	// The label would be longer than the 15 characters allowed by the kernel
+	assert.Empty(t, addressLabel("enp0s31f6.1234"))
+}
+
+func TestNamespacePath(t *testing.T) {
+	assert.Empty(t, NamespacePath(""))
+	assert.Equal(t, "/var/run/netns/tenant", NamespacePath("tenant"))
+	assert.Equal(t, "/proc/1234/ns/net", NamespacePath("/proc/1234/ns/net"))
+}
@@ -5,8 +5,11 @@
 package networkmock
 
 import (
+	context "context"
+	net "net"
 	reflect "reflect"
 
+	network "github.com/Scalingo/link/v3/network"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -34,6 +37,20 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Announce mocks base method.
+func (m *MockInterface) Announce(ip string, mode network.ARPMode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Announce", ip, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Announce indicates an expected call of Announce.
+func (mr *MockInterfaceMockRecorder) Announce(ip, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Announce", reflect.TypeOf((*MockInterface)(nil).Announce), ip, mode)
+}
+
 // EnsureIP mocks base method.
 func (m *MockInterface) EnsureIP(ip string) error {
 	m.ctrl.T.Helper()
@@ -48,6 +65,51 @@ func (mr *MockInterfaceMockRecorder) EnsureIP(ip any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIP", reflect.TypeOf((*MockInterface)(nil).EnsureIP), ip)
 }
 
+// HasIP mocks base method.
+func (m *MockInterface) HasIP(ip string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasIP", ip)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasIP indicates an expected call of HasIP.
+func (mr *MockInterfaceMockRecorder) HasIP(ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasIP", reflect.TypeOf((*MockInterface)(nil).HasIP), ip)
+}
+
+// ManagedIPs mocks base method.
+func (m *MockInterface) ManagedIPs() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedIPs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ManagedIPs indicates an expected call of ManagedIPs.
+func (mr *MockInterfaceMockRecorder) ManagedIPs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedIPs", reflect.TypeOf((*MockInterface)(nil).ManagedIPs))
+}
+
+// ProbeARP mocks base method.
+func (m *MockInterface) ProbeARP(ctx context.Context, ip string, opts network.ARPProbeOpts) (net.HardwareAddr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProbeARP", ctx, ip, opts)
+	ret0, _ := ret[0].(net.HardwareAddr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProbeARP indicates an expected call of ProbeARP.
+func (mr *MockInterfaceMockRecorder) ProbeARP(ctx, ip, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProbeARP", reflect.TypeOf((*MockInterface)(nil).ProbeARP), ctx, ip, opts)
+}
+
 // RemoveIP mocks base method.
 func (m *MockInterface) RemoveIP(ip string) error {
 	m.ctrl.T.Helper()
@@ -61,3 +123,17 @@ func (mr *MockInterfaceMockRecorder) RemoveIP(ip any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveIP", reflect.TypeOf((*MockInterface)(nil).RemoveIP), ip)
 }
+
+// WatchARPConflicts mocks base method.
+func (m *MockInterface) WatchARPConflicts(ctx context.Context, ip string, onConflict func(net.HardwareAddr)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchARPConflicts", ctx, ip, onConflict)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchARPConflicts indicates an expected call of WatchARPConflicts.
+func (mr *MockInterfaceMockRecorder) WatchARPConflicts(ctx, ip, onConflict any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchARPConflicts", reflect.TypeOf((*MockInterface)(nil).WatchARPConflicts), ctx, ip, onConflict)
+}
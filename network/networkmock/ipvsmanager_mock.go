@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/network (interfaces: IPVSManager)
+
+// Package networkmock is a generated GoMock package.
+package networkmock
+
+import (
+	reflect "reflect"
+
+	network "github.com/Scalingo/link/v3/network"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIPVSManager is a mock of IPVSManager interface.
+type MockIPVSManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPVSManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockIPVSManagerMockRecorder is the mock recorder for MockIPVSManager.
+type MockIPVSManagerMockRecorder struct {
+	mock *MockIPVSManager
+}
+
+// NewMockIPVSManager creates a new mock instance.
+func NewMockIPVSManager(ctrl *gomock.Controller) *MockIPVSManager {
+	mock := &MockIPVSManager{ctrl: ctrl}
+	mock.recorder = &MockIPVSManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPVSManager) EXPECT() *MockIPVSManagerMockRecorder {
+	return m.recorder
+}
+
+// EnsureRealServer mocks base method.
+func (m *MockIPVSManager) EnsureRealServer(service network.IPVSService, realServer network.IPVSRealServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureRealServer", service, realServer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureRealServer indicates an expected call of EnsureRealServer.
+func (mr *MockIPVSManagerMockRecorder) EnsureRealServer(service, realServer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureRealServer", reflect.TypeOf((*MockIPVSManager)(nil).EnsureRealServer), service, realServer)
+}
+
+// EnsureService mocks base method.
+func (m *MockIPVSManager) EnsureService(service network.IPVSService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureService", service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureService indicates an expected call of EnsureService.
+func (mr *MockIPVSManagerMockRecorder) EnsureService(service any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureService", reflect.TypeOf((*MockIPVSManager)(nil).EnsureService), service)
+}
+
+// HasService mocks base method.
+func (m *MockIPVSManager) HasService(service network.IPVSService) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasService", service)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasService indicates an expected call of HasService.
+func (mr *MockIPVSManagerMockRecorder) HasService(service any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasService", reflect.TypeOf((*MockIPVSManager)(nil).HasService), service)
+}
+
+// RealServers mocks base method.
+func (m *MockIPVSManager) RealServers(service network.IPVSService) ([]network.IPVSRealServer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RealServers", service)
+	ret0, _ := ret[0].([]network.IPVSRealServer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RealServers indicates an expected call of RealServers.
+func (mr *MockIPVSManagerMockRecorder) RealServers(service any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RealServers", reflect.TypeOf((*MockIPVSManager)(nil).RealServers), service)
+}
+
+// RemoveRealServer mocks base method.
+func (m *MockIPVSManager) RemoveRealServer(service network.IPVSService, realServer network.IPVSRealServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRealServer", service, realServer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRealServer indicates an expected call of RemoveRealServer.
+func (mr *MockIPVSManagerMockRecorder) RemoveRealServer(service, realServer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRealServer", reflect.TypeOf((*MockIPVSManager)(nil).RemoveRealServer), service, realServer)
+}
+
+// RemoveService mocks base method.
+func (m *MockIPVSManager) RemoveService(service network.IPVSService) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveService", service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveService indicates an expected call of RemoveService.
+func (mr *MockIPVSManagerMockRecorder) RemoveService(service any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveService", reflect.TypeOf((*MockIPVSManager)(nil).RemoveService), service)
+}
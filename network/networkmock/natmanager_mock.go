@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/network (interfaces: NATManager)
+
+// Package networkmock is a generated GoMock package.
+package networkmock
+
+import (
+	reflect "reflect"
+
+	network "github.com/Scalingo/link/v3/network"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNATManager is a mock of NATManager interface.
+type MockNATManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockNATManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockNATManagerMockRecorder is the mock recorder for MockNATManager.
+type MockNATManagerMockRecorder struct {
+	mock *MockNATManager
+}
+
+// NewMockNATManager creates a new mock instance.
+func NewMockNATManager(ctrl *gomock.Controller) *MockNATManager {
+	mock := &MockNATManager{ctrl: ctrl}
+	mock.recorder = &MockNATManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNATManager) EXPECT() *MockNATManagerMockRecorder {
+	return m.recorder
+}
+
+// EnsureRules mocks base method.
+func (m *MockNATManager) EnsureRules(owner string, rules []network.NATRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureRules", owner, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureRules indicates an expected call of EnsureRules.
+func (mr *MockNATManagerMockRecorder) EnsureRules(owner, rules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureRules", reflect.TypeOf((*MockNATManager)(nil).EnsureRules), owner, rules)
+}
+
+// HasRules mocks base method.
+func (m *MockNATManager) HasRules(owner string, rules []network.NATRule) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRules", owner, rules)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRules indicates an expected call of HasRules.
+func (mr *MockNATManagerMockRecorder) HasRules(owner, rules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRules", reflect.TypeOf((*MockNATManager)(nil).HasRules), owner, rules)
+}
+
+// RemoveRules mocks base method.
+func (m *MockNATManager) RemoveRules(owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRules", owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRules indicates an expected call of RemoveRules.
+func (mr *MockNATManagerMockRecorder) RemoveRules(owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRules", reflect.TypeOf((*MockNATManager)(nil).RemoveRules), owner)
+}
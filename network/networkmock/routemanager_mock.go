@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/network (interfaces: RouteManager)
+
+// Package networkmock is a generated GoMock package.
+package networkmock
+
+import (
+	reflect "reflect"
+
+	network "github.com/Scalingo/link/v3/network"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRouteManager is a mock of RouteManager interface.
+type MockRouteManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRouteManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockRouteManagerMockRecorder is the mock recorder for MockRouteManager.
+type MockRouteManagerMockRecorder struct {
+	mock *MockRouteManager
+}
+
+// NewMockRouteManager creates a new mock instance.
+func NewMockRouteManager(ctrl *gomock.Controller) *MockRouteManager {
+	mock := &MockRouteManager{ctrl: ctrl}
+	mock.recorder = &MockRouteManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouteManager) EXPECT() *MockRouteManagerMockRecorder {
+	return m.recorder
+}
+
+// EnsureRoute mocks base method.
+func (m *MockRouteManager) EnsureRoute(route network.Route) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureRoute", route)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureRoute indicates an expected call of EnsureRoute.
+func (mr *MockRouteManagerMockRecorder) EnsureRoute(route any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureRoute", reflect.TypeOf((*MockRouteManager)(nil).EnsureRoute), route)
+}
+
+// EnsureRule mocks base method.
+func (m *MockRouteManager) EnsureRule(rule network.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureRule indicates an expected call of EnsureRule.
+func (mr *MockRouteManagerMockRecorder) EnsureRule(rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureRule", reflect.TypeOf((*MockRouteManager)(nil).EnsureRule), rule)
+}
+
+// HasRoute mocks base method.
+func (m *MockRouteManager) HasRoute(route network.Route) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRoute", route)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRoute indicates an expected call of HasRoute.
+func (mr *MockRouteManagerMockRecorder) HasRoute(route any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRoute", reflect.TypeOf((*MockRouteManager)(nil).HasRoute), route)
+}
+
+// HasRule mocks base method.
+func (m *MockRouteManager) HasRule(rule network.Rule) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRule", rule)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRule indicates an expected call of HasRule.
+func (mr *MockRouteManagerMockRecorder) HasRule(rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRule", reflect.TypeOf((*MockRouteManager)(nil).HasRule), rule)
+}
+
+// RemoveRoute mocks base method.
+func (m *MockRouteManager) RemoveRoute(route network.Route) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRoute", route)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRoute indicates an expected call of RemoveRoute.
+func (mr *MockRouteManagerMockRecorder) RemoveRoute(route any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRoute", reflect.TypeOf((*MockRouteManager)(nil).RemoveRoute), route)
+}
+
+// RemoveRule mocks base method.
+func (m *MockRouteManager) RemoveRule(rule network.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRule indicates an expected call of RemoveRule.
+func (mr *MockRouteManagerMockRecorder) RemoveRule(rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRule", reflect.TypeOf((*MockRouteManager)(nil).RemoveRule), rule)
+}
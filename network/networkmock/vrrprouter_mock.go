@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/network (interfaces: VRRPRouter)
+
+// Package networkmock is a generated GoMock package.
+package networkmock
+
+import (
+	reflect "reflect"
+
+	network "github.com/Scalingo/link/v3/network"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockVRRPRouter is a mock of VRRPRouter interface.
+type MockVRRPRouter struct {
+	ctrl     *gomock.Controller
+	recorder *MockVRRPRouterMockRecorder
+	isgomock struct{}
+}
+
+// MockVRRPRouterMockRecorder is the mock recorder for MockVRRPRouter.
+type MockVRRPRouterMockRecorder struct {
+	mock *MockVRRPRouter
+}
+
+// NewMockVRRPRouter creates a new mock instance.
+func NewMockVRRPRouter(ctrl *gomock.Controller) *MockVRRPRouter {
+	mock := &MockVRRPRouter{ctrl: ctrl}
+	mock.recorder = &MockVRRPRouterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVRRPRouter) EXPECT() *MockVRRPRouterMockRecorder {
+	return m.recorder
+}
+
+// Advertise mocks base method.
+func (m *MockVRRPRouter) Advertise(advert network.VRRPAdvertisement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Advertise", advert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Advertise indicates an expected call of Advertise.
+func (mr *MockVRRPRouterMockRecorder) Advertise(advert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Advertise", reflect.TypeOf((*MockVRRPRouter)(nil).Advertise), advert)
+}
+
+// EnsureVirtualMAC mocks base method.
+func (m *MockVRRPRouter) EnsureVirtualMAC() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureVirtualMAC")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureVirtualMAC indicates an expected call of EnsureVirtualMAC.
+func (mr *MockVRRPRouterMockRecorder) EnsureVirtualMAC() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureVirtualMAC", reflect.TypeOf((*MockVRRPRouter)(nil).EnsureVirtualMAC))
+}
+
+// HasVirtualMAC mocks base method.
+func (m *MockVRRPRouter) HasVirtualMAC() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasVirtualMAC")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasVirtualMAC indicates an expected call of HasVirtualMAC.
+func (mr *MockVRRPRouterMockRecorder) HasVirtualMAC() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasVirtualMAC", reflect.TypeOf((*MockVRRPRouter)(nil).HasVirtualMAC))
+}
+
+// RemoveVirtualMAC mocks base method.
+func (m *MockVRRPRouter) RemoveVirtualMAC() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveVirtualMAC")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveVirtualMAC indicates an expected call of RemoveVirtualMAC.
+func (mr *MockVRRPRouterMockRecorder) RemoveVirtualMAC() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveVirtualMAC", reflect.TypeOf((*MockVRRPRouter)(nil).RemoveVirtualMAC))
+}
@@ -0,0 +1,478 @@
+package network
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"github.com/google/nftables/userdata"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// natRuleCommentPrefix prefixes the comment of the rules installed by LinK, the comment being
+	// link:<owner>:<hash of the rule>
+	natRuleCommentPrefix = "link:"
+
+	natPreroutingChain  = "prerouting"
+	natPostroutingChain = "postrouting"
+)
+
+// NATRuleType is the translation applied by a NAT rule
+type NATRuleType string
+
+const (
+	NATRuleTypeDNAT       NATRuleType = "dnat"
+	NATRuleTypeSNAT       NATRuleType = "snat"
+	NATRuleTypeMasquerade NATRuleType = "masquerade"
+)
+
+// IsValid returns true if the type is a known translation
+func (t NATRuleType) IsValid() bool {
+	return t == NATRuleTypeDNAT || t == NATRuleTypeSNAT || t == NATRuleTypeMasquerade
+}
+
+// NATRule is a NAT rule of the nftables table of LinK. DNAT rules are installed in the prerouting
+// chain, SNAT and masquerade rules in the postrouting chain.
+type NATRule struct {
+	Type NATRuleType
+	// Protocol is empty, tcp, udp or sctp. It is required to match or translate a port.
+	Protocol string
+	// Source and Destination are optional, the rule matches all the packets if they are empty
+	Source      *net.IPNet
+	Destination *net.IPNet
+	// Port is the destination port matched by the rule, 0 matches all the ports
+	Port uint16
+	// Interface is the input interface of DNAT rules and the output interface of SNAT and
+	// masquerade rules
+	Interface string
+	// ToAddress is the translated address of DNAT and SNAT rules
+	ToAddress net.IP
+	// ToPort is the translated port, 0 keeps the original port
+	ToPort uint16
+}
+
+// String returns the rule using the nft syntax (e.g. ip daddr 192.0.2.10/32 tcp dport 80 dnat to 10.0.0.1:8080)
+func (r NATRule) String() string {
+	var parts []string
+	if r.Interface != "" {
+		if r.Type == NATRuleTypeDNAT {
+			parts = append(parts, "iifname "+r.Interface)
+		} else {
+			parts = append(parts, "oifname "+r.Interface)
+		}
+	}
+	ipFamily := "ip"
+	if family, _ := natFamily(r); family == unix.NFPROTO_IPV6 {
+		ipFamily = "ip6"
+	}
+	if r.Source != nil {
+		parts = append(parts, ipFamily+" saddr "+r.Source.String())
+	}
+	if r.Destination != nil {
+		parts = append(parts, ipFamily+" daddr "+r.Destination.String())
+	}
+	if r.Protocol != "" {
+		if r.Port != 0 {
+			parts = append(parts, r.Protocol+" dport "+strconv.Itoa(int(r.Port)))
+		} else {
+			parts = append(parts, "meta l4proto "+r.Protocol)
+		}
+	}
+
+	switch r.Type {
+	case NATRuleTypeMasquerade:
+		parts = append(parts, "masquerade")
+		if r.ToPort != 0 {
+			parts = append(parts, "to :"+strconv.Itoa(int(r.ToPort)))
+		}
+	default:
+		to := r.ToAddress.String()
+		if r.ToPort != 0 {
+			to = net.JoinHostPort(to, strconv.Itoa(int(r.ToPort)))
+		}
+		parts = append(parts, string(r.Type)+" to "+to)
+	}
+	return strings.Join(parts, " ")
+}
+
+// NATManager manages the NAT rules of an nftables table dedicated to LinK. The rules are tagged
+// with their owner, the rules of other owners and the other tables are never modified.
+type NATManager interface {
+	// HasRules returns true if all the rules of the owner are installed
+	HasRules(owner string, rules []NATRule) (bool, error)
+	// EnsureRules adds the missing rules of the owner, and removes the rules of the owner which are
+	// not in rules
+	EnsureRules(owner string, rules []NATRule) error
+	// RemoveRules removes all the rules of the owner
+	RemoveRules(owner string) error
+}
+
+type nftablesNATManager struct {
+	namespace string
+	table     *nftables.Table
+}
+
+// NewNATManager returns a NATManager managing the rules of the given table, in the inet family, in
+// the network namespace: a name under /var/run/netns or a path. An empty namespace is the
+// namespace of LinK.
+func NewNATManager(namespace, table string) (NATManager, error) {
+	_, err := getNamespace(namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get network namespace")
+	}
+
+	return nftablesNATManager{
+		namespace: namespace,
+		table:     &nftables.Table{Name: table, Family: nftables.TableFamilyINet},
+	}, nil
+}
+
+// lock returns a connection to nftables in the namespace. The read-modify-write cycles of all the
+// managers of the namespace are serialized until unlock is called.
+func (m nftablesNATManager) lock() (*nftables.Conn, func(), error) {
+	ns, err := getNamespace(m.namespace)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fail to get network namespace")
+	}
+
+	ns.nftablesMutex.Lock()
+	var opts []nftables.ConnOption
+	if ns.path != "" {
+		opts = append(opts, nftables.WithNetNSFd(int(ns.ns)))
+	}
+	// The connection is not lasting: a netlink socket is opened for each operation and closed
+	// right after. A new connection is used for each cycle so that the pending messages of a
+	// failed cycle are never sent.
+	conn, err := nftables.New(opts...)
+	if err != nil {
+		ns.nftablesMutex.Unlock()
+		return nil, nil, errors.Wrap(err, "fail to open nftables netlink connection")
+	}
+	return conn, ns.nftablesMutex.Unlock, nil
+}
+
+// natRuleComment returns the comment tagging a rule of the owner
+func natRuleComment(owner string, rule NATRule) string {
+	h := fnv.New32a()
+	h.Write([]byte(rule.String()))
+	return fmt.Sprintf("%s%s:%08x", natRuleCommentPrefix, owner, h.Sum32())
+}
+
+// isOwnedBy returns true if the comment of an installed rule tags a rule of the owner
+func isOwnedBy(comment, owner string) bool {
+	hash, ok := strings.CutPrefix(comment, natRuleCommentPrefix+owner+":")
+	return ok && !strings.Contains(hash, ":")
+}
+
+func (m nftablesNATManager) chain(name string) *nftables.Chain {
+	chain := &nftables.Chain{Name: name, Table: m.table, Type: nftables.ChainTypeNAT}
+	if name == natPreroutingChain {
+		chain.Hooknum = nftables.ChainHookPrerouting
+		chain.Priority = nftables.ChainPriorityNATDest
+	} else {
+		chain.Hooknum = nftables.ChainHookPostrouting
+		chain.Priority = nftables.ChainPriorityNATSource
+	}
+	return chain
+}
+
+func (m nftablesNATManager) chainOf(rule NATRule) *nftables.Chain {
+	if rule.Type == NATRuleTypeDNAT {
+		return m.chain(natPreroutingChain)
+	}
+	return m.chain(natPostroutingChain)
+}
+
+// ownedRules returns the installed rules of the owner indexed by their comment. The table and the
+// chains may not exist yet.
+func (m nftablesNATManager) ownedRules(conn *nftables.Conn, owner string) (map[string][]*nftables.Rule, error) {
+	chains, err := conn.ListChainsOfTableFamily(nftables.TableFamilyINet)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to list nftables chains")
+	}
+
+	rules := make(map[string][]*nftables.Rule)
+	for _, chain := range chains {
+		if chain.Table.Name != m.table.Name || (chain.Name != natPreroutingChain && chain.Name != natPostroutingChain) {
+			continue
+		}
+		chainRules, err := conn.GetRules(m.table, chain)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to list rules of nftables chain %s", chain.Name)
+		}
+		for _, rule := range chainRules {
+			comment, ok := userdata.GetString(rule.UserData, userdata.TypeComment)
+			if !ok || !isOwnedBy(comment, owner) {
+				continue
+			}
+			rules[comment] = append(rules[comment], rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m nftablesNATManager) HasRules(owner string, rules []NATRule) (bool, error) {
+	conn, unlock, err := m.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	installed, err := m.ownedRules(conn, owner)
+	if err != nil {
+		return false, err
+	}
+	for _, rule := range rules {
+		if len(installed[natRuleComment(owner, rule)]) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m nftablesNATManager) EnsureRules(owner string, rules []NATRule) error {
+	conn, unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	installed, err := m.ownedRules(conn, owner)
+	if err != nil {
+		return err
+	}
+
+	desired := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		comment := natRuleComment(owner, rule)
+		if desired[comment] {
+			continue
+		}
+		desired[comment] = true
+		if len(installed[comment]) > 0 {
+			continue
+		}
+
+		exprs, err := natRuleExprs(rule)
+		if err != nil {
+			return errors.Wrapf(err, "invalid NAT rule %s", rule)
+		}
+		// The table and the chain are created if they do not exist yet, they are never removed as
+		// other owners may use them
+		conn.AddTable(m.table)
+		conn.AddChain(m.chainOf(rule))
+		conn.AddRule(&nftables.Rule{
+			Table:    m.table,
+			Chain:    m.chainOf(rule),
+			Exprs:    exprs,
+			UserData: userdata.AppendString(nil, userdata.TypeComment, comment),
+		})
+	}
+
+	for comment, installedRules := range installed {
+		for i, rule := range installedRules {
+			// The duplicates of a desired rule are removed as well
+			if desired[comment] && i == 0 {
+				continue
+			}
+			err = conn.DelRule(rule)
+			if err != nil {
+				return errors.Wrapf(err, "fail to remove nftables rule %s", comment)
+			}
+		}
+	}
+
+	err = conn.Flush()
+	if err != nil {
+		return errors.Wrapf(err, "fail to install the NAT rules in nftables table %s", m.table.Name)
+	}
+	return nil
+}
+
+func (m nftablesNATManager) RemoveRules(owner string) error {
+	conn, unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	installed, err := m.ownedRules(conn, owner)
+	if err != nil {
+		return err
+	}
+	for comment, installedRules := range installed {
+		for _, rule := range installedRules {
+			err = conn.DelRule(rule)
+			if err != nil {
+				return errors.Wrapf(err, "fail to remove nftables rule %s", comment)
+			}
+		}
+	}
+
+	err = conn.Flush()
+	if err != nil {
+		return errors.Wrapf(err, "fail to remove the NAT rules from nftables table %s", m.table.Name)
+	}
+	return nil
+}
+
+func ipOf(ipNet *net.IPNet) net.IP {
+	if ipNet == nil {
+		return nil
+	}
+	return ipNet.IP
+}
+
+// natFamily returns the NFPROTO family of the addresses of the rule, 0 if the rule has no address
+func natFamily(rule NATRule) (byte, error) {
+	var family byte
+	for _, ip := range []net.IP{rule.ToAddress, ipOf(rule.Source), ipOf(rule.Destination)} {
+		if ip == nil {
+			continue
+		}
+		ipFamily := byte(unix.NFPROTO_IPV4)
+		if ip.To4() == nil {
+			ipFamily = unix.NFPROTO_IPV6
+		}
+		if family != 0 && family != ipFamily {
+			return 0, errors.New("the addresses of the rule must be of the same IP family")
+		}
+		family = ipFamily
+	}
+	return family, nil
+}
+
+// natProtocol returns the IP protocol number of a protocol name
+func natProtocol(protocol string) (byte, error) {
+	switch protocol {
+	case "tcp":
+		return unix.IPPROTO_TCP, nil
+	case "udp":
+		return unix.IPPROTO_UDP, nil
+	case "sctp":
+		return unix.IPPROTO_SCTP, nil
+	}
+	return 0, errors.Errorf("unknown protocol %s, must be tcp, udp or sctp", protocol)
+}
+
+// natIP returns the IP with the length of its family
+func natIP(ip net.IP, family byte) []byte {
+	if family == unix.NFPROTO_IPV4 {
+		return ip.To4()
+	}
+	return ip.To16()
+}
+
+// natRuleExprs returns the expressions of the rule in a table of the inet family
+func natRuleExprs(rule NATRule) ([]expr.Any, error) {
+	if !rule.Type.IsValid() {
+		return nil, errors.Errorf("unknown NAT rule type %s", rule.Type)
+	}
+	family, err := natFamily(rule)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Type != NATRuleTypeMasquerade && rule.ToAddress == nil {
+		return nil, errors.Errorf("%s rule without address", rule.Type)
+	}
+	if (rule.Port != 0 || rule.ToPort != 0) && rule.Protocol == "" {
+		return nil, errors.New("a protocol is required to match or translate a port")
+	}
+
+	var exprs []expr.Any
+	if family != 0 {
+		exprs = append(exprs,
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{family}},
+		)
+	}
+	if rule.Interface != "" {
+		key := expr.MetaKeyOIFNAME
+		if rule.Type == NATRuleTypeDNAT {
+			key = expr.MetaKeyIIFNAME
+		}
+		// The interface names are compared with their NUL padding
+		name := make([]byte, unix.IFNAMSIZ)
+		copy(name, rule.Interface)
+		exprs = append(exprs,
+			&expr.Meta{Key: key, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: name},
+		)
+	}
+	if rule.Protocol != "" {
+		protocol, err := natProtocol(rule.Protocol)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs,
+			&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{protocol}},
+		)
+	}
+	if rule.Source != nil {
+		offset := uint32(12)
+		if family == unix.NFPROTO_IPV6 {
+			offset = 8
+		}
+		exprs = append(exprs, prefixMatchExprs(rule.Source, family, offset)...)
+	}
+	if rule.Destination != nil {
+		offset := uint32(16)
+		if family == unix.NFPROTO_IPV6 {
+			offset = 24
+		}
+		exprs = append(exprs, prefixMatchExprs(rule.Destination, family, offset)...)
+	}
+	if rule.Port != 0 {
+		exprs = append(exprs,
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.BigEndian.PutUint16(rule.Port)},
+		)
+	}
+
+	if rule.Type == NATRuleTypeMasquerade {
+		if rule.ToPort == 0 {
+			return append(exprs, &expr.Masq{}), nil
+		}
+		return append(exprs,
+			&expr.Immediate{Register: 1, Data: binaryutil.BigEndian.PutUint16(rule.ToPort)},
+			&expr.Masq{ToPorts: true, RegProtoMin: 1},
+		), nil
+	}
+
+	nat := &expr.NAT{Type: expr.NATTypeDestNAT, Family: uint32(family), RegAddrMin: 1}
+	if rule.Type == NATRuleTypeSNAT {
+		nat.Type = expr.NATTypeSourceNAT
+	}
+	exprs = append(exprs, &expr.Immediate{Register: 1, Data: natIP(rule.ToAddress, family)})
+	if rule.ToPort != 0 {
+		exprs = append(exprs, &expr.Immediate{Register: 2, Data: binaryutil.BigEndian.PutUint16(rule.ToPort)})
+		nat.RegProtoMin = 2
+		nat.Specified = true
+	}
+	return append(exprs, nat), nil
+}
+
+// prefixMatchExprs returns the expressions matching the address at the offset of the network
+// header with the prefix
+func prefixMatchExprs(prefix *net.IPNet, family byte, offset uint32) []expr.Any {
+	ip := natIP(prefix.IP.Mask(prefix.Mask), family)
+	exprs := []expr.Any{
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: uint32(len(ip))},
+	}
+	ones, bits := prefix.Mask.Size()
+	if ones != bits {
+		mask := net.CIDRMask(ones, len(ip)*8)
+		exprs = append(exprs, &expr.Bitwise{
+			SourceRegister: 1, DestRegister: 1, Len: uint32(len(ip)), Mask: mask, Xor: make([]byte, len(ip)),
+		})
+	}
+	return append(exprs, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ip})
+}
@@ -0,0 +1,115 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/nftables/expr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func mustParseCIDR(t *testing.T, s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	require.NoError(t, err)
+	return ipNet
+}
+
+func TestNATRule_String(t *testing.T) {
+	t.Run("with a DNAT rule", func(t *testing.T) {
+		rule := NATRule{
+			Type:        NATRuleTypeDNAT,
+			Protocol:    "tcp",
+			Destination: mustParseCIDR(t, "192.0.2.10/32"),
+			Port:        80,
+			Interface:   "eth0",
+			ToAddress:   net.ParseIP("10.0.0.1"),
+			ToPort:      8080,
+		}
+		assert.Equal(t, "iifname eth0 ip daddr 192.0.2.10/32 tcp dport 80 dnat to 10.0.0.1:8080", rule.String())
+	})
+
+	t.Run("with an IPv6 SNAT rule", func(t *testing.T) {
+		rule := NATRule{Type: NATRuleTypeSNAT, Source: mustParseCIDR(t, "fd00::/64"), ToAddress: net.ParseIP("2001:db8::10")}
+		assert.Equal(t, "ip6 saddr fd00::/64 snat to 2001:db8::10", rule.String())
+	})
+
+	t.Run("with a masquerade rule", func(t *testing.T) {
+		rule := NATRule{Type: NATRuleTypeMasquerade, Source: mustParseCIDR(t, "10.0.0.0/8"), Interface: "eth1"}
+		assert.Equal(t, "oifname eth1 ip saddr 10.0.0.0/8 masquerade", rule.String())
+	})
+}
+
+func TestNATRuleComment(t *testing.T) {
+	rule := NATRule{Type: NATRuleTypeMasquerade}
+	comment := natRuleComment("vip-1", rule)
+	assert.Regexp(t, `^link:vip-1:[0-9a-f]{8}$`, comment)
+	assert.Equal(t, comment, natRuleComment("vip-1", rule))
+	assert.NotEqual(t, comment, natRuleComment("vip-1", NATRule{Type: NATRuleTypeMasquerade, Interface: "eth0"}))
+
+	assert.True(t, isOwnedBy(comment, "vip-1"))
+	assert.False(t, isOwnedBy(comment, "vip"))
+	assert.False(t, isOwnedBy(natRuleComment("vip-1:2", rule), "vip-1"))
+	assert.False(t, isOwnedBy("managed by someone else", "vip-1"))
+}
+
+func TestNATRuleExprs(t *testing.T) {
+	t.Run("with a DNAT rule translating the port", func(t *testing.T) {
+		exprs, err := natRuleExprs(NATRule{
+			Type:        NATRuleTypeDNAT,
+			Protocol:    "tcp",
+			Destination: mustParseCIDR(t, "192.0.2.10/32"),
+			Port:        80,
+			ToAddress:   net.ParseIP("10.0.0.1"),
+			ToPort:      8080,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV4}},
+			&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.IPPROTO_TCP}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 16, Len: 4},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{192, 0, 2, 10}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{0, 80}},
+			&expr.Immediate{Register: 1, Data: []byte{10, 0, 0, 1}},
+			&expr.Immediate{Register: 2, Data: []byte{0x1f, 0x90}},
+			&expr.NAT{Type: expr.NATTypeDestNAT, Family: unix.NFPROTO_IPV4, RegAddrMin: 1, RegProtoMin: 2, Specified: true},
+		}, exprs)
+	})
+
+	t.Run("with a masquerade rule of a source prefix", func(t *testing.T) {
+		exprs, err := natRuleExprs(NATRule{Type: NATRuleTypeMasquerade, Source: mustParseCIDR(t, "10.1.0.0/16"), Interface: "eth0"})
+		require.NoError(t, err)
+		assert.Equal(t, []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV4}},
+			&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{'e', 't', 'h', '0', 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 12, Len: 4},
+			&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: []byte{255, 255, 0, 0}, Xor: []byte{0, 0, 0, 0}},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{10, 1, 0, 0}},
+			&expr.Masq{},
+		}, exprs)
+	})
+
+	t.Run("with an IPv6 SNAT rule", func(t *testing.T) {
+		exprs, err := natRuleExprs(NATRule{Type: NATRuleTypeSNAT, ToAddress: net.ParseIP("2001:db8::10")})
+		require.NoError(t, err)
+		require.Len(t, exprs, 4)
+		assert.Equal(t, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV6}}, exprs[1])
+		assert.Equal(t, &expr.NAT{Type: expr.NATTypeSourceNAT, Family: unix.NFPROTO_IPV6, RegAddrMin: 1}, exprs[3])
+	})
+
+	t.Run("with addresses of different families", func(t *testing.T) {
+		_, err := natRuleExprs(NATRule{Type: NATRuleTypeSNAT, Source: mustParseCIDR(t, "10.0.0.0/8"), ToAddress: net.ParseIP("2001:db8::10")})
+		require.ErrorContains(t, err, "same IP family")
+	})
+
+	t.Run("with a port without protocol", func(t *testing.T) {
+		_, err := natRuleExprs(NATRule{Type: NATRuleTypeDNAT, Port: 80, ToAddress: net.ParseIP("10.0.0.1")})
+		require.ErrorContains(t, err, "protocol is required")
+	})
+}
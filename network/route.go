@@ -0,0 +1,293 @@
+package network
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// maxRuleDeletions bounds the deletion of duplicated rules, the kernel accepting the same rule several times
+const maxRuleDeletions = 16
+
+// Route is a route of the routing tables
+type Route struct {
+	Destination *net.IPNet
+	// Gateway and/or Device must be set
+	Gateway net.IP
+	Device  string
+	Metric  int
+	Table   int
+	// Protocol is the originator of the route (see /etc/iproute2/rt_protos)
+	Protocol int
+}
+
+// Rule is a policy routing rule (ip rule)
+type Rule struct {
+	Priority int
+	// From and To are optional, the rule matches all the packets of the family if they are empty
+	From   *net.IPNet
+	To     *net.IPNet
+	IIF    string
+	FwMark uint32
+	Table  int
+	// IPv6 is used if neither From nor To is set
+	IPv6 bool
+}
+
+// RouteManager manages the routes and the policy routing rules of a network namespace
+type RouteManager interface {
+	HasRoute(route Route) (bool, error)
+	// EnsureRoute adds the route, or replaces the route with the same destination, table and metric
+	EnsureRoute(route Route) error
+	RemoveRoute(route Route) error
+
+	HasRule(rule Rule) (bool, error)
+	// EnsureRule adds the rule if it is not present yet
+	EnsureRule(rule Rule) error
+	RemoveRule(rule Rule) error
+}
+
+type netlinkRouteManager struct {
+	namespace string
+}
+
+// NewRouteManager returns a RouteManager managing the routes of the network namespace: a name
+// under /var/run/netns or a path. An empty namespace is the namespace of LinK.
+func NewRouteManager(namespace string) (RouteManager, error) {
+	_, err := getNamespace(namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get network namespace")
+	}
+	return netlinkRouteManager{namespace: namespace}, nil
+}
+
+// handle returns the netlink handle of the namespace. The namespace is looked up on each call since
+// it may have been recreated.
+func (m netlinkRouteManager) handle() (*netlink.Handle, error) {
+	ns, err := getNamespace(m.namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get network namespace")
+	}
+	return ns.handle, nil
+}
+
+// family returns the netlink family of an IP
+func family(ip net.IP) int {
+	if ip.To4() == nil {
+		return netlink.FAMILY_V6
+	}
+	return netlink.FAMILY_V4
+}
+
+func (m netlinkRouteManager) netlinkRoute(route Route) (*netlink.Route, error) {
+	nlRoute := &netlink.Route{
+		Dst:      route.Destination,
+		Gw:       route.Gateway,
+		Priority: route.Metric,
+		Table:    route.Table,
+		Protocol: netlink.RouteProtocol(route.Protocol),
+		Family:   family(route.Destination.IP),
+	}
+	if nlRoute.Table == 0 {
+		nlRoute.Table = unix.RT_TABLE_MAIN
+	}
+	if route.Device != "" {
+		handle, err := m.handle()
+		if err != nil {
+			return nil, err
+		}
+		link, err := handle.LinkByName(route.Device)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to find device %s", route.Device)
+		}
+		nlRoute.LinkIndex = link.Attrs().Index
+	}
+	return nlRoute, nil
+}
+
+func (m netlinkRouteManager) HasRoute(route Route) (bool, error) {
+	nlRoute, err := m.netlinkRoute(route)
+	if err != nil {
+		return false, err
+	}
+	handle, err := m.handle()
+	if err != nil {
+		return false, err
+	}
+
+	routes, err := handle.RouteListFiltered(nlRoute.Family, nlRoute, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_DST)
+	if err != nil {
+		return false, errors.Wrap(err, "fail to list routes")
+	}
+	for _, r := range routes {
+		if routeMatches(r, nlRoute) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// routeMatches returns true if the route of the table has the expected next hop, metric and protocol
+func routeMatches(route netlink.Route, expected *netlink.Route) bool {
+	if expected.Gw != nil && !route.Gw.Equal(expected.Gw) {
+		return false
+	}
+	if expected.LinkIndex != 0 && route.LinkIndex != expected.LinkIndex {
+		return false
+	}
+	return route.Priority == expected.Priority && route.Protocol == expected.Protocol
+}
+
+func (m netlinkRouteManager) EnsureRoute(route Route) error {
+	nlRoute, err := m.netlinkRoute(route)
+	if err != nil {
+		return err
+	}
+	handle, err := m.handle()
+	if err != nil {
+		return err
+	}
+
+	err = handle.RouteReplace(nlRoute)
+	if err != nil {
+		return errors.Wrapf(err, "fail to add route to %s", route.Destination)
+	}
+	return nil
+}
+
+func (m netlinkRouteManager) RemoveRoute(route Route) error {
+	has, err := m.HasRoute(route)
+	if err != nil {
+		return errors.Wrap(err, "fail to check if the route is present")
+	}
+	if !has {
+		return nil
+	}
+
+	nlRoute, err := m.netlinkRoute(route)
+	if err != nil {
+		return err
+	}
+	handle, err := m.handle()
+	if err != nil {
+		return err
+	}
+	err = handle.RouteDel(nlRoute)
+	if err != nil {
+		return errors.Wrapf(err, "fail to remove route to %s", route.Destination)
+	}
+	return nil
+}
+
+func (m netlinkRouteManager) netlinkRule(rule Rule) *netlink.Rule {
+	nlRule := netlink.NewRule()
+	nlRule.Priority = rule.Priority
+	nlRule.Table = rule.Table
+	nlRule.Src = rule.From
+	nlRule.Dst = rule.To
+	nlRule.IifName = rule.IIF
+	nlRule.Family = netlink.FAMILY_V4
+	if rule.IPv6 {
+		nlRule.Family = netlink.FAMILY_V6
+	}
+	if rule.From != nil {
+		nlRule.Family = family(rule.From.IP)
+	} else if rule.To != nil {
+		nlRule.Family = family(rule.To.IP)
+	}
+	if rule.FwMark != 0 {
+		nlRule.Mark = rule.FwMark
+		mask := uint32(0xffffffff)
+		nlRule.Mask = &mask
+	}
+	return nlRule
+}
+
+func (m netlinkRouteManager) HasRule(rule Rule) (bool, error) {
+	nlRule := m.netlinkRule(rule)
+	handle, err := m.handle()
+	if err != nil {
+		return false, err
+	}
+
+	rules, err := handle.RuleList(nlRule.Family)
+	if err != nil {
+		return false, errors.Wrap(err, "fail to list rules")
+	}
+	for _, r := range rules {
+		if ruleMatches(r, nlRule) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ruleMatches returns true if the rule has the same selector and table
+func ruleMatches(rule netlink.Rule, expected *netlink.Rule) bool {
+	return rule.Priority == expected.Priority &&
+		rule.Table == expected.Table &&
+		ipNetEqual(rule.Src, expected.Src) &&
+		ipNetEqual(rule.Dst, expected.Dst) &&
+		rule.IifName == expected.IifName &&
+		rule.Mark == expected.Mark
+}
+
+// ipNetEqual compares two networks, nil being equal to an empty network
+func ipNetEqual(a, b *net.IPNet) bool {
+	aSize, bSize := 0, 0
+	if a != nil {
+		aSize, _ = a.Mask.Size()
+	}
+	if b != nil {
+		bSize, _ = b.Mask.Size()
+	}
+	if aSize == 0 || bSize == 0 {
+		return aSize == bSize
+	}
+	return a.IP.Equal(b.IP) && aSize == bSize
+}
+
+func (m netlinkRouteManager) EnsureRule(rule Rule) error {
+	has, err := m.HasRule(rule)
+	if err != nil {
+		return errors.Wrap(err, "fail to check if the rule is present")
+	}
+	if has {
+		return nil
+	}
+	handle, err := m.handle()
+	if err != nil {
+		return err
+	}
+
+	err = handle.RuleAdd(m.netlinkRule(rule))
+	if err != nil {
+		return errors.Wrapf(err, "fail to add rule with priority %d", rule.Priority)
+	}
+	return nil
+}
+
+func (m netlinkRouteManager) RemoveRule(rule Rule) error {
+	// The same rule may have been added several times
+	for range maxRuleDeletions {
+		has, err := m.HasRule(rule)
+		if err != nil {
+			return errors.Wrap(err, "fail to check if the rule is present")
+		}
+		if !has {
+			return nil
+		}
+		handle, err := m.handle()
+		if err != nil {
+			return err
+		}
+
+		err = handle.RuleDel(m.netlinkRule(rule))
+		if err != nil {
+			return errors.Wrapf(err, "fail to remove rule with priority %d", rule.Priority)
+		}
+	}
+	return errors.Errorf("rule with priority %d still present after %d deletions", rule.Priority, maxRuleDeletions)
+}
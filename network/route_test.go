@@ -0,0 +1,35 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func TestRouteMatches(t *testing.T) {
+	expected := &netlink.Route{Gw: net.ParseIP("10.0.0.1"), Priority: 10, Protocol: 4}
+
+	assert.True(t, routeMatches(netlink.Route{Gw: net.ParseIP("10.0.0.1"), Priority: 10, Protocol: 4, LinkIndex: 2}, expected))
+	assert.False(t, routeMatches(netlink.Route{Gw: net.ParseIP("10.0.0.2"), Priority: 10, Protocol: 4}, expected))
+	assert.False(t, routeMatches(netlink.Route{Gw: net.ParseIP("10.0.0.1"), Priority: 20, Protocol: 4}, expected))
+	assert.False(t, routeMatches(netlink.Route{Gw: net.ParseIP("10.0.0.1"), Priority: 10, Protocol: 3}, expected))
+
+	expected = &netlink.Route{LinkIndex: 3, Protocol: 4}
+	assert.True(t, routeMatches(netlink.Route{LinkIndex: 3, Protocol: 4}, expected))
+	assert.False(t, routeMatches(netlink.Route{LinkIndex: 2, Protocol: 4}, expected))
+}
+
+func TestRuleMatches(t *testing.T) {
+	_, from, _ := net.ParseCIDR("203.0.113.0/24")
+	expected := &netlink.Rule{Priority: 1000, Table: 100, Src: from}
+
+	assert.True(t, ruleMatches(netlink.Rule{Priority: 1000, Table: 100, Src: from}, expected))
+	assert.False(t, ruleMatches(netlink.Rule{Priority: 1000, Table: 100}, expected))
+	assert.False(t, ruleMatches(netlink.Rule{Priority: 1001, Table: 100, Src: from}, expected))
+
+	// The kernel returns an empty network for the rules matching all the packets
+	_, all, _ := net.ParseCIDR("0.0.0.0/0")
+	assert.True(t, ruleMatches(netlink.Rule{Priority: 1000, Table: 100, Dst: all, Src: from}, expected))
+}
@@ -0,0 +1,358 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	vrrpProtocol          = 112
+	vrrpVersion           = 3
+	vrrpTypeAdvertisement = 1
+	vrrpHeaderLength      = 8
+	// vrrpChecksumOffset is the offset of the checksum in the VRRP header
+	vrrpChecksumOffset = 6
+	// VRRPMaxAdvertInterval is the maximum advertisement interval, sent in centiseconds on 12 bits
+	VRRPMaxAdvertInterval = 4095 * 10 * time.Millisecond
+)
+
+var (
+	// vrrpMulticastIPv4 and vrrpMulticastIPv6 are the destinations of the VRRP advertisements (RFC 5798 section 5.1)
+	vrrpMulticastIPv4 = net.ParseIP("224.0.0.18")
+	vrrpMulticastIPv6 = net.ParseIP("ff02::12")
+)
+
+// VRRPAdvertisement is the content of a VRRPv3 advertisement (RFC 5798)
+type VRRPAdvertisement struct {
+	// Priority 0 tells the backup routers that the master stopped
+	Priority uint8
+	Interval time.Duration
+	// Addresses are the virtual IPs of the virtual router, all of the family of the virtual router
+	Addresses []net.IP
+}
+
+// VRRPRouter sends the advertisements of a virtual router on an interface. The election is not
+// handled by the router: it only advertises that the current host is the master.
+type VRRPRouter interface {
+	Advertise(advert VRRPAdvertisement) error
+
+	// EnsureVirtualMAC adds the macvlan interface owning the virtual MAC address of the virtual
+	// router. The advertisements are then sent from this interface. For IPv4, the interface and its
+	// parent only answer the ARP requests for their own addresses, so that the virtual IPs added to
+	// the macvlan interface are resolved to the virtual MAC address.
+	EnsureVirtualMAC() error
+	RemoveVirtualMAC() error
+	HasVirtualMAC() (bool, error)
+}
+
+type VRRPRouterOpts struct {
+	// Interface is the interface where the advertisements are sent
+	Interface string
+	// Namespace is the network namespace of the interface: a name under /var/run/netns or a path.
+	// Defaults to the namespace of LinK.
+	Namespace string
+	VRID      uint8
+	IPv6      bool
+}
+
+type vrrpRouter struct {
+	opts VRRPRouterOpts
+	ns   *namespace
+}
+
+// NewVRRPRouter returns a VRRPRouter using raw IP sockets. A socket is opened per advertisement, so
+// that a router does not hold any resource while the endpoint is not activated.
+func NewVRRPRouter(opts VRRPRouterOpts) (VRRPRouter, error) {
+	if opts.VRID == 0 {
+		return nil, errors.New("the VRID must be between 1 and 255")
+	}
+	ns, err := getNamespace(opts.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to get network namespace")
+	}
+	_, err = ns.handle.LinkByName(opts.Interface)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open interface %s", opts.Interface)
+	}
+	return vrrpRouter{opts: opts, ns: ns}, nil
+}
+
+// VirtualMAC returns the virtual MAC address of a virtual router (RFC 5798 section 7.3)
+func VirtualMAC(vrid uint8, ipv6 bool) net.HardwareAddr {
+	if ipv6 {
+		return net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x02, vrid}
+	}
+	return net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x01, vrid}
+}
+
+// VirtualMACInterfaceName returns the name of the macvlan interface owning the virtual MAC address
+// of a virtual router, as named by keepalived
+func VirtualMACInterfaceName(vrid uint8, ipv6 bool) string {
+	if ipv6 {
+		return fmt.Sprintf("vrrp6.%d", vrid)
+	}
+	return fmt.Sprintf("vrrp.%d", vrid)
+}
+
+func (r vrrpRouter) virtualMACInterfaceName() string {
+	return VirtualMACInterfaceName(r.opts.VRID, r.opts.IPv6)
+}
+
+func (r vrrpRouter) Advertise(advert VRRPAdvertisement) error {
+	if advert.Interval <= 0 || advert.Interval > VRRPMaxAdvertInterval {
+		return errors.Errorf("invalid advertisement interval %s", advert.Interval)
+	}
+
+	parent, err := r.ns.handle.LinkByName(r.opts.Interface)
+	if err != nil {
+		return errors.Wrapf(err, "fail to open interface %s", r.opts.Interface)
+	}
+
+	// The advertisements are sent from the interface owning the virtual MAC if it exists
+	outIndex := parent.Attrs().Index
+	virtualMACLink, err := r.virtualMACLink()
+	if err != nil {
+		return err
+	}
+	if virtualMACLink != nil {
+		outIndex = virtualMACLink.Attrs().Index
+	}
+
+	if r.opts.IPv6 {
+		return r.advertiseIPv6(outIndex, vrrpAdvertisement(r.opts.VRID, advert))
+	}
+
+	// The source of the advertisements is the primary address of the interface, it is needed to
+	// compute the checksum
+	addrs, err := r.ns.handle.AddrList(parent, netlink.FAMILY_V4)
+	if err != nil {
+		return errors.Wrap(err, "fail to list interface IPs")
+	}
+	var source net.IP
+	for _, addr := range addrs {
+		if addr.Flags&unix.IFA_F_SECONDARY == 0 {
+			source = addr.IP.To4()
+			break
+		}
+	}
+	if source == nil {
+		return errors.Errorf("no IPv4 address on interface %s", r.opts.Interface)
+	}
+
+	msg := vrrpAdvertisement(r.opts.VRID, advert)
+	binary.BigEndian.PutUint16(msg[vrrpChecksumOffset:], vrrpChecksumIPv4(source, vrrpMulticastIPv4, msg))
+	return r.advertiseIPv4(outIndex, source, msg)
+}
+
+func (r vrrpRouter) advertiseIPv4(outIndex int, source net.IP, msg []byte) error {
+	return r.ns.run(func() error {
+		fd, err := unix.Socket(unix.AF_INET, unix.SOCK_RAW|unix.SOCK_CLOEXEC, vrrpProtocol)
+		if err != nil {
+			return errors.Wrap(err, "fail to open VRRP socket")
+		}
+		defer unix.Close(fd)
+
+		// RFC 5798 section 5.1.1.3: the advertisements are sent with a TTL of 255
+		err = unix.SetsockoptInt(fd, unix.IPPROTO_IP, unix.IP_MULTICAST_TTL, 255)
+		if err != nil {
+			return errors.Wrap(err, "fail to set the TTL")
+		}
+		err = unix.SetsockoptIPMreqn(fd, unix.IPPROTO_IP, unix.IP_MULTICAST_IF, &unix.IPMreqn{Ifindex: int32(outIndex)})
+		if err != nil {
+			return errors.Wrap(err, "fail to set the multicast interface")
+		}
+		src := &unix.SockaddrInet4{}
+		copy(src.Addr[:], source)
+		err = unix.Bind(fd, src)
+		if err != nil {
+			return errors.Wrapf(err, "fail to bind VRRP socket to %s", source)
+		}
+
+		dst := &unix.SockaddrInet4{}
+		copy(dst.Addr[:], vrrpMulticastIPv4.To4())
+		err = unix.Sendto(fd, msg, 0, dst)
+		if err != nil {
+			return errors.Wrap(err, "fail to send the VRRP advertisement")
+		}
+		return nil
+	})
+}
+
+func (r vrrpRouter) advertiseIPv6(outIndex int, msg []byte) error {
+	return r.ns.run(func() error {
+		fd, err := unix.Socket(unix.AF_INET6, unix.SOCK_RAW|unix.SOCK_CLOEXEC, vrrpProtocol)
+		if err != nil {
+			return errors.Wrap(err, "fail to open VRRP socket")
+		}
+		defer unix.Close(fd)
+
+		err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, 255)
+		if err != nil {
+			return errors.Wrap(err, "fail to set the hop limit")
+		}
+		err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_IF, outIndex)
+		if err != nil {
+			return errors.Wrap(err, "fail to set the multicast interface")
+		}
+		// The checksum is computed by the kernel, with the link-local source address it selects
+		err = unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_CHECKSUM, vrrpChecksumOffset)
+		if err != nil {
+			return errors.Wrap(err, "fail to enable the checksum computation")
+		}
+
+		dst := &unix.SockaddrInet6{ZoneId: uint32(outIndex)}
+		copy(dst.Addr[:], vrrpMulticastIPv6.To16())
+		err = unix.Sendto(fd, msg, 0, dst)
+		if err != nil {
+			return errors.Wrap(err, "fail to send the VRRP advertisement")
+		}
+		return nil
+	})
+}
+
+// vrrpAdvertisement builds a VRRPv3 advertisement (RFC 5798 section 5.2) with an empty checksum
+func vrrpAdvertisement(vrid uint8, advert VRRPAdvertisement) []byte {
+	msg := make([]byte, vrrpHeaderLength, vrrpHeaderLength+len(advert.Addresses)*net.IPv6len)
+	msg[0] = vrrpVersion<<4 | vrrpTypeAdvertisement
+	msg[1] = vrid
+	msg[2] = advert.Priority
+	msg[3] = byte(len(advert.Addresses))
+	// The 4 first bits are reserved, the interval is sent in centiseconds
+	binary.BigEndian.PutUint16(msg[4:6], uint16(advert.Interval/(10*time.Millisecond))&0x0fff)
+
+	for _, addr := range advert.Addresses {
+		if ip := addr.To4(); ip != nil {
+			msg = append(msg, ip...)
+		} else {
+			msg = append(msg, addr.To16()...)
+		}
+	}
+	return msg
+}
+
+// vrrpChecksumIPv4 computes the checksum of a VRRPv3 message sent over IPv4, which includes the
+// IPv4 pseudo-header
+func vrrpChecksumIPv4(source, destination net.IP, msg []byte) uint16 {
+	pseudoHeader := make([]byte, 0, 12+len(msg))
+	pseudoHeader = append(pseudoHeader, source.To4()...)
+	pseudoHeader = append(pseudoHeader, destination.To4()...)
+	pseudoHeader = append(pseudoHeader, 0, vrrpProtocol)
+	pseudoHeader = binary.BigEndian.AppendUint16(pseudoHeader, uint16(len(msg)))
+	return internetChecksum(append(pseudoHeader, msg...))
+}
+
+// internetChecksum computes the checksum of RFC 1071
+func internetChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return ^uint16(sum)
+}
+
+// virtualMACLink returns the macvlan interface owning the virtual MAC, or nil if it does not exist
+func (r vrrpRouter) virtualMACLink() (netlink.Link, error) {
+	link, err := r.ns.handle.LinkByName(r.virtualMACInterfaceName())
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "fail to open interface %s", r.virtualMACInterfaceName())
+	}
+	return link, nil
+}
+
+func (r vrrpRouter) HasVirtualMAC() (bool, error) {
+	link, err := r.virtualMACLink()
+	if err != nil {
+		return false, err
+	}
+	return link != nil && link.Attrs().Flags&net.FlagUp != 0, nil
+}
+
+func (r vrrpRouter) EnsureVirtualMAC() error {
+	link, err := r.virtualMACLink()
+	if err != nil {
+		return err
+	}
+
+	if link == nil {
+		parent, err := r.ns.handle.LinkByName(r.opts.Interface)
+		if err != nil {
+			return errors.Wrapf(err, "fail to open interface %s", r.opts.Interface)
+		}
+
+		link = &netlink.Macvlan{
+			LinkAttrs: netlink.LinkAttrs{
+				Name:         r.virtualMACInterfaceName(),
+				ParentIndex:  parent.Attrs().Index,
+				HardwareAddr: VirtualMAC(r.opts.VRID, r.opts.IPv6),
+			},
+			Mode: netlink.MACVLAN_MODE_PRIVATE,
+		}
+		err = r.ns.handle.LinkAdd(link)
+		if err != nil {
+			return errors.Wrapf(err, "fail to add interface %s", r.virtualMACInterfaceName())
+		}
+	}
+
+	if !r.opts.IPv6 {
+		for _, name := range []string{r.opts.Interface, r.virtualMACInterfaceName()} {
+			err = r.setARPIgnore(name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = r.ns.handle.LinkSetUp(link)
+	if err != nil {
+		return errors.Wrapf(err, "fail to set interface %s up", r.virtualMACInterfaceName())
+	}
+	return nil
+}
+
+// setARPIgnore makes the interface only answer the ARP requests for the addresses configured on it.
+// By default, Linux answers the ARP requests for any address of the host on all the interfaces: the
+// parent interface would answer for the virtual IPs with its own MAC address.
+func (r vrrpRouter) setARPIgnore(name string) error {
+	return r.ns.run(func() error {
+		// The sysctls under /proc/sys/net are those of the network namespace of the current thread
+		err := os.WriteFile(filepath.Join("/proc/sys/net/ipv4/conf", name, "arp_ignore"), []byte("1"), 0o644)
+		if err != nil {
+			return errors.Wrapf(err, "fail to set arp_ignore on interface %s", name)
+		}
+		return nil
+	})
+}
+
+func (r vrrpRouter) RemoveVirtualMAC() error {
+	link, err := r.virtualMACLink()
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return nil
+	}
+
+	err = r.ns.handle.LinkDel(link)
+	if err != nil {
+		return errors.Wrapf(err, "fail to remove interface %s", r.virtualMACInterfaceName())
+	}
+	return nil
+}
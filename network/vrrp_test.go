@@ -0,0 +1,64 @@
+package network
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVRRPAdvertisement(t *testing.T) {
+	t.Run("with IPv4 addresses", func(t *testing.T) {
+		msg := vrrpAdvertisement(51, VRRPAdvertisement{
+			Priority:  100,
+			Interval:  time.Second,
+			Addresses: []net.IP{net.ParseIP("192.0.2.10"), net.ParseIP("192.0.2.11")},
+		})
+
+		expected := []byte{
+			0x31, 51, 100, 2, // Version and type, VRID, priority and number of addresses
+			0x00, 100, 0, 0, // Interval in centiseconds and checksum
+			192, 0, 2, 10,
+			192, 0, 2, 11,
+		}
+		assert.Equal(t, expected, msg)
+	})
+
+	t.Run("with an IPv6 address", func(t *testing.T) {
+		msg := vrrpAdvertisement(1, VRRPAdvertisement{
+			Priority:  0,
+			Interval:  VRRPMaxAdvertInterval,
+			Addresses: []net.IP{net.ParseIP("2001:db8::10")},
+		})
+
+		expected := []byte{
+			0x31, 1, 0, 1,
+			0x0f, 0xff, 0, 0,
+			0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+		}
+		assert.Equal(t, expected, msg)
+	})
+}
+
+func TestVRRPChecksumIPv4(t *testing.T) {
+	source := net.ParseIP("192.0.2.1")
+	msg := vrrpAdvertisement(51, VRRPAdvertisement{
+		Priority:  100,
+		Interval:  time.Second,
+		Addresses: []net.IP{net.ParseIP("192.0.2.10")},
+	})
+	binary.BigEndian.PutUint16(msg[vrrpChecksumOffset:], vrrpChecksumIPv4(source, vrrpMulticastIPv4, msg))
+
+	// The checksum of a message including its checksum is 0
+	assert.Equal(t, uint16(0), vrrpChecksumIPv4(source, vrrpMulticastIPv4, msg))
+	assert.Equal(t, uint16(0x05cc), binary.BigEndian.Uint16(msg[vrrpChecksumOffset:]))
+}
+
+func TestVirtualMAC(t *testing.T) {
+	assert.Equal(t, "00:00:5e:00:01:33", VirtualMAC(51, false).String())
+	assert.Equal(t, "00:00:5e:00:02:33", VirtualMAC(51, true).String())
+	assert.Equal(t, "vrrp.51", VirtualMACInterfaceName(51, false))
+	assert.Equal(t, "vrrp6.51", VirtualMACInterfaceName(51, true))
+}
@@ -2,7 +2,10 @@ package arp
 
 import (
 	"context"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Scalingo/go-utils/errors/v2"
 	"github.com/Scalingo/go-utils/logger"
@@ -10,28 +13,78 @@ import (
 	"github.com/Scalingo/link/v3/network"
 )
 
+// garpSettings configures how the IPs are announced on the network
+type garpSettings struct {
+	count           int
+	interval        time.Duration
+	refreshInterval time.Duration
+	mode            network.ARPMode
+}
+
 type Plugin struct {
 	config       Config
 	netInterface network.Interface
 
 	endpoint models.Endpoint
-	ip       string
+	ips      []string
+	// netns is the path of the network namespace of the interface, empty for the namespace of LinK
+	netns string
+
+	garp            garpSettings
+	lastAnnouncedAt time.Time
 
-	garpCount int
+	// ARP conflict detection, conflicts are reported by the watchers running in their own goroutines
+	conflictsMutex        sync.Mutex
+	conflicts             map[string]conflict
+	stopWatchingConflicts context.CancelFunc
 }
 
+// Activate adds all the IPs of the endpoint to the network interface. The activation is all or
+// nothing: if one of the IPs cannot be added, the ones added before are removed.
+//
+// If the conflict detection is enabled, ARP probes are sent before adding the IPv4 addresses and
+// the activation fails if another host already uses one of them.
 func (p *Plugin) Activate(ctx context.Context) error {
-	p.garpCount = 0
-	err := p.netInterface.EnsureIP(p.ip)
+	log := logger.Get(ctx)
+	p.lastAnnouncedAt = time.Time{}
+
+	if p.config.ARPConflictDetection {
+		err := p.probeConflicts(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "detect address conflicts")
+		}
+	}
+
+	for i, ip := range p.ips {
+		err := p.netInterface.EnsureIP(ip)
+		if err == nil {
+			continue
+		}
+
+		for _, addedIP := range p.ips[:i] {
+			rollbackErr := p.netInterface.RemoveIP(addedIP)
+			if rollbackErr != nil {
+				log.WithError(rollbackErr).WithField("ip", addedIP).Error("Fail to remove IP during the activation rollback")
+			}
+		}
+		return errors.Wrapf(ctx, err, "activate IP %s on network interface", ip)
+	}
+
+	if p.config.ARPConflictDetection {
+		p.startWatchingConflicts(ctx)
+	}
+
+	err := p.announce(ctx)
 	if err != nil {
-		return errors.Wrap(ctx, err, "activate IP on network interface")
+		return errors.Wrap(ctx, err, "announce IPs")
 	}
 	return nil
 }
 
 func (p *Plugin) Deactivate(ctx context.Context) error {
-	p.garpCount = 0
-	err := p.netInterface.RemoveIP(p.ip)
+	p.lastAnnouncedAt = time.Time{}
+	p.stopWatching()
+	err := p.removeIPs(ctx)
 	if err != nil {
 		return errors.Wrap(ctx, err, "disable IP on network interface")
 	}
@@ -41,21 +94,109 @@ func (p *Plugin) Deactivate(ctx context.Context) error {
 func (p *Plugin) Ensure(ctx context.Context) error {
 	ctx, log := logger.WithFieldToCtx(ctx, "plugin", "arp")
 
-	if p.garpCount >= p.config.ARPGratuitousCount {
-		log.Debug("All gratuitous ARP requests sent for this activation")
+	if p.config.ARPConflictDetection {
+		// The endpoint may have been resumed after a restart without calling Activate
+		p.startWatchingConflicts(ctx)
+	}
+
+	for _, ip := range p.ips {
+		err := p.netInterface.EnsureIP(ip)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "ensure IP %s on network interface", ip)
+		}
+	}
+
+	// The endpoint may have been resumed after a restart, in this case the IPs have never been announced
+	refreshDue := p.garp.refreshInterval > 0 && time.Since(p.lastAnnouncedAt) >= p.garp.refreshInterval
+	if !p.lastAnnouncedAt.IsZero() && !refreshDue {
+		log.Debug("IPs already announced")
 		return nil
 	}
 
-	log.Info("Send gratuitous ARP request")
-	err := p.netInterface.EnsureIP(p.ip)
+	err := p.announce(ctx)
 	if err != nil {
-		return errors.Wrap(ctx, err, "send gratuitous ARP")
+		return errors.Wrap(ctx, err, "announce IPs")
 	}
-	p.garpCount++
+	return nil
+}
+
+// announce sends a burst of gratuitous ARP packets (or unsolicited neighbor advertisements for
+// IPv6 addresses) for all the IPs of the endpoint
+func (p *Plugin) announce(ctx context.Context) error {
+	log := logger.Get(ctx)
+	log.WithField("garp_mode", p.garp.mode).Info("Announce IPs (gratuitous ARP or unsolicited neighbor advertisement)")
 
+	for n := 0; n < p.garp.count; n++ {
+		if n > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(p.garp.interval):
+			}
+		}
+
+		for _, ip := range p.ips {
+			err := p.netInterface.Announce(ip, p.garp.mode)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "announce IP %s", ip)
+			}
+		}
+	}
+
+	p.lastAnnouncedAt = time.Now()
 	return nil
 }
 
+// IsActivated returns true if all the IPs are present on the network interface
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	for _, ip := range p.ips {
+		has, err := p.netInterface.HasIP(ip)
+		if err != nil {
+			return false, errors.Wrapf(ctx, err, "check IP %s on network interface", ip)
+		}
+		if !has {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// EnsureDeactivated removes the IPs from the network interface if they are still present
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	p.stopWatching()
+
+	err := p.removeIPs(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove IP from network interface")
+	}
+	return nil
+}
+
+// removeIPs tries to remove all the IPs, even if the removal of one of them fails
+func (p *Plugin) removeIPs(ctx context.Context) error {
+	var firstErr error
+	for _, ip := range p.ips {
+		err := p.netInterface.RemoveIP(ip)
+		if err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(ctx, err, "remove IP %s", ip)
+		}
+	}
+	return firstErr
+}
+
+// ElectionKey is based on the IPs of the endpoint. The key of an endpoint with a single IP is kept
+// unchanged for backward compatibility. The same IPs in different network namespaces are different
+// endpoints, so the namespace is prepended to the key.
 func (p *Plugin) ElectionKey(_ context.Context) string {
-	return strings.ReplaceAll(p.ip, "/", "_")
+	keys := make([]string, 0, len(p.ips))
+	for _, ip := range p.ips {
+		keys = append(keys, strings.ReplaceAll(ip, "/", "_"))
+	}
+	slices.Sort(keys)
+	key := strings.Join(keys, ",")
+	if p.netns != "" {
+		netns := strings.TrimPrefix(p.netns, network.NetnsDir+"/")
+		key = strings.ReplaceAll(netns, "/", "_") + ":" + key
+	}
+	return key
 }
@@ -0,0 +1,143 @@
+package arp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it adds all the IPs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil)
+		netInterface.EXPECT().EnsureIP("2001:db8::1/128").Return(nil)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32", "2001:db8::1/128"}}
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it removes the IPs already added if one of them fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		gomock.InOrder(
+			netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil),
+			netInterface.EXPECT().EnsureIP("10.0.0.2/32").Return(nil),
+			netInterface.EXPECT().EnsureIP("10.0.0.3/32").Return(errors.New("netlink error")),
+			netInterface.EXPECT().RemoveIP("10.0.0.1/32").Return(nil),
+			netInterface.EXPECT().RemoveIP("10.0.0.2/32").Return(nil),
+		)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32", "10.0.0.2/32", "10.0.0.3/32"}}
+		err := p.Activate(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "10.0.0.3/32")
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	t.Run("it tries to remove all the IPs even if one of them fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().RemoveIP("10.0.0.1/32").Return(errors.New("netlink error"))
+		netInterface.EXPECT().RemoveIP("10.0.0.2/32").Return(nil)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32", "10.0.0.2/32"}}
+		err := p.Deactivate(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	t.Run("it is not activated if one of the IPs is missing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().HasIP("10.0.0.1/32").Return(true, nil)
+		netInterface.EXPECT().HasIP("10.0.0.2/32").Return(false, nil)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32", "10.0.0.2/32"}}
+		activated, err := p.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.False(t, activated)
+	})
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	t.Run("with a single IP, the key is unchanged", func(t *testing.T) {
+		p := &Plugin{ips: []string{"10.0.0.1/32"}}
+		assert.Equal(t, "10.0.0.1_32", p.ElectionKey(context.Background()))
+	})
+
+	t.Run("with multiple IPs, the key does not depend on the order", func(t *testing.T) {
+		p1 := &Plugin{ips: []string{"10.0.0.2/32", "10.0.0.1/32"}}
+		p2 := &Plugin{ips: []string{"10.0.0.1/32", "10.0.0.2/32"}}
+		assert.Equal(t, "10.0.0.1_32,10.0.0.2_32", p1.ElectionKey(context.Background()))
+		assert.Equal(t, p1.ElectionKey(context.Background()), p2.ElectionKey(context.Background()))
+	})
+
+	t.Run("the network namespace is part of the key", func(t *testing.T) {
+		p := &Plugin{ips: []string{"10.0.0.1/32"}, netns: "/var/run/netns/tenant"}
+		assert.Equal(t, "tenant:10.0.0.1_32", p.ElectionKey(context.Background()))
+
+		p = &Plugin{ips: []string{"10.0.0.1/32"}, netns: "/proc/1234/ns/net"}
+		assert.Equal(t, "_proc_1234_ns_net:10.0.0.1_32", p.ElectionKey(context.Background()))
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	garp := garpSettings{count: 2, interval: time.Millisecond, refreshInterval: time.Minute, mode: network.ARPModeBoth}
+
+	t.Run("it sends a burst if the IPs have never been announced", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil)
+		netInterface.EXPECT().Announce("10.0.0.1/32", network.ARPModeBoth).Return(nil).Times(2)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32"}, garp: garp}
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.False(t, p.lastAnnouncedAt.IsZero())
+	})
+
+	t.Run("it does not announce the IPs again before the refresh interval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32"}, garp: garp, lastAnnouncedAt: time.Now()}
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it sends a new burst after the refresh interval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil)
+		netInterface.EXPECT().Announce("10.0.0.1/32", network.ARPModeBoth).Return(nil).Times(2)
+
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32"}, garp: garp, lastAnnouncedAt: time.Now().Add(-2 * time.Minute)}
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it never refreshes if the refresh is disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil)
+
+		noRefresh := garp
+		noRefresh.refreshInterval = 0
+		p := &Plugin{netInterface: netInterface, ips: []string{"10.0.0.1/32"}, garp: noRefresh, lastAnnouncedAt: time.Now().Add(-24 * time.Hour)}
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+}
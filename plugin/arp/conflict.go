@@ -0,0 +1,150 @@
+package arp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/network"
+)
+
+// conflict is an IPv4 address of the endpoint also used by another host
+type conflict struct {
+	hardwareAddr net.HardwareAddr
+	since        time.Time
+}
+
+// Conditions reports an AddressConflict condition if another host uses one of the IPs
+func (p *Plugin) Conditions(_ context.Context) []api.EndpointCondition {
+	p.conflictsMutex.Lock()
+	defer p.conflictsMutex.Unlock()
+
+	if len(p.conflicts) == 0 {
+		return nil
+	}
+
+	ips := make([]string, 0, len(p.conflicts))
+	for ip := range p.conflicts {
+		ips = append(ips, ip)
+	}
+	slices.Sort(ips)
+
+	messages := make([]string, 0, len(ips))
+	var since time.Time
+	for _, ip := range ips {
+		c := p.conflicts[ip]
+		messages = append(messages, fmt.Sprintf("%s is also used by %s", ip, c.hardwareAddr))
+		if since.IsZero() || c.since.Before(since) {
+			since = c.since
+		}
+	}
+
+	return []api.EndpointCondition{{
+		Type:    api.EndpointConditionAddressConflict,
+		Message: strings.Join(messages, ", "),
+		Since:   since,
+		Failing: p.config.ARPConflictFailing,
+	}}
+}
+
+// probeConflicts sends ARP probes for the IPv4 addresses of the endpoint. It returns an error if
+// another host uses one of them.
+//
+// No conflict is reported: the host answering may be the previous owner of the endpoint which has
+// not released the addresses yet. The activation is retried by the plugin control loop until the
+// addresses are free.
+func (p *Plugin) probeConflicts(ctx context.Context) error {
+	for _, ip := range p.ipv4s() {
+		hardwareAddr, err := p.netInterface.ProbeARP(ctx, ip, network.ARPProbeOpts{
+			Count:    p.config.ARPConflictProbeCount,
+			Interval: p.config.ARPConflictProbeInterval,
+			Wait:     p.config.ARPConflictProbeWait,
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "probe IP %s", ip)
+		}
+		if hardwareAddr != nil {
+			return errors.Newf(ctx, "address conflict: %s is already used by %s", ip, hardwareAddr)
+		}
+	}
+	return nil
+}
+
+// startWatchingConflicts starts listening for ARP packets of other hosts claiming one of the IPv4
+// addresses. It does nothing if the watchers are already running.
+func (p *Plugin) startWatchingConflicts(ctx context.Context) {
+	if p.stopWatchingConflicts != nil {
+		return
+	}
+
+	// The watchers outlive the Activate call, only the context values are kept
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	p.stopWatchingConflicts = cancel
+
+	for _, ip := range p.ipv4s() {
+		go func() {
+			err := p.netInterface.WatchARPConflicts(ctx, ip, func(hardwareAddr net.HardwareAddr) {
+				p.reportConflict(ctx, ip, hardwareAddr)
+			})
+			if err != nil {
+				logger.Get(ctx).WithError(err).WithField("ip", ip).Error("Fail to watch ARP conflicts")
+			}
+		}()
+	}
+}
+
+// stopWatching stops the watchers and clears the detected conflicts. Conflicts are only reported
+// while the endpoint is activated: once deactivated, the other host is the legitimate user of the
+// addresses.
+func (p *Plugin) stopWatching() {
+	if p.stopWatchingConflicts != nil {
+		p.stopWatchingConflicts()
+		p.stopWatchingConflicts = nil
+	}
+
+	p.conflictsMutex.Lock()
+	defer p.conflictsMutex.Unlock()
+	clear(p.conflicts)
+}
+
+func (p *Plugin) reportConflict(ctx context.Context, ip string, hardwareAddr net.HardwareAddr) {
+	p.conflictsMutex.Lock()
+	defer p.conflictsMutex.Unlock()
+
+	// The watchers have been stopped: a conflict seen in the meantime must not outlive the deactivation
+	if ctx.Err() != nil {
+		return
+	}
+
+	existing, ok := p.conflicts[ip]
+	if ok && existing.hardwareAddr.String() == hardwareAddr.String() {
+		return
+	}
+
+	logger.Get(ctx).WithFields(logrus.Fields{
+		"ip":                 ip,
+		"conflicting_device": hardwareAddr.String(),
+	}).Error("ARP address conflict detected: another host uses the IP")
+	p.conflicts[ip] = conflict{hardwareAddr: hardwareAddr, since: time.Now()}
+}
+
+// ipv4s returns the IPv4 addresses of the endpoint. IPv6 addresses rely on the duplicate address
+// detection of the kernel instead.
+func (p *Plugin) ipv4s() []string {
+	ips := make([]string, 0, len(p.ips))
+	for _, ip := range p.ips {
+		addr, _, err := net.ParseCIDR(ip)
+		if err == nil && addr.To4() != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,106 @@
+package arp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func newConflictDetectionPlugin(netInterface *networkmock.MockInterface) *Plugin {
+	return &Plugin{
+		netInterface: netInterface,
+		ips:          []string{"10.0.0.1/32", "2001:db8::1/128"},
+		config: Config{
+			ARPConflictDetection: true,
+			ARPConflictFailing:   true,
+		},
+		conflicts: make(map[string]conflict),
+	}
+}
+
+func TestPlugin_Activate_ConflictDetection(t *testing.T) {
+	otherHost, _ := net.ParseMAC("02:00:00:00:00:02")
+
+	t.Run("it does not add the IPs if another host answers the probes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		// IPv6 addresses are not probed
+		netInterface.EXPECT().ProbeARP(gomock.Any(), "10.0.0.1/32", gomock.Any()).Return(otherHost, nil)
+
+		p := newConflictDetectionPlugin(netInterface)
+		err := p.Activate(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "address conflict")
+
+		// The other host may be the previous owner of the endpoint, it is not reported as a conflict
+		assert.Empty(t, p.Conditions(context.Background()))
+	})
+
+	t.Run("it adds the IPs and watches the conflicts if no one answers the probes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		watching := make(chan struct{})
+		netInterface.EXPECT().ProbeARP(gomock.Any(), "10.0.0.1/32", gomock.Any()).Return(nil, nil)
+		netInterface.EXPECT().EnsureIP("10.0.0.1/32").Return(nil)
+		netInterface.EXPECT().EnsureIP("2001:db8::1/128").Return(nil)
+		netInterface.EXPECT().WatchARPConflicts(gomock.Any(), "10.0.0.1/32", gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ string, onConflict func(net.HardwareAddr)) error {
+				onConflict(otherHost)
+				close(watching)
+				<-ctx.Done()
+				return nil
+			},
+		)
+
+		p := newConflictDetectionPlugin(netInterface)
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+
+		select {
+		case <-watching:
+		case <-time.After(time.Second):
+			t.Fatal("the conflicts are not watched")
+		}
+		conditions := p.Conditions(context.Background())
+		require.Len(t, conditions, 1)
+		assert.Equal(t, api.EndpointConditionAddressConflict, conditions[0].Type)
+		assert.Contains(t, conditions[0].Message, "02:00:00:00:00:02")
+		assert.True(t, conditions[0].Failing)
+
+		p.stopWatching()
+	})
+}
+
+func TestPlugin_Deactivate_ConflictDetection(t *testing.T) {
+	otherHost, _ := net.ParseMAC("02:00:00:00:00:02")
+
+	t.Run("it clears the conflicts since the other host is the legitimate user of the IPs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		netInterface.EXPECT().RemoveIP(gomock.Any()).Return(nil).Times(2)
+
+		p := newConflictDetectionPlugin(netInterface)
+		p.conflicts["10.0.0.1/32"] = conflict{hardwareAddr: otherHost, since: time.Now()}
+
+		err := p.Deactivate(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, p.Conditions(context.Background()))
+	})
+
+	t.Run("it does not report the conflicts seen once the watchers are stopped", func(t *testing.T) {
+		p := newConflictDetectionPlugin(nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p.reportConflict(ctx, "10.0.0.1/32", otherHost)
+		assert.Empty(t, p.Conditions(context.Background()))
+	})
+}
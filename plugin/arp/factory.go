@@ -3,6 +3,7 @@ package arp
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/vishvananda/netlink"
@@ -17,9 +18,39 @@ import (
 const Name = api.PluginARP
 
 type Config struct {
-	// Number of gratuitous ARP (GARP) packets sent when the state becomes 'ACTIVATED'
-	ARPGratuitousCount int    `envconfig:"ARP_GRATUITOUS_COUNT" default:"3"`
-	Interface          string `envconfig:"INTERFACE"`
+	// Default gratuitous ARP (GARP) settings, they can be overridden per endpoint. For IPv6
+	// addresses, unsolicited neighbor advertisements are sent instead.
+	// Number of packets sent when the state becomes 'ACTIVATED' and on each refresh
+	ARPGratuitousCount int `envconfig:"ARP_GRATUITOUS_COUNT" default:"3"`
+	// Duration between two packets of a burst
+	ARPGratuitousBurstInterval time.Duration `envconfig:"ARP_GRATUITOUS_BURST_INTERVAL" default:"1s"`
+	// Duration between two bursts while the endpoint is activated, 0 disables the refresh
+	ARPGratuitousRefreshInterval time.Duration `envconfig:"ARP_GRATUITOUS_REFRESH_INTERVAL" default:"0"`
+	// Kind of gratuitous ARP packets: request, reply or both
+	ARPGratuitousMode string `envconfig:"ARP_GRATUITOUS_MODE" default:"request"`
+
+	// Default interface of the endpoints. It can be overridden per endpoint.
+	Interface string `envconfig:"INTERFACE"`
+	// Default network namespace of the interfaces (a name under /var/run/netns or a path). It can
+	// be overridden per endpoint. Defaults to the namespace of LinK.
+	Netns string `envconfig:"NETNS"`
+
+	// Duplicate Address Detection for IPv6 addresses
+	IPv6DAD        bool          `envconfig:"IPV6_DAD" default:"false"`
+	IPv6DADTimeout time.Duration `envconfig:"IPV6_DAD_TIMEOUT" default:"3s"`
+
+	// Remove the IPs left on the interfaces by a previous run of LinK when it starts
+	StartupCleanup bool `envconfig:"ARP_STARTUP_CLEANUP" default:"true"`
+	// Also remove the unlabelled IPs matching the IP of an endpoint, they may have been added by hand
+	StartupCleanupUnlabelled bool `envconfig:"ARP_STARTUP_CLEANUP_UNLABELLED" default:"false"`
+
+	// ARP conflict detection (RFC 5227) for IPv4 addresses
+	ARPConflictDetection     bool          `envconfig:"ARP_CONFLICT_DETECTION" default:"false"`
+	ARPConflictProbeCount    int           `envconfig:"ARP_CONFLICT_PROBE_COUNT" default:"3"`
+	ARPConflictProbeInterval time.Duration `envconfig:"ARP_CONFLICT_PROBE_INTERVAL" default:"200ms"`
+	ARPConflictProbeWait     time.Duration `envconfig:"ARP_CONFLICT_PROBE_WAIT" default:"1s"`
+	// Push the endpoint to FAILING when a conflict is detected
+	ARPConflictFailing bool `envconfig:"ARP_CONFLICT_FAILING" default:"false"`
 }
 
 func Register(ctx context.Context, registry plugin.Registry) error {
@@ -28,52 +59,94 @@ func Register(ctx context.Context, registry plugin.Registry) error {
 	if err != nil {
 		return errors.Wrap(ctx, err, "parse environment")
 	}
+	if !network.ARPMode(config.ARPGratuitousMode).IsValid() {
+		return errors.Newf(ctx, "invalid ARP_GRATUITOUS_MODE: %s", config.ARPGratuitousMode)
+	}
 
-	i, err := network.NewNetworkInterfaceFromName(config.Interface)
-	if err != nil {
-		return errors.Wrap(ctx, err, "get network interface")
+	factory := Factory{
+		config: config,
+		newNetInterface: func(name, netns string) (network.Interface, error) {
+			return network.NewNetworkInterfaceFromName(name, network.NetInterfaceOpts{
+				IPv6DAD:        config.IPv6DAD,
+				IPv6DADTimeout: config.IPv6DADTimeout,
+				Namespace:      netns,
+			})
+		},
+		interfaceExists: network.InterfaceExists,
 	}
 
-	registry.Register(ctx, Name, Factory{
-		config:       config,
-		netInterface: i,
-	})
+	// The default interface is checked at startup, so that a misconfiguration is detected early
+	if config.Interface != "" {
+		_, err = factory.newNetInterface(config.Interface, config.Netns)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get network interface")
+		}
+	}
+
+	registry.Register(ctx, Name, factory)
 
 	return nil
 }
 
 type Factory struct {
-	config       Config
-	netInterface network.Interface
+	config          Config
+	newNetInterface func(name, netns string) (network.Interface, error)
+	interfaceExists func(name, netns string) error
 }
 
 type PluginConfig = api.ARPPluginConfig
 
 func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	cfg, err := pluginConfig(ctx, endpoint)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get plugin config")
+	}
+
+	ips := cfg.Addresses()
+	if len(ips) == 0 {
+		return nil, errors.New(ctx, "invalid plugin config: no IP")
+	}
+
+	garp, err := f.garpSettings(ctx, cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid gratuitous ARP settings")
+	}
+
+	netInterface, err := f.newNetInterface(f.interfaceName(cfg), f.netns(cfg))
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get network interface")
+	}
+
+	return &Plugin{
+		endpoint:     endpoint,
+		ips:          ips,
+		netns:        network.NamespacePath(f.netns(cfg)),
+		config:       f.config,
+		garp:         garp,
+		netInterface: netInterface,
+		conflicts:    make(map[string]conflict),
+	}, nil
+}
+
+// pluginConfig returns the configuration of the endpoint
+func pluginConfig(ctx context.Context, endpoint models.Endpoint) (PluginConfig, error) {
 	var cfg PluginConfig
 
 	if endpoint.PluginConfig != nil {
 		err := json.Unmarshal(endpoint.PluginConfig, &cfg)
 		if err != nil {
-			return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+			return cfg, errors.Wrap(ctx, err, "unmarshal plugin config")
 		}
 	} else { // Retro compatibility
 		if endpoint.IP == "" {
-			return nil, errors.New(ctx, "invalid plugin config: empty")
+			return cfg, errors.New(ctx, "invalid plugin config: empty")
 		}
 		cfg.IP = endpoint.IP
 	}
-
-	return &Plugin{
-		endpoint:     endpoint,
-		ip:           cfg.IP,
-		config:       f.config,
-		garpCount:    0,
-		netInterface: f.netInterface,
-	}, nil
+	return cfg, nil
 }
 
-func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+func (f Factory) Validate(ctx context.Context, endpoint models.Endpoint) error {
 	validation := errors.NewValidationErrorsBuilder()
 	var cfg PluginConfig
 	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
@@ -82,12 +155,36 @@ func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
 		return validation.Build()
 	}
 
-	if cfg.IP == "" {
+	ips := cfg.Addresses()
+	if len(ips) == 0 {
 		validation.Set("plugin_config.ip", "ip is required")
+	}
+
+	seen := make(map[string]bool, len(ips))
+	for _, ip := range ips {
+		addr, err := netlink.ParseAddr(ip)
+		if err != nil {
+			validation.Set("plugin_config.ips", "invalid IP address: "+err.Error())
+			continue
+		}
+		if seen[addr.IP.String()] {
+			validation.Set("plugin_config.ips", "duplicated IP address: "+ip)
+		}
+		seen[addr.IP.String()] = true
+	}
+
+	_, err = f.garpSettings(ctx, cfg)
+	if err != nil {
+		validation.Set("plugin_config.garp", err.Error())
+	}
+
+	interfaceName := f.interfaceName(cfg)
+	if interfaceName == "" {
+		validation.Set("plugin_config.interface", "interface is required if no default interface is configured on the host")
 	} else {
-		_, err = netlink.ParseAddr(cfg.IP)
+		err = f.interfaceExists(interfaceName, f.netns(cfg))
 		if err != nil {
-			validation.Set("plugin_config.ip", "invalid IP address: "+err.Error())
+			validation.Set("plugin_config.interface", "interface "+interfaceName+" not found: "+err.Error())
 		}
 	}
 
@@ -97,3 +194,70 @@ func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
 	}
 	return nil
 }
+
+// garpSettings returns the gratuitous ARP settings of the endpoint, based on the settings of the host
+func (f Factory) garpSettings(ctx context.Context, cfg PluginConfig) (garpSettings, error) {
+	settings := garpSettings{
+		count:           f.config.ARPGratuitousCount,
+		interval:        f.config.ARPGratuitousBurstInterval,
+		refreshInterval: f.config.ARPGratuitousRefreshInterval,
+		mode:            network.ARPMode(f.config.ARPGratuitousMode),
+	}
+	if cfg.GARP == nil {
+		return settings, nil
+	}
+
+	var err error
+	if cfg.GARP.BurstCount < 0 {
+		return settings, errors.New(ctx, "burst_count must be positive")
+	}
+	if cfg.GARP.BurstCount > 0 {
+		settings.count = cfg.GARP.BurstCount
+	}
+	if cfg.GARP.BurstInterval != "" {
+		settings.interval, err = parsePositiveDuration(ctx, cfg.GARP.BurstInterval)
+		if err != nil {
+			return settings, errors.Wrap(ctx, err, "invalid burst_interval")
+		}
+	}
+	if cfg.GARP.RefreshInterval != "" {
+		settings.refreshInterval, err = parsePositiveDuration(ctx, cfg.GARP.RefreshInterval)
+		if err != nil {
+			return settings, errors.Wrap(ctx, err, "invalid refresh_interval")
+		}
+	}
+	if cfg.GARP.Mode != "" {
+		settings.mode = network.ARPMode(cfg.GARP.Mode)
+		if !settings.mode.IsValid() {
+			return settings, errors.Newf(ctx, "invalid mode %s, must be request, reply or both", cfg.GARP.Mode)
+		}
+	}
+	return settings, nil
+}
+
+func parsePositiveDuration(ctx context.Context, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrap(ctx, err, "parse duration")
+	}
+	if d < 0 {
+		return 0, errors.New(ctx, "duration must be positive")
+	}
+	return d, nil
+}
+
+// interfaceName returns the interface of the endpoint, or the default one of the host
+func (f Factory) interfaceName(cfg PluginConfig) string {
+	if cfg.Interface != "" {
+		return cfg.Interface
+	}
+	return f.config.Interface
+}
+
+// netns returns the network namespace of the endpoint, or the default one of the host
+func (f Factory) netns(cfg PluginConfig) string {
+	if cfg.Netns != "" {
+		return cfg.Netns
+	}
+	return f.config.Netns
+}
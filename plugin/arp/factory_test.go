@@ -0,0 +1,110 @@
+package arp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name             string
+		DefaultInterface string
+		Config           PluginConfig
+		ExpectedError    string
+	}{
+		{
+			Name:             "with a single IP",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32"},
+		}, {
+			Name:             "with multiple IPs and an interface override",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IPs: []string{"10.0.0.1/32", "2001:db8::1/128"}, Interface: "vlan10"},
+		}, {
+			Name:             "without IP",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{},
+			ExpectedError:    "ip is required",
+		}, {
+			Name:             "with an invalid IP",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IPs: []string{"10.0.0.1/32", "invalid"}},
+			ExpectedError:    "invalid IP address",
+		}, {
+			Name:             "with a duplicated IP",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32", IPs: []string{"10.0.0.1/32"}},
+			ExpectedError:    "duplicated IP address",
+		}, {
+			Name:             "with custom gratuitous ARP settings",
+			DefaultInterface: "eth0",
+			Config: PluginConfig{IP: "10.0.0.1/32", GARP: &api.ARPGratuitousConfig{
+				BurstCount: 5, BurstInterval: "200ms", RefreshInterval: "5m", Mode: "reply",
+			}},
+		}, {
+			Name:             "with an invalid gratuitous ARP mode",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32", GARP: &api.ARPGratuitousConfig{Mode: "broadcast"}},
+			ExpectedError:    "invalid mode broadcast",
+		}, {
+			Name:             "with an invalid gratuitous ARP interval",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32", GARP: &api.ARPGratuitousConfig{BurstInterval: "-1s"}},
+			ExpectedError:    "invalid burst_interval",
+		}, {
+			Name:          "without interface",
+			Config:        PluginConfig{IP: "10.0.0.1/32"},
+			ExpectedError: "interface is required",
+		}, {
+			Name:             "with an unknown interface",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32", Interface: "unknown"},
+			ExpectedError:    "interface unknown not found",
+		}, {
+			Name:             "with a network namespace",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32", Interface: "veth-tenant", Netns: "tenant"},
+		}, {
+			Name:             "with an unknown network namespace",
+			DefaultInterface: "eth0",
+			Config:           PluginConfig{IP: "10.0.0.1/32", Netns: "unknown"},
+			ExpectedError:    "fail to open network namespace",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			factory := Factory{
+				config: Config{Interface: spec.DefaultInterface},
+				interfaceExists: func(name, netns string) error {
+					if netns == "unknown" {
+						return errors.New("fail to open network namespace /var/run/netns/unknown")
+					}
+					if name == "unknown" {
+						return errors.New("Link not found")
+					}
+					return nil
+				},
+			}
+
+			pluginConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = factory.Validate(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			if spec.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.ExpectedError)
+		})
+	}
+}
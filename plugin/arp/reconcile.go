@@ -0,0 +1,154 @@
+package arp
+
+import (
+	"context"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+// ReconcileAtStartup removes the IPs left on the managed interfaces by a previous run of LinK. If
+// LinK crashed while an endpoint was ACTIVATED, its IPs are still on the interface and Deactivate
+// will never be called for them.
+//
+// The managed interfaces are the default interface of the host and the interfaces of the endpoints,
+// in their network namespaces.
+// An IP is removed if it has been added by LinK (it carries the LinK label), unless one of the
+// endpoints of this IP is owned by the host. The IPs without label matching the IP of an endpoint
+// configured on this host are only removed if StartupCleanupUnlabelled is enabled: they may have
+// been added by an operator.
+func (f Factory) ReconcileAtStartup(ctx context.Context, endpoints []models.Endpoint, isOwned plugin.OwnershipChecker) error {
+	ctx, log := logger.WithFieldToCtx(ctx, "process", "arp_startup_cleanup")
+	if !f.config.StartupCleanup {
+		log.Debug("Startup cleanup disabled")
+		return nil
+	}
+
+	// IP -> IDs of the endpoints configured with this IP, per interface
+	owners := make(map[managedInterface]map[string][]string)
+	if f.config.Interface != "" {
+		owners[managedInterface{name: f.config.Interface, netns: network.NamespacePath(f.config.Netns)}] = make(map[string][]string)
+	}
+	for _, endpoint := range endpoints {
+		cfg, err := pluginConfig(ctx, endpoint)
+		if err != nil {
+			log.WithError(err).WithField("endpoint_id", endpoint.ID).Error("Fail to get the plugin config of the endpoint")
+			continue
+		}
+
+		iface := managedInterface{name: f.interfaceName(cfg), netns: network.NamespacePath(f.netns(cfg))}
+		if owners[iface] == nil {
+			owners[iface] = make(map[string][]string)
+		}
+		for _, ip := range cfg.Addresses() {
+			owners[iface][ip] = append(owners[iface][ip], endpoint.ID)
+		}
+	}
+
+	var firstErr error
+	removed := 0
+	for iface, ipOwners := range owners {
+		n, err := f.cleanupInterface(ctx, iface, ipOwners, isOwned)
+		removed += n
+		if err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(ctx, err, "clean up interface %s", iface.name)
+		}
+	}
+
+	log.WithField("removed_ips", removed).Info("Startup cleanup of the network interfaces done")
+	return firstErr
+}
+
+// managedInterface is an interface in a network namespace, an empty netns being the namespace of LinK
+type managedInterface struct {
+	name  string
+	netns string
+}
+
+// cleanupInterface removes the IPs of the interface which are not owned by the host and returns the
+// number of removed IPs
+func (f Factory) cleanupInterface(ctx context.Context, iface managedInterface, ipOwners map[string][]string, isOwned plugin.OwnershipChecker) (int, error) {
+	log := logger.Get(ctx).WithFields(logrus.Fields{
+		"interface": iface.name,
+		"netns":     iface.netns,
+	})
+
+	netInterface, err := f.newNetInterface(iface.name, iface.netns)
+	if err != nil {
+		return 0, errors.Wrap(ctx, err, "get network interface")
+	}
+
+	candidates, err := netInterface.ManagedIPs()
+	if err != nil {
+		return 0, errors.Wrap(ctx, err, "list the IPs added by LinK")
+	}
+	// IPv6 addresses cannot be labelled, they are only found through the IPs of the configured endpoints
+	if f.config.StartupCleanupUnlabelled {
+		for ip := range ipOwners {
+			has, err := netInterface.HasIP(ip)
+			if err != nil {
+				return 0, errors.Wrapf(ctx, err, "check IP %s on network interface", ip)
+			}
+			if has {
+				candidates = append(candidates, ip)
+			}
+		}
+	}
+
+	var firstErr error
+	removed := make(map[string]bool)
+	for _, ip := range candidates {
+		addr, err := netlink.ParseAddr(ip)
+		if err != nil {
+			log.WithError(err).WithField("ip", ip).Error("Invalid IP on the interface")
+			continue
+		}
+		if removed[addr.IP.String()] {
+			continue
+		}
+
+		endpointIDs := endpointsOfIP(addr, ipOwners)
+		owned := slices.ContainsFunc(endpointIDs, func(endpointID string) bool {
+			return isOwned(ctx, endpointID)
+		})
+		if owned {
+			continue
+		}
+
+		err = netInterface.RemoveIP(ip)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = errors.Wrapf(ctx, err, "remove IP %s", ip)
+			}
+			continue
+		}
+		removed[addr.IP.String()] = true
+		log.WithFields(logrus.Fields{
+			"ip":           ip,
+			"endpoint_ids": endpointIDs,
+		}).Warn("Removed an IP left on the interface by a previous run of LinK")
+	}
+	return len(removed), firstErr
+}
+
+// endpointsOfIP returns the IDs of the endpoints configured with this IP
+func endpointsOfIP(addr *netlink.Addr, ipOwners map[string][]string) []string {
+	var endpointIDs []string
+	for ip, ids := range ipOwners {
+		ownerAddr, err := netlink.ParseAddr(ip)
+		if err != nil {
+			continue
+		}
+		if ownerAddr.IP.Equal(addr.IP) {
+			endpointIDs = append(endpointIDs, ids...)
+		}
+	}
+	return endpointIDs
+}
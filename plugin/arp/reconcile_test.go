@@ -0,0 +1,174 @@
+package arp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func TestFactory_ReconcileAtStartup(t *testing.T) {
+	newEndpoint := func(t *testing.T, id string, cfg PluginConfig) models.Endpoint {
+		rawConfig, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		return models.Endpoint{ID: id, Plugin: Name, PluginConfig: rawConfig}
+	}
+
+	specs := []struct {
+		Name              string
+		Endpoints         func(t *testing.T) []models.Endpoint
+		Owned             []string
+		CleanupUnlabelled bool
+		ExpectMocks       func(netInterface *networkmock.MockInterface)
+		ExpectedError     string
+	}{
+		{
+			Name: "it removes the labelled IPs of the endpoints not owned by the host",
+			Endpoints: func(t *testing.T) []models.Endpoint {
+				return []models.Endpoint{
+					newEndpoint(t, "owned", PluginConfig{IP: "10.0.0.1/32"}),
+					newEndpoint(t, "not-owned", PluginConfig{IPs: []string{"10.0.0.2/32", "2001:db8::2/128"}}),
+				}
+			},
+			Owned: []string{"owned"},
+			ExpectMocks: func(netInterface *networkmock.MockInterface) {
+				// The unlabelled IPs are kept, they may have been added by an operator
+				netInterface.EXPECT().ManagedIPs().Return([]string{"10.0.0.1/32", "10.0.0.2/32"}, nil)
+				netInterface.EXPECT().RemoveIP("10.0.0.2/32").Return(nil)
+			},
+		}, {
+			Name: "if enabled, it removes the unlabelled IPs of the endpoints not owned by the host",
+			Endpoints: func(t *testing.T) []models.Endpoint {
+				return []models.Endpoint{
+					newEndpoint(t, "owned", PluginConfig{IP: "10.0.0.1/32"}),
+					newEndpoint(t, "not-owned", PluginConfig{IPs: []string{"10.0.0.2/32", "2001:db8::2/128"}}),
+				}
+			},
+			Owned:             []string{"owned"},
+			CleanupUnlabelled: true,
+			ExpectMocks: func(netInterface *networkmock.MockInterface) {
+				netInterface.EXPECT().ManagedIPs().Return([]string{"10.0.0.1/32"}, nil)
+				netInterface.EXPECT().HasIP("10.0.0.1/32").Return(true, nil)
+				netInterface.EXPECT().HasIP("10.0.0.2/32").Return(true, nil)
+				netInterface.EXPECT().HasIP("2001:db8::2/128").Return(true, nil)
+				netInterface.EXPECT().RemoveIP("10.0.0.2/32").Return(nil)
+				netInterface.EXPECT().RemoveIP("2001:db8::2/128").Return(nil)
+			},
+		}, {
+			Name: "it removes the labelled IPs of endpoints not configured anymore",
+			Endpoints: func(*testing.T) []models.Endpoint {
+				return nil
+			},
+			ExpectMocks: func(netInterface *networkmock.MockInterface) {
+				netInterface.EXPECT().ManagedIPs().Return([]string{"10.0.0.3/32"}, nil)
+				netInterface.EXPECT().RemoveIP("10.0.0.3/32").Return(nil)
+			},
+		}, {
+			Name: "it keeps an IP if one of its endpoints is owned",
+			Endpoints: func(t *testing.T) []models.Endpoint {
+				return []models.Endpoint{
+					newEndpoint(t, "owned", PluginConfig{IP: "10.0.0.1/32"}),
+					newEndpoint(t, "not-owned", PluginConfig{IP: "10.0.0.1/24"}),
+				}
+			},
+			Owned:             []string{"owned"},
+			CleanupUnlabelled: true,
+			ExpectMocks: func(netInterface *networkmock.MockInterface) {
+				netInterface.EXPECT().ManagedIPs().Return([]string{"10.0.0.1/24"}, nil)
+				netInterface.EXPECT().HasIP("10.0.0.1/32").Return(true, nil)
+				netInterface.EXPECT().HasIP("10.0.0.1/24").Return(true, nil)
+			},
+		}, {
+			Name: "it continues if an IP cannot be removed",
+			Endpoints: func(*testing.T) []models.Endpoint {
+				return nil
+			},
+			ExpectMocks: func(netInterface *networkmock.MockInterface) {
+				netInterface.EXPECT().ManagedIPs().Return([]string{"10.0.0.3/32", "10.0.0.4/32"}, nil)
+				netInterface.EXPECT().RemoveIP("10.0.0.3/32").Return(assert.AnError)
+				netInterface.EXPECT().RemoveIP("10.0.0.4/32").Return(nil)
+			},
+			ExpectedError: "remove IP 10.0.0.3/32",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			netInterface := networkmock.NewMockInterface(ctrl)
+			spec.ExpectMocks(netInterface)
+
+			factory := Factory{
+				config: Config{Interface: "eth0", StartupCleanup: true, StartupCleanupUnlabelled: spec.CleanupUnlabelled},
+				newNetInterface: func(name, netns string) (network.Interface, error) {
+					assert.Equal(t, "eth0", name)
+					assert.Empty(t, netns)
+					return netInterface, nil
+				},
+			}
+			isOwned := func(_ context.Context, endpointID string) bool {
+				for _, id := range spec.Owned {
+					if id == endpointID {
+						return true
+					}
+				}
+				return false
+			}
+
+			err := factory.ReconcileAtStartup(context.Background(), spec.Endpoints(t), isOwned)
+			if spec.ExpectedError != "" {
+				require.ErrorContains(t, err, spec.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("it does nothing if the startup cleanup is disabled", func(t *testing.T) {
+		factory := Factory{
+			config: Config{Interface: "eth0"},
+			newNetInterface: func(string, string) (network.Interface, error) {
+				t.Fatal("the network interface must not be opened")
+				return nil, nil
+			},
+		}
+
+		err := factory.ReconcileAtStartup(context.Background(), nil, nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestFactory_ReconcileAtStartup_Netns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	hostInterface := networkmock.NewMockInterface(ctrl)
+	hostInterface.EXPECT().ManagedIPs().Return(nil, nil)
+	tenantInterface := networkmock.NewMockInterface(ctrl)
+	tenantInterface.EXPECT().ManagedIPs().Return([]string{"10.0.0.1/32"}, nil)
+	tenantInterface.EXPECT().RemoveIP("10.0.0.1/32").Return(nil)
+
+	factory := Factory{
+		config: Config{Interface: "eth0", StartupCleanup: true},
+		newNetInterface: func(name, netns string) (network.Interface, error) {
+			if netns == "/var/run/netns/tenant" {
+				assert.Equal(t, "eth1", name)
+				return tenantInterface, nil
+			}
+			assert.Empty(t, netns)
+			return hostInterface, nil
+		},
+	}
+
+	rawConfig, err := json.Marshal(PluginConfig{IP: "10.0.0.1/32", Interface: "eth1", Netns: "tenant"})
+	require.NoError(t, err)
+	endpoints := []models.Endpoint{{ID: "tenant-endpoint", Plugin: Name, PluginConfig: rawConfig}}
+
+	err = factory.ReconcileAtStartup(context.Background(), endpoints, func(context.Context, string) bool { return false })
+	require.NoError(t, err)
+}
@@ -0,0 +1,174 @@
+package awseip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/services/aws"
+)
+
+type ElasticIPPlugin struct {
+	awsClient aws.ElasticIPClient
+
+	refreshEvery time.Duration
+
+	// Elastic IP Configuration
+	allocationID       string // Allocation ID of the Elastic IP to move
+	networkInterfaceID string // ID of the ENI to move the Elastic IP to
+	// privateIP is the private IP of the ENI associated with the Elastic IP, the primary private IP
+	// if it is empty
+	privateIP          string
+	allowReassociation bool
+
+	// Internal configuration
+	associationID   string // ID of the association of the Elastic IP with the ENI
+	lastRefreshedAt time.Time
+}
+
+func (p *ElasticIPPlugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	log.Info("Associating Elastic IP with ENI")
+	associationID, err := p.awsClient.AssociateAddress(ctx, aws.AssociateAddressParams{
+		AllocationID:       p.allocationID,
+		NetworkInterfaceID: p.networkInterfaceID,
+		PrivateIPAddress:   p.privateIP,
+		AllowReassociation: p.allowReassociation,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "associate Elastic IP")
+	}
+
+	p.associationID = associationID
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+func (p *ElasticIPPlugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+	if p.associationID == "" {
+		log.Info("Elastic IP was not associated with an ENI, skipping disassociation")
+		return nil
+	}
+
+	log.WithField("association_id", p.associationID).Info("Disassociating Elastic IP from ENI")
+
+	err := p.awsClient.DisassociateAddress(ctx, p.associationID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "disassociate Elastic IP")
+	}
+
+	p.associationID = ""
+
+	return nil
+}
+
+func (p *ElasticIPPlugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	address, err := p.awsClient.DescribeAddress(ctx, p.allocationID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "describe Elastic IP")
+	}
+
+	// If the Elastic IP is not associated with the ENI, we need to associate it
+	if !p.isAssociated(address) {
+		log.WithFields(logrus.Fields{
+			"current_network_interface_id": address.NetworkInterfaceID,
+			"current_private_ip":           address.PrivateIPAddress,
+		}).Info("Elastic IP is not associated with the ENI, associating it")
+		err := p.Activate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "associate Elastic IP")
+		}
+		return nil
+	}
+
+	// The association ID changes if the Elastic IP has been associated again in the meantime
+	p.associationID = address.AssociationID
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// IsActivated returns true if the Elastic IP is associated with the ENI
+func (p *ElasticIPPlugin) IsActivated(ctx context.Context) (bool, error) {
+	address, err := p.awsClient.DescribeAddress(ctx, p.allocationID)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "describe Elastic IP")
+	}
+
+	if !p.isAssociated(address) {
+		return false, nil
+	}
+
+	p.associationID = address.AssociationID
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated disassociates the Elastic IP if it is still associated with the ENI of this
+// host.
+func (p *ElasticIPPlugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	address, err := p.awsClient.DescribeAddress(ctx, p.allocationID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "describe Elastic IP")
+	}
+
+	// The Elastic IP is associated with another ENI (or not associated at all), nothing to do
+	if address.NetworkInterfaceID != p.networkInterfaceID {
+		p.associationID = ""
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.WithField("association_id", address.AssociationID).Info("Elastic IP is still associated with the ENI, disassociating it")
+	err = p.awsClient.DisassociateAddress(ctx, address.AssociationID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "disassociate Elastic IP")
+	}
+
+	p.associationID = ""
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// isAssociated returns true if the Elastic IP is associated with the ENI, and with the configured
+// private IP if any
+func (p *ElasticIPPlugin) isAssociated(address aws.Address) bool {
+	if address.NetworkInterfaceID != p.networkInterfaceID {
+		return false
+	}
+	return p.privateIP == "" || address.PrivateIPAddress == p.privateIP
+}
+
+func (p *ElasticIPPlugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, p.allocationID)
+}
+
+func (p *ElasticIPPlugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":                 "aws_eip",
+		"allocation_id":        p.allocationID,
+		"network_interface_id": p.networkInterfaceID,
+	}
+}
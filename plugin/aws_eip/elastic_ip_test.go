@@ -0,0 +1,239 @@
+package awseip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/aws"
+	"github.com/Scalingo/link/v3/services/aws/awsmock"
+)
+
+const (
+	testAllocationID       = "eipalloc-0123456789abcdef0"
+	testNetworkInterfaceID = "eni-0123456789abcdef0"
+	testAssociationID      = "eipassoc-0123456789abcdef0"
+)
+
+func newElasticIPPlugin(mockClient *awsmock.MockElasticIPClient) *ElasticIPPlugin {
+	return &ElasticIPPlugin{
+		awsClient:          mockClient,
+		refreshEvery:       time.Minute,
+		allocationID:       testAllocationID,
+		networkInterfaceID: testNetworkInterfaceID,
+		allowReassociation: true,
+	}
+}
+
+func TestElasticIPPlugin_Activate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+
+		mockClient.EXPECT().
+			AssociateAddress(gomock.Any(), aws.AssociateAddressParams{
+				AllocationID:       testAllocationID,
+				NetworkInterfaceID: testNetworkInterfaceID,
+				AllowReassociation: true,
+			}).
+			Return(testAssociationID, nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testAssociationID, plugin.associationID)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+
+		mockClient.EXPECT().
+			AssociateAddress(gomock.Any(), gomock.Any()).
+			Return("", errors.New("associate error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "associate error")
+		assert.Empty(t, plugin.associationID)
+	})
+}
+
+func TestElasticIPPlugin_Deactivate(t *testing.T) {
+	t.Run("it disassociates the Elastic IP", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+		plugin.associationID = testAssociationID
+
+		mockClient.EXPECT().DisassociateAddress(gomock.Any(), testAssociationID).Return(nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, plugin.associationID)
+	})
+
+	t.Run("without association, it does nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestElasticIPPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("an Elastic IP associated with another ENI is associated again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+			AllocationID:       testAllocationID,
+			AssociationID:      "eipassoc-0000000000000000a",
+			NetworkInterfaceID: "eni-0000000000000000a",
+		}, nil)
+		mockClient.EXPECT().AssociateAddress(gomock.Any(), gomock.Any()).Return(testAssociationID, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testAssociationID, plugin.associationID)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("an Elastic IP associated with another private IP of the ENI is associated again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+		plugin.privateIP = "10.0.1.10"
+
+		mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+			AllocationID:       testAllocationID,
+			AssociationID:      testAssociationID,
+			NetworkInterfaceID: testNetworkInterfaceID,
+			PrivateIPAddress:   "10.0.1.5",
+		}, nil)
+		mockClient.EXPECT().
+			AssociateAddress(gomock.Any(), aws.AssociateAddressParams{
+				AllocationID:       testAllocationID,
+				NetworkInterfaceID: testNetworkInterfaceID,
+				PrivateIPAddress:   "10.0.1.10",
+				AllowReassociation: true,
+			}).
+			Return(testAssociationID, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("an Elastic IP associated with the ENI updates the association ID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+		plugin.associationID = "eipassoc-0000000000000000a"
+
+		mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+			AllocationID:       testAllocationID,
+			AssociationID:      testAssociationID,
+			NetworkInterfaceID: testNetworkInterfaceID,
+		}, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testAssociationID, plugin.associationID)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+}
+
+func TestElasticIPPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("an Elastic IP still associated with our ENI is disassociated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+			AllocationID:       testAllocationID,
+			AssociationID:      testAssociationID,
+			NetworkInterfaceID: testNetworkInterfaceID,
+		}, nil)
+		mockClient.EXPECT().DisassociateAddress(gomock.Any(), testAssociationID).Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("an Elastic IP associated with another ENI is left untouched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+		plugin.associationID = testAssociationID
+
+		mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+			AllocationID:       testAllocationID,
+			AssociationID:      "eipassoc-0000000000000000a",
+			NetworkInterfaceID: "eni-0000000000000000a",
+		}, nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, plugin.associationID)
+	})
+
+	t.Run("disassociate error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockElasticIPClient(ctrl)
+		plugin := newElasticIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+			AllocationID:       testAllocationID,
+			AssociationID:      testAssociationID,
+			NetworkInterfaceID: testNetworkInterfaceID,
+		}, nil)
+		mockClient.EXPECT().DisassociateAddress(gomock.Any(), testAssociationID).Return(errors.New("disassociate error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestElasticIPPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := awsmock.NewMockElasticIPClient(ctrl)
+	plugin := newElasticIPPlugin(mockClient)
+
+	mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+		AllocationID:       testAllocationID,
+		AssociationID:      testAssociationID,
+		NetworkInterfaceID: testNetworkInterfaceID,
+	}, nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+	assert.Equal(t, testAssociationID, plugin.associationID)
+
+	mockClient.EXPECT().DescribeAddress(gomock.Any(), testAllocationID).Return(aws.Address{
+		AllocationID: testAllocationID,
+	}, nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
@@ -0,0 +1,184 @@
+package awseip
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/aws"
+)
+
+const Name = api.PluginAWSEIP
+
+type Config struct {
+	RefreshEvery time.Duration `envconfig:"AWS_EIP_REFRESH_INTERVAL" default:"1m"`
+	// EndpointURL overrides the endpoint of the EC2 API, the public endpoint of the region is used
+	// if it is empty
+	EndpointURL string `envconfig:"AWS_EC2_ENDPOINT_URL"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+		newInterface: func(name string) (network.Interface, error) {
+			return network.NewNetworkInterfaceFromName(name, network.NetInterfaceOpts{})
+		},
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+	newInterface     func(name string) (network.Interface, error)
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	awsClient, err := aws.NewClientFromCredentials(ctx, f.encryptedStorage, cfg.StorableCredentials, f.config.EndpointURL)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create AWS client")
+	}
+
+	if cfg.Mode == api.AWSEIPModePrivateIP {
+		p := &PrivateIPPlugin{
+			awsClient:          awsClient,
+			refreshEvery:       f.config.RefreshEvery,
+			privateIP:          cfg.PrivateIP,
+			networkInterfaceID: cfg.NetworkInterfaceID,
+			allowReassignment:  cfg.AllowReassociation,
+		}
+		if cfg.Interface != "" {
+			p.netInterface, err = f.newInterface(cfg.Interface)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "get interface %s", cfg.Interface)
+			}
+		}
+		return p, nil
+	}
+
+	return &ElasticIPPlugin{
+		awsClient:          awsClient,
+		refreshEvery:       f.config.RefreshEvery,
+		allocationID:       cfg.AllocationID,
+		networkInterfaceID: cfg.NetworkInterfaceID,
+		privateIP:          cfg.PrivateIP,
+		allowReassociation: cfg.AllowReassociation,
+	}, nil
+}
+
+type PluginConfig = api.AWSEIPPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	aws.ValidateCredentials(validations, req.AccessKey, req.SecretKey, req.Region)
+
+	if req.NetworkInterfaceID == "" {
+		validations.Set("plugin_config.network_interface_id", "missing network interface ID")
+	}
+	if req.NetworkInterfaceID != "" && !aws.IDRegex.MatchString(req.NetworkInterfaceID) {
+		validations.Set("plugin_config.network_interface_id", "invalid network interface ID format")
+	}
+
+	ip := net.ParseIP(req.PrivateIP)
+	if req.PrivateIP != "" && (ip == nil || ip.To4() == nil) {
+		validations.Set("plugin_config.private_ip", "invalid private IP format, must be an IPv4 address")
+	}
+
+	switch req.Mode {
+	case "", api.AWSEIPModeElasticIP:
+		if req.AllocationID == "" {
+			validations.Set("plugin_config.allocation_id", "missing allocation ID")
+		}
+		if req.AllocationID != "" && !aws.IDRegex.MatchString(req.AllocationID) {
+			validations.Set("plugin_config.allocation_id", "invalid allocation ID format")
+		}
+		if req.Interface != "" {
+			validations.Set("plugin_config.interface", "the interface can only be set with the private_ip mode")
+		}
+	case api.AWSEIPModePrivateIP:
+		if req.PrivateIP == "" {
+			validations.Set("plugin_config.private_ip", "missing private IP")
+		}
+		if req.AllocationID != "" {
+			validations.Set("plugin_config.allocation_id", "the allocation ID can only be set with the elastic_ip mode")
+		}
+	default:
+		validations.Set("plugin_config.mode", "invalid mode, must be elastic_ip or private_ip")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		Mode:               req.Mode,
+		AllocationID:       req.AllocationID,
+		NetworkInterfaceID: req.NetworkInterfaceID,
+		PrivateIP:          req.PrivateIP,
+		AllowReassociation: req.AllowReassociation == nil || *req.AllowReassociation,
+		Interface:          req.Interface,
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = api.AWSEIPModeElasticIP
+	}
+	cfg.StorableCredentials, err = aws.EncryptCredentials(ctx, f.encryptedStorage, endpoint.ID, req.AccessKey, req.SecretKey, req.Region)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt credentials")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	aws.StorableCredentials
+
+	Mode               string `json:"mode"`
+	AllocationID       string `json:"allocation_id,omitempty"`
+	NetworkInterfaceID string `json:"network_interface_id"`
+	PrivateIP          string `json:"private_ip,omitempty"`
+	AllowReassociation bool   `json:"allow_reassociation"`
+	Interface          string `json:"interface,omitempty"`
+}
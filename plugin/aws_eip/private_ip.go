@@ -0,0 +1,217 @@
+package awseip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/services/aws"
+)
+
+type PrivateIPPlugin struct {
+	awsClient aws.PrivateIPClient
+
+	refreshEvery time.Duration
+
+	// Private IP Configuration
+	privateIP          string // Secondary private IP to move
+	networkInterfaceID string // ID of the ENI to move the private IP to
+	allowReassignment  bool
+	// netInterface is the local interface where the private IP is added, nil if the IP is not
+	// configured on the host
+	netInterface network.Interface
+
+	// Internal configuration
+	lastRefreshedAt time.Time
+}
+
+// Activate assigns the private IP to the ENI, unassigning it from the ENI it was assigned to, then
+// adds it to the local interface
+func (p *PrivateIPPlugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	log.Info("Assigning private IP to ENI")
+	err := p.awsClient.AssignPrivateIPAddresses(ctx, aws.AssignPrivateIPAddressesParams{
+		NetworkInterfaceID: p.networkInterfaceID,
+		PrivateIPAddresses: []string{p.privateIP},
+		AllowReassignment:  p.allowReassignment,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "assign private IP")
+	}
+	p.lastRefreshedAt = time.Now()
+
+	if p.netInterface != nil {
+		err = p.netInterface.EnsureIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "add private IP to the interface")
+		}
+	}
+
+	return nil
+}
+
+// Deactivate removes the private IP from the local interface, then unassigns it from the ENI if it
+// has not been assigned to another ENI yet
+func (p *PrivateIPPlugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.netInterface != nil {
+		err := p.netInterface.RemoveIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "remove private IP from the interface")
+		}
+	}
+
+	assigned, err := p.isAssigned(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "describe ENI")
+	}
+	if !assigned {
+		log.Info("Private IP is not assigned to the ENI, skipping unassignment")
+		return nil
+	}
+
+	log.Info("Unassigning private IP from ENI")
+	err = p.unassign(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unassign private IP")
+	}
+	return nil
+}
+
+func (p *PrivateIPPlugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	// The local interface is checked at every run, it does not call the EC2 API
+	if p.netInterface != nil {
+		err := p.netInterface.EnsureIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "add private IP to the interface")
+		}
+	}
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	assigned, err := p.isAssigned(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "describe ENI")
+	}
+
+	// If the private IP is not assigned to the ENI, we need to assign it
+	if !assigned {
+		log.Info("Private IP is not assigned to the ENI, assigning it")
+		err := p.Activate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "assign private IP")
+		}
+		return nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// IsActivated returns true if the private IP is assigned to the ENI
+func (p *PrivateIPPlugin) IsActivated(ctx context.Context) (bool, error) {
+	assigned, err := p.isAssigned(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "describe ENI")
+	}
+	if !assigned {
+		return false, nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated removes the private IP from the local interface and unassigns it if it is
+// still assigned to the ENI of this host.
+func (p *PrivateIPPlugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.netInterface != nil {
+		has, err := p.netInterface.HasIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "check the interface")
+		}
+		if has {
+			log.Info("Private IP is still on the interface, removing it")
+			err = p.netInterface.RemoveIP(p.cidr())
+			if err != nil {
+				return errors.Wrap(ctx, err, "remove private IP from the interface")
+			}
+		}
+	}
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	assigned, err := p.isAssigned(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "describe ENI")
+	}
+
+	// The private IP is assigned to another ENI, nothing to do
+	if !assigned {
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.Info("Private IP is still assigned to the ENI, unassigning it")
+	err = p.unassign(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unassign private IP")
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// isAssigned returns true if the private IP is a secondary private IP of the ENI
+func (p *PrivateIPPlugin) isAssigned(ctx context.Context) (bool, error) {
+	networkInterface, err := p.awsClient.DescribeNetworkInterface(ctx, p.networkInterfaceID)
+	if err != nil {
+		return false, err
+	}
+	for _, privateIP := range networkInterface.PrivateIPAddresses {
+		if privateIP.PrivateIPAddress == p.privateIP {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *PrivateIPPlugin) unassign(ctx context.Context) error {
+	return p.awsClient.UnassignPrivateIPAddresses(ctx, p.networkInterfaceID, []string{p.privateIP})
+}
+
+// cidr returns the private IP as configured on the local interface
+func (p *PrivateIPPlugin) cidr() string {
+	return p.privateIP + "/32"
+}
+
+func (p *PrivateIPPlugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, p.privateIP)
+}
+
+func (p *PrivateIPPlugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":                 "aws_eip",
+		"private_ip":           p.privateIP,
+		"network_interface_id": p.networkInterfaceID,
+	}
+}
@@ -0,0 +1,208 @@
+package awseip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network/networkmock"
+	"github.com/Scalingo/link/v3/services/aws"
+	"github.com/Scalingo/link/v3/services/aws/awsmock"
+)
+
+const testPrivateIP = "10.0.1.10"
+
+func newPrivateIPPlugin(mockClient *awsmock.MockPrivateIPClient) *PrivateIPPlugin {
+	return &PrivateIPPlugin{
+		awsClient:          mockClient,
+		refreshEvery:       time.Minute,
+		privateIP:          testPrivateIP,
+		networkInterfaceID: testNetworkInterfaceID,
+		allowReassignment:  true,
+	}
+}
+
+// eniWithPrivateIPs returns an ENI with the given private IPs, the first one being the primary IP
+func eniWithPrivateIPs(ips ...string) aws.NetworkInterface {
+	privateIPs := make([]aws.PrivateIPAddress, 0, len(ips))
+	for i, ip := range ips {
+		privateIPs = append(privateIPs, aws.PrivateIPAddress{PrivateIPAddress: ip, Primary: i == 0})
+	}
+	return aws.NetworkInterface{
+		NetworkInterfaceID: testNetworkInterfaceID,
+		PrivateIPAddresses: privateIPs,
+	}
+}
+
+func TestPrivateIPPlugin_Activate(t *testing.T) {
+	t.Run("it reassigns the private IP to the ENI and adds it to the interface", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+		plugin.netInterface = netInterface
+
+		gomock.InOrder(
+			mockClient.EXPECT().
+				AssignPrivateIPAddresses(gomock.Any(), aws.AssignPrivateIPAddressesParams{
+					NetworkInterfaceID: testNetworkInterfaceID,
+					PrivateIPAddresses: []string{testPrivateIP},
+					AllowReassignment:  true,
+				}).
+				Return(nil),
+			netInterface.EXPECT().EnsureIP(testPrivateIP+"/32").Return(nil),
+		)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+
+		mockClient.EXPECT().
+			AssignPrivateIPAddresses(gomock.Any(), gomock.Any()).
+			Return(errors.New("assign error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "assign error")
+	})
+}
+
+func TestPrivateIPPlugin_Deactivate(t *testing.T) {
+	t.Run("it removes the private IP from the interface and unassigns it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+		plugin.netInterface = netInterface
+
+		gomock.InOrder(
+			netInterface.EXPECT().RemoveIP(testPrivateIP+"/32").Return(nil),
+			mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5", testPrivateIP), nil),
+			mockClient.EXPECT().UnassignPrivateIPAddresses(gomock.Any(), testNetworkInterfaceID, []string{testPrivateIP}).Return(nil),
+		)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a private IP already reassigned to another ENI is not unassigned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5"), nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPrivateIPPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed, only the interface is checked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+		plugin.netInterface = netInterface
+		plugin.lastRefreshedAt = time.Now()
+
+		netInterface.EXPECT().EnsureIP(testPrivateIP + "/32").Return(nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a private IP assigned to another ENI is assigned again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5"), nil)
+		mockClient.EXPECT().AssignPrivateIPAddresses(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a private IP assigned to the ENI", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+}
+
+func TestPrivateIPPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("a private IP left on the interface is removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+		plugin.netInterface = netInterface
+		plugin.lastRefreshedAt = time.Now()
+
+		netInterface.EXPECT().HasIP(testPrivateIP+"/32").Return(true, nil)
+		netInterface.EXPECT().RemoveIP(testPrivateIP + "/32").Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a private IP still assigned to our ENI is unassigned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+		mockClient.EXPECT().UnassignPrivateIPAddresses(gomock.Any(), testNetworkInterfaceID, []string{testPrivateIP}).Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("unassign error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+		plugin := newPrivateIPPlugin(mockClient)
+
+		mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+		mockClient.EXPECT().UnassignPrivateIPAddresses(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("unassign error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestPrivateIPPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := awsmock.NewMockPrivateIPClient(ctrl)
+	plugin := newPrivateIPPlugin(mockClient)
+
+	mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockClient.EXPECT().DescribeNetworkInterface(gomock.Any(), testNetworkInterfaceID).Return(eniWithPrivateIPs("10.0.1.5"), nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
@@ -0,0 +1,110 @@
+package bgp
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network/bgp"
+)
+
+type Plugin struct {
+	endpoint models.Endpoint
+	paths    []bgp.Path
+	peers    []bgp.PeerConfig
+	pool     *speakerPool
+	// speakers are the sessions with the peers, they may be shared with other endpoints. They are
+	// only acquired while the endpoint is activated.
+	speakers []bgp.Speaker
+}
+
+// Activate opens the sessions with the peers and announces the prefixes. The prefixes are sent as
+// soon as the sessions are established.
+func (p *Plugin) Activate(ctx context.Context) error {
+	log := logger.Get(ctx)
+	p.acquireSpeakers()
+	p.announce()
+	log.WithField("bgp_prefixes", p.prefixes()).Info("Prefixes announced")
+	return nil
+}
+
+// Deactivate withdraws the prefixes from the peers. The sessions are closed if no other endpoint
+// uses them.
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	log := logger.Get(ctx)
+	p.withdraw()
+	p.releaseSpeakers()
+	log.WithField("bgp_prefixes", p.prefixes()).Info("Prefixes withdrawn")
+	return nil
+}
+
+func (p *Plugin) acquireSpeakers() {
+	if p.speakers != nil {
+		return
+	}
+	p.speakers = make([]bgp.Speaker, 0, len(p.peers))
+	for _, peer := range p.peers {
+		p.speakers = append(p.speakers, p.pool.acquire(peer))
+	}
+}
+
+func (p *Plugin) releaseSpeakers() {
+	if p.speakers == nil {
+		return
+	}
+	for _, peer := range p.peers {
+		p.pool.release(peer)
+	}
+	p.speakers = nil
+}
+
+// Ensure announces the prefixes again and checks that at least one session is established
+func (p *Plugin) Ensure(ctx context.Context) error {
+	p.acquireSpeakers()
+	p.announce()
+	for _, speaker := range p.speakers {
+		if speaker.Established() {
+			return nil
+		}
+	}
+	return errors.New(ctx, "no BGP session established")
+}
+
+// EnsureDeactivated withdraws the prefixes if they are still announced
+func (p *Plugin) EnsureDeactivated(_ context.Context) error {
+	p.withdraw()
+	return nil
+}
+
+func (p *Plugin) announce() {
+	for _, speaker := range p.speakers {
+		for _, path := range p.paths {
+			speaker.Announce(path)
+		}
+	}
+}
+
+func (p *Plugin) withdraw() {
+	for _, speaker := range p.speakers {
+		for _, path := range p.paths {
+			speaker.Withdraw(path.Prefix)
+		}
+	}
+}
+
+func (p *Plugin) prefixes() []string {
+	prefixes := make([]string, 0, len(p.paths))
+	for _, path := range p.paths {
+		prefixes = append(prefixes, path.Prefix.String())
+	}
+	slices.Sort(prefixes)
+	return prefixes
+}
+
+// ElectionKey is based on the announced prefixes
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return "bgp:" + strings.ReplaceAll(strings.Join(p.prefixes(), ","), "/", "_")
+}
@@ -0,0 +1,84 @@
+package bgp
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network/bgp"
+	"github.com/Scalingo/link/v3/network/bgp/bgpmock"
+)
+
+func newTestPlugin(speakers ...bgp.Speaker) (*Plugin, bgp.Path, bgp.Path) {
+	path1 := bgp.Path{Prefix: netip.MustParsePrefix("203.0.113.10/32")}
+	path2 := bgp.Path{Prefix: netip.MustParsePrefix("2001:db8::/48")}
+	return &Plugin{
+		paths:    []bgp.Path{path1, path2},
+		speakers: speakers,
+	}, path1, path2
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	speaker1 := bgpmock.NewMockSpeaker(ctrl)
+	speaker2 := bgpmock.NewMockSpeaker(ctrl)
+	p, path1, path2 := newTestPlugin(speaker1, speaker2)
+
+	for _, speaker := range []*bgpmock.MockSpeaker{speaker1, speaker2} {
+		speaker.EXPECT().Announce(path1)
+		speaker.EXPECT().Announce(path2)
+	}
+
+	err := p.Activate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	speaker := bgpmock.NewMockSpeaker(ctrl)
+	p, path1, path2 := newTestPlugin(speaker)
+
+	speaker.EXPECT().Withdraw(path1.Prefix)
+	speaker.EXPECT().Withdraw(path2.Prefix)
+
+	err := p.Deactivate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("it announces the prefixes again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		speaker1 := bgpmock.NewMockSpeaker(ctrl)
+		speaker2 := bgpmock.NewMockSpeaker(ctrl)
+		p, _, _ := newTestPlugin(speaker1, speaker2)
+
+		speaker1.EXPECT().Announce(gomock.Any()).Times(2)
+		speaker2.EXPECT().Announce(gomock.Any()).Times(2)
+		speaker1.EXPECT().Established().Return(false)
+		speaker2.EXPECT().Established().Return(true)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it fails if no session is established", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		speaker := bgpmock.NewMockSpeaker(ctrl)
+		p, _, _ := newTestPlugin(speaker)
+
+		speaker.EXPECT().Announce(gomock.Any()).Times(2)
+		speaker.EXPECT().Established().Return(false)
+
+		err := p.Ensure(context.Background())
+		require.ErrorContains(t, err, "no BGP session established")
+	})
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	p, _, _ := newTestPlugin()
+	assert.Equal(t, "bgp:2001:db8::_48,203.0.113.10_32", p.ElectionKey(context.Background()))
+}
@@ -0,0 +1,381 @@
+package bgp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network/bgp"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+const Name = api.PluginBGP
+
+type Config struct {
+	// AS number of LinK. It can be overridden per endpoint.
+	LocalASN uint32 `envconfig:"BGP_LOCAL_ASN"`
+	// BGP identifier of LinK, an IPv4 address usually set to the main IP of the host
+	RouterID string `envconfig:"BGP_ROUTER_ID"`
+	// Comma-separated list of peers: <ASN>@<address>[:<port>] (e.g. 65000@192.0.2.1,65000@[2001:db8::1]:1179).
+	// They can be overridden per endpoint.
+	Peers []string `envconfig:"BGP_PEERS"`
+	// Default next hop of the prefixes of the same IP family. Defaults to the local address of the
+	// BGP sessions.
+	NextHop string `envconfig:"BGP_NEXT_HOP"`
+	// Hold time proposed to the peers
+	HoldTime time.Duration `envconfig:"BGP_HOLD_TIME" default:"90s"`
+	// Duration between two connection attempts to a peer
+	ConnectRetryInterval time.Duration `envconfig:"BGP_CONNECT_RETRY_INTERVAL" default:"5s"`
+}
+
+type PluginConfig = api.BGPPluginConfig
+
+type Factory struct {
+	config   Config
+	peers    []api.BGPPeerConfig
+	routerID netip.Addr
+	nextHop  netip.Addr
+	speakers *speakerPool
+}
+
+// speakerPool shares the BGP sessions between the endpoints: a single session is opened with each
+// peer. A session is closed once the last endpoint using it releases it.
+type speakerPool struct {
+	mutex      sync.Mutex
+	speakers   map[bgp.PeerConfig]*pooledSpeaker
+	newSpeaker func(config bgp.PeerConfig) (bgp.Speaker, func())
+}
+
+type pooledSpeaker struct {
+	speaker bgp.Speaker
+	// stop closes the session, a CEASE notification is sent to the peer if it is established
+	stop  func()
+	users int
+}
+
+func newSpeakerPool(newSpeaker func(config bgp.PeerConfig) (bgp.Speaker, func())) *speakerPool {
+	return &speakerPool{
+		speakers:   make(map[bgp.PeerConfig]*pooledSpeaker),
+		newSpeaker: newSpeaker,
+	}
+}
+
+// acquire returns the speaker of the peer, starting the session if needed. The speaker must be
+// released once it is not used anymore.
+func (p *speakerPool) acquire(config bgp.PeerConfig) bgp.Speaker {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pooled, ok := p.speakers[config]
+	if !ok {
+		speaker, stop := p.newSpeaker(config)
+		pooled = &pooledSpeaker{speaker: speaker, stop: stop}
+		p.speakers[config] = pooled
+	}
+	pooled.users++
+	return pooled.speaker
+}
+
+// release closes the session with the peer if no other endpoint uses it
+func (p *speakerPool) release(config bgp.PeerConfig) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	pooled, ok := p.speakers[config]
+	if !ok {
+		return
+	}
+	pooled.users--
+	if pooled.users > 0 {
+		return
+	}
+	delete(p.speakers, config)
+	pooled.stop()
+}
+
+// startSession connects to the peer in the background. The returned function closes the session.
+func startSession(ctx context.Context, config bgp.PeerConfig) (bgp.Speaker, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	session := bgp.NewSession(config)
+	go session.Run(ctx)
+	return session, cancel
+}
+
+func Register(ctx context.Context, registry plugin.Registry) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	factory := Factory{
+		config: config,
+		speakers: newSpeakerPool(func(config bgp.PeerConfig) (bgp.Speaker, func()) {
+			return startSession(ctx, config)
+		}),
+	}
+
+	if config.RouterID != "" {
+		factory.routerID, err = netip.ParseAddr(config.RouterID)
+		if err != nil || !factory.routerID.Is4() {
+			return errors.Newf(ctx, "invalid BGP_ROUTER_ID %s, it must be an IPv4 address", config.RouterID)
+		}
+	}
+	if config.NextHop != "" {
+		factory.nextHop, err = netip.ParseAddr(config.NextHop)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "invalid BGP_NEXT_HOP %s", config.NextHop)
+		}
+	}
+	for _, peer := range config.Peers {
+		peerConfig, err := parsePeer(ctx, peer)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "invalid peer %s in BGP_PEERS", peer)
+		}
+		factory.peers = append(factory.peers, peerConfig)
+	}
+	if config.HoldTime != 0 && config.HoldTime < 3*time.Second {
+		return errors.New(ctx, "invalid BGP_HOLD_TIME, it must be 0 or at least 3s")
+	}
+	if config.ConnectRetryInterval <= 0 {
+		return errors.New(ctx, "invalid BGP_CONNECT_RETRY_INTERVAL, it must be positive")
+	}
+
+	registry.Register(ctx, Name, factory)
+	return nil
+}
+
+// parsePeer parses a peer written as <ASN>@<address>[:<port>]
+func parsePeer(ctx context.Context, peer string) (api.BGPPeerConfig, error) {
+	asn, address, ok := strings.Cut(strings.TrimSpace(peer), "@")
+	if !ok {
+		return api.BGPPeerConfig{}, errors.New(ctx, "expected <ASN>@<address>[:<port>]")
+	}
+	peerASN, err := strconv.ParseUint(asn, 10, 32)
+	if err != nil {
+		return api.BGPPeerConfig{}, errors.Wrap(ctx, err, "invalid ASN")
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err == nil {
+		return api.BGPPeerConfig{Address: addrPort.Addr().String(), Port: int(addrPort.Port()), ASN: uint32(peerASN)}, nil
+	}
+	return api.BGPPeerConfig{Address: address, ASN: uint32(peerASN)}, nil
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	paths, err := f.paths(ctx, cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid plugin config")
+	}
+	peers, err := f.peerConfigs(ctx, cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid plugin config")
+	}
+
+	return &Plugin{
+		endpoint: endpoint,
+		paths:    paths,
+		peers:    peers,
+		pool:     f.speakers,
+	}, nil
+}
+
+func (f Factory) Validate(ctx context.Context, endpoint models.Endpoint) error {
+	validation := errors.NewValidationErrorsBuilder()
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		validation.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validation.Build()
+	}
+
+	prefixes := cfg.Prefixes()
+	if len(prefixes) == 0 {
+		validation.Set("plugin_config.ip", "ip is required")
+	}
+	seen := make(map[netip.Prefix]bool, len(prefixes))
+	for _, p := range prefixes {
+		prefix, err := parsePrefix(ctx, p)
+		if err != nil {
+			validation.Set("plugin_config.ips", err.Error())
+			continue
+		}
+		if seen[prefix] {
+			validation.Set("plugin_config.ips", "duplicated prefix: "+p)
+		}
+		seen[prefix] = true
+	}
+
+	if cfg.NextHop != "" {
+		_, err = netip.ParseAddr(cfg.NextHop)
+		if err != nil {
+			validation.Set("plugin_config.next_hop", "invalid next hop: "+err.Error())
+		}
+	}
+	for i, community := range cfg.Communities {
+		_, err = bgp.ParseCommunity(community)
+		if err != nil {
+			validation.Set(fmt.Sprintf("plugin_config.communities.%d", i), err.Error())
+		}
+	}
+
+	if !f.routerID.IsValid() {
+		validation.Set("plugin_config", "BGP_ROUTER_ID must be configured on the host")
+	}
+	if cfg.LocalASN == 0 && f.config.LocalASN == 0 {
+		validation.Set("plugin_config.local_asn", "local_asn is required if no AS number is configured on the host")
+	}
+	if len(cfg.Peers) == 0 && len(f.peers) == 0 {
+		validation.Set("plugin_config.peers", "peers are required if no peer is configured on the host")
+	}
+	for i, peer := range cfg.Peers {
+		_, err = parsePeerAddress(ctx, peer)
+		if err != nil {
+			validation.Set(fmt.Sprintf("plugin_config.peers.%d", i), err.Error())
+		}
+	}
+
+	validationErr := validation.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+	return nil
+}
+
+// parsePrefix parses a prefix using CIDR notation, or a bare IP which is a host prefix
+func parsePrefix(ctx context.Context, value string) (netip.Prefix, error) {
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, errors.Wrapf(ctx, err, "invalid IP address %s", value)
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, errors.Wrapf(ctx, err, "invalid prefix %s", value)
+	}
+	if prefix != prefix.Masked() {
+		return netip.Prefix{}, errors.Newf(ctx, "invalid prefix %s, the host bits must be zero", value)
+	}
+	return prefix, nil
+}
+
+// paths returns the paths announced by the endpoint
+func (f Factory) paths(ctx context.Context, cfg PluginConfig) ([]bgp.Path, error) {
+	prefixes := cfg.Prefixes()
+	if len(prefixes) == 0 {
+		return nil, errors.New(ctx, "no IP")
+	}
+
+	nextHop := f.nextHop
+	if cfg.NextHop != "" {
+		var err error
+		nextHop, err = netip.ParseAddr(cfg.NextHop)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid next hop %s", cfg.NextHop)
+		}
+	}
+
+	communities := make([]uint32, 0, len(cfg.Communities))
+	for _, c := range cfg.Communities {
+		community, err := bgp.ParseCommunity(c)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "invalid community")
+		}
+		communities = append(communities, community)
+	}
+
+	paths := make([]bgp.Path, 0, len(prefixes))
+	for _, p := range prefixes {
+		prefix, err := parsePrefix(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		path := bgp.Path{Prefix: prefix, Communities: communities}
+		// The next hop only applies to the prefixes of the same IP family, the other ones use the
+		// local address of the session
+		if nextHop.IsValid() && nextHop.Is4() == prefix.Addr().Is4() {
+			path.NextHop = nextHop
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// peerConfigs returns the configuration of the sessions with the peers of the endpoint
+func (f Factory) peerConfigs(ctx context.Context, cfg PluginConfig) ([]bgp.PeerConfig, error) {
+	if !f.routerID.IsValid() {
+		return nil, errors.New(ctx, "no BGP_ROUTER_ID configured on the host")
+	}
+	localASN := f.config.LocalASN
+	if cfg.LocalASN != 0 {
+		localASN = cfg.LocalASN
+	}
+	if localASN == 0 {
+		return nil, errors.New(ctx, "no local AS number")
+	}
+	peers := f.peers
+	if len(cfg.Peers) > 0 {
+		peers = cfg.Peers
+	}
+	if len(peers) == 0 {
+		return nil, errors.New(ctx, "no peer")
+	}
+
+	peerConfigs := make([]bgp.PeerConfig, 0, len(peers))
+	for _, peer := range peers {
+		address, err := parsePeerAddress(ctx, peer)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid peer %s", peer.Address)
+		}
+		// The defaults are filled in here since the configuration identifies the session in the pool
+		port := peer.Port
+		if port == 0 {
+			port = bgp.DefaultPort
+		}
+		peerConfigs = append(peerConfigs, bgp.PeerConfig{
+			Address:              address,
+			Port:                 port,
+			ASN:                  peer.ASN,
+			LocalASN:             localASN,
+			RouterID:             f.routerID,
+			HoldTime:             f.config.HoldTime,
+			ConnectRetryInterval: f.config.ConnectRetryInterval,
+		})
+	}
+	return peerConfigs, nil
+}
+
+// parsePeerAddress checks the peer configuration and returns its address
+func parsePeerAddress(ctx context.Context, peer api.BGPPeerConfig) (netip.Addr, error) {
+	address, err := netip.ParseAddr(peer.Address)
+	if err != nil {
+		return netip.Addr{}, errors.Wrapf(ctx, err, "invalid address %s", peer.Address)
+	}
+	if peer.ASN == 0 {
+		return netip.Addr{}, errors.New(ctx, "asn is required")
+	}
+	if peer.Port < 0 || peer.Port > 65535 {
+		return netip.Addr{}, errors.New(ctx, "port must be between 0 and 65535")
+	}
+	return address.Unmap(), nil
+}
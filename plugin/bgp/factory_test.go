@@ -0,0 +1,343 @@
+package bgp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network/bgp"
+	"github.com/Scalingo/link/v3/network/bgp/bgpmock"
+)
+
+func newTestFactory(newSpeaker func(bgp.PeerConfig) (bgp.Speaker, func())) Factory {
+	return Factory{
+		config: Config{
+			LocalASN:             65000,
+			HoldTime:             90 * time.Second,
+			ConnectRetryInterval: 5 * time.Second,
+		},
+		peers:    []api.BGPPeerConfig{{Address: "192.0.2.1", ASN: 65001}},
+		routerID: netip.MustParseAddr("192.0.2.10"),
+		speakers: newSpeakerPool(newSpeaker),
+	}
+}
+
+// acceptTestSession accepts a session from LinK on the listener and establishes it
+func acceptTestSession(t *testing.T, listener net.Listener) net.Conn {
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	msgType, _, err := readTestMessage(conn)
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), msgType)
+
+	// OPEN message of AS 65001 with a hold time of 30s and the router ID 192.0.2.1, followed by a
+	// KEEPALIVE message
+	_, err = conn.Write(testMessage(1, []byte{4, 0xfd, 0xe9, 0, 30, 192, 0, 2, 1, 0}))
+	require.NoError(t, err)
+	_, err = conn.Write(testMessage(4, nil))
+	require.NoError(t, err)
+
+	msgType, _, err = readTestMessage(conn)
+	require.NoError(t, err)
+	require.Equal(t, uint8(4), msgType)
+	return conn
+}
+
+func testMessage(msgType uint8, body []byte) []byte {
+	msg := bytes.Repeat([]byte{0xff}, 16)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(19+len(body)))
+	msg = append(msg, msgType)
+	return append(msg, body...)
+}
+
+func readTestMessage(conn net.Conn) (uint8, []byte, error) {
+	header := make([]byte, 19)
+	_, err := io.ReadFull(conn, header)
+	if err != nil {
+		return 0, nil, err
+	}
+	body := make([]byte, int(binary.BigEndian.Uint16(header[16:18]))-19)
+	_, err = io.ReadFull(conn, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return header[18], body, nil
+}
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		NoHostConfig  bool
+		ExpectedError string
+	}{
+		{
+			Name:   "with an IP",
+			Config: PluginConfig{IP: "203.0.113.10"},
+		}, {
+			Name: "with prefixes, communities and peers",
+			Config: PluginConfig{
+				IP:          "203.0.113.0/24",
+				IPs:         []string{"2001:db8::/48"},
+				NextHop:     "10.0.0.1",
+				Communities: []string{"65000:100", "no-export"},
+				LocalASN:    4200000000,
+				Peers:       []api.BGPPeerConfig{{Address: "2001:db8::1", Port: 1179, ASN: 65001}},
+			},
+		}, {
+			Name:          "without IP",
+			Config:        PluginConfig{},
+			ExpectedError: "ip is required",
+		}, {
+			Name:          "with a prefix with host bits",
+			Config:        PluginConfig{IP: "203.0.113.10/24"},
+			ExpectedError: "the host bits must be zero",
+		}, {
+			Name:          "with a duplicated prefix",
+			Config:        PluginConfig{IP: "203.0.113.10", IPs: []string{"203.0.113.10/32"}},
+			ExpectedError: "duplicated prefix",
+		}, {
+			Name:          "with an invalid next hop",
+			Config:        PluginConfig{IP: "203.0.113.10", NextHop: "gateway"},
+			ExpectedError: "invalid next hop",
+		}, {
+			Name:          "with an invalid community",
+			Config:        PluginConfig{IP: "203.0.113.10", Communities: []string{"65000"}},
+			ExpectedError: "invalid community",
+		}, {
+			Name:          "with a peer without ASN",
+			Config:        PluginConfig{IP: "203.0.113.10", Peers: []api.BGPPeerConfig{{Address: "192.0.2.2"}}},
+			ExpectedError: "asn is required",
+		}, {
+			Name:          "without host configuration",
+			Config:        PluginConfig{IP: "203.0.113.10"},
+			NoHostConfig:  true,
+			ExpectedError: "peers are required if no peer is configured on the host",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			factory := newTestFactory(nil)
+			if spec.NoHostConfig {
+				factory = Factory{}
+			}
+
+			pluginConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = factory.Validate(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			if spec.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.ExpectedError)
+		})
+	}
+}
+
+func TestFactory_Create(t *testing.T) {
+	t.Run("it does not open the sessions before the activation", func(t *testing.T) {
+		factory := newTestFactory(func(config bgp.PeerConfig) (bgp.Speaker, func()) {
+			t.Fatal("unexpected session")
+			return nil, nil
+		})
+
+		pluginConfig, err := json.Marshal(PluginConfig{IP: "203.0.113.10", IPs: []string{"2001:db8::10"}, NextHop: "10.0.0.1", Communities: []string{"65000:100"}})
+		require.NoError(t, err)
+		p, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+		require.NoError(t, err)
+
+		assert.Equal(t, []bgp.PeerConfig{{
+			Address:              netip.MustParseAddr("192.0.2.1"),
+			Port:                 179,
+			ASN:                  65001,
+			LocalASN:             65000,
+			RouterID:             netip.MustParseAddr("192.0.2.10"),
+			HoldTime:             90 * time.Second,
+			ConnectRetryInterval: 5 * time.Second,
+		}}, p.(*Plugin).peers)
+		// The next hop only applies to the prefixes of its IP family
+		assert.Equal(t, []bgp.Path{
+			{Prefix: netip.MustParsePrefix("203.0.113.10/32"), NextHop: netip.MustParseAddr("10.0.0.1"), Communities: []uint32{0xfde80064}},
+			{Prefix: netip.MustParsePrefix("2001:db8::10/128"), Communities: []uint32{0xfde80064}},
+		}, p.(*Plugin).paths)
+	})
+
+	t.Run("it shares the sessions between the activated endpoints", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		speaker := bgpmock.NewMockSpeaker(ctrl)
+		speaker.EXPECT().Announce(gomock.Any()).AnyTimes()
+		speaker.EXPECT().Withdraw(gomock.Any()).AnyTimes()
+		sessions, stops := 0, 0
+		factory := newTestFactory(func(config bgp.PeerConfig) (bgp.Speaker, func()) {
+			sessions++
+			return speaker, func() { stops++ }
+		})
+
+		pluginConfig, err := json.Marshal(PluginConfig{IP: "203.0.113.10"})
+		require.NoError(t, err)
+		p1, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+		require.NoError(t, err)
+		p2, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+		require.NoError(t, err)
+
+		require.NoError(t, p1.Activate(context.Background()))
+		require.NoError(t, p2.Activate(context.Background()))
+		// Activating an endpoint twice does not take another reference on the session
+		require.NoError(t, p2.Activate(context.Background()))
+		assert.Equal(t, 1, sessions)
+
+		require.NoError(t, p1.Deactivate(context.Background()))
+		assert.Equal(t, 0, stops)
+		require.NoError(t, p2.Deactivate(context.Background()))
+		assert.Equal(t, 1, stops)
+		require.NoError(t, p2.Deactivate(context.Background()))
+		assert.Equal(t, 1, stops)
+		assert.Empty(t, factory.speakers.speakers)
+	})
+
+	t.Run("it closes the session with the peer once the last endpoint using it is deactivated", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		factory := newTestFactory(func(config bgp.PeerConfig) (bgp.Speaker, func()) {
+			return startSession(ctx, config)
+		})
+		factory.config.ConnectRetryInterval = 100 * time.Millisecond
+
+		pluginConfig, err := json.Marshal(PluginConfig{
+			IP:    "203.0.113.10",
+			Peers: []api.BGPPeerConfig{{Address: "127.0.0.1", Port: listener.Addr().(*net.TCPAddr).Port, ASN: 65001}},
+		})
+		require.NoError(t, err)
+		p1, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+		require.NoError(t, err)
+		p2, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+		require.NoError(t, err)
+
+		require.NoError(t, p1.Activate(context.Background()))
+		require.NoError(t, p2.Activate(context.Background()))
+		conn := acceptTestSession(t, listener)
+		assert.Eventually(t, func() bool {
+			return p1.Ensure(context.Background()) == nil
+		}, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, p1.Deactivate(context.Background()))
+		require.NoError(t, p2.Ensure(context.Background()))
+
+		require.NoError(t, p2.Deactivate(context.Background()))
+		for {
+			msgType, body, err := readTestMessage(conn)
+			require.NoError(t, err)
+			if msgType == 3 {
+				// CEASE notification
+				assert.Equal(t, uint8(6), body[0])
+				break
+			}
+		}
+		_, _, err = readTestMessage(conn)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("the peers and the AS number of the endpoint override the ones of the host", func(t *testing.T) {
+		factory := newTestFactory(nil)
+
+		pluginConfig, err := json.Marshal(PluginConfig{
+			IP:       "203.0.113.10",
+			LocalASN: 65100,
+			Peers:    []api.BGPPeerConfig{{Address: "192.0.2.2", Port: 1179, ASN: 65002}},
+		})
+		require.NoError(t, err)
+		p, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+		require.NoError(t, err)
+
+		peerConfigs := p.(*Plugin).peers
+		require.Len(t, peerConfigs, 1)
+		assert.Equal(t, netip.MustParseAddr("192.0.2.2"), peerConfigs[0].Address)
+		assert.Equal(t, 1179, peerConfigs[0].Port)
+		assert.Equal(t, uint32(65002), peerConfigs[0].ASN)
+		assert.Equal(t, uint32(65100), peerConfigs[0].LocalASN)
+	})
+
+	t.Run("a peer with the default port shares the session of the same peer with an explicit port", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		var peerConfigs []bgp.PeerConfig
+		factory := newTestFactory(func(config bgp.PeerConfig) (bgp.Speaker, func()) {
+			peerConfigs = append(peerConfigs, config)
+			speaker := bgpmock.NewMockSpeaker(ctrl)
+			speaker.EXPECT().Announce(gomock.Any()).AnyTimes()
+			return speaker, func() {}
+		})
+
+		for _, port := range []int{0, 179} {
+			pluginConfig, err := json.Marshal(PluginConfig{
+				IP:    "203.0.113.10",
+				Peers: []api.BGPPeerConfig{{Address: "192.0.2.2", Port: port, ASN: 65002}},
+			})
+			require.NoError(t, err)
+			p, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			require.NoError(t, err)
+			require.NoError(t, p.Activate(context.Background()))
+		}
+
+		require.Len(t, peerConfigs, 1)
+		assert.Equal(t, 179, peerConfigs[0].Port)
+	})
+}
+
+func TestParsePeer(t *testing.T) {
+	tests := map[string]struct {
+		peer          string
+		expected      api.BGPPeerConfig
+		expectedError string
+	}{
+		"with an IPv4 address": {
+			peer:     "65001@192.0.2.1",
+			expected: api.BGPPeerConfig{Address: "192.0.2.1", ASN: 65001},
+		},
+		"with an IPv6 address and a port": {
+			peer:     "65001@[2001:db8::1]:1179",
+			expected: api.BGPPeerConfig{Address: "2001:db8::1", Port: 1179, ASN: 65001},
+		},
+		"without ASN": {
+			peer:          "192.0.2.1",
+			expectedError: "expected <ASN>@<address>[:<port>]",
+		},
+		"with an invalid ASN": {
+			peer:          "AS65001@192.0.2.1",
+			expectedError: "invalid ASN",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			peer, err := parsePeer(context.Background(), test.peer)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, peer)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package dnsapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/plugin/internal/dnsrecord"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+type Plugin struct {
+	provider     dnsprovider.Provider
+	providerName string
+
+	// record is the record of the current host, in its canonical form
+	record    dnsprovider.Record
+	refresher *dnsrecord.Refresher
+}
+
+// Activate replaces the records with the name and type of the record by the record of the current
+// host
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+
+	err := p.refresher.Replace(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "replace record")
+	}
+	return nil
+}
+
+// Deactivate keeps the record, it is replaced when another host activates the endpoint and the
+// name keeps resolving in the meantime
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	_, log := logger.WithStructToCtx(ctx, "plugin", p)
+	log.Info("Keeping DNS record until another host replaces it")
+	return nil
+}
+
+// Ensure replaces the records again if they differ from the record of the current host
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+
+	err := p.refresher.Ensure(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "ensure record")
+	}
+	return nil
+}
+
+// IsActivated returns true if the record of the current host is the only record with its name and
+// type
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	upToDate, err := p.refresher.IsUpToDate(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "check record")
+	}
+	return upToDate, nil
+}
+
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s/%s", Name, strings.TrimSuffix(p.record.Name, "."), p.record.Type)
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":         "dns_api",
+		"provider":     p.providerName,
+		"record_name":  p.record.Name,
+		"record_type":  p.record.Type,
+		"record_value": p.record.Value,
+	}
+}
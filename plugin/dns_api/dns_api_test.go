@@ -0,0 +1,143 @@
+package dnsapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/plugin/internal/dnsrecord"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+	"github.com/Scalingo/link/v3/services/dnsprovider/dnsprovidermock"
+)
+
+var testRecord = dnsprovider.Record{Name: "www.example.com.", Type: "AAAA", TTL: 60, Value: "2001:db8::1"}
+
+func newPlugin(mockProvider *dnsprovidermock.MockProvider) *Plugin {
+	return &Plugin{
+		provider:     mockProvider,
+		providerName: "cloudflare",
+		record:       testRecord,
+		refresher:    dnsrecord.NewRefresher(mockProvider, testRecord, time.Minute),
+	}
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+		plugin := newPlugin(mockProvider)
+
+		mockProvider.EXPECT().ReplaceRecord(gomock.Any(), testRecord).Return(nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.True(t, plugin.refresher.RefreshedRecently())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+		plugin := newPlugin(mockProvider)
+
+		mockProvider.EXPECT().ReplaceRecord(gomock.Any(), testRecord).Return(errors.New("unauthorized"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "unauthorized")
+		assert.False(t, plugin.refresher.RefreshedRecently())
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+	plugin := newPlugin(mockProvider)
+
+	// The record is kept, the mock fails on any call
+	err := plugin.Deactivate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+		plugin := newPlugin(mockProvider)
+		plugin.refresher.MarkRefreshed()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	specs := map[string][]dnsprovider.Record{
+		"a missing record is added again":            nil,
+		"a record of another host is replaced":       {{Name: testRecord.Name, Type: "AAAA", TTL: 60, Value: "2001:db8::2"}},
+		"the records of the other hosts are removed": {testRecord, {Name: testRecord.Name, Type: "AAAA", TTL: 60, Value: "2001:db8::2"}},
+		"a record with another TTL is replaced":      {{Name: testRecord.Name, Type: "AAAA", TTL: 300, Value: testRecord.Value}},
+	}
+	for name, records := range specs {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+			plugin := newPlugin(mockProvider)
+
+			mockProvider.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "AAAA").Return(records, nil)
+			mockProvider.EXPECT().ReplaceRecord(gomock.Any(), testRecord).Return(nil)
+
+			err := plugin.Ensure(context.Background())
+			require.NoError(t, err)
+			assert.True(t, plugin.refresher.RefreshedRecently(), "the refresh should be recorded")
+		})
+	}
+
+	t.Run("an up to date record written in another form", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+		plugin := newPlugin(mockProvider)
+
+		mockProvider.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "AAAA").Return([]dnsprovider.Record{
+			{Name: testRecord.Name, Type: "AAAA", TTL: 60, Value: "2001:0db8:0000::1"},
+		}, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.True(t, plugin.refresher.RefreshedRecently(), "the refresh should be recorded")
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+		plugin := newPlugin(mockProvider)
+
+		mockProvider.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "AAAA").Return(nil, errors.New("rate limited"))
+
+		err := plugin.Ensure(context.Background())
+		require.ErrorContains(t, err, "rate limited")
+		assert.False(t, plugin.refresher.RefreshedRecently(), "the refresh should not be recorded")
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+	plugin := newPlugin(mockProvider)
+
+	mockProvider.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "AAAA").Return([]dnsprovider.Record{testRecord}, nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockProvider.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "AAAA").Return(nil, nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	plugin := newPlugin(nil)
+	assert.Equal(t, "dns_api/www.example.com/AAAA", plugin.ElectionKey(context.Background()))
+}
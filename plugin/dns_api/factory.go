@@ -0,0 +1,212 @@
+package dnsapi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/miekg/dns"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/plugin/internal/dnsrecord"
+	"github.com/Scalingo/link/v3/services/cloudflare"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+	"github.com/Scalingo/link/v3/services/powerdns"
+)
+
+const Name = api.PluginDNSAPI
+
+// DefaultTTL is the TTL of the record if the configuration of the endpoint has none
+const DefaultTTL = 60
+
+type Config struct {
+	RefreshEvery     time.Duration `envconfig:"DNS_API_REFRESH_INTERVAL" default:"1m"`
+	CloudflareAPIURL string        `envconfig:"CLOUDFLARE_API_URL" default:"https://api.cloudflare.com/client/v4"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	var apiToken string
+	err = f.encryptedStorage.Decrypt(ctx, cfg.APIToken, &apiToken)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt API token")
+	}
+
+	var provider dnsprovider.Provider
+	switch cfg.Provider {
+	case api.DNSAPIProviderCloudflare:
+		provider = cloudflare.NewClient(apiToken, cfg.Zone, f.config.CloudflareAPIURL)
+	case api.DNSAPIProviderPowerDNS:
+		provider = powerdns.NewClient(apiToken, cfg.APIURL, cfg.ServerID, cfg.Zone)
+	default:
+		return nil, errors.Newf(ctx, "unknown provider %s", cfg.Provider)
+	}
+
+	record := dnsprovider.Record{
+		Name:  cfg.Name,
+		Type:  cfg.Type,
+		TTL:   cfg.TTL,
+		Value: cfg.IP,
+	}
+	return &Plugin{
+		provider:     provider,
+		providerName: cfg.Provider,
+		record:       record,
+		refresher:    dnsrecord.NewRefresher(provider, record, f.config.RefreshEvery),
+	}, nil
+}
+
+type PluginConfig = api.DNSAPIPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	switch req.Provider {
+	case api.DNSAPIProviderCloudflare:
+		if req.APIURL != "" {
+			validations.Set("plugin_config.api_url", "the API URL can only be set with the powerdns provider, use CLOUDFLARE_API_URL for Cloudflare")
+		}
+		if req.ServerID != "" {
+			validations.Set("plugin_config.server_id", "the server ID can only be set with the powerdns provider")
+		}
+	case api.DNSAPIProviderPowerDNS:
+		if req.APIURL == "" {
+			validations.Set("plugin_config.api_url", "missing API URL")
+		}
+		apiURL, err := url.Parse(req.APIURL)
+		if req.APIURL != "" && (err != nil || (apiURL.Scheme != "http" && apiURL.Scheme != "https") || apiURL.Host == "") {
+			validations.Set("plugin_config.api_url", "invalid API URL, must be an http or https URL")
+		}
+	default:
+		validations.Set("plugin_config.provider", "invalid provider, must be cloudflare or powerdns")
+	}
+
+	if req.APIToken == "" {
+		validations.Set("plugin_config.api_token", "missing API token")
+	}
+
+	if req.Zone == "" {
+		validations.Set("plugin_config.zone", "missing zone")
+	}
+	if _, ok := dns.IsDomainName(req.Zone); req.Zone != "" && !ok {
+		validations.Set("plugin_config.zone", "invalid zone")
+	}
+
+	if req.Name == "" {
+		validations.Set("plugin_config.name", "missing name")
+	}
+	if _, ok := dns.IsDomainName(req.Name); req.Name != "" && !ok {
+		validations.Set("plugin_config.name", "invalid name")
+	} else if req.Name != "" && req.Zone != "" && !dns.IsSubDomain(dns.Fqdn(req.Zone), dnsrecord.CanonicalName(req.Name, req.Zone)) {
+		validations.Set("plugin_config.name", "the name must be in the zone")
+	}
+
+	if req.IP == "" {
+		validations.Set("plugin_config.ip", "missing IP")
+	}
+	if req.IP != "" && net.ParseIP(req.IP) == nil {
+		validations.Set("plugin_config.ip", "invalid IP format")
+	}
+
+	if req.TTL > math.MaxInt32 {
+		validations.Set("plugin_config.ttl", "invalid TTL, must be lower than 2147483648")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+// Mutate encrypts the API token, and saves the record in its canonical form for the comparisons
+// with the records returned by the provider
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	ip := net.ParseIP(req.IP)
+	cfg := StorablePluginConfig{
+		Provider: req.Provider,
+		APIURL:   req.APIURL,
+		ServerID: req.ServerID,
+		Zone:     dns.CanonicalName(req.Zone),
+		Name:     dnsrecord.CanonicalName(req.Name, req.Zone),
+		Type:     "AAAA",
+		IP:       ip.String(),
+		TTL:      req.TTL,
+	}
+	if ip.To4() != nil {
+		cfg.Type = "A"
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.Provider == api.DNSAPIProviderPowerDNS && cfg.ServerID == "" {
+		cfg.ServerID = powerdns.DefaultServerID
+	}
+
+	cfg.APIToken, err = f.encryptedStorage.Encrypt(ctx, endpoint.ID, req.APIToken)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt API token")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	Provider string                   `json:"provider"`
+	APIToken models.EncryptedDataLink `json:"api_token"`
+	APIURL   string                   `json:"api_url,omitempty"`
+	ServerID string                   `json:"server_id,omitempty"`
+	Zone     string                   `json:"zone"`
+	Name     string                   `json:"name"`
+	Type     string                   `json:"type"`
+	IP       string                   `json:"ip"`
+	TTL      uint32                   `json:"ttl"`
+}
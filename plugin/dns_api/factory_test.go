@@ -0,0 +1,180 @@
+package dnsapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/services/cloudflare"
+	"github.com/Scalingo/link/v3/services/powerdns"
+)
+
+func validConfig() PluginConfig {
+	return PluginConfig{
+		Provider: api.DNSAPIProviderCloudflare,
+		APIToken: "my-token",
+		Zone:     "example.com",
+		Name:     "www",
+		IP:       "192.0.2.1",
+	}
+}
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        func(cfg *PluginConfig)
+		ExpectedError string
+	}{
+		{
+			Name:   "with a valid Cloudflare configuration",
+			Config: func(*PluginConfig) {},
+		}, {
+			Name: "with a valid PowerDNS configuration",
+			Config: func(cfg *PluginConfig) {
+				cfg.Provider = api.DNSAPIProviderPowerDNS
+				cfg.APIURL = "http://127.0.0.1:8081"
+				cfg.Name = "www.example.com."
+				cfg.IP = "2001:db8::1"
+				cfg.TTL = 30
+			},
+		}, {
+			Name: "with an unknown provider",
+			Config: func(cfg *PluginConfig) {
+				cfg.Provider = "route53"
+			},
+			ExpectedError: "invalid provider, must be cloudflare or powerdns",
+		}, {
+			Name: "with an API URL for Cloudflare",
+			Config: func(cfg *PluginConfig) {
+				cfg.APIURL = "http://127.0.0.1:8081"
+			},
+			ExpectedError: "the API URL can only be set with the powerdns provider",
+		}, {
+			Name: "with a missing API URL for PowerDNS",
+			Config: func(cfg *PluginConfig) {
+				cfg.Provider = api.DNSAPIProviderPowerDNS
+			},
+			ExpectedError: "missing API URL",
+		}, {
+			Name: "with an invalid API URL for PowerDNS",
+			Config: func(cfg *PluginConfig) {
+				cfg.Provider = api.DNSAPIProviderPowerDNS
+				cfg.APIURL = "127.0.0.1:8081"
+			},
+			ExpectedError: "invalid API URL",
+		}, {
+			Name: "with a missing API token",
+			Config: func(cfg *PluginConfig) {
+				cfg.APIToken = ""
+			},
+			ExpectedError: "missing API token",
+		}, {
+			Name: "with a name outside of the zone",
+			Config: func(cfg *PluginConfig) {
+				cfg.Name = "www.example.org."
+			},
+			ExpectedError: "the name must be in the zone",
+		}, {
+			Name: "with an invalid IP",
+			Config: func(cfg *PluginConfig) {
+				cfg.IP = "192.0.2.300"
+			},
+			ExpectedError: "invalid IP format",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			cfg := validConfig()
+			spec.Config(&cfg)
+			rawConfig, err := json.Marshal(cfg)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := validConfig()
+	req.Provider = api.DNSAPIProviderPowerDNS
+	req.APIURL = "http://127.0.0.1:8081"
+	req.Name = "WWW"
+	req.IP = "2001:DB8:0::1"
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-token").Return(models.EncryptedDataLink{
+		ID:         "token-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the API token and save the record in its canonical form
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "token-id", stored.APIToken.ID)
+	assert.Equal(t, "example.com.", stored.Zone)
+	assert.Equal(t, "www.example.com.", stored.Name)
+	assert.Equal(t, "AAAA", stored.Type)
+	assert.Equal(t, "2001:db8::1", stored.IP)
+	assert.Equal(t, uint32(DefaultTTL), stored.TTL)
+	assert.Equal(t, "localhost", stored.ServerID)
+	assert.NotContains(t, string(res), "my-token")
+}
+
+func TestFactory_Create(t *testing.T) {
+	ctx := context.Background()
+
+	for provider, expectedType := range map[string]any{
+		api.DNSAPIProviderCloudflare: &cloudflare.APIClient{},
+		api.DNSAPIProviderPowerDNS:   &powerdns.APIClient{},
+	} {
+		t.Run(provider, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStorage := models.NewMockEncryptedStorage(ctrl)
+			mockStorage.EXPECT().Decrypt(ctx, models.EncryptedDataLink{ID: "token-id"}, gomock.Any()).Return(nil)
+
+			raw, _ := json.Marshal(StorablePluginConfig{
+				Provider: provider,
+				APIToken: models.EncryptedDataLink{ID: "token-id"},
+				Zone:     "example.com.",
+				Name:     "www.example.com.",
+				Type:     "A",
+				IP:       "192.0.2.1",
+				TTL:      60,
+			})
+			f := Factory{encryptedStorage: mockStorage}
+
+			p, err := f.Create(ctx, models.Endpoint{PluginConfig: raw})
+			require.NoError(t, err)
+			assert.IsType(t, expectedType, p.(*Plugin).provider)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package dnsrfc2136
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/plugin/internal/dnsrecord"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+	"github.com/Scalingo/link/v3/services/rfc2136"
+)
+
+type Plugin struct {
+	dnsClient rfc2136.RecordClient
+
+	// Record Configuration
+	server string // Primary server of the zone receiving the updates
+	// record is the record of the current host, in its canonical form
+	record             dnsprovider.Record
+	removeOnDeactivate bool
+
+	refresher *dnsrecord.Refresher
+}
+
+// Activate replaces the records with the name and type of the record by the record of the current
+// host
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+
+	err := p.refresher.Replace(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "replace record")
+	}
+	return nil
+}
+
+// Deactivate removes the record of the current host if RemoveOnDeactivate is set. The record set by
+// another host in the meantime is never removed.
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if !p.removeOnDeactivate {
+		log.Info("Keeping DNS record until another host replaces it")
+		return nil
+	}
+
+	log.Info("Removing DNS record")
+	err := p.dnsClient.RemoveRecord(ctx, p.record)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove record")
+	}
+	return nil
+}
+
+// Ensure queries the server and replaces the records again if they differ from the record of the
+// current host
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+
+	err := p.refresher.Ensure(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "ensure record")
+	}
+	return nil
+}
+
+// IsActivated returns true if the record of the current host is the only record with its name and
+// type
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	upToDate, err := p.refresher.IsUpToDate(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "check record")
+	}
+	return upToDate, nil
+}
+
+// EnsureDeactivated removes the record of the current host if it is still present and
+// RemoveOnDeactivate is set
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if !p.removeOnDeactivate {
+		return nil
+	}
+	if p.refresher.RefreshedRecently() {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	records, err := p.dnsClient.GetRecords(ctx, p.record.Name, p.record.Type)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get records")
+	}
+
+	// The record of the current host has already been removed or replaced, nothing to do
+	if dnsrecord.IndexOf(records, p.record) == -1 {
+		p.refresher.MarkRefreshed()
+		return nil
+	}
+
+	log.Info("DNS record is still present, removing it")
+	err = p.dnsClient.RemoveRecord(ctx, p.record)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove record")
+	}
+
+	p.refresher.MarkRefreshed()
+
+	return nil
+}
+
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s/%s", Name, strings.TrimSuffix(p.record.Name, "."), p.record.Type)
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":         "dns_rfc2136",
+		"server":       p.server,
+		"record_name":  p.record.Name,
+		"record_type":  p.record.Type,
+		"record_value": p.record.Value,
+	}
+}
@@ -0,0 +1,198 @@
+package dnsrfc2136
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/plugin/internal/dnsrecord"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+	"github.com/Scalingo/link/v3/services/rfc2136/rfc2136mock"
+)
+
+var testRecord = dnsprovider.Record{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.1"}
+
+func newPlugin(mockClient *rfc2136mock.MockRecordClient) *Plugin {
+	return &Plugin{
+		dnsClient: mockClient,
+		server:    "ns1.example.com",
+		record:    testRecord,
+		refresher: dnsrecord.NewRefresher(mockClient, testRecord, time.Minute),
+	}
+}
+
+func recordWithValue(value string) dnsprovider.Record {
+	record := testRecord
+	record.Value = value
+	return record
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReplaceRecord(gomock.Any(), testRecord).Return(nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.True(t, plugin.refresher.RefreshedRecently())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReplaceRecord(gomock.Any(), testRecord).Return(errors.New("update refused"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "update refused")
+		assert.False(t, plugin.refresher.RefreshedRecently())
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	t.Run("the record is kept by default", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("the record is removed with removeOnDeactivate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.removeOnDeactivate = true
+
+		mockClient.EXPECT().RemoveRecord(gomock.Any(), testRecord).Return(nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.refresher.MarkRefreshed()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	specs := map[string][]dnsprovider.Record{
+		"a missing record is added again":            nil,
+		"a record of another host is replaced":       {recordWithValue("192.0.2.2")},
+		"the records of the other hosts are removed": {testRecord, recordWithValue("192.0.2.2")},
+		"a record with another TTL is replaced":      {{Name: testRecord.Name, Type: "A", TTL: 3600, Value: testRecord.Value}},
+	}
+	for name, records := range specs {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+			plugin := newPlugin(mockClient)
+
+			mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return(records, nil)
+			mockClient.EXPECT().ReplaceRecord(gomock.Any(), testRecord).Return(nil)
+
+			err := plugin.Ensure(context.Background())
+			require.NoError(t, err)
+			assert.True(t, plugin.refresher.RefreshedRecently(), "the refresh should be recorded")
+		})
+	}
+
+	t.Run("an up to date record", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return([]dnsprovider.Record{testRecord}, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.True(t, plugin.refresher.RefreshedRecently(), "the refresh should be recorded")
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return(nil, errors.New("i/o timeout"))
+
+		err := plugin.Ensure(context.Background())
+		require.ErrorContains(t, err, "i/o timeout")
+		assert.False(t, plugin.refresher.RefreshedRecently(), "the refresh should not be recorded")
+	})
+}
+
+func TestPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("nothing is done without removeOnDeactivate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a record still present is removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.removeOnDeactivate = true
+
+		mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return([]dnsprovider.Record{recordWithValue("192.0.2.2"), testRecord}, nil)
+		mockClient.EXPECT().RemoveRecord(gomock.Any(), testRecord).Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, plugin.refresher.RefreshedRecently(), "the refresh should be recorded")
+	})
+
+	t.Run("a record replaced by another host is left untouched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.removeOnDeactivate = true
+
+		mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return([]dnsprovider.Record{recordWithValue("192.0.2.2")}, nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, plugin.refresher.RefreshedRecently(), "the refresh should be recorded")
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := rfc2136mock.NewMockRecordClient(ctrl)
+	plugin := newPlugin(mockClient)
+
+	mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return([]dnsprovider.Record{testRecord}, nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockClient.EXPECT().GetRecords(gomock.Any(), "www.example.com.", "A").Return([]dnsprovider.Record{recordWithValue("192.0.2.2")}, nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	plugin := newPlugin(nil)
+	assert.Equal(t, "dns_rfc2136/www.example.com/A", plugin.ElectionKey(context.Background()))
+}
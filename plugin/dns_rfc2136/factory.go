@@ -0,0 +1,205 @@
+package dnsrfc2136
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/miekg/dns"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/plugin/internal/dnsrecord"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+	"github.com/Scalingo/link/v3/services/rfc2136"
+)
+
+const Name = api.PluginDNSRFC2136
+
+// DefaultTTL is the TTL of the record if the configuration of the endpoint has none
+const DefaultTTL = 60
+
+type Config struct {
+	RefreshEvery time.Duration `envconfig:"DNS_RFC2136_REFRESH_INTERVAL" default:"1m"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	var tsigSecret string
+	err = f.encryptedStorage.Decrypt(ctx, cfg.TSIGSecret, &tsigSecret)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt TSIG secret")
+	}
+
+	dnsClient := rfc2136.NewClient(cfg.Server, cfg.Zone, cfg.TSIGKeyName, cfg.TSIGAlgorithm, tsigSecret)
+	record := dnsprovider.Record{
+		Name:  cfg.Name,
+		Type:  cfg.Type,
+		TTL:   cfg.TTL,
+		Value: cfg.Value,
+	}
+	return &Plugin{
+		dnsClient:          dnsClient,
+		server:             cfg.Server,
+		record:             record,
+		removeOnDeactivate: cfg.RemoveOnDeactivate,
+		refresher:          dnsrecord.NewRefresher(dnsClient, record, f.config.RefreshEvery),
+	}, nil
+}
+
+type PluginConfig = api.DNSRFC2136PluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	if req.Server == "" {
+		validations.Set("plugin_config.server", "missing server")
+	}
+	if _, _, err := net.SplitHostPort(rfc2136.ServerAddress(req.Server)); req.Server != "" && err != nil {
+		validations.Set("plugin_config.server", "invalid server, must be a host or host:port")
+	}
+
+	if req.Zone == "" {
+		validations.Set("plugin_config.zone", "missing zone")
+	}
+	if _, ok := dns.IsDomainName(req.Zone); req.Zone != "" && !ok {
+		validations.Set("plugin_config.zone", "invalid zone")
+	}
+
+	recordType := strings.ToUpper(req.Type)
+	name := dnsrecord.CanonicalName(req.Name, req.Zone)
+	if req.Name == "" {
+		validations.Set("plugin_config.name", "missing name")
+	}
+	if _, ok := dns.IsDomainName(req.Name); req.Name != "" && !ok {
+		validations.Set("plugin_config.name", "invalid name")
+	} else if req.Name != "" && req.Zone != "" && !dns.IsSubDomain(dns.Fqdn(req.Zone), name) {
+		validations.Set("plugin_config.name", "the name must be in the zone")
+	} else if recordType == "CNAME" && req.Zone != "" && name == dns.CanonicalName(req.Zone) {
+		validations.Set("plugin_config.name", "a CNAME record can not be at the apex of the zone")
+	}
+
+	switch recordType {
+	case "A":
+		ip := net.ParseIP(req.Value)
+		if ip == nil || ip.To4() == nil {
+			validations.Set("plugin_config.value", "invalid value, must be an IPv4 for an A record")
+		}
+	case "AAAA":
+		ip := net.ParseIP(req.Value)
+		if ip == nil || ip.To4() != nil {
+			validations.Set("plugin_config.value", "invalid value, must be an IPv6 for an AAAA record")
+		}
+	case "CNAME":
+		if _, ok := dns.IsDomainName(req.Value); req.Value == "" || !ok {
+			validations.Set("plugin_config.value", "invalid value, must be a domain name for a CNAME record")
+		}
+	default:
+		validations.Set("plugin_config.type", "invalid type, must be A, AAAA or CNAME")
+	}
+
+	if req.TTL > math.MaxInt32 {
+		validations.Set("plugin_config.ttl", "invalid TTL, must be lower than 2147483648")
+	}
+
+	rfc2136.ValidateTSIG(validations, req.TSIGKeyName, req.TSIGAlgorithm, req.TSIGSecret)
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+// Mutate encrypts the TSIG secret, and saves the record in its canonical form for the comparisons
+// with the records returned by the server
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		Server:             req.Server,
+		Zone:               dns.CanonicalName(req.Zone),
+		Name:               dnsrecord.CanonicalName(req.Name, req.Zone),
+		Type:               strings.ToUpper(req.Type),
+		TTL:                req.TTL,
+		TSIGKeyName:        dns.CanonicalName(req.TSIGKeyName),
+		TSIGAlgorithm:      req.TSIGAlgorithm,
+		RemoveOnDeactivate: req.RemoveOnDeactivate,
+	}
+	if cfg.Type == "CNAME" {
+		cfg.Value = dns.CanonicalName(req.Value)
+	} else {
+		cfg.Value = net.ParseIP(req.Value).String()
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.TSIGAlgorithm == "" {
+		cfg.TSIGAlgorithm = rfc2136.DefaultAlgorithm
+	}
+
+	cfg.TSIGSecret, err = f.encryptedStorage.Encrypt(ctx, endpoint.ID, req.TSIGSecret)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt TSIG secret")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	Server             string                   `json:"server"`
+	Zone               string                   `json:"zone"`
+	Name               string                   `json:"name"`
+	Type               string                   `json:"type"`
+	Value              string                   `json:"value"`
+	TTL                uint32                   `json:"ttl"`
+	TSIGKeyName        string                   `json:"tsig_key_name"`
+	TSIGAlgorithm      string                   `json:"tsig_algorithm"`
+	TSIGSecret         models.EncryptedDataLink `json:"tsig_secret"`
+	RemoveOnDeactivate bool                     `json:"remove_on_deactivate"`
+}
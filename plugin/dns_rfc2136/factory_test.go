@@ -0,0 +1,164 @@
+package dnsrfc2136
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+const testTSIGSecret = "c2VjcmV0LWtleS1vZi1saW5rLWZvci10aGUtdGVzdHM="
+
+func validConfig() PluginConfig {
+	return PluginConfig{
+		Server:      "ns1.example.com",
+		Zone:        "example.com",
+		Name:        "www",
+		Type:        "A",
+		Value:       "192.0.2.1",
+		TSIGKeyName: "link-key",
+		TSIGSecret:  testTSIGSecret,
+	}
+}
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        func(cfg *PluginConfig)
+		ExpectedError string
+	}{
+		{
+			Name:   "with a valid configuration",
+			Config: func(*PluginConfig) {},
+		}, {
+			Name: "with a valid AAAA record at the apex of the zone",
+			Config: func(cfg *PluginConfig) {
+				cfg.Name = "@"
+				cfg.Type = "aaaa"
+				cfg.Value = "2001:db8::1"
+			},
+		}, {
+			Name: "with a valid CNAME record",
+			Config: func(cfg *PluginConfig) {
+				cfg.Name = "www.example.com."
+				cfg.Type = "CNAME"
+				cfg.Value = "host-1.example.net."
+				cfg.TSIGAlgorithm = "hmac-sha512"
+			},
+		}, {
+			Name: "with a missing server",
+			Config: func(cfg *PluginConfig) {
+				cfg.Server = ""
+			},
+			ExpectedError: "missing server",
+		}, {
+			Name: "with a name outside of the zone",
+			Config: func(cfg *PluginConfig) {
+				cfg.Name = "www.example.org."
+			},
+			ExpectedError: "the name must be in the zone",
+		}, {
+			Name: "with a CNAME record at the apex of the zone",
+			Config: func(cfg *PluginConfig) {
+				cfg.Name = "example.com."
+				cfg.Type = "CNAME"
+				cfg.Value = "host-1.example.net."
+			},
+			ExpectedError: "a CNAME record can not be at the apex of the zone",
+		}, {
+			Name: "with an unsupported type",
+			Config: func(cfg *PluginConfig) {
+				cfg.Type = "TXT"
+			},
+			ExpectedError: "invalid type",
+		}, {
+			Name: "with an IPv6 in an A record",
+			Config: func(cfg *PluginConfig) {
+				cfg.Value = "2001:db8::1"
+			},
+			ExpectedError: "must be an IPv4 for an A record",
+		}, {
+			Name: "with a missing TSIG secret",
+			Config: func(cfg *PluginConfig) {
+				cfg.TSIGSecret = ""
+			},
+			ExpectedError: "missing TSIG secret",
+		}, {
+			Name: "with a TSIG secret which is not base64 encoded",
+			Config: func(cfg *PluginConfig) {
+				cfg.TSIGSecret = "my-secret!"
+			},
+			ExpectedError: "invalid TSIG secret",
+		}, {
+			Name: "with an unknown TSIG algorithm",
+			Config: func(cfg *PluginConfig) {
+				cfg.TSIGAlgorithm = "hmac-md5"
+			},
+			ExpectedError: "invalid TSIG algorithm",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			cfg := validConfig()
+			spec.Config(&cfg)
+			rawConfig, err := json.Marshal(cfg)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := validConfig()
+	req.Name = "WWW"
+	req.Type = "aaaa"
+	req.Value = "2001:DB8:0::1"
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", testTSIGSecret).Return(models.EncryptedDataLink{
+		ID:         "secret-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the TSIG secret and save the record in its canonical form
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-id", stored.TSIGSecret.ID)
+	assert.Equal(t, "example.com.", stored.Zone)
+	assert.Equal(t, "www.example.com.", stored.Name)
+	assert.Equal(t, "AAAA", stored.Type)
+	assert.Equal(t, "2001:db8::1", stored.Value)
+	assert.Equal(t, uint32(DefaultTTL), stored.TTL)
+	assert.Equal(t, "link-key.", stored.TSIGKeyName)
+	assert.Equal(t, "hmac-sha256", stored.TSIGAlgorithm)
+	assert.NotContains(t, string(res), testTSIGSecret)
+}
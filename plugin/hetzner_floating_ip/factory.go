@@ -0,0 +1,121 @@
+package hetznerfloatingip
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/hetzner"
+)
+
+const Name = api.PluginHetznerFloatingIP
+
+type Config struct {
+	RefreshEvery time.Duration `envconfig:"HETZNER_FLOATING_IP_REFRESH_INTERVAL" default:"1m"`
+	APIURL       string        `envconfig:"HETZNER_API_URL" default:"https://api.hetzner.cloud/v1"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	var apiToken string
+	err = f.encryptedStorage.Decrypt(ctx, cfg.APIToken, &apiToken)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt API token")
+	}
+
+	return newPlugin(hetzner.NewClient(apiToken, f.config.APIURL), f.config.RefreshEvery, cfg.FloatingIPID, cfg.ServerID), nil
+}
+
+type PluginConfig = api.HetznerFloatingIPPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	hetzner.ValidateAPIToken(validations, req.APIToken)
+
+	if req.FloatingIPID == 0 {
+		validations.Set("plugin_config.floating_ip_id", "missing floating IP ID")
+	}
+	if req.FloatingIPID < 0 {
+		validations.Set("plugin_config.floating_ip_id", "invalid floating IP ID, must be positive")
+	}
+
+	if req.ServerID == 0 {
+		validations.Set("plugin_config.server_id", "missing server ID")
+	}
+	if req.ServerID < 0 {
+		validations.Set("plugin_config.server_id", "invalid server ID, must be positive")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		FloatingIPID: req.FloatingIPID,
+		ServerID:     req.ServerID,
+	}
+	cfg.APIToken, err = f.encryptedStorage.Encrypt(ctx, endpoint.ID, req.APIToken)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt API token")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	APIToken     models.EncryptedDataLink `json:"api_token"`
+	FloatingIPID int64                    `json:"floating_ip_id"`
+	ServerID     int64                    `json:"server_id"`
+}
@@ -0,0 +1,115 @@
+package hetznerfloatingip
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+const testAPIToken = "jEheVytlAoFl7F8MqUQ7jAo2hOXASztX1S2e4wJVQ6SrTDzZmMi2d2Q0ZyJyxMXW"
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a valid configuration",
+			Config: PluginConfig{
+				APIToken:     testAPIToken,
+				FloatingIPID: 4711,
+				ServerID:     42,
+			},
+		}, {
+			Name: "with a missing API token",
+			Config: PluginConfig{
+				FloatingIPID: 4711,
+				ServerID:     42,
+			},
+			ExpectedError: "missing API token",
+		}, {
+			Name: "with an invalid API token",
+			Config: PluginConfig{
+				APIToken:     "my-token",
+				FloatingIPID: 4711,
+				ServerID:     42,
+			},
+			ExpectedError: "invalid API token format",
+		}, {
+			Name: "with a missing floating IP ID",
+			Config: PluginConfig{
+				APIToken: testAPIToken,
+				ServerID: 42,
+			},
+			ExpectedError: "missing floating IP ID",
+		}, {
+			Name: "with a negative server ID",
+			Config: PluginConfig{
+				APIToken:     testAPIToken,
+				FloatingIPID: 4711,
+				ServerID:     -42,
+			},
+			ExpectedError: "invalid server ID",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := PluginConfig{
+		APIToken:     "my-token",
+		FloatingIPID: 4711,
+		ServerID:     42,
+	}
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-token").Return(models.EncryptedDataLink{
+		ID:         "token-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the sensitive data and keep the rest
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "token-id", stored.APIToken.ID)
+	assert.Equal(t, req.FloatingIPID, stored.FloatingIPID)
+	assert.Equal(t, req.ServerID, stored.ServerID)
+	assert.NotContains(t, string(res), "my-token")
+}
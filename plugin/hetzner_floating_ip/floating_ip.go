@@ -0,0 +1,91 @@
+package hetznerfloatingip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/plugin/internal/ipassignment"
+	"github.com/Scalingo/link/v3/services/hetzner"
+)
+
+type Plugin struct {
+	// Floating IP Configuration
+	floatingIPID int64 // ID of the floating IP to move
+	serverID     int64 // ID of the server to assign the floating IP to
+
+	assignment *ipassignment.Assignment[int64]
+}
+
+func newPlugin(client hetzner.FloatingIPClient, refreshEvery time.Duration, floatingIPID, serverID int64) *Plugin {
+	return &Plugin{
+		floatingIPID: floatingIPID,
+		serverID:     serverID,
+		assignment:   ipassignment.New[int64](floatingIP{client: client, id: floatingIPID}, serverID, refreshEvery),
+	}
+}
+
+// floatingIP moves the floating IP between the servers, the server ID 0 means not assigned
+type floatingIP struct {
+	client hetzner.FloatingIPClient
+	id     int64
+}
+
+func (ip floatingIP) Assign(ctx context.Context, serverID int64) error {
+	return ip.client.AssignFloatingIP(ctx, ip.id, serverID)
+}
+
+func (ip floatingIP) Unassign(ctx context.Context) error {
+	return ip.client.UnassignFloatingIP(ctx, ip.id)
+}
+
+func (ip floatingIP) AssignedServer(ctx context.Context) (int64, error) {
+	floatingIP, err := ip.client.GetFloatingIP(ctx, ip.id)
+	if err != nil {
+		return 0, err
+	}
+	return floatingIP.ServerID(), nil
+}
+
+// Activate assigns the floating IP to the server, unassigning it from the server it was assigned to
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.Activate(ctx)
+}
+
+// Deactivate unassigns the floating IP, unless it has already been assigned to another server
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.Deactivate(ctx)
+}
+
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.Ensure(ctx)
+}
+
+// IsActivated returns true if the floating IP is assigned to the server
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	return p.assignment.IsActivated(ctx)
+}
+
+// EnsureDeactivated unassigns the floating IP if it is still assigned to the server of this host.
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.EnsureDeactivated(ctx)
+}
+
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%d", Name, p.floatingIPID)
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":           "hetzner_floating_ip",
+		"floating_ip_id": p.floatingIPID,
+		"server_id":      p.serverID,
+	}
+}
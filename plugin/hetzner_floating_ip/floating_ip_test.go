@@ -0,0 +1,85 @@
+package hetznerfloatingip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/hetzner"
+	"github.com/Scalingo/link/v3/services/hetzner/hetznermock"
+)
+
+// The assignment logic is tested in the ipassignment package, only the calls to the Hetzner API
+// are tested here
+
+const (
+	testFloatingIPID int64 = 4711
+	testServerID     int64 = 42
+)
+
+// floatingIPAssignedTo returns the floating IP assigned to the server, not assigned if serverID is 0
+func floatingIPAssignedTo(serverID int64) hetzner.FloatingIP {
+	floatingIP := hetzner.FloatingIP{ID: testFloatingIPID, IP: "203.0.113.1"}
+	if serverID != 0 {
+		floatingIP.Server = &serverID
+	}
+	return floatingIP
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := hetznermock.NewMockFloatingIPClient(ctrl)
+	plugin := newPlugin(mockClient, time.Minute, testFloatingIPID, testServerID)
+
+	mockClient.EXPECT().AssignFloatingIP(gomock.Any(), testFloatingIPID, testServerID).Return(nil)
+
+	err := plugin.Activate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := hetznermock.NewMockFloatingIPClient(ctrl)
+	plugin := newPlugin(mockClient, time.Minute, testFloatingIPID, testServerID)
+
+	mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssignedTo(testServerID), nil)
+	mockClient.EXPECT().UnassignFloatingIP(gomock.Any(), testFloatingIPID).Return(nil)
+
+	err := plugin.Deactivate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	t.Run("a floating IP which is not assigned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := hetznermock.NewMockFloatingIPClient(ctrl)
+		plugin := newPlugin(mockClient, time.Minute, testFloatingIPID, testServerID)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssignedTo(0), nil)
+
+		activated, err := plugin.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.False(t, activated)
+	})
+
+	t.Run("a floating IP assigned to the server", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := hetznermock.NewMockFloatingIPClient(ctrl)
+		plugin := newPlugin(mockClient, time.Minute, testFloatingIPID, testServerID)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssignedTo(testServerID), nil)
+
+		activated, err := plugin.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, activated)
+	})
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	plugin := newPlugin(nil, time.Minute, testFloatingIPID, testServerID)
+	assert.Equal(t, "hetzner_floating_ip/4711", plugin.ElectionKey(context.Background()))
+}
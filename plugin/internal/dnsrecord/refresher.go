@@ -0,0 +1,152 @@
+// Package dnsrecord contains the logic shared by the plugins pointing a DNS record to the host
+// owning the endpoint
+package dnsrecord
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+// Refresher makes the record of the current host the only record with its name and type, and
+// regularly checks that it has not been changed
+type Refresher struct {
+	provider     dnsprovider.Provider
+	record       dnsprovider.Record
+	refreshEvery time.Duration
+
+	lastRefreshedAt time.Time
+}
+
+// NewRefresher returns a refresher of the record, which must be in its canonical form
+func NewRefresher(provider dnsprovider.Provider, record dnsprovider.Record, refreshEvery time.Duration) *Refresher {
+	return &Refresher{
+		provider:     provider,
+		record:       record,
+		refreshEvery: refreshEvery,
+	}
+}
+
+// Replace replaces the records with the name and type of the record by the record of the current
+// host
+func (r *Refresher) Replace(ctx context.Context) error {
+	logger.Get(ctx).Info("Replacing DNS record")
+	err := r.provider.ReplaceRecord(ctx, r.record)
+	if err != nil {
+		return errors.Wrap(ctx, err, "replace record")
+	}
+	r.MarkRefreshed()
+
+	return nil
+}
+
+// Ensure replaces the records again if they differ from the record of the current host. The
+// records are only checked once per refresh interval.
+func (r *Refresher) Ensure(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	if r.RefreshedRecently() {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	records, err := r.provider.GetRecords(ctx, r.record.Name, r.record.Type)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get records")
+	}
+
+	if !r.isUpToDate(records) {
+		log.WithField("current_records", values(records)).Info("DNS record is not up to date, replacing it")
+		err := r.Replace(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "replace record")
+		}
+		return nil
+	}
+
+	r.MarkRefreshed()
+
+	return nil
+}
+
+// IsUpToDate returns true if the record of the current host is the only record with its name and
+// type
+func (r *Refresher) IsUpToDate(ctx context.Context) (bool, error) {
+	records, err := r.provider.GetRecords(ctx, r.record.Name, r.record.Type)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "get records")
+	}
+	if !r.isUpToDate(records) {
+		return false, nil
+	}
+
+	r.MarkRefreshed()
+	return true, nil
+}
+
+// RefreshedRecently returns true if the records have been checked or replaced during the last
+// refresh interval
+func (r *Refresher) RefreshedRecently() bool {
+	return r.lastRefreshedAt.Add(r.refreshEvery).After(time.Now())
+}
+
+// MarkRefreshed records that the records have just been checked or replaced
+func (r *Refresher) MarkRefreshed() {
+	r.lastRefreshedAt = time.Now()
+}
+
+// isUpToDate returns true if the records only contain the record of the current host, with the
+// configured TTL unless the TTL is managed by the provider
+func (r *Refresher) isUpToDate(records []dnsprovider.Record) bool {
+	if len(records) != 1 || IndexOf(records, r.record) != 0 {
+		return false
+	}
+	return records[0].TTL == 0 || records[0].TTL == r.record.TTL
+}
+
+// IndexOf returns the index of the record with the value of the record, or -1 if there is none
+func IndexOf(records []dnsprovider.Record, record dnsprovider.Record) int {
+	for i, other := range records {
+		if sameValue(other.Value, record.Value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameValue compares the IPs, which may be written in different forms, and the names without
+// taking the case into account
+func sameValue(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA != nil && ipB != nil {
+		return ipA.Equal(ipB)
+	}
+	return strings.EqualFold(a, b)
+}
+
+func values(records []dnsprovider.Record) []string {
+	res := make([]string, 0, len(records))
+	for _, record := range records {
+		res = append(res, record.Value)
+	}
+	return res
+}
+
+// CanonicalName returns the canonical name of the record, a name which is not fully qualified being
+// relative to the zone
+func CanonicalName(name, zone string) string {
+	if name == "@" {
+		return dns.CanonicalName(zone)
+	}
+	if !dns.IsFqdn(name) {
+		name = name + "." + dns.Fqdn(zone)
+	}
+	return dns.CanonicalName(name)
+}
@@ -0,0 +1,80 @@
+package dnsrecord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+	"github.com/Scalingo/link/v3/services/dnsprovider/dnsprovidermock"
+)
+
+func TestRefresher_Ensure(t *testing.T) {
+	record := dnsprovider.Record{Name: "www.example.com.", Type: "AAAA", TTL: 60, Value: "2001:db8::1"}
+
+	specs := map[string]struct {
+		records         []dnsprovider.Record
+		expectedReplace bool
+	}{
+		"an up to date record": {
+			records: []dnsprovider.Record{record},
+		},
+		"an up to date record written in another form": {
+			records: []dnsprovider.Record{{Name: record.Name, Type: "AAAA", TTL: 60, Value: "2001:0db8:0000::1"}},
+		},
+		"a record with a TTL managed by the provider": {
+			records: []dnsprovider.Record{{Name: record.Name, Type: "AAAA", TTL: 0, Value: record.Value}},
+		},
+		"a record with another TTL": {
+			records:         []dnsprovider.Record{{Name: record.Name, Type: "AAAA", TTL: 300, Value: record.Value}},
+			expectedReplace: true,
+		},
+		"a record of another host": {
+			records:         []dnsprovider.Record{{Name: record.Name, Type: "AAAA", TTL: 60, Value: "2001:db8::2"}},
+			expectedReplace: true,
+		},
+		"a missing record": {
+			expectedReplace: true,
+		},
+	}
+	for name, spec := range specs {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockProvider := dnsprovidermock.NewMockProvider(ctrl)
+			refresher := NewRefresher(mockProvider, record, time.Minute)
+
+			mockProvider.EXPECT().GetRecords(gomock.Any(), record.Name, record.Type).Return(spec.records, nil)
+			if spec.expectedReplace {
+				mockProvider.EXPECT().ReplaceRecord(gomock.Any(), record).Return(nil)
+			}
+
+			err := refresher.Ensure(context.Background())
+			require.NoError(t, err)
+			assert.True(t, refresher.RefreshedRecently())
+
+			// The records are not checked again before the refresh interval
+			err = refresher.Ensure(context.Background())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	records := []dnsprovider.Record{
+		{Name: "www.example.com.", Type: "CNAME", Value: "lb1.example.com."},
+		{Name: "www.example.com.", Type: "CNAME", Value: "lb2.example.com."},
+	}
+
+	assert.Equal(t, 1, IndexOf(records, dnsprovider.Record{Value: "LB2.example.com."}))
+	assert.Equal(t, -1, IndexOf(records, dnsprovider.Record{Value: "lb3.example.com."}))
+}
+
+func TestCanonicalName(t *testing.T) {
+	assert.Equal(t, "example.com.", CanonicalName("@", "Example.com"))
+	assert.Equal(t, "www.example.com.", CanonicalName("WWW", "example.com."))
+	assert.Equal(t, "www.example.org.", CanonicalName("www.example.org.", "example.com"))
+}
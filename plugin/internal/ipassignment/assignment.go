@@ -0,0 +1,151 @@
+// Package ipassignment contains the logic shared by the plugins assigning an IP of a cloud provider
+// to the server of the host owning the endpoint
+package ipassignment
+
+import (
+	"context"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+)
+
+// Provider moves the IP between the servers through the API of the cloud provider. ID is the type
+// of the server IDs of the provider.
+type Provider[ID comparable] interface {
+	// Assign assigns the IP to the server, unassigning it from the server it was assigned to
+	Assign(ctx context.Context, serverID ID) error
+	// Unassign unassigns the IP from the server it is assigned to
+	Unassign(ctx context.Context) error
+	// AssignedServer returns the ID of the server the IP is assigned to, the zero value if it is not
+	// assigned
+	AssignedServer(ctx context.Context) (ID, error)
+}
+
+// Assignment assigns the IP to the server of the current host, and regularly checks that it has
+// not been assigned to another server
+type Assignment[ID comparable] struct {
+	provider     Provider[ID]
+	serverID     ID
+	refreshEvery time.Duration
+
+	lastRefreshedAt time.Time
+}
+
+// New returns the assignment of the IP to the server. The state of the IP is checked at most once
+// every refreshEvery by Ensure and EnsureDeactivated.
+func New[ID comparable](provider Provider[ID], serverID ID, refreshEvery time.Duration) *Assignment[ID] {
+	return &Assignment[ID]{
+		provider:     provider,
+		serverID:     serverID,
+		refreshEvery: refreshEvery,
+	}
+}
+
+// Activate assigns the IP to the server
+func (a *Assignment[ID]) Activate(ctx context.Context) error {
+	logger.Get(ctx).Info("Assigning IP to server")
+	err := a.provider.Assign(ctx, a.serverID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "assign IP")
+	}
+	a.MarkRefreshed()
+	return nil
+}
+
+// Deactivate unassigns the IP, unless it has already been assigned to another server
+func (a *Assignment[ID]) Deactivate(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	serverID, err := a.provider.AssignedServer(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get IP")
+	}
+	if serverID != a.serverID {
+		log.Info("IP is not assigned to the server, skipping unassign")
+		return nil
+	}
+
+	log.Info("Unassigning IP from server")
+	err = a.provider.Unassign(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unassign IP")
+	}
+	return nil
+}
+
+// Ensure assigns the IP again if it has been assigned to another server
+func (a *Assignment[ID]) Ensure(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	if a.RefreshedRecently() {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	serverID, err := a.provider.AssignedServer(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get IP")
+	}
+	if serverID != a.serverID {
+		log.WithField("current_server_id", serverID).Info("IP is not assigned to the server, assigning it")
+		return a.Activate(ctx)
+	}
+
+	a.MarkRefreshed()
+	return nil
+}
+
+// IsActivated returns true if the IP is assigned to the server
+func (a *Assignment[ID]) IsActivated(ctx context.Context) (bool, error) {
+	serverID, err := a.provider.AssignedServer(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "get IP")
+	}
+	if serverID != a.serverID {
+		return false, nil
+	}
+
+	a.MarkRefreshed()
+	return true, nil
+}
+
+// EnsureDeactivated unassigns the IP if it is still assigned to the server
+func (a *Assignment[ID]) EnsureDeactivated(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	if a.RefreshedRecently() {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	serverID, err := a.provider.AssignedServer(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get IP")
+	}
+
+	// The IP is assigned to another server (or not assigned at all), nothing to do
+	if serverID != a.serverID {
+		a.MarkRefreshed()
+		return nil
+	}
+
+	log.Info("IP is still assigned to the server, unassigning it")
+	err = a.provider.Unassign(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unassign IP")
+	}
+
+	a.MarkRefreshed()
+	return nil
+}
+
+// RefreshedRecently returns true if the state of the IP has been checked less than refreshEvery ago
+func (a *Assignment[ID]) RefreshedRecently() bool {
+	return a.lastRefreshedAt.Add(a.refreshEvery).After(time.Now())
+}
+
+// MarkRefreshed records that the state of the IP has just been checked
+func (a *Assignment[ID]) MarkRefreshed() {
+	a.lastRefreshedAt = time.Now()
+}
@@ -0,0 +1,138 @@
+package ipassignment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/plugin/internal/ipassignment/ipassignmentmock"
+)
+
+const (
+	testServerID      = "server-1"
+	testOtherServerID = "server-2"
+)
+
+func newTestAssignment(t *testing.T) (*Assignment[string], *ipassignmentmock.MockProvider[string]) {
+	ctrl := gomock.NewController(t)
+	mockProvider := ipassignmentmock.NewMockProvider[string](ctrl)
+	return New[string](mockProvider, testServerID, time.Minute), mockProvider
+}
+
+func TestAssignment_Activate(t *testing.T) {
+	t.Run("it assigns the IP to the server", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().Assign(gomock.Any(), testServerID).Return(nil)
+
+		err := assignment.Activate(context.Background())
+		require.NoError(t, err)
+		assert.True(t, assignment.RefreshedRecently())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().Assign(gomock.Any(), testServerID).Return(errors.New("assign error"))
+
+		err := assignment.Activate(context.Background())
+		require.ErrorContains(t, err, "assign error")
+		assert.False(t, assignment.RefreshedRecently())
+	})
+}
+
+func TestAssignment_Deactivate(t *testing.T) {
+	t.Run("it unassigns the IP", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testServerID, nil)
+		mockProvider.EXPECT().Unassign(gomock.Any()).Return(nil)
+
+		err := assignment.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("an IP already assigned to another server is not unassigned", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testOtherServerID, nil)
+
+		err := assignment.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestAssignment_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		assignment, _ := newTestAssignment(t)
+		assignment.MarkRefreshed()
+
+		err := assignment.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("an IP assigned to another server is assigned again", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testOtherServerID, nil)
+		mockProvider.EXPECT().Assign(gomock.Any(), testServerID).Return(nil)
+
+		err := assignment.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.True(t, assignment.RefreshedRecently())
+	})
+
+	t.Run("an IP assigned to the server", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testServerID, nil)
+
+		err := assignment.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.True(t, assignment.RefreshedRecently())
+	})
+}
+
+func TestAssignment_EnsureDeactivated(t *testing.T) {
+	t.Run("an IP still assigned to the server is unassigned", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testServerID, nil)
+		mockProvider.EXPECT().Unassign(gomock.Any()).Return(nil)
+
+		err := assignment.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, assignment.RefreshedRecently())
+	})
+
+	t.Run("an unassigned IP is left untouched", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return("", nil)
+
+		err := assignment.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, assignment.RefreshedRecently())
+	})
+
+	t.Run("unassign error", func(t *testing.T) {
+		assignment, mockProvider := newTestAssignment(t)
+		mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testServerID, nil)
+		mockProvider.EXPECT().Unassign(gomock.Any()).Return(errors.New("unassign error"))
+
+		err := assignment.EnsureDeactivated(context.Background())
+		require.ErrorContains(t, err, "unassign error")
+		assert.False(t, assignment.RefreshedRecently())
+	})
+}
+
+func TestAssignment_IsActivated(t *testing.T) {
+	assignment, mockProvider := newTestAssignment(t)
+
+	mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testServerID, nil)
+	activated, err := assignment.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockProvider.EXPECT().AssignedServer(gomock.Any()).Return(testOtherServerID, nil)
+	activated, err = assignment.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
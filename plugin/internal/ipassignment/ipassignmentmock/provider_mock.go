@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/plugin/internal/ipassignment (interfaces: Provider)
+
+// Package ipassignmentmock is a generated GoMock package.
+package ipassignmentmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProvider is a mock of Provider interface.
+type MockProvider[ID comparable] struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderMockRecorder[ID]
+	isgomock struct{}
+}
+
+// MockProviderMockRecorder is the mock recorder for MockProvider.
+type MockProviderMockRecorder[ID comparable] struct {
+	mock *MockProvider[ID]
+}
+
+// NewMockProvider creates a new mock instance.
+func NewMockProvider[ID comparable](ctrl *gomock.Controller) *MockProvider[ID] {
+	mock := &MockProvider[ID]{ctrl: ctrl}
+	mock.recorder = &MockProviderMockRecorder[ID]{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProvider[ID]) EXPECT() *MockProviderMockRecorder[ID] {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockProvider[ID]) Assign(ctx context.Context, serverID ID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", ctx, serverID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockProviderMockRecorder[ID]) Assign(ctx, serverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockProvider[ID])(nil).Assign), ctx, serverID)
+}
+
+// AssignedServer mocks base method.
+func (m *MockProvider[ID]) AssignedServer(ctx context.Context) (ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignedServer", ctx)
+	ret0, _ := ret[0].(ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignedServer indicates an expected call of AssignedServer.
+func (mr *MockProviderMockRecorder[ID]) AssignedServer(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignedServer", reflect.TypeOf((*MockProvider[ID])(nil).AssignedServer), ctx)
+}
+
+// Unassign mocks base method.
+func (m *MockProvider[ID]) Unassign(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unassign", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unassign indicates an expected call of Unassign.
+func (mr *MockProviderMockRecorder[ID]) Unassign(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockProvider[ID])(nil).Unassign), ctx)
+}
@@ -0,0 +1,183 @@
+package ipvs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+const Name = api.PluginIPVS
+
+const (
+	defaultProtocol   = "tcp"
+	defaultScheduler  = "rr"
+	defaultWeight     = 1
+	defaultForwarding = network.IPVSForwardingDirectRoute
+)
+
+type PluginConfig = api.IPVSPluginConfig
+
+type Factory struct {
+	newIPVSManager func(netns string) (network.IPVSManager, error)
+}
+
+func Register(ctx context.Context, registry plugin.Registry) error {
+	registry.Register(ctx, Name, Factory{
+		newIPVSManager: network.NewIPVSManager,
+	})
+	return nil
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	service, err := parseService(ctx, cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid service")
+	}
+
+	realServers := make([]network.IPVSRealServer, 0, len(cfg.RealServers))
+	for _, realServerConfig := range cfg.RealServers {
+		realServer, err := parseRealServer(ctx, service, realServerConfig)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid real server %s", realServerConfig.Address)
+		}
+		realServers = append(realServers, realServer)
+	}
+
+	ipvsManager, err := f.newIPVSManager(cfg.Netns)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get IPVS manager")
+	}
+
+	return &Plugin{
+		endpoint:    endpoint,
+		service:     service,
+		realServers: realServers,
+		netns:       network.NamespacePath(cfg.Netns),
+		ipvsManager: ipvsManager,
+	}, nil
+}
+
+func (f Factory) Validate(ctx context.Context, endpoint models.Endpoint) error {
+	validation := errors.NewValidationErrorsBuilder()
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		validation.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validation.Build()
+	}
+
+	service, err := parseService(ctx, cfg)
+	if err != nil {
+		validation.Set("plugin_config", err.Error())
+		return validation.Build()
+	}
+
+	seen := make(map[string]bool, len(cfg.RealServers))
+	for i, realServerConfig := range cfg.RealServers {
+		field := fmt.Sprintf("plugin_config.real_servers.%d", i)
+		realServer, err := parseRealServer(ctx, service, realServerConfig)
+		if err != nil {
+			validation.Set(field, err.Error())
+			continue
+		}
+		if seen[realServer.String()] {
+			validation.Set(field, "duplicated real server "+realServer.String())
+		}
+		seen[realServer.String()] = true
+	}
+
+	validationErr := validation.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+	return nil
+}
+
+// parseService checks the configuration of the virtual service and returns the service to add
+func parseService(ctx context.Context, cfg PluginConfig) (network.IPVSService, error) {
+	service := network.IPVSService{
+		Address:   net.ParseIP(cfg.Address),
+		Protocol:  cfg.Protocol,
+		Scheduler: cfg.Scheduler,
+	}
+	if service.Address == nil {
+		return network.IPVSService{}, errors.Newf(ctx, "invalid address %s", cfg.Address)
+	}
+	if service.Protocol == "" {
+		service.Protocol = defaultProtocol
+	}
+	_, err := network.IPVSProtocol(service.Protocol)
+	if err != nil {
+		return network.IPVSService{}, err
+	}
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		return network.IPVSService{}, errors.New(ctx, "port must be between 1 and 65535")
+	}
+	service.Port = uint16(cfg.Port)
+	if service.Scheduler == "" {
+		service.Scheduler = defaultScheduler
+	}
+
+	if cfg.PersistenceTimeout != "" {
+		service.PersistenceTimeout, err = time.ParseDuration(cfg.PersistenceTimeout)
+		if err != nil {
+			return network.IPVSService{}, errors.Wrap(ctx, err, "invalid persistence_timeout")
+		}
+		if service.PersistenceTimeout < time.Second {
+			return network.IPVSService{}, errors.New(ctx, "persistence_timeout must be at least 1s")
+		}
+	}
+	return service, nil
+}
+
+// parseRealServer checks the configuration of a real server and returns the real server to add
+func parseRealServer(ctx context.Context, service network.IPVSService, cfg api.IPVSRealServerConfig) (network.IPVSRealServer, error) {
+	realServer := network.IPVSRealServer{
+		Address:    net.ParseIP(cfg.Address),
+		Port:       service.Port,
+		Weight:     defaultWeight,
+		Forwarding: network.IPVSForwarding(cfg.Forwarding),
+	}
+	if realServer.Address == nil {
+		return network.IPVSRealServer{}, errors.Newf(ctx, "invalid address %s", cfg.Address)
+	}
+	if (realServer.Address.To4() == nil) != (service.Address.To4() == nil) {
+		return network.IPVSRealServer{}, errors.New(ctx, "the real server and the service must be of the same IP family")
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return network.IPVSRealServer{}, errors.New(ctx, "port must be between 1 and 65535")
+	}
+	if cfg.Port != 0 {
+		realServer.Port = uint16(cfg.Port)
+	}
+	if cfg.Weight != nil {
+		if *cfg.Weight < 0 {
+			return network.IPVSRealServer{}, errors.New(ctx, "weight must be positive")
+		}
+		realServer.Weight = *cfg.Weight
+	}
+	if realServer.Forwarding == "" {
+		realServer.Forwarding = defaultForwarding
+	}
+	if !realServer.Forwarding.IsValid() {
+		return network.IPVSRealServer{}, errors.Newf(ctx, "invalid forwarding %s, must be dr, nat or tunnel", cfg.Forwarding)
+	}
+	if realServer.Forwarding == network.IPVSForwardingDirectRoute && realServer.Port != service.Port {
+		return network.IPVSRealServer{}, errors.New(ctx, "the port of a real server using direct routing must be the port of the service")
+	}
+	return realServer, nil
+}
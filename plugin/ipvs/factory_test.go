@@ -0,0 +1,135 @@
+package ipvs
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	weight := 0
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a service and real servers",
+			Config: PluginConfig{Address: "192.0.2.10", Port: 80, RealServers: []api.IPVSRealServerConfig{
+				{Address: "10.0.0.1"},
+				{Address: "10.0.0.2", Weight: &weight},
+			}},
+		}, {
+			Name: "with all the settings",
+			Config: PluginConfig{
+				Address:            "2001:db8::10",
+				Protocol:           "udp",
+				Port:               53,
+				Scheduler:          "wlc",
+				PersistenceTimeout: "5m",
+				RealServers: []api.IPVSRealServerConfig{
+					{Address: "2001:db8:1::1", Port: 5353, Forwarding: "nat"},
+				},
+			},
+		}, {
+			Name:          "with an invalid address",
+			Config:        PluginConfig{Address: "192.0.2", Port: 80},
+			ExpectedError: "invalid address",
+		}, {
+			Name:          "without port",
+			Config:        PluginConfig{Address: "192.0.2.10"},
+			ExpectedError: "port must be between 1 and 65535",
+		}, {
+			Name:          "with an unknown protocol",
+			Config:        PluginConfig{Address: "192.0.2.10", Protocol: "icmp", Port: 80},
+			ExpectedError: "unknown protocol icmp",
+		}, {
+			Name:          "with a too short persistence timeout",
+			Config:        PluginConfig{Address: "192.0.2.10", Port: 80, PersistenceTimeout: "100ms"},
+			ExpectedError: "persistence_timeout must be at least 1s",
+		}, {
+			Name: "with a real server of another family",
+			Config: PluginConfig{Address: "192.0.2.10", Port: 80, RealServers: []api.IPVSRealServerConfig{
+				{Address: "2001:db8::1"},
+			}},
+			ExpectedError: "same IP family",
+		}, {
+			Name: "with an invalid forwarding method",
+			Config: PluginConfig{Address: "192.0.2.10", Port: 80, RealServers: []api.IPVSRealServerConfig{
+				{Address: "10.0.0.1", Forwarding: "masq"},
+			}},
+			ExpectedError: "invalid forwarding masq",
+		}, {
+			Name: "with a port translation in direct routing",
+			Config: PluginConfig{Address: "192.0.2.10", Port: 80, RealServers: []api.IPVSRealServerConfig{
+				{Address: "10.0.0.1", Port: 8080},
+			}},
+			ExpectedError: "must be the port of the service",
+		}, {
+			Name: "with a duplicated real server",
+			Config: PluginConfig{Address: "192.0.2.10", Port: 80, RealServers: []api.IPVSRealServerConfig{
+				{Address: "10.0.0.1"},
+				{Address: "10.0.0.1", Port: 80},
+			}},
+			ExpectedError: "duplicated real server",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			pluginConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			if spec.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.ExpectedError)
+		})
+	}
+}
+
+func TestFactory_Create(t *testing.T) {
+	var netns string
+	factory := Factory{
+		newIPVSManager: func(ns string) (network.IPVSManager, error) {
+			netns = ns
+			return nil, nil
+		},
+	}
+
+	pluginConfig, err := json.Marshal(PluginConfig{
+		Address:            "192.0.2.10",
+		Port:               443,
+		PersistenceTimeout: "5m",
+		RealServers:        []api.IPVSRealServerConfig{{Address: "10.0.0.1"}},
+		Netns:              "blue",
+	})
+	require.NoError(t, err)
+	p, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+	require.NoError(t, err)
+
+	// The defaults are applied
+	assert.Equal(t, "blue", netns)
+	assert.Equal(t, network.IPVSService{
+		Address:            net.ParseIP("192.0.2.10"),
+		Protocol:           "tcp",
+		Port:               443,
+		Scheduler:          "rr",
+		PersistenceTimeout: 5 * time.Minute,
+	}, p.(*Plugin).service)
+	assert.Equal(t, []network.IPVSRealServer{
+		{Address: net.ParseIP("10.0.0.1"), Port: 443, Weight: 1, Forwarding: network.IPVSForwardingDirectRoute},
+	}, p.(*Plugin).realServers)
+}
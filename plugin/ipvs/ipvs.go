@@ -0,0 +1,148 @@
+package ipvs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+type Plugin struct {
+	endpoint    models.Endpoint
+	service     network.IPVSService
+	realServers []network.IPVSRealServer
+	ipvsManager network.IPVSManager
+	// netns is the path of the network namespace of the service, empty for the namespace of LinK
+	netns string
+}
+
+// Activate adds the virtual service and its real servers. If the real servers cannot be added,
+// the service is removed.
+func (p *Plugin) Activate(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	err := p.ipvsManager.EnsureService(p.service)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "add IPVS service %s", p.service)
+	}
+
+	err = p.reconcileRealServers(ctx)
+	if err != nil {
+		removeErr := p.ipvsManager.RemoveService(p.service)
+		if removeErr != nil {
+			log.WithError(removeErr).Error("Fail to remove the IPVS service during the activation rollback")
+		}
+		return errors.Wrap(ctx, err, "add real servers")
+	}
+
+	log.WithField("ipvs_service", p.service.String()).Info("IPVS service added")
+	return nil
+}
+
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	err := p.ipvsManager.RemoveService(p.service)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "remove IPVS service %s", p.service)
+	}
+	logger.Get(ctx).WithField("ipvs_service", p.service.String()).Info("IPVS service removed")
+	return nil
+}
+
+// Ensure adds the virtual service if it is missing and reconciles its real servers
+func (p *Plugin) Ensure(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	has, err := p.ipvsManager.HasService(p.service)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "check IPVS service %s", p.service)
+	}
+	if !has {
+		log.WithField("ipvs_service", p.service.String()).Warn("IPVS service missing, adding it again")
+	}
+	// The scheduler and the persistence are also updated if they have been modified
+	err = p.ipvsManager.EnsureService(p.service)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "add IPVS service %s", p.service)
+	}
+
+	err = p.reconcileRealServers(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "reconcile real servers")
+	}
+	return nil
+}
+
+// EnsureDeactivated removes the virtual service if it is still present
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	err := p.ipvsManager.RemoveService(p.service)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "remove IPVS service %s", p.service)
+	}
+	return nil
+}
+
+// IsActivated returns true if the virtual service is present, its real servers being reconciled
+// by Ensure
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	has, err := p.ipvsManager.HasService(p.service)
+	if err != nil {
+		return false, errors.Wrapf(ctx, err, "check IPVS service %s", p.service)
+	}
+	return has, nil
+}
+
+// reconcileRealServers adds the missing real servers, updates the modified ones and removes the
+// ones which are not configured
+func (p *Plugin) reconcileRealServers(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	current, err := p.ipvsManager.RealServers(p.service)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list real servers")
+	}
+	currentByAddress := make(map[string]network.IPVSRealServer, len(current))
+	for _, realServer := range current {
+		currentByAddress[realServer.String()] = realServer
+	}
+
+	desired := make(map[string]bool, len(p.realServers))
+	for _, realServer := range p.realServers {
+		desired[realServer.String()] = true
+		currentRealServer, ok := currentByAddress[realServer.String()]
+		if ok && currentRealServer.Weight == realServer.Weight && currentRealServer.Forwarding == realServer.Forwarding {
+			continue
+		}
+
+		err = p.ipvsManager.EnsureRealServer(p.service, realServer)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "add real server %s", realServer)
+		}
+		log.WithField("ipvs_real_server", realServer.String()).Info("Real server added or updated")
+	}
+
+	for _, realServer := range current {
+		if desired[realServer.String()] {
+			continue
+		}
+		err = p.ipvsManager.RemoveRealServer(p.service, realServer)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "remove real server %s", realServer)
+		}
+		log.WithField("ipvs_real_server", realServer.String()).Info("Unknown real server removed")
+	}
+	return nil
+}
+
+// ElectionKey is based on the protocol, address and port of the virtual service, and the network namespace
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	key := fmt.Sprintf("%s_%s_%d", p.service.Protocol, p.service.Address, p.service.Port)
+	if p.netns != "" {
+		netns := strings.TrimPrefix(p.netns, network.NetnsDir+"/")
+		key = strings.ReplaceAll(netns, "/", "_") + ":" + key
+	}
+	return "ipvs:" + key
+}
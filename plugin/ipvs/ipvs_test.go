@@ -0,0 +1,112 @@
+package ipvs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func newTestPlugin(ipvsManager network.IPVSManager) (*Plugin, network.IPVSRealServer, network.IPVSRealServer) {
+	realServer1 := network.IPVSRealServer{Address: net.ParseIP("10.0.0.1"), Port: 80, Weight: 1, Forwarding: network.IPVSForwardingDirectRoute}
+	realServer2 := network.IPVSRealServer{Address: net.ParseIP("10.0.0.2"), Port: 80, Weight: 1, Forwarding: network.IPVSForwardingDirectRoute}
+	return &Plugin{
+		service:     network.IPVSService{Address: net.ParseIP("192.0.2.10"), Protocol: "tcp", Port: 80, Scheduler: "rr"},
+		realServers: []network.IPVSRealServer{realServer1, realServer2},
+		ipvsManager: ipvsManager,
+	}, realServer1, realServer2
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it adds the service, then the real servers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ipvsManager := networkmock.NewMockIPVSManager(ctrl)
+		p, realServer1, realServer2 := newTestPlugin(ipvsManager)
+
+		gomock.InOrder(
+			ipvsManager.EXPECT().EnsureService(p.service).Return(nil),
+			ipvsManager.EXPECT().RealServers(p.service).Return(nil, nil),
+			ipvsManager.EXPECT().EnsureRealServer(p.service, realServer1).Return(nil),
+			ipvsManager.EXPECT().EnsureRealServer(p.service, realServer2).Return(nil),
+		)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("if a real server cannot be added, the service is removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ipvsManager := networkmock.NewMockIPVSManager(ctrl)
+		p, realServer1, _ := newTestPlugin(ipvsManager)
+
+		ipvsManager.EXPECT().EnsureService(p.service).Return(nil)
+		ipvsManager.EXPECT().RealServers(p.service).Return(nil, nil)
+		ipvsManager.EXPECT().EnsureRealServer(p.service, realServer1).Return(errors.New("no such file or directory"))
+		ipvsManager.EXPECT().RemoveService(p.service).Return(nil)
+
+		err := p.Activate(context.Background())
+		require.ErrorContains(t, err, "no such file or directory")
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("it reconciles the real servers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ipvsManager := networkmock.NewMockIPVSManager(ctrl)
+		p, realServer1, realServer2 := newTestPlugin(ipvsManager)
+
+		modifiedRealServer2 := realServer2
+		modifiedRealServer2.Weight = 5
+		unknownRealServer := network.IPVSRealServer{Address: net.ParseIP("10.0.0.3"), Port: 80, Weight: 1, Forwarding: network.IPVSForwardingNAT}
+
+		ipvsManager.EXPECT().HasService(p.service).Return(true, nil)
+		ipvsManager.EXPECT().EnsureService(p.service).Return(nil)
+		ipvsManager.EXPECT().RealServers(p.service).Return([]network.IPVSRealServer{realServer1, modifiedRealServer2, unknownRealServer}, nil)
+		ipvsManager.EXPECT().EnsureRealServer(p.service, realServer2).Return(nil)
+		ipvsManager.EXPECT().RemoveRealServer(p.service, unknownRealServer).Return(nil)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it adds the service if it is missing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		ipvsManager := networkmock.NewMockIPVSManager(ctrl)
+		p, realServer1, realServer2 := newTestPlugin(ipvsManager)
+
+		ipvsManager.EXPECT().HasService(p.service).Return(false, nil)
+		ipvsManager.EXPECT().EnsureService(p.service).Return(nil)
+		ipvsManager.EXPECT().RealServers(p.service).Return(nil, nil)
+		ipvsManager.EXPECT().EnsureRealServer(p.service, realServer1).Return(nil)
+		ipvsManager.EXPECT().EnsureRealServer(p.service, realServer2).Return(nil)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ipvsManager := networkmock.NewMockIPVSManager(ctrl)
+	p, _, _ := newTestPlugin(ipvsManager)
+
+	ipvsManager.EXPECT().RemoveService(p.service).Return(nil)
+
+	err := p.Deactivate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	p, _, _ := newTestPlugin(nil)
+	assert.Equal(t, "ipvs:tcp_192.0.2.10_80", p.ElectionKey(context.Background()))
+
+	p.netns = "/var/run/netns/blue"
+	assert.Equal(t, "ipvs:blue:tcp_192.0.2.10_80", p.ElectionKey(context.Background()))
+}
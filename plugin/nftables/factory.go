@@ -0,0 +1,201 @@
+package nftables
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+const Name = api.PluginNftables
+
+type Config struct {
+	// Table is the nftables table of the inet family dedicated to LinK, the rules of the endpoints
+	// are installed in its prerouting and postrouting chains
+	Table string `envconfig:"NFTABLES_TABLE" default:"link"`
+}
+
+type PluginConfig = api.NftablesPluginConfig
+
+type Factory struct {
+	config        Config
+	newNATManager func(netns, table string) (network.NATManager, error)
+}
+
+func Register(ctx context.Context, registry plugin.Registry) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+	if config.Table == "" {
+		return errors.New(ctx, "invalid NFTABLES_TABLE, it must not be empty")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:        config,
+		newNATManager: network.NewNATManager,
+	})
+	return nil
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+	if len(cfg.Rules) == 0 {
+		return nil, errors.New(ctx, "invalid plugin config: no rule")
+	}
+
+	rules := make([]network.NATRule, 0, len(cfg.Rules))
+	for i, ruleConfig := range cfg.Rules {
+		rule, err := parseRule(ctx, ruleConfig)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid rule %d", i)
+		}
+		rules = append(rules, rule)
+	}
+
+	natManager, err := f.newNATManager(cfg.Netns, f.config.Table)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get NAT manager")
+	}
+
+	return &Plugin{
+		endpoint:   endpoint,
+		rules:      rules,
+		netns:      network.NamespacePath(cfg.Netns),
+		natManager: natManager,
+	}, nil
+}
+
+func (f Factory) Validate(ctx context.Context, endpoint models.Endpoint) error {
+	validation := errors.NewValidationErrorsBuilder()
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		validation.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validation.Build()
+	}
+
+	if len(cfg.Rules) == 0 {
+		validation.Set("plugin_config.rules", "at least one rule is required")
+	}
+	seen := make(map[string]bool, len(cfg.Rules))
+	for i, ruleConfig := range cfg.Rules {
+		field := fmt.Sprintf("plugin_config.rules.%d", i)
+		rule, err := parseRule(ctx, ruleConfig)
+		if err != nil {
+			validation.Set(field, err.Error())
+			continue
+		}
+		if seen[rule.String()] {
+			validation.Set(field, "duplicated rule "+rule.String())
+		}
+		seen[rule.String()] = true
+	}
+
+	validationErr := validation.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+	return nil
+}
+
+// parseRule checks the configuration of a rule and returns the rule to install
+func parseRule(ctx context.Context, cfg api.NftablesRuleConfig) (network.NATRule, error) {
+	rule := network.NATRule{
+		Type:      network.NATRuleType(cfg.Type),
+		Protocol:  cfg.Protocol,
+		Interface: cfg.Interface,
+	}
+	if !rule.Type.IsValid() {
+		return network.NATRule{}, errors.Newf(ctx, "invalid type %s, must be dnat, snat or masquerade", cfg.Type)
+	}
+	if rule.Protocol != "" && rule.Protocol != "tcp" && rule.Protocol != "udp" && rule.Protocol != "sctp" {
+		return network.NATRule{}, errors.Newf(ctx, "unknown protocol %s, must be tcp, udp or sctp", rule.Protocol)
+	}
+	if len(rule.Interface) >= 16 {
+		return network.NATRule{}, errors.Newf(ctx, "invalid interface %s, the name is too long", rule.Interface)
+	}
+
+	var err error
+	if cfg.Source != "" {
+		rule.Source, err = parsePrefix(cfg.Source)
+		if err != nil {
+			return network.NATRule{}, errors.Wrap(ctx, err, "invalid source")
+		}
+	}
+	if cfg.Destination != "" {
+		rule.Destination, err = parsePrefix(cfg.Destination)
+		if err != nil {
+			return network.NATRule{}, errors.Wrap(ctx, err, "invalid destination")
+		}
+	}
+
+	if cfg.Port < 0 || cfg.Port > 65535 || cfg.ToPort < 0 || cfg.ToPort > 65535 {
+		return network.NATRule{}, errors.New(ctx, "port must be between 1 and 65535")
+	}
+	rule.Port = uint16(cfg.Port)
+	rule.ToPort = uint16(cfg.ToPort)
+	if (rule.Port != 0 || rule.ToPort != 0) && rule.Protocol == "" {
+		return network.NATRule{}, errors.New(ctx, "a protocol is required to match or translate a port")
+	}
+
+	switch rule.Type {
+	case network.NATRuleTypeMasquerade:
+		if cfg.ToAddress != "" {
+			return network.NATRule{}, errors.New(ctx, "a masquerade rule has no to_address, the address of the output interface is used")
+		}
+	default:
+		rule.ToAddress = net.ParseIP(cfg.ToAddress)
+		if rule.ToAddress == nil {
+			return network.NATRule{}, errors.Newf(ctx, "invalid to_address %s", cfg.ToAddress)
+		}
+	}
+
+	var previous net.IP
+	for _, ip := range []net.IP{rule.ToAddress, ipOf(rule.Source), ipOf(rule.Destination)} {
+		if ip == nil {
+			continue
+		}
+		if previous != nil && (ip.To4() == nil) != (previous.To4() == nil) {
+			return network.NATRule{}, errors.New(ctx, "the addresses of the rule must be of the same IP family")
+		}
+		previous = ip
+	}
+	return rule, nil
+}
+
+// parsePrefix parses a prefix using CIDR notation, a bare IP being a host prefix
+func parsePrefix(s string) (*net.IPNet, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		_, prefix, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		return prefix, nil
+	}
+	if ip.To4() != nil {
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func ipOf(prefix *net.IPNet) net.IP {
+	if prefix == nil {
+		return nil
+	}
+	return prefix.IP
+}
@@ -0,0 +1,120 @@
+package nftables
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a DNAT rule",
+			Config: PluginConfig{Rules: []api.NftablesRuleConfig{
+				{Type: "dnat", Protocol: "tcp", Destination: "192.0.2.10", Port: 80, ToAddress: "10.0.0.1", ToPort: 8080},
+			}},
+		}, {
+			Name: "with SNAT and masquerade rules",
+			Config: PluginConfig{Rules: []api.NftablesRuleConfig{
+				{Type: "snat", Source: "fd00::/64", ToAddress: "2001:db8::10"},
+				{Type: "masquerade", Source: "10.0.0.0/8", Interface: "eth0"},
+			}},
+		}, {
+			Name:          "without rule",
+			Config:        PluginConfig{},
+			ExpectedError: "at least one rule is required",
+		}, {
+			Name:          "with an unknown type",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "redirect"}}},
+			ExpectedError: "invalid type redirect",
+		}, {
+			Name:          "with an unknown protocol",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "masquerade", Protocol: "icmp"}}},
+			ExpectedError: "unknown protocol icmp",
+		}, {
+			Name:          "with a port without protocol",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "dnat", Port: 80, ToAddress: "10.0.0.1"}}},
+			ExpectedError: "a protocol is required",
+		}, {
+			Name:          "with a DNAT rule without address",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "dnat", Destination: "192.0.2.10"}}},
+			ExpectedError: "invalid to_address",
+		}, {
+			Name:          "with a masquerade rule with an address",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "masquerade", ToAddress: "192.0.2.10"}}},
+			ExpectedError: "a masquerade rule has no to_address",
+		}, {
+			Name:          "with an invalid source",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "masquerade", Source: "10.0.0.0/33"}}},
+			ExpectedError: "invalid source",
+		}, {
+			Name:          "with addresses of different families",
+			Config:        PluginConfig{Rules: []api.NftablesRuleConfig{{Type: "snat", Source: "10.0.0.0/8", ToAddress: "2001:db8::10"}}},
+			ExpectedError: "same IP family",
+		}, {
+			Name: "with a duplicated rule",
+			Config: PluginConfig{Rules: []api.NftablesRuleConfig{
+				{Type: "masquerade", Source: "10.0.0.0/8"},
+				{Type: "masquerade", Source: "10.0.0.0/8"},
+			}},
+			ExpectedError: "duplicated rule",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			pluginConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			if spec.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.ExpectedError)
+		})
+	}
+}
+
+func TestFactory_Create(t *testing.T) {
+	var netns, table string
+	factory := Factory{
+		config: Config{Table: "link"},
+		newNATManager: func(ns, t string) (network.NATManager, error) {
+			netns, table = ns, t
+			return nil, nil
+		},
+	}
+
+	pluginConfig, err := json.Marshal(PluginConfig{
+		Rules: []api.NftablesRuleConfig{{Type: "dnat", Protocol: "tcp", Destination: "192.0.2.10", Port: 80, ToAddress: "10.0.0.1"}},
+		Netns: "blue",
+	})
+	require.NoError(t, err)
+	p, err := factory.Create(context.Background(), models.Endpoint{ID: "vip-1", PluginConfig: pluginConfig})
+	require.NoError(t, err)
+
+	assert.Equal(t, "blue", netns)
+	assert.Equal(t, "link", table)
+	assert.Equal(t, []network.NATRule{{
+		Type:        network.NATRuleTypeDNAT,
+		Protocol:    "tcp",
+		Destination: &net.IPNet{IP: net.ParseIP("192.0.2.10").To4(), Mask: net.CIDRMask(32, 32)},
+		Port:        80,
+		ToAddress:   net.ParseIP("10.0.0.1"),
+	}}, p.(*Plugin).rules)
+	assert.Equal(t, "nftables:blue:ip_daddr_192.0.2.10_32_tcp_dport_80_dnat_to_10.0.0.1", p.ElectionKey(context.Background()))
+}
@@ -0,0 +1,94 @@
+package nftables
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+type Plugin struct {
+	endpoint models.Endpoint
+	rules    []network.NATRule
+	// natManager installs the rules tagged with the ID of the endpoint, it never modifies the other rules
+	natManager network.NATManager
+	// netns is the path of the network namespace of the rules, empty for the namespace of LinK
+	netns string
+}
+
+func (p *Plugin) Activate(ctx context.Context) error {
+	err := p.natManager.EnsureRules(p.endpoint.ID, p.rules)
+	if err != nil {
+		return errors.Wrap(ctx, err, "install NAT rules")
+	}
+	logger.Get(ctx).WithField("nftables_rules", p.ruleStrings()).Info("NAT rules installed")
+	return nil
+}
+
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	err := p.natManager.RemoveRules(p.endpoint.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove NAT rules")
+	}
+	logger.Get(ctx).WithField("nftables_rules", p.ruleStrings()).Info("NAT rules removed")
+	return nil
+}
+
+// Ensure installs the missing rules of the endpoint, and removes its rules which are not
+// configured anymore
+func (p *Plugin) Ensure(ctx context.Context) error {
+	has, err := p.natManager.HasRules(p.endpoint.ID, p.rules)
+	if err != nil {
+		return errors.Wrap(ctx, err, "check NAT rules")
+	}
+	if !has {
+		logger.Get(ctx).WithField("nftables_rules", p.ruleStrings()).Warn("NAT rules missing, installing them again")
+	}
+
+	err = p.natManager.EnsureRules(p.endpoint.ID, p.rules)
+	if err != nil {
+		return errors.Wrap(ctx, err, "install NAT rules")
+	}
+	return nil
+}
+
+// EnsureDeactivated removes the rules of the endpoint if they are still installed
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	err := p.natManager.RemoveRules(p.endpoint.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove NAT rules")
+	}
+	return nil
+}
+
+// IsActivated returns true if all the rules of the endpoint are installed
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	has, err := p.natManager.HasRules(p.endpoint.ID, p.rules)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "check NAT rules")
+	}
+	return has, nil
+}
+
+func (p *Plugin) ruleStrings() []string {
+	rules := make([]string, 0, len(p.rules))
+	for _, rule := range p.rules {
+		rules = append(rules, rule.String())
+	}
+	slices.Sort(rules)
+	return rules
+}
+
+// ElectionKey is based on the rules and the network namespace
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	key := strings.NewReplacer(" ", "_", "/", "_").Replace(strings.Join(p.ruleStrings(), ","))
+	if p.netns != "" {
+		netns := strings.TrimPrefix(p.netns, network.NetnsDir+"/")
+		key = strings.ReplaceAll(netns, "/", "_") + ":" + key
+	}
+	return "nftables:" + key
+}
@@ -0,0 +1,95 @@
+package nftables
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func newTestPlugin(natManager network.NATManager) *Plugin {
+	return &Plugin{
+		endpoint: models.Endpoint{ID: "vip-1"},
+		rules: []network.NATRule{
+			{Type: network.NATRuleTypeSNAT, Source: &net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}, ToAddress: net.ParseIP("192.0.2.10")},
+		},
+		natManager: natManager,
+	}
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it installs the rules tagged with the endpoint ID", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		natManager := networkmock.NewMockNATManager(ctrl)
+		p := newTestPlugin(natManager)
+
+		natManager.EXPECT().EnsureRules("vip-1", p.rules).Return(nil)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it returns the error of the installation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		natManager := networkmock.NewMockNATManager(ctrl)
+		p := newTestPlugin(natManager)
+
+		natManager.EXPECT().EnsureRules("vip-1", p.rules).Return(errors.New("operation not permitted"))
+
+		err := p.Activate(context.Background())
+		require.ErrorContains(t, err, "operation not permitted")
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	for _, installed := range []bool{true, false} {
+		ctrl := gomock.NewController(t)
+		natManager := networkmock.NewMockNATManager(ctrl)
+		p := newTestPlugin(natManager)
+
+		natManager.EXPECT().HasRules("vip-1", p.rules).Return(installed, nil)
+		natManager.EXPECT().EnsureRules("vip-1", p.rules).Return(nil)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	}
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	natManager := networkmock.NewMockNATManager(ctrl)
+	p := newTestPlugin(natManager)
+
+	natManager.EXPECT().RemoveRules("vip-1").Return(nil).Times(2)
+
+	require.NoError(t, p.Deactivate(context.Background()))
+	require.NoError(t, p.EnsureDeactivated(context.Background()))
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	natManager := networkmock.NewMockNATManager(ctrl)
+	p := newTestPlugin(natManager)
+
+	natManager.EXPECT().HasRules("vip-1", p.rules).Return(true, nil)
+
+	activated, err := p.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	p := newTestPlugin(nil)
+	assert.Equal(t, "nftables:ip_saddr_10.0.0.0_8_snat_to_192.0.2.10", p.ElectionKey(context.Background()))
+
+	p.netns = "/var/run/netns/blue"
+	assert.Equal(t, "nftables:blue:ip_saddr_10.0.0.0_8_snat_to_192.0.2.10", p.ElectionKey(context.Background()))
+}
@@ -0,0 +1,188 @@
+package openstackneutron
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/services/openstack"
+)
+
+type AllowedAddressPairPlugin struct {
+	openstackClient openstack.PortClient
+
+	refreshEvery time.Duration
+
+	// Allowed Address Pair Configuration
+	portID string // ID of the port of the current host
+	vip    string // IP or prefix added to the allowed address pairs of the port
+
+	// Internal configuration
+	lastRefreshedAt time.Time
+}
+
+// Activate adds the VIP to the allowed address pairs of the port. The other pairs of the port are
+// kept.
+func (p *AllowedAddressPairPlugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	port, err := p.openstackClient.GetPort(ctx, p.portID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get port")
+	}
+	if p.indexOfVIP(port) != -1 {
+		log.Info("VIP is already an allowed address pair of the port")
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.Info("Adding VIP to the allowed address pairs of the port")
+	pairs := append(port.AllowedAddressPairs, openstack.AddressPair{IPAddress: p.vip})
+	err = p.openstackClient.UpdateAllowedAddressPairs(ctx, p.portID, pairs)
+	if err != nil {
+		return errors.Wrap(ctx, err, "add VIP to the allowed address pairs")
+	}
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// Deactivate removes the VIP from the allowed address pairs of the port
+func (p *AllowedAddressPairPlugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	port, err := p.openstackClient.GetPort(ctx, p.portID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get port")
+	}
+	i := p.indexOfVIP(port)
+	if i == -1 {
+		log.Info("VIP is not an allowed address pair of the port, skipping removal")
+		return nil
+	}
+
+	log.Info("Removing VIP from the allowed address pairs of the port")
+	err = p.openstackClient.UpdateAllowedAddressPairs(ctx, p.portID, slices.Delete(port.AllowedAddressPairs, i, i+1))
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove VIP from the allowed address pairs")
+	}
+	return nil
+}
+
+func (p *AllowedAddressPairPlugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	activated, err := p.IsActivated(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "check allowed address pairs")
+	}
+
+	// If the VIP is not an allowed address pair of the port, we need to add it
+	if !activated {
+		log.Info("VIP is not an allowed address pair of the port, adding it")
+		err := p.Activate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "add VIP to the allowed address pairs")
+		}
+	}
+
+	return nil
+}
+
+// IsActivated returns true if the VIP is an allowed address pair of the port
+func (p *AllowedAddressPairPlugin) IsActivated(ctx context.Context) (bool, error) {
+	port, err := p.openstackClient.GetPort(ctx, p.portID)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "get port")
+	}
+	if p.indexOfVIP(port) == -1 {
+		return false, nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated removes the VIP from the allowed address pairs of the port of this host if it
+// is still there.
+func (p *AllowedAddressPairPlugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	port, err := p.openstackClient.GetPort(ctx, p.portID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get port")
+	}
+
+	i := p.indexOfVIP(port)
+	if i == -1 {
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.Info("VIP is still an allowed address pair of the port, removing it")
+	err = p.openstackClient.UpdateAllowedAddressPairs(ctx, p.portID, slices.Delete(port.AllowedAddressPairs, i, i+1))
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove VIP from the allowed address pairs")
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// indexOfVIP returns the index of the VIP in the allowed address pairs of the port, -1 if it is not
+// there. A bare IP and the host prefix of this IP are the same address.
+func (p *AllowedAddressPairPlugin) indexOfVIP(port openstack.Port) int {
+	vip, ok := parsePrefix(p.vip)
+	return slices.IndexFunc(port.AllowedAddressPairs, func(pair openstack.AddressPair) bool {
+		if !ok {
+			return pair.IPAddress == p.vip
+		}
+		prefix, ok := parsePrefix(pair.IPAddress)
+		return ok && prefix == vip
+	})
+}
+
+// parsePrefix parses a prefix using CIDR notation, a bare IP being a host prefix
+func parsePrefix(s string) (netip.Prefix, bool) {
+	addr, err := netip.ParseAddr(s)
+	if err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	return prefix.Masked(), true
+}
+
+// ElectionKey is based on the VIP, the endpoints of the hosts sharing the VIP compete for the same
+// key
+func (p *AllowedAddressPairPlugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, strings.ReplaceAll(p.vip, "/", "_"))
+}
+
+func (p *AllowedAddressPairPlugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":    "openstack_neutron",
+		"vip":     p.vip,
+		"port_id": p.portID,
+	}
+}
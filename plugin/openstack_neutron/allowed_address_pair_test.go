@@ -0,0 +1,174 @@
+package openstackneutron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/openstack"
+	"github.com/Scalingo/link/v3/services/openstack/openstackmock"
+)
+
+const testVIP = "10.0.0.100"
+
+func newAllowedAddressPairPlugin(mockClient *openstackmock.MockPortClient) *AllowedAddressPairPlugin {
+	return &AllowedAddressPairPlugin{
+		openstackClient: mockClient,
+		refreshEvery:    time.Minute,
+		portID:          testPortID,
+		vip:             testVIP,
+	}
+}
+
+// portWithPairs returns the port with the given allowed address pairs
+func portWithPairs(ips ...string) openstack.Port {
+	port := openstack.Port{ID: testPortID, MACAddress: "fa:16:3e:00:00:01"}
+	for _, ip := range ips {
+		port.AllowedAddressPairs = append(port.AllowedAddressPairs, openstack.AddressPair{IPAddress: ip, MACAddress: port.MACAddress})
+	}
+	return port
+}
+
+func TestAllowedAddressPairPlugin_Activate(t *testing.T) {
+	t.Run("it adds the VIP and keeps the other pairs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs("10.0.1.0/24"), nil)
+		mockClient.EXPECT().UpdateAllowedAddressPairs(gomock.Any(), testPortID, []openstack.AddressPair{
+			{IPAddress: "10.0.1.0/24", MACAddress: "fa:16:3e:00:00:01"},
+			{IPAddress: testVIP},
+		}).Return(nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("a VIP already allowed as a host prefix is not added", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs(testVIP+"/32"), nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs(), nil)
+		mockClient.EXPECT().UpdateAllowedAddressPairs(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("update error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "update error")
+		assert.True(t, plugin.lastRefreshedAt.IsZero())
+	})
+}
+
+func TestAllowedAddressPairPlugin_Deactivate(t *testing.T) {
+	t.Run("it removes the VIP and keeps the other pairs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs("10.0.1.0/24", testVIP), nil)
+		mockClient.EXPECT().UpdateAllowedAddressPairs(gomock.Any(), testPortID, []openstack.AddressPair{
+			{IPAddress: "10.0.1.0/24", MACAddress: "fa:16:3e:00:00:01"},
+		}).Return(nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("without the VIP, the port is not updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs("10.0.1.0/24"), nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestAllowedAddressPairPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a missing VIP is added again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs(), nil).Times(2)
+		mockClient.EXPECT().UpdateAllowedAddressPairs(gomock.Any(), testPortID, []openstack.AddressPair{{IPAddress: testVIP}}).Return(nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a VIP allowed on the port", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs(testVIP), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+}
+
+func TestAllowedAddressPairPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("a VIP still allowed on our port is removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs(testVIP), nil)
+		mockClient.EXPECT().UpdateAllowedAddressPairs(gomock.Any(), testPortID, []openstack.AddressPair{}).Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockPortClient(ctrl)
+		plugin := newAllowedAddressPairPlugin(mockClient)
+
+		mockClient.EXPECT().GetPort(gomock.Any(), testPortID).Return(portWithPairs(testVIP), nil)
+		mockClient.EXPECT().UpdateAllowedAddressPairs(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("update error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestAllowedAddressPairPlugin_ElectionKey(t *testing.T) {
+	plugin := &AllowedAddressPairPlugin{vip: "10.0.0.0/28"}
+	assert.Equal(t, "openstack_neutron/10.0.0.0_28", plugin.ElectionKey(context.Background()))
+}
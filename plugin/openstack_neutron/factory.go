@@ -0,0 +1,169 @@
+package openstackneutron
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/openstack"
+)
+
+const Name = api.PluginOpenStackNeutron
+
+type Config struct {
+	RefreshEvery time.Duration `envconfig:"OPENSTACK_NEUTRON_REFRESH_INTERVAL" default:"1m"`
+	// NetworkURL overrides the network endpoint of the service catalog
+	NetworkURL string `envconfig:"OPENSTACK_NETWORK_URL"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	openstackClient, err := openstack.NewClientFromCredentials(ctx, f.encryptedStorage, cfg.StorableCredentials, f.config.NetworkURL)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create OpenStack client")
+	}
+
+	if cfg.Mode == api.OpenStackNeutronModeAllowedAddressPair {
+		return &AllowedAddressPairPlugin{
+			openstackClient: openstackClient,
+			refreshEvery:    f.config.RefreshEvery,
+			portID:          cfg.PortID,
+			vip:             cfg.VIP,
+		}, nil
+	}
+
+	return &FloatingIPPlugin{
+		openstackClient: openstackClient,
+		refreshEvery:    f.config.RefreshEvery,
+		floatingIPID:    cfg.FloatingIPID,
+		portID:          cfg.PortID,
+		fixedIP:         cfg.FixedIP,
+	}, nil
+}
+
+type PluginConfig = api.OpenStackNeutronPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	openstack.ValidateCredentials(validations, req.AuthURL, req.ApplicationCredentialID, req.ApplicationCredentialSecret)
+
+	if req.PortID == "" {
+		validations.Set("plugin_config.port_id", "missing port ID")
+	}
+	if req.PortID != "" && !openstack.IDRegex.MatchString(req.PortID) {
+		validations.Set("plugin_config.port_id", "invalid port ID format")
+	}
+
+	switch req.Mode {
+	case "", api.OpenStackNeutronModeFloatingIP:
+		if req.FloatingIPID == "" {
+			validations.Set("plugin_config.floating_ip_id", "missing floating IP ID")
+		}
+		if req.FloatingIPID != "" && !openstack.IDRegex.MatchString(req.FloatingIPID) {
+			validations.Set("plugin_config.floating_ip_id", "invalid floating IP ID format")
+		}
+		if req.FixedIP != "" && net.ParseIP(req.FixedIP) == nil {
+			validations.Set("plugin_config.fixed_ip", "invalid fixed IP format")
+		}
+		if req.VIP != "" {
+			validations.Set("plugin_config.vip", "the VIP can only be set with the allowed_address_pair mode")
+		}
+	case api.OpenStackNeutronModeAllowedAddressPair:
+		if req.VIP == "" {
+			validations.Set("plugin_config.vip", "missing VIP")
+		}
+		_, _, err := net.ParseCIDR(req.VIP)
+		if req.VIP != "" && net.ParseIP(req.VIP) == nil && err != nil {
+			validations.Set("plugin_config.vip", "invalid VIP format, must be an IP or a prefix using CIDR notation")
+		}
+		if req.FloatingIPID != "" || req.FixedIP != "" {
+			validations.Set("plugin_config.floating_ip_id", "the floating IP can only be set with the floating_ip mode")
+		}
+	default:
+		validations.Set("plugin_config.mode", "invalid mode, must be floating_ip or allowed_address_pair")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		Mode:         req.Mode,
+		PortID:       req.PortID,
+		FloatingIPID: req.FloatingIPID,
+		FixedIP:      req.FixedIP,
+		VIP:          req.VIP,
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = api.OpenStackNeutronModeFloatingIP
+	}
+	cfg.StorableCredentials, err = openstack.EncryptCredentials(ctx, f.encryptedStorage, endpoint.ID, req.AuthURL, req.ApplicationCredentialID, req.ApplicationCredentialSecret, req.Region)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt credentials")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	openstack.StorableCredentials
+
+	Mode         string `json:"mode"`
+	PortID       string `json:"port_id"`
+	FloatingIPID string `json:"floating_ip_id,omitempty"`
+	FixedIP      string `json:"fixed_ip,omitempty"`
+	VIP          string `json:"vip,omitempty"`
+}
@@ -0,0 +1,183 @@
+package openstackneutron
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+)
+
+const (
+	testAuthURL                 = "https://keystone.example.com:5000/v3"
+	testApplicationCredentialID = "0123456789abcdef0123456789abcdef"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a valid floating IP configuration",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				PortID:                      testPortID,
+				FloatingIPID:                testFloatingIPID,
+				FixedIP:                     "10.0.0.5",
+			},
+		}, {
+			Name: "with a valid allowed address pair configuration",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				Mode:                        api.OpenStackNeutronModeAllowedAddressPair,
+				PortID:                      testPortID,
+				VIP:                         "10.0.0.100",
+			},
+		}, {
+			Name: "with an invalid auth URL",
+			Config: PluginConfig{
+				AuthURL:                     "keystone.example.com",
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				PortID:                      testPortID,
+				FloatingIPID:                testFloatingIPID,
+			},
+			ExpectedError: "invalid auth URL",
+		}, {
+			Name: "with a missing application credential secret",
+			Config: PluginConfig{
+				AuthURL:                 testAuthURL,
+				ApplicationCredentialID: testApplicationCredentialID,
+				PortID:                  testPortID,
+				FloatingIPID:            testFloatingIPID,
+			},
+			ExpectedError: "missing application credential secret",
+		}, {
+			Name: "with an invalid port ID",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				PortID:                      "eth0",
+				FloatingIPID:                testFloatingIPID,
+			},
+			ExpectedError: "invalid port ID format",
+		}, {
+			Name: "with an unknown mode",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				Mode:                        "vip",
+				PortID:                      testPortID,
+			},
+			ExpectedError: "invalid mode",
+		}, {
+			Name: "with a missing floating IP ID",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				PortID:                      testPortID,
+			},
+			ExpectedError: "missing floating IP ID",
+		}, {
+			Name: "with a VIP in the floating_ip mode",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				PortID:                      testPortID,
+				FloatingIPID:                testFloatingIPID,
+				VIP:                         "10.0.0.100",
+			},
+			ExpectedError: "the VIP can only be set with the allowed_address_pair mode",
+		}, {
+			Name: "with an invalid VIP",
+			Config: PluginConfig{
+				AuthURL:                     testAuthURL,
+				ApplicationCredentialID:     testApplicationCredentialID,
+				ApplicationCredentialSecret: "my-secret",
+				Mode:                        api.OpenStackNeutronModeAllowedAddressPair,
+				PortID:                      testPortID,
+				VIP:                         "10.0.0.300",
+			},
+			ExpectedError: "invalid VIP format",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := PluginConfig{
+		AuthURL:                     testAuthURL,
+		ApplicationCredentialID:     testApplicationCredentialID,
+		ApplicationCredentialSecret: "my-secret",
+		Region:                      "RegionOne",
+		PortID:                      testPortID,
+		FloatingIPID:                testFloatingIPID,
+	}
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", testApplicationCredentialID).Return(models.EncryptedDataLink{
+		ID:         "credential-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-secret").Return(models.EncryptedDataLink{
+		ID:         "secret-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the sensitive data, keep the rest and set the default mode
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "credential-id", stored.ApplicationCredentialID.ID)
+	assert.Equal(t, "secret-id", stored.ApplicationCredentialSecret.ID)
+	assert.Equal(t, req.AuthURL, stored.AuthURL)
+	assert.Equal(t, req.Region, stored.Region)
+	assert.Equal(t, api.OpenStackNeutronModeFloatingIP, stored.Mode)
+	assert.Equal(t, req.PortID, stored.PortID)
+	assert.Equal(t, req.FloatingIPID, stored.FloatingIPID)
+	assert.NotContains(t, string(res), "my-secret")
+}
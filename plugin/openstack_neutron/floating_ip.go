@@ -0,0 +1,162 @@
+package openstackneutron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/services/openstack"
+)
+
+type FloatingIPPlugin struct {
+	openstackClient openstack.FloatingIPClient
+
+	refreshEvery time.Duration
+
+	// Floating IP Configuration
+	floatingIPID string // ID of the floating IP to move
+	portID       string // ID of the port to associate the floating IP with
+	// fixedIP is the IP of the port associated with the floating IP, Neutron picks the IPv4 of the
+	// port if it is empty
+	fixedIP string
+
+	// Internal configuration
+	lastRefreshedAt time.Time
+}
+
+// Activate associates the floating IP with the port, moving it from the port it was associated with
+func (p *FloatingIPPlugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	log.Info("Associating floating IP with port")
+	err := p.openstackClient.AssociateFloatingIP(ctx, p.floatingIPID, p.portID, p.fixedIP)
+	if err != nil {
+		return errors.Wrap(ctx, err, "associate floating IP")
+	}
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// Deactivate disassociates the floating IP, unless it has already been associated with another port
+func (p *FloatingIPPlugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	floatingIP, err := p.openstackClient.GetFloatingIP(ctx, p.floatingIPID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get floating IP")
+	}
+	if floatingIP.PortID != p.portID {
+		log.Info("Floating IP is not associated with the port, skipping disassociation")
+		return nil
+	}
+
+	log.Info("Disassociating floating IP from port")
+	err = p.openstackClient.DisassociateFloatingIP(ctx, p.floatingIPID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "disassociate floating IP")
+	}
+	return nil
+}
+
+func (p *FloatingIPPlugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	floatingIP, err := p.openstackClient.GetFloatingIP(ctx, p.floatingIPID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get floating IP")
+	}
+
+	// If the floating IP is not associated with the port, we need to associate it
+	if !p.isAssociated(floatingIP) {
+		log.WithFields(logrus.Fields{
+			"current_port_id":  floatingIP.PortID,
+			"current_fixed_ip": floatingIP.FixedIPAddress,
+		}).Info("Floating IP is not associated with the port, associating it")
+		err := p.Activate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "associate floating IP")
+		}
+		return nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// IsActivated returns true if the floating IP is associated with the port
+func (p *FloatingIPPlugin) IsActivated(ctx context.Context) (bool, error) {
+	floatingIP, err := p.openstackClient.GetFloatingIP(ctx, p.floatingIPID)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "get floating IP")
+	}
+	if !p.isAssociated(floatingIP) {
+		return false, nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated disassociates the floating IP if it is still associated with the port of this
+// host.
+func (p *FloatingIPPlugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	floatingIP, err := p.openstackClient.GetFloatingIP(ctx, p.floatingIPID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get floating IP")
+	}
+
+	// The floating IP is associated with another port (or not associated at all), nothing to do
+	if floatingIP.PortID != p.portID {
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.Info("Floating IP is still associated with the port, disassociating it")
+	err = p.openstackClient.DisassociateFloatingIP(ctx, p.floatingIPID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "disassociate floating IP")
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// isAssociated returns true if the floating IP is associated with the port, and with the configured
+// fixed IP if any
+func (p *FloatingIPPlugin) isAssociated(floatingIP openstack.FloatingIP) bool {
+	if floatingIP.PortID != p.portID {
+		return false
+	}
+	return p.fixedIP == "" || floatingIP.FixedIPAddress == p.fixedIP
+}
+
+func (p *FloatingIPPlugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, p.floatingIPID)
+}
+
+func (p *FloatingIPPlugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":           "openstack_neutron",
+		"floating_ip_id": p.floatingIPID,
+		"port_id":        p.portID,
+	}
+}
@@ -0,0 +1,195 @@
+package openstackneutron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/openstack"
+	"github.com/Scalingo/link/v3/services/openstack/openstackmock"
+)
+
+const (
+	testFloatingIPID = "11111111-1111-4111-8111-111111111111"
+	testPortID       = "22222222-2222-4222-8222-222222222222"
+	testOtherPortID  = "33333333-3333-4333-8333-333333333333"
+)
+
+func newFloatingIPPlugin(mockClient *openstackmock.MockFloatingIPClient) *FloatingIPPlugin {
+	return &FloatingIPPlugin{
+		openstackClient: mockClient,
+		refreshEvery:    time.Minute,
+		floatingIPID:    testFloatingIPID,
+		portID:          testPortID,
+	}
+}
+
+func floatingIPAssociatedWith(portID, fixedIP string) openstack.FloatingIP {
+	return openstack.FloatingIP{
+		ID:                testFloatingIPID,
+		FloatingIPAddress: "203.0.113.10",
+		PortID:            portID,
+		FixedIPAddress:    fixedIP,
+	}
+}
+
+func TestFloatingIPPlugin_Activate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+		plugin.fixedIP = "10.0.0.5"
+
+		mockClient.EXPECT().AssociateFloatingIP(gomock.Any(), testFloatingIPID, testPortID, "10.0.0.5").Return(nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().AssociateFloatingIP(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("associate error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "associate error")
+	})
+}
+
+func TestFloatingIPPlugin_Deactivate(t *testing.T) {
+	t.Run("it disassociates the floating IP", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testPortID, "10.0.0.5"), nil)
+		mockClient.EXPECT().DisassociateFloatingIP(gomock.Any(), testFloatingIPID).Return(nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a floating IP already associated with another port is not disassociated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testOtherPortID, "10.0.0.6"), nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestFloatingIPPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a floating IP associated with another port is associated again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testOtherPortID, "10.0.0.6"), nil)
+		mockClient.EXPECT().AssociateFloatingIP(gomock.Any(), testFloatingIPID, testPortID, "").Return(nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a floating IP associated with another fixed IP of the port is associated again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+		plugin.fixedIP = "10.0.0.5"
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testPortID, "10.0.0.7"), nil)
+		mockClient.EXPECT().AssociateFloatingIP(gomock.Any(), testFloatingIPID, testPortID, "10.0.0.5").Return(nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a floating IP associated with the port", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testPortID, "10.0.0.5"), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+}
+
+func TestFloatingIPPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("a floating IP still associated with our port is disassociated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testPortID, "10.0.0.5"), nil)
+		mockClient.EXPECT().DisassociateFloatingIP(gomock.Any(), testFloatingIPID).Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a floating IP associated with another port is left untouched", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testOtherPortID, "10.0.0.6"), nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("disassociate error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+		plugin := newFloatingIPPlugin(mockClient)
+
+		mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testPortID, "10.0.0.5"), nil)
+		mockClient.EXPECT().DisassociateFloatingIP(gomock.Any(), testFloatingIPID).Return(errors.New("disassociate error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestFloatingIPPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := openstackmock.NewMockFloatingIPClient(ctrl)
+	plugin := newFloatingIPPlugin(mockClient)
+
+	mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith(testPortID, "10.0.0.5"), nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockClient.EXPECT().GetFloatingIP(gomock.Any(), testFloatingIPID).Return(floatingIPAssociatedWith("", ""), nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
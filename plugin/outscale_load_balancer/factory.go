@@ -0,0 +1,132 @@
+package outscaleloadbalancer
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/outscale"
+)
+
+const Name = api.PluginOutscaleLoadBalancer
+
+// loadBalancerNameRegex matches the names accepted by Outscale for the load balancers
+var loadBalancerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9-]{1,32}$`)
+
+type Config struct {
+	GoEnv        string        `envconfig:"GO_ENV"`
+	RefreshEvery time.Duration `envconfig:"OUTSCALE_LOAD_BALANCER_REFRESH_INTERVAL" default:"1m"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage, oscClients *outscale.Clients) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+		oscClients:       oscClients,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+	oscClients       *outscale.Clients
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	oscClient, err := f.oscClients.FromCredentials(ctx, f.encryptedStorage, cfg.StorableCredentials)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create Outscale client")
+	}
+
+	return &Plugin{
+		oscClient:        oscClient,
+		refreshEvery:     f.config.RefreshEvery,
+		loadBalancerName: cfg.LoadBalancerName,
+		vmID:             cfg.VMID,
+	}, nil
+}
+
+type PluginConfig = api.OutscaleLoadBalancerPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	outscale.ValidateCredentials(validations, req.AccessKey, req.SecretKey, req.Region, f.config.GoEnv)
+
+	if req.LoadBalancerName == "" {
+		validations.Set("plugin_config.load_balancer_name", "missing load balancer name")
+	}
+	if req.LoadBalancerName != "" && !loadBalancerNameRegex.MatchString(req.LoadBalancerName) {
+		validations.Set("plugin_config.load_balancer_name", "invalid load balancer name format")
+	}
+
+	if req.VMID == "" {
+		validations.Set("plugin_config.vm_id", "missing VM ID")
+	}
+	if req.VMID != "" && !outscale.IDRegex.MatchString(req.VMID) {
+		validations.Set("plugin_config.vm_id", "invalid VM ID format")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		LoadBalancerName: req.LoadBalancerName,
+		VMID:             req.VMID,
+	}
+	cfg.StorableCredentials, err = outscale.EncryptCredentials(ctx, f.encryptedStorage, endpoint.ID, req.AccessKey, req.SecretKey, req.Region)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt credentials")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	outscale.StorableCredentials
+
+	LoadBalancerName string `json:"load_balancer_name"`
+	VMID             string `json:"vm_id"`
+}
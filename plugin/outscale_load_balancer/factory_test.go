@@ -0,0 +1,139 @@
+package outscaleloadbalancer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a valid configuration",
+			Config: PluginConfig{
+				AccessKey:        "ABC1234",
+				SecretKey:        "ABC1234",
+				Region:           "eu-west-2",
+				LoadBalancerName: "my-load-balancer",
+				VMID:             "i-456",
+			},
+		}, {
+			Name: "with a missing load balancer name",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				VMID:      "i-456",
+			},
+			ExpectedError: "missing load balancer name",
+		}, {
+			Name: "with an invalid load balancer name",
+			Config: PluginConfig{
+				AccessKey:        "ABC1234",
+				SecretKey:        "ABC1234",
+				Region:           "eu-west-2",
+				LoadBalancerName: "my_load_balancer",
+				VMID:             "i-456",
+			},
+			ExpectedError: "invalid load balancer name format",
+		}, {
+			Name: "with a missing VM ID",
+			Config: PluginConfig{
+				AccessKey:        "ABC1234",
+				SecretKey:        "ABC1234",
+				Region:           "eu-west-2",
+				LoadBalancerName: "my-load-balancer",
+			},
+			ExpectedError: "missing VM ID",
+		}, {
+			Name: "with an invalid VM ID format",
+			Config: PluginConfig{
+				AccessKey:        "ABC1234",
+				SecretKey:        "ABC1234",
+				Region:           "eu-west-2",
+				LoadBalancerName: "my-load-balancer",
+				VMID:             "invalid-vm-id",
+			},
+			ExpectedError: "invalid VM ID format",
+		}, {
+			Name: "with missing credentials",
+			Config: PluginConfig{
+				Region:           "eu-west-2",
+				LoadBalancerName: "my-load-balancer",
+				VMID:             "i-456",
+			},
+			ExpectedError: "missing access key",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := PluginConfig{
+		AccessKey:        "my-access",
+		SecretKey:        "my-secret",
+		Region:           "eu-west-2",
+		LoadBalancerName: "my-load-balancer",
+		VMID:             "i-456",
+	}
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-access").Return(models.EncryptedDataLink{
+		ID:         "access-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-secret").Return(models.EncryptedDataLink{
+		ID:         "secret-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the sensitive data and keep the rest
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "access-id", stored.AccessKey.ID)
+	assert.Equal(t, "secret-id", stored.SecretKey.ID)
+	assert.Equal(t, req.Region, stored.Region)
+	assert.Equal(t, req.LoadBalancerName, stored.LoadBalancerName)
+	assert.Equal(t, req.VMID, stored.VMID)
+	assert.NotContains(t, string(res), "my-secret")
+}
@@ -0,0 +1,175 @@
+package outscaleloadbalancer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/services/outscale"
+)
+
+type Plugin struct {
+	oscClient outscale.LoadBalancerClient
+
+	refreshEvery time.Duration
+
+	// Load Balancer Configuration
+	loadBalancerName string // Name of the load balancer
+	vmID             string // ID of the VM of this host, registered as a backend of the load balancer
+
+	// Internal configuration
+	lastRefreshedAt time.Time
+}
+
+// Activate registers the VM as a backend of the load balancer
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	log.Info("Registering VM in load balancer")
+	err := p.register(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "register VM")
+	}
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// Deactivate deregisters the VM from the load balancer if it is still registered
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	registered, err := p.isRegistered(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read load balancer")
+	}
+	if !registered {
+		log.Info("VM is not registered in the load balancer, skipping deregistration")
+		return nil
+	}
+
+	log.Info("Deregistering VM from load balancer")
+	err = p.deregister(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "deregister VM")
+	}
+	return nil
+}
+
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	registered, err := p.isRegistered(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read load balancer")
+	}
+
+	// If the VM is not a backend of the load balancer, we need to register it
+	if !registered {
+		log.Info("VM is not registered in the load balancer, registering it")
+		err := p.Activate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "register VM")
+		}
+		return nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// IsActivated returns true if the VM is a backend of the load balancer
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	registered, err := p.isRegistered(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "read load balancer")
+	}
+	if !registered {
+		return false, nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated deregisters the VM if it is still a backend of the load balancer (e.g. after
+// a failed de-activation).
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	registered, err := p.isRegistered(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read load balancer")
+	}
+
+	if !registered {
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.Info("VM is still registered in the load balancer, deregistering it")
+	err = p.deregister(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "deregister VM")
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// isRegistered returns true if the VM is a backend of the load balancer
+func (p *Plugin) isRegistered(ctx context.Context) (bool, error) {
+	loadBalancer, err := p.oscClient.ReadLoadBalancer(ctx, p.loadBalancerName)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(loadBalancer.GetBackendVmIds(), p.vmID), nil
+}
+
+func (p *Plugin) register(ctx context.Context) error {
+	_, err := p.oscClient.RegisterVMsInLoadBalancer(ctx, osc.RegisterVmsInLoadBalancerRequest{
+		LoadBalancerName: p.loadBalancerName,
+		BackendVmIds:     []string{p.vmID},
+	})
+	return err
+}
+
+func (p *Plugin) deregister(ctx context.Context) error {
+	_, err := p.oscClient.DeregisterVMsInLoadBalancer(ctx, osc.DeregisterVmsInLoadBalancerRequest{
+		LoadBalancerName: p.loadBalancerName,
+		BackendVmIds:     []string{p.vmID},
+	})
+	return err
+}
+
+// ElectionKey is based on the load balancer: a single VM is registered by the endpoints of a load
+// balancer
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, p.loadBalancerName)
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":               "outscale_load_balancer",
+		"load_balancer_name": p.loadBalancerName,
+		"vm_id":              p.vmID,
+	}
+}
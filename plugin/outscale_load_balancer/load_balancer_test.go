@@ -0,0 +1,188 @@
+package outscaleloadbalancer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/outscale/outscalemock"
+)
+
+const (
+	testLoadBalancerName = "my-load-balancer"
+	testVMID             = "i-456"
+)
+
+func newPlugin(mockClient *outscalemock.MockLoadBalancerClient) *Plugin {
+	return &Plugin{
+		oscClient:        mockClient,
+		refreshEvery:     time.Minute,
+		loadBalancerName: testLoadBalancerName,
+		vmID:             testVMID,
+	}
+}
+
+func loadBalancerWithBackends(vmIDs ...string) osc.LoadBalancer {
+	loadBalancer := osc.LoadBalancer{}
+	loadBalancer.SetLoadBalancerName(testLoadBalancerName)
+	loadBalancer.SetBackendVmIds(vmIDs)
+	return loadBalancer
+}
+
+var (
+	registerRequest = osc.RegisterVmsInLoadBalancerRequest{
+		LoadBalancerName: testLoadBalancerName,
+		BackendVmIds:     []string{testVMID},
+	}
+	deregisterRequest = osc.DeregisterVmsInLoadBalancerRequest{
+		LoadBalancerName: testLoadBalancerName,
+		BackendVmIds:     []string{testVMID},
+	}
+)
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().RegisterVMsInLoadBalancer(gomock.Any(), registerRequest).Return(osc.RegisterVmsInLoadBalancerResponse{}, nil)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().RegisterVMsInLoadBalancer(gomock.Any(), registerRequest).Return(osc.RegisterVmsInLoadBalancerResponse{}, errors.New("register error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "register error")
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	t.Run("a registered VM is deregistered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends("i-123", testVMID), nil)
+		mockClient.EXPECT().DeregisterVMsInLoadBalancer(gomock.Any(), deregisterRequest).Return(osc.DeregisterVmsInLoadBalancerResponse{}, nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a VM which is not registered is not deregistered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends("i-123"), nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a VM which is not registered is registered again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends(), nil)
+		mockClient.EXPECT().RegisterVMsInLoadBalancer(gomock.Any(), registerRequest).Return(osc.RegisterVmsInLoadBalancerResponse{}, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a registered VM", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends(testVMID), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+}
+
+func TestPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a VM still registered is deregistered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends(testVMID), nil)
+		mockClient.EXPECT().DeregisterVMsInLoadBalancer(gomock.Any(), deregisterRequest).Return(osc.DeregisterVmsInLoadBalancerResponse{}, nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("deregister error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends(testVMID), nil)
+		mockClient.EXPECT().DeregisterVMsInLoadBalancer(gomock.Any(), deregisterRequest).Return(osc.DeregisterVmsInLoadBalancerResponse{}, errors.New("deregister error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := outscalemock.NewMockLoadBalancerClient(ctrl)
+	plugin := newPlugin(mockClient)
+
+	mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends("i-123", testVMID), nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockClient.EXPECT().ReadLoadBalancer(gomock.Any(), testLoadBalancerName).Return(loadBalancerWithBackends("i-123"), nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
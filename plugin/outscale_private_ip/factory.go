@@ -0,0 +1,144 @@
+package outscaleprivateip
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/outscale"
+)
+
+const Name = api.PluginOutscalePrivateIP
+
+type Config struct {
+	GoEnv        string        `envconfig:"GO_ENV"`
+	RefreshEvery time.Duration `envconfig:"OUTSCALE_PRIVATE_IP_REFRESH_INTERVAL" default:"1m"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage, oscClients *outscale.Clients) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+		oscClients:       oscClients,
+		newInterface: func(name string) (network.Interface, error) {
+			return network.NewNetworkInterfaceFromName(name, network.NetInterfaceOpts{})
+		},
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+	oscClients       *outscale.Clients
+	newInterface     func(name string) (network.Interface, error)
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	oscClient, err := f.oscClients.FromCredentials(ctx, f.encryptedStorage, cfg.StorableCredentials)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create Outscale client")
+	}
+
+	p := &Plugin{
+		oscClient:    oscClient,
+		refreshEvery: f.config.RefreshEvery,
+		privateIP:    cfg.PrivateIP,
+		nicID:        cfg.NICID,
+	}
+	if cfg.Interface != "" {
+		p.netInterface, err = f.newInterface(cfg.Interface)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "get interface %s", cfg.Interface)
+		}
+	}
+	return p, nil
+}
+
+type PluginConfig = api.OutscalePrivateIPPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	outscale.ValidateCredentials(validations, req.AccessKey, req.SecretKey, req.Region, f.config.GoEnv)
+
+	if req.PrivateIP == "" {
+		validations.Set("plugin_config.private_ip", "missing private IP")
+	}
+	ip := net.ParseIP(req.PrivateIP)
+	if req.PrivateIP != "" && (ip == nil || ip.To4() == nil) {
+		validations.Set("plugin_config.private_ip", "invalid private IP format, must be an IPv4 address")
+	}
+
+	if req.NICID == "" {
+		validations.Set("plugin_config.nic_id", "missing NIC ID")
+	}
+	if req.NICID != "" && !outscale.IDRegex.MatchString(req.NICID) {
+		validations.Set("plugin_config.nic_id", "invalid NIC ID format")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		PrivateIP: req.PrivateIP,
+		NICID:     req.NICID,
+		Interface: req.Interface,
+	}
+	cfg.StorableCredentials, err = outscale.EncryptCredentials(ctx, f.encryptedStorage, endpoint.ID, req.AccessKey, req.SecretKey, req.Region)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt credentials")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	outscale.StorableCredentials
+
+	PrivateIP string `json:"private_ip"`
+	NICID     string `json:"nic_id"`
+	Interface string `json:"interface,omitempty"`
+}
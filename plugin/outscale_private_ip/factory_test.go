@@ -0,0 +1,144 @@
+package outscaleprivateip
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a valid configuration",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				PrivateIP: "10.0.1.10",
+				NICID:     "nic-456",
+				Interface: "eth0",
+			},
+		}, {
+			Name: "with an invalid region",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "mars-north-1",
+				PrivateIP: "10.0.1.10",
+				NICID:     "nic-456",
+			},
+			ExpectedError: "invalid region",
+		}, {
+			Name: "with a missing private IP",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+			},
+			ExpectedError: "missing private IP",
+		}, {
+			Name: "with an IPv6 private IP",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				PrivateIP: "2001:db8::10",
+				NICID:     "nic-456",
+			},
+			ExpectedError: "invalid private IP format",
+		}, {
+			Name: "with a missing NIC ID",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				PrivateIP: "10.0.1.10",
+			},
+			ExpectedError: "missing NIC ID",
+		}, {
+			Name: "with an invalid NIC ID format",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				PrivateIP: "10.0.1.10",
+				NICID:     "invalid-nic-id",
+			},
+			ExpectedError: "invalid NIC ID format",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := PluginConfig{
+		AccessKey: "my-access",
+		SecretKey: "my-secret",
+		Region:    "eu-west-2",
+		PrivateIP: "10.0.1.10",
+		NICID:     "nic-456",
+		Interface: "eth0",
+	}
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-access").Return(models.EncryptedDataLink{
+		ID:         "access-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-secret").Return(models.EncryptedDataLink{
+		ID:         "secret-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the sensitive data and keep the rest
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "access-id", stored.AccessKey.ID)
+	assert.Equal(t, "secret-id", stored.SecretKey.ID)
+	assert.Equal(t, req.Region, stored.Region)
+	assert.Equal(t, req.PrivateIP, stored.PrivateIP)
+	assert.Equal(t, req.NICID, stored.NICID)
+	assert.Equal(t, req.Interface, stored.Interface)
+	assert.NotContains(t, string(res), "my-secret")
+}
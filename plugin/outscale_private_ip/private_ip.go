@@ -0,0 +1,221 @@
+package outscaleprivateip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/services/outscale"
+)
+
+type Plugin struct {
+	oscClient outscale.PrivateIPClient
+
+	refreshEvery time.Duration
+
+	// Private IP Configuration
+	privateIP string // Secondary private IP to move
+	nicID     string // ID of the NIC to move the private IP to
+	// netInterface is the local interface where the private IP is added, nil if the IP is not
+	// configured on the host
+	netInterface network.Interface
+
+	// Internal configuration
+	lastRefreshedAt time.Time
+}
+
+// Activate links the private IP to the NIC, unlinking it from the NIC it was linked to, then adds
+// it to the local interface
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	log.Info("Linking private IP to NIC")
+	_, err := p.oscClient.LinkPrivateIPs(ctx, osc.LinkPrivateIpsRequest{
+		NicId:       p.nicID,
+		PrivateIps:  &[]string{p.privateIP},
+		AllowRelink: osc.PtrBool(true),
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "link private IP")
+	}
+	p.lastRefreshedAt = time.Now()
+
+	if p.netInterface != nil {
+		err = p.netInterface.EnsureIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "add private IP to the interface")
+		}
+	}
+
+	return nil
+}
+
+// Deactivate removes the private IP from the local interface, then unlinks it from the NIC if it
+// has not been linked to another NIC yet
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.netInterface != nil {
+		err := p.netInterface.RemoveIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "remove private IP from the interface")
+		}
+	}
+
+	linked, err := p.isLinked(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read NIC")
+	}
+	if !linked {
+		log.Info("Private IP is not linked to the NIC, skipping unlink")
+		return nil
+	}
+
+	log.Info("Unlinking private IP from NIC")
+	err = p.unlink(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unlink private IP")
+	}
+	return nil
+}
+
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	// The local interface is checked at every run, it does not call the Outscale API
+	if p.netInterface != nil {
+		err := p.netInterface.EnsureIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "add private IP to the interface")
+		}
+	}
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	linked, err := p.isLinked(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read NIC")
+	}
+
+	// If the private IP is not linked to the NIC, we need to link it
+	if !linked {
+		log.Info("Private IP is not linked to the NIC, linking it")
+		err := p.Activate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "link private IP")
+		}
+		return nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// IsActivated returns true if the private IP is linked to the NIC
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	linked, err := p.isLinked(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "read NIC")
+	}
+	if !linked {
+		return false, nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated removes the private IP from the local interface and unlinks it if it is still
+// linked to the NIC of this host.
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.netInterface != nil {
+		has, err := p.netInterface.HasIP(p.cidr())
+		if err != nil {
+			return errors.Wrap(ctx, err, "check the interface")
+		}
+		if has {
+			log.Info("Private IP is still on the interface, removing it")
+			err = p.netInterface.RemoveIP(p.cidr())
+			if err != nil {
+				return errors.Wrap(ctx, err, "remove private IP from the interface")
+			}
+		}
+	}
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	linked, err := p.isLinked(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read NIC")
+	}
+
+	// The private IP is linked to another NIC, nothing to do
+	if !linked {
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	log.Info("Private IP is still linked to the NIC, unlinking it")
+	err = p.unlink(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unlink private IP")
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// isLinked returns true if the private IP is a secondary private IP of the NIC
+func (p *Plugin) isLinked(ctx context.Context) (bool, error) {
+	nic, err := p.oscClient.ReadNIC(ctx, p.nicID)
+	if err != nil {
+		return false, err
+	}
+	for _, privateIP := range nic.GetPrivateIps() {
+		if privateIP.GetPrivateIp() == p.privateIP {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *Plugin) unlink(ctx context.Context) error {
+	_, err := p.oscClient.UnlinkPrivateIPs(ctx, osc.UnlinkPrivateIpsRequest{
+		NicId:      p.nicID,
+		PrivateIps: []string{p.privateIP},
+	})
+	return err
+}
+
+// cidr returns the private IP as configured on the local interface
+func (p *Plugin) cidr() string {
+	return p.privateIP + "/32"
+}
+
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, p.privateIP)
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":       "outscale_private_ip",
+		"private_ip": p.privateIP,
+		"nic_id":     p.nicID,
+	}
+}
@@ -0,0 +1,215 @@
+package outscaleprivateip
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network/networkmock"
+	"github.com/Scalingo/link/v3/services/outscale/outscalemock"
+)
+
+const (
+	testPrivateIP = "10.0.1.10"
+	testNicID     = "nic-456"
+)
+
+func newPlugin(mockClient *outscalemock.MockPrivateIPClient) *Plugin {
+	return &Plugin{
+		oscClient:    mockClient,
+		refreshEvery: time.Minute,
+		privateIP:    testPrivateIP,
+		nicID:        testNicID,
+	}
+}
+
+// nicWithPrivateIPs returns a NIC with the given private IPs, the first one being the primary IP
+func nicWithPrivateIPs(ips ...string) osc.Nic {
+	privateIPs := make([]osc.PrivateIp, 0, len(ips))
+	for i, ip := range ips {
+		privateIPs = append(privateIPs, osc.PrivateIp{PrivateIp: osc.PtrString(ip), IsPrimary: osc.PtrBool(i == 0)})
+	}
+	nic := osc.Nic{}
+	nic.SetNicId(testNicID)
+	nic.SetPrivateIps(privateIPs)
+	return nic
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it relinks the private IP to the NIC and adds it to the interface", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.netInterface = netInterface
+
+		gomock.InOrder(
+			mockClient.EXPECT().
+				LinkPrivateIPs(gomock.Any(), osc.LinkPrivateIpsRequest{
+					NicId:       testNicID,
+					PrivateIps:  &[]string{testPrivateIP},
+					AllowRelink: osc.PtrBool(true),
+				}).
+				Return(osc.LinkPrivateIpsResponse{}, nil),
+			netInterface.EXPECT().EnsureIP(testPrivateIP+"/32").Return(nil),
+		)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().
+			LinkPrivateIPs(gomock.Any(), gomock.Any()).
+			Return(osc.LinkPrivateIpsResponse{}, errors.New("link error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "link error")
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	t.Run("it removes the private IP from the interface and unlinks it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.netInterface = netInterface
+
+		gomock.InOrder(
+			netInterface.EXPECT().RemoveIP(testPrivateIP+"/32").Return(nil),
+			mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5", testPrivateIP), nil),
+			mockClient.EXPECT().
+				UnlinkPrivateIPs(gomock.Any(), osc.UnlinkPrivateIpsRequest{
+					NicId:      testNicID,
+					PrivateIps: []string{testPrivateIP},
+				}).
+				Return(osc.UnlinkPrivateIpsResponse{}, nil),
+		)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a private IP already relinked to another NIC is not unlinked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5"), nil)
+
+		err := plugin.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed, only the interface is checked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.netInterface = netInterface
+		plugin.lastRefreshedAt = time.Now()
+
+		netInterface.EXPECT().EnsureIP(testPrivateIP + "/32").Return(nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a private IP linked to another NIC is linked again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5"), nil)
+		mockClient.EXPECT().LinkPrivateIPs(gomock.Any(), gomock.Any()).Return(osc.LinkPrivateIpsResponse{}, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a private IP linked to the NIC", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+}
+
+func TestPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("a private IP left on the interface is removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.netInterface = netInterface
+		plugin.lastRefreshedAt = time.Now()
+
+		netInterface.EXPECT().HasIP(testPrivateIP+"/32").Return(true, nil)
+		netInterface.EXPECT().RemoveIP(testPrivateIP + "/32").Return(nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a private IP still linked to our NIC is unlinked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+		mockClient.EXPECT().UnlinkPrivateIPs(gomock.Any(), gomock.Any()).Return(osc.UnlinkPrivateIpsResponse{}, nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("unlink error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+		mockClient.EXPECT().UnlinkPrivateIPs(gomock.Any(), gomock.Any()).Return(osc.UnlinkPrivateIpsResponse{}, errors.New("unlink error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := outscalemock.NewMockPrivateIPClient(ctrl)
+	plugin := newPlugin(mockClient)
+
+	mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5", testPrivateIP), nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockClient.EXPECT().ReadNIC(gomock.Any(), testNicID).Return(nicWithPrivateIPs("10.0.1.5"), nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
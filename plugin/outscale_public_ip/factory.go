@@ -3,9 +3,6 @@ package outscalepublicip
 import (
 	"context"
 	"encoding/json"
-	"regexp"
-	"slices"
-	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -19,24 +16,13 @@ import (
 
 const Name = api.PluginOutscalePublicIP
 
-var outscaleRegions = []string{
-	"ap-northeast-1",
-	"cloudgouv-eu-west-1",
-	"eu-west-2",
-	"us-east-2",
-	"us-west-1",
-}
-
-var tokenRegex = regexp.MustCompile(`^[A-Z0-9]{3,64}$`)
-
-var outscaleIDRegex = regexp.MustCompile(`^[a-z]+-[a-f0-9]{3,64}$`)
-
 type Config struct {
 	GoEnv        string        `envconfig:"GO_ENV"`
 	RefreshEvery time.Duration `envconfig:"OUTSCALE_PUBLIC_IP_REFRESH_INTERVAL" default:"1m"`
+	MetadataURL  string        `envconfig:"OUTSCALE_METADATA_URL" default:"http://169.254.169.254/latest/meta-data"`
 }
 
-func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage, oscClients *outscale.Clients) error {
 	var config Config
 	err := envconfig.Process("", &config)
 	if err != nil {
@@ -46,6 +32,8 @@ func Register(ctx context.Context, registry plugin.Registry, encryptedStorage mo
 	registry.Register(ctx, Name, Factory{
 		config:           config,
 		encryptedStorage: encryptedStorage,
+		oscClients:       oscClients,
+		metadata:         outscale.NewMetadataClient(config.MetadataURL),
 	})
 
 	return nil
@@ -54,6 +42,9 @@ func Register(ctx context.Context, registry plugin.Registry, encryptedStorage mo
 type Factory struct {
 	config           Config
 	encryptedStorage models.EncryptedStorage
+	oscClients       *outscale.Clients
+	// metadata is used to discover the NIC of the endpoints without nic_id
+	metadata outscale.Metadata
 }
 
 func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
@@ -63,22 +54,28 @@ func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.P
 		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
 	}
 
-	var accessKey, secretKey string
-	err = f.encryptedStorage.Decrypt(ctx, cfg.AccessKey, &accessKey)
+	oscClient, err := f.oscClients.FromCredentials(ctx, f.encryptedStorage, cfg.StorableCredentials)
 	if err != nil {
-		return nil, errors.Wrap(ctx, err, "decrypt access key")
+		return nil, errors.Wrap(ctx, err, "create Outscale client")
 	}
-	err = f.encryptedStorage.Decrypt(ctx, cfg.SecretKey, &secretKey)
-	if err != nil {
-		return nil, errors.Wrap(ctx, err, "decrypt secret key")
+
+	nicID := cfg.NICID
+	if nicID == "" {
+		deviceIndex := outscale.PrimaryNICDeviceIndex
+		if cfg.NICDeviceIndex != nil {
+			deviceIndex = *cfg.NICDeviceIndex
+		}
+		nicID, err = f.metadata.NICID(ctx, deviceIndex)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "discover the NIC with device index %d", deviceIndex)
+		}
 	}
-	oscClient := outscale.NewClient(accessKey, secretKey, cfg.Region)
 
 	return &Plugin{
 		oscClient:    oscClient,
 		refreshEvery: f.config.RefreshEvery,
 		publicIPID:   cfg.PublicIPID,
-		nicID:        cfg.NICID,
+		nicID:        nicID,
 	}, nil
 }
 
@@ -93,46 +90,25 @@ func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
 		return validations.Build()
 	}
 
-	if req.AccessKey == "" {
-		validations.Set("plugin_config.access_key", "missing access key")
-	}
-	if req.AccessKey != "" && !tokenRegex.MatchString(req.AccessKey) {
-		validations.Set("plugin_config.access_key", "invalid access key format")
-	}
-
-	if req.SecretKey == "" {
-		validations.Set("plugin_config.secret_key", "missing secret key")
-	}
-	if req.SecretKey != "" && !tokenRegex.MatchString(req.SecretKey) {
-		validations.Set("plugin_config.secret_key", "invalid secret key format")
-	}
-
-	if req.Region == "" {
-		validations.Set("plugin_config.region", "missing region")
-	}
-
-	validRegions := outscaleRegions
-	if f.config.GoEnv == "test" {
-		validRegions = append(validRegions, "test-region")
-	}
-
-	if req.Region != "" && !slices.Contains(validRegions, strings.ToLower(req.Region)) {
-		validations.Set("plugin_config.region", "invalid region: "+req.Region)
-	}
+	outscale.ValidateCredentials(validations, req.AccessKey, req.SecretKey, req.Region, f.config.GoEnv)
 
 	if req.PublicIPID == "" {
 		validations.Set("plugin_config.public_ip_id", "missing public IP ID")
 	}
-	if req.PublicIPID != "" && !outscaleIDRegex.MatchString(req.PublicIPID) {
+	if req.PublicIPID != "" && !outscale.IDRegex.MatchString(req.PublicIPID) {
 		validations.Set("plugin_config.public_ip_id", "invalid public IP ID format")
 	}
 
-	if req.NICID == "" {
-		validations.Set("plugin_config.nic_id", "missing NIC ID")
-	}
-	if req.NICID != "" && !outscaleIDRegex.MatchString(req.NICID) {
+	// Without NIC ID, the NIC is discovered with the metadata service
+	if req.NICID != "" && !outscale.IDRegex.MatchString(req.NICID) {
 		validations.Set("plugin_config.nic_id", "invalid NIC ID format")
 	}
+	if req.NICID != "" && req.NICDeviceIndex != nil {
+		validations.Set("plugin_config.nic_device_index", "the NIC device index can't be set with a NIC ID")
+	}
+	if req.NICDeviceIndex != nil && *req.NICDeviceIndex < 0 {
+		validations.Set("plugin_config.nic_device_index", "invalid NIC device index, must be positive")
+	}
 
 	validationErr := validations.Build()
 	if validationErr != nil {
@@ -151,18 +127,13 @@ func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.Raw
 	}
 
 	cfg := StorablePluginConfig{
-		Region:     req.Region,
-		PublicIPID: req.PublicIPID,
-		NICID:      req.NICID,
-	}
-
-	cfg.AccessKey, err = f.encryptedStorage.Encrypt(ctx, endpoint.ID, req.AccessKey)
-	if err != nil {
-		return nil, errors.Wrap(ctx, err, "encrypt access key")
+		PublicIPID:     req.PublicIPID,
+		NICID:          req.NICID,
+		NICDeviceIndex: req.NICDeviceIndex,
 	}
-	cfg.SecretKey, err = f.encryptedStorage.Encrypt(ctx, endpoint.ID, req.SecretKey)
+	cfg.StorableCredentials, err = outscale.EncryptCredentials(ctx, f.encryptedStorage, endpoint.ID, req.AccessKey, req.SecretKey, req.Region)
 	if err != nil {
-		return nil, errors.Wrap(ctx, err, "encrypt secret key")
+		return nil, errors.Wrap(ctx, err, "encrypt credentials")
 	}
 
 	res, _ := json.Marshal(cfg)
@@ -171,10 +142,9 @@ func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.Raw
 }
 
 type StorablePluginConfig struct {
-	AccessKey models.EncryptedDataLink `json:"access_key"`
-	SecretKey models.EncryptedDataLink `json:"secret_key"`
-	Region    string                   `json:"region"`
+	outscale.StorableCredentials
 
-	PublicIPID string `json:"public_ip_id"`
-	NICID      string `json:"nic_id"`
+	PublicIPID     string `json:"public_ip_id"`
+	NICID          string `json:"nic_id,omitempty"`
+	NICDeviceIndex *int   `json:"nic_device_index,omitempty"`
 }
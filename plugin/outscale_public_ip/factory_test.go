@@ -10,6 +10,8 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/services/outscale"
+	"github.com/Scalingo/link/v3/services/outscale/outscalemock"
 )
 
 func TestFactory_Validate(t *testing.T) {
@@ -99,15 +101,26 @@ func TestFactory_Validate(t *testing.T) {
 			},
 			ExpectedError: "invalid public IP ID format",
 		}, {
-			Name: "with a missing NIC ID",
+			Name: "with a NIC ID and a NIC device index",
 			Config: PluginConfig{
-				AccessKey:  "ABC1234",
-				SecretKey:  "ABC1234",
-				Region:     "eu-west-2",
-				PublicIPID: "pip-123",
-				NICID:      "",
+				AccessKey:      "ABC1234",
+				SecretKey:      "ABC1234",
+				Region:         "eu-west-2",
+				PublicIPID:     "pip-123",
+				NICID:          "nic-456",
+				NICDeviceIndex: ptr(1),
+			},
+			ExpectedError: "the NIC device index can't be set with a NIC ID",
+		}, {
+			Name: "with a negative NIC device index",
+			Config: PluginConfig{
+				AccessKey:      "ABC1234",
+				SecretKey:      "ABC1234",
+				Region:         "eu-west-2",
+				PublicIPID:     "pip-123",
+				NICDeviceIndex: ptr(-1),
 			},
-			ExpectedError: "missing NIC ID",
+			ExpectedError: "invalid NIC device index",
 		}, {
 			Name: "with an invalid NIC ID format",
 			Config: PluginConfig{
@@ -128,6 +141,16 @@ func TestFactory_Validate(t *testing.T) {
 				NICID:      "nic-456",
 			},
 			ExpectedError: "",
+		}, {
+			Name: "with a valid configuration without NIC ID",
+			Config: PluginConfig{
+				AccessKey:      "ABC1234",
+				SecretKey:      "ABC1234",
+				Region:         "eu-west-2",
+				PublicIPID:     "pip-123",
+				NICDeviceIndex: ptr(1),
+			},
+			ExpectedError: "",
 		},
 	}
 
@@ -203,3 +226,60 @@ func TestFactory_Mutate_Success(t *testing.T) {
 	assert.Equal(t, req.PublicIPID, stored.PublicIPID)
 	assert.Equal(t, req.NICID, stored.NICID)
 }
+
+func TestFactory_Create(t *testing.T) {
+	specs := []struct {
+		Name                string
+		Config              StorablePluginConfig
+		ExpectedDeviceIndex int
+		ExpectedNICID       string
+	}{
+		{
+			Name:          "with a NIC ID",
+			Config:        StorablePluginConfig{PublicIPID: "pip-123", NICID: "nic-456"},
+			ExpectedNICID: "nic-456",
+		}, {
+			Name:                "without NIC ID, the primary NIC is used",
+			Config:              StorablePluginConfig{PublicIPID: "pip-123"},
+			ExpectedDeviceIndex: outscale.PrimaryNICDeviceIndex,
+			ExpectedNICID:       "nic-primary",
+		}, {
+			Name:                "without NIC ID, with a NIC device index",
+			Config:              StorablePluginConfig{PublicIPID: "pip-123", NICDeviceIndex: ptr(1)},
+			ExpectedDeviceIndex: 1,
+			ExpectedNICID:       "nic-secondary",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+
+			spec.Config.Region = "eu-west-2"
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			mockStorage := models.NewMockEncryptedStorage(ctrl)
+			mockStorage.EXPECT().Decrypt(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockMetadata := outscalemock.NewMockMetadata(ctrl)
+			if spec.Config.NICID == "" {
+				mockMetadata.EXPECT().NICID(ctx, spec.ExpectedDeviceIndex).Return(spec.ExpectedNICID, nil)
+			}
+
+			f := Factory{
+				encryptedStorage: mockStorage,
+				oscClients:       outscale.NewClients(outscale.ClientConfig{}),
+				metadata:         mockMetadata,
+			}
+
+			p, err := f.Create(ctx, models.Endpoint{PluginConfig: rawConfig})
+			require.NoError(t, err)
+			assert.Equal(t, spec.ExpectedNICID, p.(*Plugin).nicID)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -102,6 +102,58 @@ func (p *Plugin) Ensure(ctx context.Context) error {
 	return nil
 }
 
+// IsActivated returns true if the public IP is linked to the NIC
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	publicIP, err := p.oscClient.ReadPublicIP(ctx, p.publicIPID)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "read public IP")
+	}
+
+	if publicIP.GetNicId() != p.nicID {
+		return false, nil
+	}
+
+	p.linkPublicIPID = publicIP.GetLinkPublicIpId()
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+// EnsureDeactivated unlinks the public IP if it is still linked to the NIC of this host.
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	publicIP, err := p.oscClient.ReadPublicIP(ctx, p.publicIPID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read public IP")
+	}
+
+	// The public IP is linked to another NIC (or not linked at all), nothing to do
+	if publicIP.GetNicId() != p.nicID {
+		p.linkPublicIPID = ""
+		p.lastRefreshedAt = time.Now()
+		return nil
+	}
+
+	linkPublicIPID := publicIP.GetLinkPublicIpId()
+	log.WithField("link_public_ip_id", linkPublicIPID).Info("Public IP is still linked to the NIC, unlinking it")
+	_, err = p.oscClient.UnlinkPublicIP(ctx, osc.UnlinkPublicIpRequest{
+		LinkPublicIpId: &linkPublicIPID,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "unlink public IP")
+	}
+
+	p.linkPublicIPID = ""
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
 func (p *Plugin) ElectionKey(_ context.Context) string {
 	return fmt.Sprintf("%s/%s", Name, p.publicIPID)
 }
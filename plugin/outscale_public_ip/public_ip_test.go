@@ -197,3 +197,129 @@ func TestPlugin_Ensure(t *testing.T) {
 		assert.Equal(t, testLinkID, plugin.linkPublicIPID)
 	})
 }
+
+func TestPlugin_EnsureDeactivated(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a public ip linked to another NIC should not be unlinked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		publicIP := osc.PublicIp{}
+		publicIP.SetNicId("other-nic")
+		publicIP.SetLinkPublicIpId("other-link")
+
+		mockClient.EXPECT().
+			ReadPublicIP(gomock.Any(), testPublicIPID).
+			Return(publicIP, nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a public ip still linked to our NIC should be unlinked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		publicIP := osc.PublicIp{}
+		publicIP.SetNicId(testNicID)
+		publicIP.SetLinkPublicIpId(testLinkID)
+
+		mockClient.EXPECT().
+			ReadPublicIP(gomock.Any(), testPublicIPID).
+			Return(publicIP, nil)
+		mockClient.EXPECT().
+			UnlinkPublicIP(gomock.Any(), osc.UnlinkPublicIpRequest{
+				LinkPublicIpId: osc.PtrString(testLinkID),
+			}).
+			Return(osc.UnlinkPublicIpResponse{}, nil)
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, plugin.linkPublicIPID)
+	})
+
+	t.Run("unlink error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		publicIP := osc.PublicIp{}
+		publicIP.SetNicId(testNicID)
+		publicIP.SetLinkPublicIpId(testLinkID)
+
+		mockClient.EXPECT().
+			ReadPublicIP(gomock.Any(), testPublicIPID).
+			Return(publicIP, nil)
+		mockClient.EXPECT().
+			UnlinkPublicIP(gomock.Any(), gomock.Any()).
+			Return(osc.UnlinkPublicIpResponse{}, errors.New("unlink error"))
+
+		err := plugin.EnsureDeactivated(context.Background())
+		require.Error(t, err)
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	t.Run("a public ip linked to our NIC is activated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		publicIP := osc.PublicIp{}
+		publicIP.SetNicId(testNicID)
+		publicIP.SetLinkPublicIpId(testLinkID)
+
+		mockClient.EXPECT().
+			ReadPublicIP(gomock.Any(), testPublicIPID).
+			Return(publicIP, nil)
+
+		activated, err := plugin.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, activated)
+		assert.Equal(t, testLinkID, plugin.linkPublicIPID)
+	})
+
+	t.Run("a public ip linked to another NIC is not activated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		publicIP := osc.PublicIp{}
+		publicIP.SetNicId("other-nic")
+
+		mockClient.EXPECT().
+			ReadPublicIP(gomock.Any(), testPublicIPID).
+			Return(publicIP, nil)
+
+		activated, err := plugin.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.False(t, activated)
+		assert.Empty(t, plugin.linkPublicIPID)
+	})
+
+	t.Run("read public ip error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockPublicIPClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().
+			ReadPublicIP(gomock.Any(), testPublicIPID).
+			Return(osc.PublicIp{}, errors.New("read error"))
+
+		_, err := plugin.IsActivated(context.Background())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,166 @@
+package outscaleroutetable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/outscale"
+)
+
+const Name = api.PluginOutscaleRouteTable
+
+type Config struct {
+	GoEnv        string        `envconfig:"GO_ENV"`
+	RefreshEvery time.Duration `envconfig:"OUTSCALE_ROUTE_TABLE_REFRESH_INTERVAL" default:"1m"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage, oscClients *outscale.Clients) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+		oscClients:       oscClients,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+	oscClients       *outscale.Clients
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+	if len(cfg.Routes) == 0 {
+		return nil, errors.New(ctx, "invalid plugin config: no route")
+	}
+
+	oscClient, err := f.oscClients.FromCredentials(ctx, f.encryptedStorage, cfg.StorableCredentials)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create Outscale client")
+	}
+
+	routes := make([]route, 0, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		routes = append(routes, route{
+			routeTableID:       r.RouteTableID,
+			destinationIPRange: r.DestinationIPRange,
+		})
+	}
+
+	return &Plugin{
+		oscClient:    oscClient,
+		refreshEvery: f.config.RefreshEvery,
+		nicID:        cfg.NICID,
+		routes:       routes,
+	}, nil
+}
+
+type PluginConfig = api.OutscaleRouteTablePluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	outscale.ValidateCredentials(validations, req.AccessKey, req.SecretKey, req.Region, f.config.GoEnv)
+
+	if req.NICID == "" {
+		validations.Set("plugin_config.nic_id", "missing NIC ID")
+	}
+	if req.NICID != "" && !outscale.IDRegex.MatchString(req.NICID) {
+		validations.Set("plugin_config.nic_id", "invalid NIC ID format")
+	}
+
+	if len(req.Routes) == 0 {
+		validations.Set("plugin_config.routes", "at least one route is required")
+	}
+	seen := make(map[api.OutscaleRouteTableRoute]bool, len(req.Routes))
+	for i, r := range req.Routes {
+		field := fmt.Sprintf("plugin_config.routes.%d", i)
+		if r.RouteTableID == "" {
+			validations.Set(field+".route_table_id", "missing route table ID")
+		}
+		if r.RouteTableID != "" && !outscale.IDRegex.MatchString(r.RouteTableID) {
+			validations.Set(field+".route_table_id", "invalid route table ID format")
+		}
+
+		if r.DestinationIPRange == "" {
+			validations.Set(field+".destination_ip_range", "missing destination IP range")
+		}
+		if r.DestinationIPRange != "" {
+			// The Outscale API returns the destination of the routes in their canonical form
+			_, ipRange, err := net.ParseCIDR(r.DestinationIPRange)
+			if err != nil || ipRange.IP.To4() == nil {
+				validations.Set(field+".destination_ip_range", "invalid destination IP range format, must be an IPv4 range using CIDR notation")
+			} else if ipRange.String() != r.DestinationIPRange {
+				validations.Set(field+".destination_ip_range", "invalid destination IP range, should be "+ipRange.String())
+			}
+		}
+
+		if seen[r] {
+			validations.Set(field, "duplicated route")
+		}
+		seen[r] = true
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		NICID:  req.NICID,
+		Routes: req.Routes,
+	}
+	cfg.StorableCredentials, err = outscale.EncryptCredentials(ctx, f.encryptedStorage, endpoint.ID, req.AccessKey, req.SecretKey, req.Region)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt credentials")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	outscale.StorableCredentials
+
+	NICID  string                        `json:"nic_id"`
+	Routes []api.OutscaleRouteTableRoute `json:"routes"`
+}
@@ -0,0 +1,167 @@
+package outscaleroutetable
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	validRoutes := []api.OutscaleRouteTableRoute{
+		{RouteTableID: "rtb-123", DestinationIPRange: "0.0.0.0/0"},
+		{RouteTableID: "rtb-456", DestinationIPRange: "10.1.0.0/16"},
+	}
+
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a valid configuration",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+				Routes:    validRoutes,
+			},
+		}, {
+			Name: "with a missing NIC ID",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				Routes:    validRoutes,
+			},
+			ExpectedError: "missing NIC ID",
+		}, {
+			Name: "without route",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+			},
+			ExpectedError: "at least one route is required",
+		}, {
+			Name: "with an invalid route table ID format",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+				Routes:    []api.OutscaleRouteTableRoute{{RouteTableID: "invalid-route-table", DestinationIPRange: "0.0.0.0/0"}},
+			},
+			ExpectedError: "invalid route table ID format",
+		}, {
+			Name: "with a missing destination",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+				Routes:    []api.OutscaleRouteTableRoute{{RouteTableID: "rtb-123"}},
+			},
+			ExpectedError: "missing destination IP range",
+		}, {
+			Name: "with an IPv6 destination",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+				Routes:    []api.OutscaleRouteTableRoute{{RouteTableID: "rtb-123", DestinationIPRange: "::/0"}},
+			},
+			ExpectedError: "invalid destination IP range format",
+		}, {
+			Name: "with a destination which is not canonical",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+				Routes:    []api.OutscaleRouteTableRoute{{RouteTableID: "rtb-123", DestinationIPRange: "10.1.2.3/16"}},
+			},
+			ExpectedError: "should be 10.1.0.0/16",
+		}, {
+			Name: "with a duplicated route",
+			Config: PluginConfig{
+				AccessKey: "ABC1234",
+				SecretKey: "ABC1234",
+				Region:    "eu-west-2",
+				NICID:     "nic-456",
+				Routes:    []api.OutscaleRouteTableRoute{validRoutes[0], validRoutes[0]},
+			},
+			ExpectedError: "duplicated route",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := PluginConfig{
+		AccessKey: "my-access",
+		SecretKey: "my-secret",
+		Region:    "eu-west-2",
+		NICID:     "nic-456",
+		Routes:    []api.OutscaleRouteTableRoute{{RouteTableID: "rtb-123", DestinationIPRange: "0.0.0.0/0"}},
+	}
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-access").Return(models.EncryptedDataLink{
+		ID:         "access-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-secret").Return(models.EncryptedDataLink{
+		ID:         "secret-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the sensitive data and keep the rest
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "access-id", stored.AccessKey.ID)
+	assert.Equal(t, "secret-id", stored.SecretKey.ID)
+	assert.Equal(t, req.Region, stored.Region)
+	assert.Equal(t, req.NICID, stored.NICID)
+	assert.Equal(t, req.Routes, stored.Routes)
+	assert.NotContains(t, string(res), "my-secret")
+}
@@ -0,0 +1,165 @@
+package outscaleroutetable
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/services/outscale"
+)
+
+type Plugin struct {
+	oscClient outscale.RouteTableClient
+
+	refreshEvery time.Duration
+
+	// Route Configuration
+	nicID  string // ID of the NIC set as the target of the routes
+	routes []route
+
+	// Internal configuration
+	lastRefreshedAt time.Time
+}
+
+type route struct {
+	routeTableID       string
+	destinationIPRange string
+}
+
+func (r route) String() string {
+	return r.routeTableID + ":" + r.destinationIPRange
+}
+
+// Activate sets the NIC as the target of all the routes
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	log.Info("Setting the NIC as target of the routes")
+	err := p.updateRoutes(ctx, p.routes)
+	if err != nil {
+		return errors.Wrap(ctx, err, "update routes")
+	}
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// Deactivate does not modify the routes: a route always has a target, it is replaced by the NIC of
+// the next host activating the endpoint
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	_, log := logger.WithStructToCtx(ctx, "plugin", p)
+	log.Info("Routes are kept until another host takes them over")
+	return nil
+}
+
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, log := logger.WithStructToCtx(ctx, "plugin", p)
+
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("Already refreshed recently, skipping")
+		return nil
+	}
+
+	routes, err := p.routesNotTargetingNIC(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read route tables")
+	}
+
+	// The routes targeting another NIC or another resource are set back to our NIC
+	if len(routes) > 0 {
+		log.WithField("routes", routeStrings(routes)).Info("Routes are not targeting the NIC, updating them")
+		err = p.updateRoutes(ctx, routes)
+		if err != nil {
+			return errors.Wrap(ctx, err, "update routes")
+		}
+	}
+
+	p.lastRefreshedAt = time.Now()
+
+	return nil
+}
+
+// IsActivated returns true if all the routes target the NIC
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	routes, err := p.routesNotTargetingNIC(ctx)
+	if err != nil {
+		return false, errors.Wrap(ctx, err, "read route tables")
+	}
+	if len(routes) > 0 {
+		return false, nil
+	}
+
+	p.lastRefreshedAt = time.Now()
+	return true, nil
+}
+
+func (p *Plugin) updateRoutes(ctx context.Context, routes []route) error {
+	for _, r := range routes {
+		_, err := p.oscClient.UpdateRoute(ctx, osc.UpdateRouteRequest{
+			RouteTableId:       r.routeTableID,
+			DestinationIpRange: r.destinationIPRange,
+			NicId:              osc.PtrString(p.nicID),
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "update route %s", r)
+		}
+	}
+	return nil
+}
+
+// routesNotTargetingNIC returns the routes which do not target the NIC. Each route table is only
+// read once.
+func (p *Plugin) routesNotTargetingNIC(ctx context.Context) ([]route, error) {
+	routeTables := make(map[string]osc.RouteTable)
+	var res []route
+	for _, r := range p.routes {
+		routeTable, ok := routeTables[r.routeTableID]
+		if !ok {
+			var err error
+			routeTable, err = p.oscClient.ReadRouteTable(ctx, r.routeTableID)
+			if err != nil {
+				return nil, errors.Wrapf(ctx, err, "read route table %s", r.routeTableID)
+			}
+			routeTables[r.routeTableID] = routeTable
+		}
+
+		i := slices.IndexFunc(routeTable.GetRoutes(), func(oscRoute osc.Route) bool {
+			return oscRoute.GetDestinationIpRange() == r.destinationIPRange
+		})
+		if i == -1 {
+			return nil, errors.Newf(ctx, "route to %s not found in route table %s", r.destinationIPRange, r.routeTableID)
+		}
+		if routeTable.GetRoutes()[i].GetNicId() != p.nicID {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func routeStrings(routes []route) []string {
+	res := make([]string, 0, len(routes))
+	for _, r := range routes {
+		res = append(res, r.String())
+	}
+	slices.Sort(res)
+	return res
+}
+
+// ElectionKey is based on the routes, the endpoints sharing a route are part of the same election
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return Name + "/" + strings.ReplaceAll(strings.Join(routeStrings(p.routes), ","), "/", "_")
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":   "outscale_route_table",
+		"nic_id": p.nicID,
+		"routes": routeStrings(p.routes),
+	}
+}
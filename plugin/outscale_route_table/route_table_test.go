@@ -0,0 +1,167 @@
+package outscaleroutetable
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/outscale/outscalemock"
+)
+
+const (
+	testNicID      = "nic-456"
+	testOtherNicID = "nic-789"
+)
+
+func newPlugin(mockClient *outscalemock.MockRouteTableClient) *Plugin {
+	return &Plugin{
+		oscClient:    mockClient,
+		refreshEvery: time.Minute,
+		nicID:        testNicID,
+		routes: []route{
+			{routeTableID: "rtb-123", destinationIPRange: "0.0.0.0/0"},
+			{routeTableID: "rtb-123", destinationIPRange: "10.1.0.0/16"},
+			{routeTableID: "rtb-456", destinationIPRange: "0.0.0.0/0"},
+		},
+	}
+}
+
+// routeTable returns a route table with routes to the given destinations targeting the given NIC
+func routeTable(nicID string, destinations ...string) osc.RouteTable {
+	routes := []osc.Route{{DestinationIpRange: osc.PtrString("10.0.0.0/16"), GatewayId: osc.PtrString("local")}}
+	for _, destination := range destinations {
+		routes = append(routes, osc.Route{DestinationIpRange: osc.PtrString(destination), NicId: osc.PtrString(nicID)})
+	}
+	routeTable := osc.RouteTable{}
+	routeTable.SetRoutes(routes)
+	return routeTable
+}
+
+func updateRouteRequest(routeTableID, destination string) osc.UpdateRouteRequest {
+	return osc.UpdateRouteRequest{
+		RouteTableId:       routeTableID,
+		DestinationIpRange: destination,
+		NicId:              osc.PtrString(testNicID),
+	}
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it sets the NIC as the target of all the routes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		gomock.InOrder(
+			mockClient.EXPECT().UpdateRoute(gomock.Any(), updateRouteRequest("rtb-123", "0.0.0.0/0")).Return(osc.UpdateRouteResponse{}, nil),
+			mockClient.EXPECT().UpdateRoute(gomock.Any(), updateRouteRequest("rtb-123", "10.1.0.0/16")).Return(osc.UpdateRouteResponse{}, nil),
+			mockClient.EXPECT().UpdateRoute(gomock.Any(), updateRouteRequest("rtb-456", "0.0.0.0/0")).Return(osc.UpdateRouteResponse{}, nil),
+		)
+
+		err := plugin.Activate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, plugin.lastRefreshedAt.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().UpdateRoute(gomock.Any(), gomock.Any()).Return(osc.UpdateRouteResponse{}, errors.New("update error"))
+
+		err := plugin.Activate(context.Background())
+		require.ErrorContains(t, err, "update error")
+		assert.True(t, plugin.lastRefreshedAt.IsZero())
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+	plugin := newPlugin(mockClient)
+
+	// The routes are not modified
+	err := plugin.Deactivate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("already refreshed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+		plugin := newPlugin(mockClient)
+		plugin.lastRefreshedAt = time.Now()
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("only the routes targeting another NIC are updated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		// Each route table is read once
+		mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-123").Return(routeTable(testNicID, "0.0.0.0/0", "10.1.0.0/16"), nil)
+		mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-456").Return(routeTable(testOtherNicID, "0.0.0.0/0"), nil)
+		mockClient.EXPECT().UpdateRoute(gomock.Any(), updateRouteRequest("rtb-456", "0.0.0.0/0")).Return(osc.UpdateRouteResponse{}, nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("all the routes target the NIC", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-123").Return(routeTable(testNicID, "0.0.0.0/0", "10.1.0.0/16"), nil)
+		mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-456").Return(routeTable(testNicID, "0.0.0.0/0"), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, plugin.lastRefreshedAt, time.Now().Add(-1*time.Minute), "lastRefreshedAt should be updated")
+	})
+
+	t.Run("a route missing from the route table", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+		plugin := newPlugin(mockClient)
+
+		mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-123").Return(routeTable(testNicID, "0.0.0.0/0"), nil)
+
+		err := plugin.Ensure(context.Background())
+		require.ErrorContains(t, err, "route to 10.1.0.0/16 not found in route table rtb-123")
+		assert.True(t, plugin.lastRefreshedAt.IsZero(), "lastRefreshedAt should not be updated")
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := outscalemock.NewMockRouteTableClient(ctrl)
+	plugin := newPlugin(mockClient)
+
+	mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-123").Return(routeTable(testNicID, "0.0.0.0/0", "10.1.0.0/16"), nil)
+	mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-456").Return(routeTable(testNicID, "0.0.0.0/0"), nil)
+	activated, err := plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.True(t, activated)
+
+	mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-123").Return(routeTable(testOtherNicID, "0.0.0.0/0", "10.1.0.0/16"), nil)
+	mockClient.EXPECT().ReadRouteTable(gomock.Any(), "rtb-456").Return(routeTable(testNicID, "0.0.0.0/0"), nil)
+	activated, err = plugin.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	plugin := newPlugin(nil)
+	assert.Equal(t, "outscale_route_table/rtb-123:0.0.0.0_0,rtb-123:10.1.0.0_16,rtb-456:0.0.0.0_0", plugin.ElectionKey(context.Background()))
+}
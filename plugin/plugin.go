@@ -2,6 +2,8 @@ package plugin
 
 import (
 	"context"
+
+	"github.com/Scalingo/link/v3/api"
 )
 
 // Plugin is an interface used by endpoint plugins to manage the actions needed to activate and deactivate an endpoint.
@@ -23,3 +25,23 @@ type Plugin interface {
 	// Note: There's no prefix per plugin, if they same key is used by multiple plugins, they will all be part of the same election.
 	ElectionKey(ctx context.Context) string
 }
+
+// DeactivationEnsurer is an optional interface plugins can implement if they are able to check that the endpoint is not active on the current host.
+type DeactivationEnsurer interface {
+	// EnsureDeactivated is called at regular interval when the endpoint is in the STANDBY or FAILING state.
+	// It must repair any leftover of a previous activation (e.g. after a failed Deactivate call).
+	EnsureDeactivated(ctx context.Context) error
+}
+
+// ActivationChecker is an optional interface plugins can implement if they are able to check the actual state of the endpoint.
+type ActivationChecker interface {
+	// IsActivated returns true if the endpoint is currently active on the current host.
+	// It is called when LinK restarts and resumes an endpoint it still owns, to avoid calling Activate again.
+	IsActivated(ctx context.Context) (bool, error)
+}
+
+// ConditionReporter is an optional interface plugins can implement to report abnormal conditions of the endpoint.
+type ConditionReporter interface {
+	// Conditions returns the current conditions of the endpoint. It can be called concurrently with the other methods of the plugin.
+	Conditions(ctx context.Context) []api.EndpointCondition
+}
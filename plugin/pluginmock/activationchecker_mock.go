@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/plugin (interfaces: ActivationChecker)
+
+// Package pluginmock is a generated GoMock package.
+package pluginmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockActivationChecker is a mock of ActivationChecker interface.
+type MockActivationChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockActivationCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockActivationCheckerMockRecorder is the mock recorder for MockActivationChecker.
+type MockActivationCheckerMockRecorder struct {
+	mock *MockActivationChecker
+}
+
+// NewMockActivationChecker creates a new mock instance.
+func NewMockActivationChecker(ctrl *gomock.Controller) *MockActivationChecker {
+	mock := &MockActivationChecker{ctrl: ctrl}
+	mock.recorder = &MockActivationCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActivationChecker) EXPECT() *MockActivationCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsActivated mocks base method.
+func (m *MockActivationChecker) IsActivated(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsActivated", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsActivated indicates an expected call of IsActivated.
+func (mr *MockActivationCheckerMockRecorder) IsActivated(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsActivated", reflect.TypeOf((*MockActivationChecker)(nil).IsActivated), ctx)
+}
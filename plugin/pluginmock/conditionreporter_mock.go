@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/plugin (interfaces: ConditionReporter)
+
+// Package pluginmock is a generated GoMock package.
+package pluginmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	api "github.com/Scalingo/link/v3/api"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConditionReporter is a mock of ConditionReporter interface.
+type MockConditionReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockConditionReporterMockRecorder
+	isgomock struct{}
+}
+
+// MockConditionReporterMockRecorder is the mock recorder for MockConditionReporter.
+type MockConditionReporterMockRecorder struct {
+	mock *MockConditionReporter
+}
+
+// NewMockConditionReporter creates a new mock instance.
+func NewMockConditionReporter(ctrl *gomock.Controller) *MockConditionReporter {
+	mock := &MockConditionReporter{ctrl: ctrl}
+	mock.recorder = &MockConditionReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConditionReporter) EXPECT() *MockConditionReporterMockRecorder {
+	return m.recorder
+}
+
+// Conditions mocks base method.
+func (m *MockConditionReporter) Conditions(ctx context.Context) []api.EndpointCondition {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Conditions", ctx)
+	ret0, _ := ret[0].([]api.EndpointCondition)
+	return ret0
+}
+
+// Conditions indicates an expected call of Conditions.
+func (mr *MockConditionReporterMockRecorder) Conditions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conditions", reflect.TypeOf((*MockConditionReporter)(nil).Conditions), ctx)
+}
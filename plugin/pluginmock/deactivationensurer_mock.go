@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/plugin (interfaces: DeactivationEnsurer)
+
+// Package pluginmock is a generated GoMock package.
+package pluginmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeactivationEnsurer is a mock of DeactivationEnsurer interface.
+type MockDeactivationEnsurer struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeactivationEnsurerMockRecorder
+	isgomock struct{}
+}
+
+// MockDeactivationEnsurerMockRecorder is the mock recorder for MockDeactivationEnsurer.
+type MockDeactivationEnsurerMockRecorder struct {
+	mock *MockDeactivationEnsurer
+}
+
+// NewMockDeactivationEnsurer creates a new mock instance.
+func NewMockDeactivationEnsurer(ctrl *gomock.Controller) *MockDeactivationEnsurer {
+	mock := &MockDeactivationEnsurer{ctrl: ctrl}
+	mock.recorder = &MockDeactivationEnsurerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeactivationEnsurer) EXPECT() *MockDeactivationEnsurerMockRecorder {
+	return m.recorder
+}
+
+// EnsureDeactivated mocks base method.
+func (m *MockDeactivationEnsurer) EnsureDeactivated(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureDeactivated", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureDeactivated indicates an expected call of EnsureDeactivated.
+func (mr *MockDeactivationEnsurerMockRecorder) EnsureDeactivated(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureDeactivated", reflect.TypeOf((*MockDeactivationEnsurer)(nil).EnsureDeactivated), ctx)
+}
@@ -68,6 +68,20 @@ func (mr *MockRegistryMockRecorder) Mutate(ctx, endpoint any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mutate", reflect.TypeOf((*MockRegistry)(nil).Mutate), ctx, endpoint)
 }
 
+// ReconcileAtStartup mocks base method.
+func (m *MockRegistry) ReconcileAtStartup(ctx context.Context, endpoints []models.Endpoint, isOwned plugin.OwnershipChecker) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileAtStartup", ctx, endpoints, isOwned)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileAtStartup indicates an expected call of ReconcileAtStartup.
+func (mr *MockRegistryMockRecorder) ReconcileAtStartup(ctx, endpoints, isOwned any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileAtStartup", reflect.TypeOf((*MockRegistry)(nil).ReconcileAtStartup), ctx, endpoints, isOwned)
+}
+
 // Register mocks base method.
 func (m *MockRegistry) Register(ctx context.Context, pluginName string, factory plugin.Factory) {
 	m.ctrl.T.Helper()
@@ -30,11 +30,23 @@ type MutableFactory interface {
 	Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error)
 }
 
+// OwnershipChecker returns true if the endpoint is currently owned by the current host
+type OwnershipChecker func(ctx context.Context, endpointID string) bool
+
+// StartupReconciler is an interface that factories can implement to clean up what a previous run
+// of LinK left on the host (e.g. after a crash, Deactivate was never called).
+type StartupReconciler interface {
+	// ReconcileAtStartup is called before the endpoints configured on the host are started. It
+	// receives the endpoints of the plugin and must keep the resources of the ones owned by the host.
+	ReconcileAtStartup(ctx context.Context, endpoints []models.Endpoint, isOwned OwnershipChecker) error
+}
+
 type Registry interface {
 	Register(ctx context.Context, pluginName string, factory Factory)
 	Create(ctx context.Context, endpoint models.Endpoint) (Plugin, error)
 	Validate(ctx context.Context, endpoint models.Endpoint) error
 	Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error)
+	ReconcileAtStartup(ctx context.Context, endpoints []models.Endpoint, isOwned OwnershipChecker) error
 }
 
 type registry struct {
@@ -97,3 +109,30 @@ func (r *registry) Mutate(ctx context.Context, endpoint models.Endpoint) (json.R
 	}
 	return res, nil
 }
+
+// ReconcileAtStartup calls the startup reconciler of every plugin implementing it, with the
+// endpoints of this plugin. A failure of a plugin does not prevent the others from running.
+func (r *registry) ReconcileAtStartup(ctx context.Context, endpoints []models.Endpoint, isOwned OwnershipChecker) error {
+	endpointsByPlugin := make(map[string][]models.Endpoint)
+	for _, endpoint := range endpoints {
+		pluginName := endpoint.Plugin
+		if pluginName == "" {
+			pluginName = "arp"
+		}
+		endpointsByPlugin[pluginName] = append(endpointsByPlugin[pluginName], endpoint)
+	}
+
+	var firstErr error
+	for pluginName, factory := range r.plugins {
+		reconciler, ok := factory.(StartupReconciler)
+		if !ok {
+			continue
+		}
+
+		err := reconciler.ReconcileAtStartup(ctx, endpointsByPlugin[pluginName], isOwned)
+		if err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(ctx, err, "reconcile plugin %s", pluginName)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,218 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+const Name = api.PluginRoute
+
+// defaultProtocol is the protocol of the routes if none is configured
+const defaultProtocol = unix.RTPROT_STATIC
+
+type PluginConfig = api.RoutePluginConfig
+
+type Factory struct {
+	newRouteManager func(netns string) (network.RouteManager, error)
+	interfaceExists func(name, netns string) error
+}
+
+func Register(ctx context.Context, registry plugin.Registry) error {
+	registry.Register(ctx, Name, Factory{
+		newRouteManager: network.NewRouteManager,
+		interfaceExists: network.InterfaceExists,
+	})
+	return nil
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+	if len(cfg.Routes) == 0 {
+		return nil, errors.New(ctx, "invalid plugin config: no route")
+	}
+
+	routes := make([]network.Route, 0, len(cfg.Routes))
+	for _, routeConfig := range cfg.Routes {
+		route, err := parseRoute(ctx, routeConfig)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid route to %s", routeConfig.Destination)
+		}
+		routes = append(routes, route)
+	}
+
+	rules := make([]network.Rule, 0, len(cfg.Rules))
+	for _, ruleConfig := range cfg.Rules {
+		rule, err := parseRule(ctx, ruleConfig)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid rule with priority %d", ruleConfig.Priority)
+		}
+		rules = append(rules, rule)
+	}
+
+	routeManager, err := f.newRouteManager(cfg.Netns)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get route manager")
+	}
+
+	return &Plugin{
+		endpoint:     endpoint,
+		routes:       routes,
+		rules:        rules,
+		netns:        network.NamespacePath(cfg.Netns),
+		routeManager: routeManager,
+	}, nil
+}
+
+func (f Factory) Validate(ctx context.Context, endpoint models.Endpoint) error {
+	validation := errors.NewValidationErrorsBuilder()
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		validation.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validation.Build()
+	}
+
+	if len(cfg.Routes) == 0 {
+		validation.Set("plugin_config.routes", "at least one route is required")
+	}
+
+	destinations := make(map[string]bool, len(cfg.Routes))
+	for i, routeConfig := range cfg.Routes {
+		field := fmt.Sprintf("plugin_config.routes.%d", i)
+		route, err := parseRoute(ctx, routeConfig)
+		if err != nil {
+			validation.Set(field, err.Error())
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%s/%d", route.Table, route.Destination, route.Metric)
+		if destinations[key] {
+			validation.Set(field, "duplicated route to "+route.Destination.String())
+		}
+		destinations[key] = true
+
+		if route.Device != "" {
+			err = f.interfaceExists(route.Device, cfg.Netns)
+			if err != nil {
+				validation.Set(field+".device", "device "+route.Device+" not found: "+err.Error())
+			}
+		}
+	}
+
+	priorities := make(map[int]bool, len(cfg.Rules))
+	for i, ruleConfig := range cfg.Rules {
+		field := fmt.Sprintf("plugin_config.rules.%d", i)
+		_, err := parseRule(ctx, ruleConfig)
+		if err != nil {
+			validation.Set(field, err.Error())
+			continue
+		}
+
+		if priorities[ruleConfig.Priority] {
+			validation.Set(field+".priority", fmt.Sprintf("duplicated priority %d", ruleConfig.Priority))
+		}
+		priorities[ruleConfig.Priority] = true
+	}
+
+	validationErr := validation.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+	return nil
+}
+
+// parseRoute checks the route configuration and returns the route to install
+func parseRoute(ctx context.Context, cfg api.RouteConfig) (network.Route, error) {
+	_, destination, err := net.ParseCIDR(cfg.Destination)
+	if err != nil {
+		return network.Route{}, errors.Wrap(ctx, err, "invalid destination")
+	}
+
+	route := network.Route{
+		Destination: destination,
+		Device:      cfg.Device,
+		Metric:      cfg.Metric,
+		Table:       cfg.Table,
+		Protocol:    cfg.Protocol,
+	}
+	if route.Table == 0 {
+		route.Table = unix.RT_TABLE_MAIN
+	}
+	if route.Protocol == 0 {
+		route.Protocol = defaultProtocol
+	}
+
+	if cfg.Gateway == "" && cfg.Device == "" {
+		return network.Route{}, errors.New(ctx, "gateway or device is required")
+	}
+	if cfg.Gateway != "" {
+		route.Gateway = net.ParseIP(cfg.Gateway)
+		if route.Gateway == nil {
+			return network.Route{}, errors.Newf(ctx, "invalid gateway %s", cfg.Gateway)
+		}
+		if (route.Gateway.To4() == nil) != (destination.IP.To4() == nil) {
+			return network.Route{}, errors.New(ctx, "the gateway and the destination must be of the same IP family")
+		}
+	}
+	if route.Metric < 0 {
+		return network.Route{}, errors.New(ctx, "metric must be positive")
+	}
+	if route.Table < 0 {
+		return network.Route{}, errors.New(ctx, "table must be positive")
+	}
+	if route.Protocol < 0 || route.Protocol > 255 {
+		return network.Route{}, errors.New(ctx, "protocol must be between 0 and 255")
+	}
+	return route, nil
+}
+
+// parseRule checks the rule configuration and returns the rule to install
+func parseRule(ctx context.Context, cfg api.RuleConfig) (network.Rule, error) {
+	rule := network.Rule{
+		Priority: cfg.Priority,
+		IIF:      cfg.IIF,
+		FwMark:   cfg.FwMark,
+		Table:    cfg.Table,
+		IPv6:     cfg.IPv6,
+	}
+
+	// The priority 0 is reserved for the local table rule
+	if rule.Priority <= 0 {
+		return network.Rule{}, errors.New(ctx, "priority must be greater than 0")
+	}
+	if rule.Table <= 0 {
+		return network.Rule{}, errors.New(ctx, "table is required")
+	}
+
+	var err error
+	if cfg.From != "" {
+		_, rule.From, err = net.ParseCIDR(cfg.From)
+		if err != nil {
+			return network.Rule{}, errors.Wrap(ctx, err, "invalid from")
+		}
+	}
+	if cfg.To != "" {
+		_, rule.To, err = net.ParseCIDR(cfg.To)
+		if err != nil {
+			return network.Rule{}, errors.Wrap(ctx, err, "invalid to")
+		}
+	}
+	if rule.From != nil && rule.To != nil && (rule.From.IP.To4() == nil) != (rule.To.IP.To4() == nil) {
+		return network.Rule{}, errors.New(ctx, "from and to must be of the same IP family")
+	}
+	return rule, nil
+}
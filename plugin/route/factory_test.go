@@ -0,0 +1,140 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a route via a gateway",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0/24", Gateway: "10.0.0.1"},
+			}},
+		}, {
+			Name: "with routes and rules",
+			Config: PluginConfig{
+				Routes: []api.RouteConfig{
+					{Destination: "0.0.0.0/0", Gateway: "10.0.0.1", Table: 100},
+					{Destination: "2001:db8::/64", Device: "eth1", Metric: 10, Protocol: 4},
+				},
+				Rules: []api.RuleConfig{
+					{Priority: 1000, From: "203.0.113.0/24", Table: 100},
+					{Priority: 1001, FwMark: 42, Table: 100},
+				},
+			},
+		}, {
+			Name:          "without route",
+			Config:        PluginConfig{},
+			ExpectedError: "at least one route is required",
+		}, {
+			Name: "with an invalid destination",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0", Gateway: "10.0.0.1"},
+			}},
+			ExpectedError: "invalid destination",
+		}, {
+			Name: "without gateway nor device",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0/24"},
+			}},
+			ExpectedError: "gateway or device is required",
+		}, {
+			Name: "with a gateway of another family",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0/24", Gateway: "2001:db8::1"},
+			}},
+			ExpectedError: "same IP family",
+		}, {
+			Name: "with an unknown device",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0/24", Device: "unknown"},
+			}},
+			ExpectedError: "device unknown not found",
+		}, {
+			Name: "with a duplicated route",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0/24", Gateway: "10.0.0.1"},
+				{Destination: "203.0.113.0/24", Gateway: "10.0.0.2", Table: 254},
+			}},
+			ExpectedError: "duplicated route",
+		}, {
+			Name: "with an invalid protocol",
+			Config: PluginConfig{Routes: []api.RouteConfig{
+				{Destination: "203.0.113.0/24", Gateway: "10.0.0.1", Protocol: 256},
+			}},
+			ExpectedError: "protocol must be between 0 and 255",
+		}, {
+			Name: "with a rule without priority",
+			Config: PluginConfig{
+				Routes: []api.RouteConfig{{Destination: "203.0.113.0/24", Gateway: "10.0.0.1"}},
+				Rules:  []api.RuleConfig{{Table: 100}},
+			},
+			ExpectedError: "priority must be greater than 0",
+		}, {
+			Name: "with a rule without table",
+			Config: PluginConfig{
+				Routes: []api.RouteConfig{{Destination: "203.0.113.0/24", Gateway: "10.0.0.1"}},
+				Rules:  []api.RuleConfig{{Priority: 1000}},
+			},
+			ExpectedError: "table is required",
+		}, {
+			Name: "with rules with the same priority",
+			Config: PluginConfig{
+				Routes: []api.RouteConfig{{Destination: "203.0.113.0/24", Gateway: "10.0.0.1"}},
+				Rules: []api.RuleConfig{
+					{Priority: 1000, From: "203.0.113.0/24", Table: 100},
+					{Priority: 1000, To: "198.51.100.0/24", Table: 100},
+				},
+			},
+			ExpectedError: "duplicated priority 1000",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			factory := Factory{
+				interfaceExists: func(name, _ string) error {
+					if name == "unknown" {
+						return errors.New("Link not found")
+					}
+					return nil
+				},
+			}
+
+			pluginConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = factory.Validate(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			if spec.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.ExpectedError)
+		})
+	}
+}
+
+func TestParseRoute(t *testing.T) {
+	route, err := parseRoute(context.Background(), api.RouteConfig{Destination: "203.0.113.10/24", Gateway: "10.0.0.1"})
+	require.NoError(t, err)
+
+	// The destination is normalized and the defaults are applied
+	assert.Equal(t, "203.0.113.0/24", route.Destination.String())
+	assert.Equal(t, 254, route.Table)
+	assert.Equal(t, defaultProtocol, route.Protocol)
+}
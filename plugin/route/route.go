@@ -0,0 +1,181 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+type Plugin struct {
+	endpoint     models.Endpoint
+	routes       []network.Route
+	rules        []network.Rule
+	routeManager network.RouteManager
+	// netns is the path of the network namespace of the routes, empty for the namespace of LinK
+	netns string
+}
+
+// Activate installs the routes, then the rules. The activation is all or nothing: if a route or a
+// rule cannot be installed, the ones installed before are removed.
+func (p *Plugin) Activate(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	for i, route := range p.routes {
+		err := p.routeManager.EnsureRoute(route)
+		if err == nil {
+			continue
+		}
+
+		p.rollback(ctx, p.routes[:i], nil)
+		return errors.Wrapf(ctx, err, "install route to %s", route.Destination)
+	}
+
+	for i, rule := range p.rules {
+		err := p.routeManager.EnsureRule(rule)
+		if err == nil {
+			continue
+		}
+
+		p.rollback(ctx, p.routes, p.rules[:i])
+		return errors.Wrapf(ctx, err, "install rule with priority %d", rule.Priority)
+	}
+
+	log.Info("Routes installed")
+	return nil
+}
+
+// rollback removes the routes and rules installed by a failed activation
+func (p *Plugin) rollback(ctx context.Context, routes []network.Route, rules []network.Rule) {
+	log := logger.Get(ctx)
+	for _, rule := range rules {
+		err := p.routeManager.RemoveRule(rule)
+		if err != nil {
+			log.WithError(err).WithField("rule_priority", rule.Priority).Error("Fail to remove rule during the activation rollback")
+		}
+	}
+	for _, route := range routes {
+		err := p.routeManager.RemoveRoute(route)
+		if err != nil {
+			log.WithError(err).WithField("route_destination", route.Destination).Error("Fail to remove route during the activation rollback")
+		}
+	}
+}
+
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	err := p.remove(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove routes")
+	}
+	return nil
+}
+
+// Ensure checks that all the routes and rules are installed, and installs the missing ones
+func (p *Plugin) Ensure(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	for _, route := range p.routes {
+		has, err := p.routeManager.HasRoute(route)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "check route to %s", route.Destination)
+		}
+		if has {
+			continue
+		}
+
+		log.WithField("route_destination", route.Destination).Warn("Route missing, installing it again")
+		err = p.routeManager.EnsureRoute(route)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "install route to %s", route.Destination)
+		}
+	}
+
+	for _, rule := range p.rules {
+		has, err := p.routeManager.HasRule(rule)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "check rule with priority %d", rule.Priority)
+		}
+		if has {
+			continue
+		}
+
+		log.WithField("rule_priority", rule.Priority).Warn("Rule missing, installing it again")
+		err = p.routeManager.EnsureRule(rule)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "install rule with priority %d", rule.Priority)
+		}
+	}
+	return nil
+}
+
+// EnsureDeactivated removes the routes and rules if they are still installed
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	err := p.remove(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "remove routes")
+	}
+	return nil
+}
+
+// IsActivated returns true if all the routes and rules are installed
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	for _, route := range p.routes {
+		has, err := p.routeManager.HasRoute(route)
+		if err != nil {
+			return false, errors.Wrapf(ctx, err, "check route to %s", route.Destination)
+		}
+		if !has {
+			return false, nil
+		}
+	}
+	for _, rule := range p.rules {
+		has, err := p.routeManager.HasRule(rule)
+		if err != nil {
+			return false, errors.Wrapf(ctx, err, "check rule with priority %d", rule.Priority)
+		}
+		if !has {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// remove removes the rules, then the routes. It tries to remove all of them, even if the removal of
+// one of them fails.
+func (p *Plugin) remove(ctx context.Context) error {
+	var firstErr error
+	for _, rule := range p.rules {
+		err := p.routeManager.RemoveRule(rule)
+		if err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(ctx, err, "remove rule with priority %d", rule.Priority)
+		}
+	}
+	for _, route := range p.routes {
+		err := p.routeManager.RemoveRoute(route)
+		if err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(ctx, err, "remove route to %s", route.Destination)
+		}
+	}
+	return firstErr
+}
+
+// ElectionKey is based on the destinations and tables of the routes, and the network namespace
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	keys := make([]string, 0, len(p.routes))
+	for _, route := range p.routes {
+		keys = append(keys, strings.ReplaceAll(fmt.Sprintf("%d_%s", route.Table, route.Destination), "/", "_"))
+	}
+	slices.Sort(keys)
+
+	key := strings.Join(keys, ",")
+	if p.netns != "" {
+		netns := strings.TrimPrefix(p.netns, network.NetnsDir+"/")
+		key = strings.ReplaceAll(netns, "/", "_") + ":" + key
+	}
+	return "route:" + key
+}
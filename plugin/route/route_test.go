@@ -0,0 +1,142 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func newTestPlugin(routeManager network.RouteManager) (*Plugin, network.Route, network.Route, network.Rule) {
+	_, customer, _ := net.ParseCIDR("203.0.113.0/24")
+	_, defaultRoute, _ := net.ParseCIDR("0.0.0.0/0")
+	route1 := network.Route{Destination: customer, Gateway: net.ParseIP("10.0.0.1"), Table: 254, Protocol: 4}
+	route2 := network.Route{Destination: defaultRoute, Gateway: net.ParseIP("10.0.0.1"), Table: 100, Protocol: 4}
+	rule := network.Rule{Priority: 1000, From: customer, Table: 100}
+
+	return &Plugin{
+		routes:       []network.Route{route1, route2},
+		rules:        []network.Rule{rule},
+		routeManager: routeManager,
+	}, route1, route2, rule
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it installs the routes, then the rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		routeManager := networkmock.NewMockRouteManager(ctrl)
+		p, route1, route2, rule := newTestPlugin(routeManager)
+
+		gomock.InOrder(
+			routeManager.EXPECT().EnsureRoute(route1).Return(nil),
+			routeManager.EXPECT().EnsureRoute(route2).Return(nil),
+			routeManager.EXPECT().EnsureRule(rule).Return(nil),
+		)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("if a route cannot be installed, the installed routes are removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		routeManager := networkmock.NewMockRouteManager(ctrl)
+		p, route1, route2, _ := newTestPlugin(routeManager)
+
+		routeManager.EXPECT().EnsureRoute(route1).Return(nil)
+		routeManager.EXPECT().EnsureRoute(route2).Return(errors.New("network is unreachable"))
+		routeManager.EXPECT().RemoveRoute(route1).Return(nil)
+
+		err := p.Activate(context.Background())
+		require.ErrorContains(t, err, "network is unreachable")
+	})
+
+	t.Run("if a rule cannot be installed, all the routes are removed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		routeManager := networkmock.NewMockRouteManager(ctrl)
+		p, route1, route2, rule := newTestPlugin(routeManager)
+
+		routeManager.EXPECT().EnsureRoute(route1).Return(nil)
+		routeManager.EXPECT().EnsureRoute(route2).Return(nil)
+		routeManager.EXPECT().EnsureRule(rule).Return(errors.New("file exists"))
+		routeManager.EXPECT().RemoveRoute(route1).Return(nil)
+		routeManager.EXPECT().RemoveRoute(route2).Return(nil)
+
+		err := p.Activate(context.Background())
+		require.ErrorContains(t, err, "file exists")
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	t.Run("it removes the rules, then the routes, even if a removal fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		routeManager := networkmock.NewMockRouteManager(ctrl)
+		p, route1, route2, rule := newTestPlugin(routeManager)
+
+		gomock.InOrder(
+			routeManager.EXPECT().RemoveRule(rule).Return(errors.New("operation not permitted")),
+			routeManager.EXPECT().RemoveRoute(route1).Return(nil),
+			routeManager.EXPECT().RemoveRoute(route2).Return(nil),
+		)
+
+		err := p.Deactivate(context.Background())
+		require.ErrorContains(t, err, "operation not permitted")
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("it does nothing if everything is installed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		routeManager := networkmock.NewMockRouteManager(ctrl)
+		p, route1, route2, rule := newTestPlugin(routeManager)
+
+		routeManager.EXPECT().HasRoute(route1).Return(true, nil)
+		routeManager.EXPECT().HasRoute(route2).Return(true, nil)
+		routeManager.EXPECT().HasRule(rule).Return(true, nil)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("it installs the missing routes and rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		routeManager := networkmock.NewMockRouteManager(ctrl)
+		p, route1, route2, rule := newTestPlugin(routeManager)
+
+		routeManager.EXPECT().HasRoute(route1).Return(true, nil)
+		routeManager.EXPECT().HasRoute(route2).Return(false, nil)
+		routeManager.EXPECT().EnsureRoute(route2).Return(nil)
+		routeManager.EXPECT().HasRule(rule).Return(false, nil)
+		routeManager.EXPECT().EnsureRule(rule).Return(nil)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	routeManager := networkmock.NewMockRouteManager(ctrl)
+	p, route1, route2, _ := newTestPlugin(routeManager)
+
+	routeManager.EXPECT().HasRoute(route1).Return(true, nil)
+	routeManager.EXPECT().HasRoute(route2).Return(false, nil)
+
+	activated, err := p.IsActivated(context.Background())
+	require.NoError(t, err)
+	assert.False(t, activated)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	p, _, _, _ := newTestPlugin(nil)
+	assert.Equal(t, "route:100_0.0.0.0_0,254_203.0.113.0_24", p.ElectionKey(context.Background()))
+
+	p.netns = "/var/run/netns/tenant"
+	assert.Equal(t, "route:tenant:100_0.0.0.0_0,254_203.0.113.0_24", p.ElectionKey(context.Background()))
+}
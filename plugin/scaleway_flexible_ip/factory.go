@@ -0,0 +1,123 @@
+package scalewayflexibleip
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/plugin"
+	"github.com/Scalingo/link/v3/services/scaleway"
+)
+
+const Name = api.PluginScalewayFlexibleIP
+
+type Config struct {
+	RefreshEvery time.Duration `envconfig:"SCALEWAY_FLEXIBLE_IP_REFRESH_INTERVAL" default:"1m"`
+	APIURL       string        `envconfig:"SCALEWAY_API_URL" default:"https://api.scaleway.com"`
+}
+
+func Register(ctx context.Context, registry plugin.Registry, encryptedStorage models.EncryptedStorage) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:           config,
+		encryptedStorage: encryptedStorage,
+	})
+
+	return nil
+}
+
+type Factory struct {
+	config           Config
+	encryptedStorage models.EncryptedStorage
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg StorablePluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	var secretKey string
+	err = f.encryptedStorage.Decrypt(ctx, cfg.SecretKey, &secretKey)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt secret key")
+	}
+
+	return newPlugin(scaleway.NewClient(secretKey, cfg.Zone, f.config.APIURL), f.config.RefreshEvery, cfg.IPID, cfg.ServerID), nil
+}
+
+type PluginConfig = api.ScalewayFlexibleIPPluginConfig
+
+func (f Factory) Validate(_ context.Context, endpoint models.Endpoint) error {
+	validations := errors.NewValidationErrorsBuilder()
+	var req PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		validations.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validations.Build()
+	}
+
+	scaleway.ValidateCredentials(validations, req.SecretKey, req.Zone)
+
+	if req.IPID == "" {
+		validations.Set("plugin_config.ip_id", "missing IP ID")
+	}
+	if req.IPID != "" && !scaleway.IDRegex.MatchString(req.IPID) {
+		validations.Set("plugin_config.ip_id", "invalid IP ID format")
+	}
+
+	if req.ServerID == "" {
+		validations.Set("plugin_config.server_id", "missing server ID")
+	}
+	if req.ServerID != "" && !scaleway.IDRegex.MatchString(req.ServerID) {
+		validations.Set("plugin_config.server_id", "invalid server ID format")
+	}
+
+	validationErr := validations.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return nil
+}
+
+func (f Factory) Mutate(ctx context.Context, endpoint models.Endpoint) (json.RawMessage, error) {
+	var req PluginConfig
+
+	err := json.Unmarshal(endpoint.PluginConfig, &req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	cfg := StorablePluginConfig{
+		Zone:     req.Zone,
+		IPID:     req.IPID,
+		ServerID: req.ServerID,
+	}
+	cfg.SecretKey, err = f.encryptedStorage.Encrypt(ctx, endpoint.ID, req.SecretKey)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "encrypt secret key")
+	}
+
+	res, _ := json.Marshal(cfg)
+
+	return res, nil
+}
+
+type StorablePluginConfig struct {
+	SecretKey models.EncryptedDataLink `json:"secret_key"`
+	Zone      string                   `json:"zone"`
+	IPID      string                   `json:"ip_id"`
+	ServerID  string                   `json:"server_id"`
+}
@@ -0,0 +1,123 @@
+package scalewayflexibleip
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/models"
+)
+
+const testSecretKey = "11111111-1111-4111-8111-111111111111"
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name: "with a valid configuration",
+			Config: PluginConfig{
+				SecretKey: testSecretKey,
+				Zone:      "fr-par-1",
+				IPID:      testIPID,
+				ServerID:  testServerID,
+			},
+		}, {
+			Name: "with an invalid secret key",
+			Config: PluginConfig{
+				SecretKey: "my-secret",
+				Zone:      "fr-par-1",
+				IPID:      testIPID,
+				ServerID:  testServerID,
+			},
+			ExpectedError: "invalid secret key format",
+		}, {
+			Name: "with an invalid zone",
+			Config: PluginConfig{
+				SecretKey: testSecretKey,
+				Zone:      "fr-par",
+				IPID:      testIPID,
+				ServerID:  testServerID,
+			},
+			ExpectedError: "invalid zone",
+		}, {
+			Name: "with a missing IP ID",
+			Config: PluginConfig{
+				SecretKey: testSecretKey,
+				Zone:      "fr-par-1",
+				ServerID:  testServerID,
+			},
+			ExpectedError: "missing IP ID",
+		}, {
+			Name: "with an invalid server ID",
+			Config: PluginConfig{
+				SecretKey: testSecretKey,
+				Zone:      "fr-par-1",
+				IPID:      testIPID,
+				ServerID:  "node-1",
+			},
+			ExpectedError: "invalid server ID format",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			rawConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = Factory{}.Validate(context.Background(), models.Endpoint{PluginConfig: rawConfig})
+			if spec.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), spec.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFactory_Mutate_Success(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	// Given a plugin config with sensitive data
+	req := PluginConfig{
+		SecretKey: "my-secret",
+		Zone:      "fr-par-1",
+		IPID:      testIPID,
+		ServerID:  testServerID,
+	}
+	raw, _ := json.Marshal(req)
+	endpoint := models.Endpoint{
+		ID:           "endpoint-id",
+		PluginConfig: raw,
+	}
+
+	mockStorage := models.NewMockEncryptedStorage(ctrl)
+	mockStorage.EXPECT().Encrypt(ctx, "endpoint-id", "my-secret").Return(models.EncryptedDataLink{
+		ID:         "secret-id",
+		EndpointID: "endpoint-id",
+	}, nil)
+
+	f := Factory{encryptedStorage: mockStorage}
+
+	// When we mutate the plugin config
+	res, err := f.Mutate(ctx, endpoint)
+	require.NoError(t, err)
+
+	// It should encrypt the sensitive data and keep the rest
+	var stored StorablePluginConfig
+	err = json.Unmarshal(res, &stored)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-id", stored.SecretKey.ID)
+	assert.Equal(t, req.Zone, stored.Zone)
+	assert.Equal(t, req.IPID, stored.IPID)
+	assert.Equal(t, req.ServerID, stored.ServerID)
+	assert.NotContains(t, string(res), "my-secret")
+}
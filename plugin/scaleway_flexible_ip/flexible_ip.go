@@ -0,0 +1,91 @@
+package scalewayflexibleip
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/plugin/internal/ipassignment"
+	"github.com/Scalingo/link/v3/services/scaleway"
+)
+
+type Plugin struct {
+	// Flexible IP Configuration
+	ipID     string // ID of the flexible IP to move
+	serverID string // ID of the Instance to attach the flexible IP to
+
+	assignment *ipassignment.Assignment[string]
+}
+
+func newPlugin(client scaleway.FlexibleIPClient, refreshEvery time.Duration, ipID, serverID string) *Plugin {
+	return &Plugin{
+		ipID:       ipID,
+		serverID:   serverID,
+		assignment: ipassignment.New[string](flexibleIP{client: client, id: ipID}, serverID, refreshEvery),
+	}
+}
+
+// flexibleIP moves the flexible IP between the servers, an empty server ID means not attached
+type flexibleIP struct {
+	client scaleway.FlexibleIPClient
+	id     string
+}
+
+func (ip flexibleIP) Assign(ctx context.Context, serverID string) error {
+	return ip.client.AttachIP(ctx, ip.id, serverID)
+}
+
+func (ip flexibleIP) Unassign(ctx context.Context) error {
+	return ip.client.DetachIP(ctx, ip.id)
+}
+
+func (ip flexibleIP) AssignedServer(ctx context.Context) (string, error) {
+	flexibleIP, err := ip.client.GetIP(ctx, ip.id)
+	if err != nil {
+		return "", err
+	}
+	return flexibleIP.ServerID(), nil
+}
+
+// Activate attaches the flexible IP to the server, detaching it from the server it was attached to
+func (p *Plugin) Activate(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.Activate(ctx)
+}
+
+// Deactivate detaches the flexible IP, unless it has already been attached to another server
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.Deactivate(ctx)
+}
+
+func (p *Plugin) Ensure(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.Ensure(ctx)
+}
+
+// IsActivated returns true if the flexible IP is attached to the server
+func (p *Plugin) IsActivated(ctx context.Context) (bool, error) {
+	return p.assignment.IsActivated(ctx)
+}
+
+// EnsureDeactivated detaches the flexible IP if it is still attached to the server of this host.
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	ctx, _ = logger.WithStructToCtx(ctx, "plugin", p)
+	return p.assignment.EnsureDeactivated(ctx)
+}
+
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	return fmt.Sprintf("%s/%s", Name, p.ipID)
+}
+
+func (p *Plugin) LogFields() logrus.Fields {
+	return logrus.Fields{
+		"name":      "scaleway_flexible_ip",
+		"ip_id":     p.ipID,
+		"server_id": p.serverID,
+	}
+}
@@ -0,0 +1,85 @@
+package scalewayflexibleip
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/services/scaleway"
+	"github.com/Scalingo/link/v3/services/scaleway/scalewaymock"
+)
+
+// These tests only check how the plugin calls the Instance API, see the ipassignment package for
+// the refresh logic
+
+const (
+	testIPID     = "22222222-2222-4222-8222-222222222222"
+	testServerID = "33333333-3333-4333-8333-333333333333"
+)
+
+// ipAttachedTo returns the flexible IP attached to the server, not attached if serverID is empty
+func ipAttachedTo(serverID string) scaleway.IP {
+	ip := scaleway.IP{ID: testIPID, Address: "51.15.0.1"}
+	if serverID != "" {
+		ip.Server = &scaleway.IPServer{ID: serverID}
+	}
+	return ip
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := scalewaymock.NewMockFlexibleIPClient(ctrl)
+	plugin := newPlugin(mockClient, time.Minute, testIPID, testServerID)
+
+	mockClient.EXPECT().AttachIP(gomock.Any(), testIPID, testServerID).Return(nil)
+
+	err := plugin.Activate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := scalewaymock.NewMockFlexibleIPClient(ctrl)
+	plugin := newPlugin(mockClient, time.Minute, testIPID, testServerID)
+
+	mockClient.EXPECT().GetIP(gomock.Any(), testIPID).Return(ipAttachedTo(testServerID), nil)
+	mockClient.EXPECT().DetachIP(gomock.Any(), testIPID).Return(nil)
+
+	err := plugin.Deactivate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_IsActivated(t *testing.T) {
+	t.Run("a flexible IP which is not attached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := scalewaymock.NewMockFlexibleIPClient(ctrl)
+		plugin := newPlugin(mockClient, time.Minute, testIPID, testServerID)
+
+		mockClient.EXPECT().GetIP(gomock.Any(), testIPID).Return(ipAttachedTo(""), nil)
+
+		activated, err := plugin.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.False(t, activated)
+	})
+
+	t.Run("a flexible IP attached to the server", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockClient := scalewaymock.NewMockFlexibleIPClient(ctrl)
+		plugin := newPlugin(mockClient, time.Minute, testIPID, testServerID)
+
+		mockClient.EXPECT().GetIP(gomock.Any(), testIPID).Return(ipAttachedTo(testServerID), nil)
+
+		activated, err := plugin.IsActivated(context.Background())
+		require.NoError(t, err)
+		assert.True(t, activated)
+	})
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	plugin := newPlugin(nil, time.Minute, testIPID, testServerID)
+	assert.Equal(t, "scaleway_flexible_ip/"+testIPID, plugin.ElectionKey(context.Background()))
+}
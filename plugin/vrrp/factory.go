@@ -0,0 +1,238 @@
+package vrrp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/api"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/plugin"
+)
+
+const Name = api.PluginVRRP
+
+// defaultPriority is the priority of the advertisements if none is configured (RFC 5798 section 5.2.4)
+const defaultPriority = 100
+
+type Config struct {
+	// Default interface of the endpoints, shared with the ARP plugin. It can be overridden per endpoint.
+	Interface string `envconfig:"INTERFACE"`
+	// Default network namespace of the interfaces, shared with the ARP plugin. It can be overridden
+	// per endpoint.
+	Netns string `envconfig:"NETNS"`
+	// Default duration between two advertisements. It can be overridden per endpoint.
+	VRRPAdvertInterval time.Duration `envconfig:"VRRP_ADVERT_INTERVAL" default:"1s"`
+}
+
+type PluginConfig = api.VRRPPluginConfig
+
+type Factory struct {
+	config          Config
+	newVRRPRouter   func(opts network.VRRPRouterOpts) (network.VRRPRouter, error)
+	newNetInterface func(name, netns string) (network.Interface, error)
+	interfaceExists func(name, netns string) error
+}
+
+func Register(ctx context.Context, registry plugin.Registry) error {
+	var config Config
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse environment")
+	}
+	if config.VRRPAdvertInterval < 10*time.Millisecond || config.VRRPAdvertInterval > network.VRRPMaxAdvertInterval {
+		return errors.Newf(ctx, "invalid VRRP_ADVERT_INTERVAL, it must be between 10ms and %s", network.VRRPMaxAdvertInterval)
+	}
+
+	registry.Register(ctx, Name, Factory{
+		config:        config,
+		newVRRPRouter: network.NewVRRPRouter,
+		newNetInterface: func(name, netns string) (network.Interface, error) {
+			return network.NewNetworkInterfaceFromName(name, network.NetInterfaceOpts{Namespace: netns})
+		},
+		interfaceExists: network.InterfaceExists,
+	})
+	return nil
+}
+
+// settings is the parsed configuration of an endpoint
+type settings struct {
+	vrid       uint8
+	ipv6       bool
+	advert     network.VRRPAdvertisement
+	virtualMAC bool
+}
+
+func (f Factory) Create(ctx context.Context, endpoint models.Endpoint) (plugin.Plugin, error) {
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "unmarshal plugin config")
+	}
+
+	settings, err := f.settings(ctx, cfg)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid plugin config")
+	}
+	interfaceName := f.interfaceName(cfg)
+	if interfaceName == "" {
+		return nil, errors.New(ctx, "invalid plugin config: no interface")
+	}
+
+	router, err := f.newVRRPRouter(network.VRRPRouterOpts{
+		Interface: interfaceName,
+		Namespace: f.netns(cfg),
+		VRID:      settings.vrid,
+		IPv6:      settings.ipv6,
+	})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get VRRP router")
+	}
+
+	return &Plugin{
+		endpoint:        endpoint,
+		settings:        settings,
+		interfaceName:   interfaceName,
+		netns:           network.NamespacePath(f.netns(cfg)),
+		router:          router,
+		newNetInterface: f.newNetInterface,
+	}, nil
+}
+
+func (f Factory) Validate(ctx context.Context, endpoint models.Endpoint) error {
+	validation := errors.NewValidationErrorsBuilder()
+	var cfg PluginConfig
+	err := json.Unmarshal(endpoint.PluginConfig, &cfg)
+	if err != nil {
+		validation.Set("plugin_config", "invalid JSON: "+err.Error())
+		return validation.Build()
+	}
+
+	if cfg.VRID < 1 || cfg.VRID > 255 {
+		validation.Set("plugin_config.vrid", "vrid must be between 1 and 255")
+	}
+	if cfg.Priority < 0 || cfg.Priority > 255 {
+		validation.Set("plugin_config.priority", "priority must be between 1 and 255")
+	}
+	if len(cfg.VirtualIPs) == 0 {
+		validation.Set("plugin_config.virtual_ips", "at least one virtual IP is required")
+	}
+	_, err = parseVirtualIPs(ctx, cfg.VirtualIPs)
+	if err != nil {
+		validation.Set("plugin_config.virtual_ips", err.Error())
+	}
+	if cfg.AdvertInterval != "" {
+		_, err = parseAdvertInterval(ctx, cfg.AdvertInterval)
+		if err != nil {
+			validation.Set("plugin_config.advert_interval", err.Error())
+		}
+	}
+
+	interfaceName := f.interfaceName(cfg)
+	if interfaceName == "" {
+		validation.Set("plugin_config.interface", "interface is required if no default interface is configured on the host")
+	} else {
+		err = f.interfaceExists(interfaceName, f.netns(cfg))
+		if err != nil {
+			validation.Set("plugin_config.interface", "interface "+interfaceName+" not found: "+err.Error())
+		}
+	}
+
+	validationErr := validation.Build()
+	if validationErr != nil {
+		return validationErr
+	}
+	return nil
+}
+
+// settings returns the settings of the endpoint, based on the settings of the host
+func (f Factory) settings(ctx context.Context, cfg PluginConfig) (settings, error) {
+	if cfg.VRID < 1 || cfg.VRID > 255 {
+		return settings{}, errors.New(ctx, "vrid must be between 1 and 255")
+	}
+	priority := cfg.Priority
+	if priority == 0 {
+		priority = defaultPriority
+	}
+	if priority < 1 || priority > 255 {
+		return settings{}, errors.New(ctx, "priority must be between 1 and 255")
+	}
+
+	addresses, err := parseVirtualIPs(ctx, cfg.VirtualIPs)
+	if err != nil {
+		return settings{}, errors.Wrap(ctx, err, "invalid virtual IPs")
+	}
+	if len(addresses) == 0 {
+		return settings{}, errors.New(ctx, "no virtual IP")
+	}
+
+	interval := f.config.VRRPAdvertInterval
+	if cfg.AdvertInterval != "" {
+		interval, err = parseAdvertInterval(ctx, cfg.AdvertInterval)
+		if err != nil {
+			return settings{}, errors.Wrap(ctx, err, "invalid advert_interval")
+		}
+	}
+
+	return settings{
+		vrid: uint8(cfg.VRID),
+		ipv6: addresses[0].To4() == nil,
+		advert: network.VRRPAdvertisement{
+			Priority:  uint8(priority),
+			Interval:  interval,
+			Addresses: addresses,
+		},
+		virtualMAC: cfg.VirtualMAC,
+	}, nil
+}
+
+// parseVirtualIPs parses the virtual IPs, which must all be of the same IP family
+func parseVirtualIPs(ctx context.Context, values []string) ([]net.IP, error) {
+	ips := make([]net.IP, 0, len(values))
+	for _, value := range values {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, errors.Newf(ctx, "invalid IP address %s", value)
+		}
+		if len(ips) > 0 && (ips[0].To4() == nil) != (ip.To4() == nil) {
+			return nil, errors.New(ctx, "the virtual IPs must be of the same IP family")
+		}
+		ips = append(ips, ip)
+	}
+	if len(ips) > 255 {
+		return nil, errors.New(ctx, "at most 255 virtual IPs are supported")
+	}
+	return ips, nil
+}
+
+func parseAdvertInterval(ctx context.Context, value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrap(ctx, err, "parse duration")
+	}
+	if interval < 10*time.Millisecond || interval > network.VRRPMaxAdvertInterval {
+		return 0, errors.Newf(ctx, "advert_interval must be between 10ms and %s", network.VRRPMaxAdvertInterval)
+	}
+	return interval, nil
+}
+
+// interfaceName returns the interface of the endpoint, or the default one of the host
+func (f Factory) interfaceName(cfg PluginConfig) string {
+	if cfg.Interface != "" {
+		return cfg.Interface
+	}
+	return f.config.Interface
+}
+
+// netns returns the network namespace of the endpoint, or the default one of the host
+func (f Factory) netns(cfg PluginConfig) string {
+	if cfg.Netns != "" {
+		return cfg.Netns
+	}
+	return f.config.Netns
+}
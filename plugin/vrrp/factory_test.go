@@ -0,0 +1,122 @@
+package vrrp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+func TestFactory_Validate(t *testing.T) {
+	specs := []struct {
+		Name          string
+		Config        PluginConfig
+		ExpectedError string
+	}{
+		{
+			Name:   "with a VRID and a virtual IP",
+			Config: PluginConfig{VRID: 51, VirtualIPs: []string{"192.0.2.10"}},
+		}, {
+			Name: "with all the settings",
+			Config: PluginConfig{
+				VRID:           51,
+				Priority:       200,
+				VirtualIPs:     []string{"2001:db8::10", "2001:db8::11"},
+				AdvertInterval: "100ms",
+				VirtualMAC:     true,
+				Interface:      "eth1",
+			},
+		}, {
+			Name:          "without VRID",
+			Config:        PluginConfig{VirtualIPs: []string{"192.0.2.10"}},
+			ExpectedError: "vrid must be between 1 and 255",
+		}, {
+			Name:          "with an invalid priority",
+			Config:        PluginConfig{VRID: 51, Priority: 256, VirtualIPs: []string{"192.0.2.10"}},
+			ExpectedError: "priority must be between 1 and 255",
+		}, {
+			Name:          "without virtual IP",
+			Config:        PluginConfig{VRID: 51},
+			ExpectedError: "at least one virtual IP is required",
+		}, {
+			Name:          "with virtual IPs of different families",
+			Config:        PluginConfig{VRID: 51, VirtualIPs: []string{"192.0.2.10", "2001:db8::10"}},
+			ExpectedError: "the virtual IPs must be of the same IP family",
+		}, {
+			Name:          "with a too long advertisement interval",
+			Config:        PluginConfig{VRID: 51, VirtualIPs: []string{"192.0.2.10"}, AdvertInterval: "1m"},
+			ExpectedError: "advert_interval must be between 10ms and 40.95s",
+		}, {
+			Name:          "with an unknown interface",
+			Config:        PluginConfig{VRID: 51, VirtualIPs: []string{"192.0.2.10"}, Interface: "unknown"},
+			ExpectedError: "interface unknown not found",
+		},
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Name, func(t *testing.T) {
+			factory := Factory{
+				config: Config{Interface: "eth0", VRRPAdvertInterval: time.Second},
+				interfaceExists: func(name, _ string) error {
+					if name == "unknown" {
+						return errors.New("Link not found")
+					}
+					return nil
+				},
+			}
+
+			pluginConfig, err := json.Marshal(spec.Config)
+			require.NoError(t, err)
+
+			err = factory.Validate(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+			if spec.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.ExpectedError)
+		})
+	}
+}
+
+func TestFactory_Create(t *testing.T) {
+	var routerOpts network.VRRPRouterOpts
+	factory := Factory{
+		config: Config{Interface: "eth0", Netns: "blue", VRRPAdvertInterval: time.Second},
+		newVRRPRouter: func(opts network.VRRPRouterOpts) (network.VRRPRouter, error) {
+			routerOpts = opts
+			return nil, nil
+		},
+		newNetInterface: func(string, string) (network.Interface, error) {
+			return nil, nil
+		},
+	}
+
+	pluginConfig, err := json.Marshal(PluginConfig{VRID: 51, VirtualIPs: []string{"2001:db8::10"}, VirtualMAC: true})
+	require.NoError(t, err)
+	p, err := factory.Create(context.Background(), models.Endpoint{PluginConfig: pluginConfig})
+	require.NoError(t, err)
+
+	assert.Equal(t, network.VRRPRouterOpts{Interface: "eth0", Namespace: "blue", VRID: 51, IPv6: true}, routerOpts)
+	// The defaults of the host are applied
+	assert.Equal(t, settings{
+		vrid: 51,
+		ipv6: true,
+		advert: network.VRRPAdvertisement{
+			Priority:  defaultPriority,
+			Interval:  time.Second,
+			Addresses: []net.IP{net.ParseIP("2001:db8::10")},
+		},
+		virtualMAC: true,
+	}, p.(*Plugin).settings)
+	assert.Equal(t, "/var/run/netns/blue", p.(*Plugin).netns)
+	assert.NotNil(t, p.(*Plugin).newNetInterface)
+}
@@ -0,0 +1,246 @@
+package vrrp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/go-utils/logger"
+	"github.com/Scalingo/link/v3/models"
+	"github.com/Scalingo/link/v3/network"
+)
+
+type Plugin struct {
+	endpoint      models.Endpoint
+	settings      settings
+	interfaceName string
+	// netns is the path of the network namespace of the interface, empty for the namespace of LinK
+	netns  string
+	router network.VRRPRouter
+	// newNetInterface opens the macvlan interface owning the virtual MAC, where the virtual IPs are added
+	newNetInterface func(name, netns string) (network.Interface, error)
+
+	// The advertisements are sent by a goroutine running while the endpoint is activated
+	advertiserMutex sync.Mutex
+	stopAdvertiser  context.CancelFunc
+	advertiserDone  chan struct{}
+}
+
+// Activate adds the virtual MAC and the virtual IPs if enabled, sends a first advertisement and
+// starts sending them at the advertisement interval
+func (p *Plugin) Activate(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	if p.settings.virtualMAC {
+		err := p.router.EnsureVirtualMAC()
+		if err != nil {
+			return errors.Wrap(ctx, err, "add virtual MAC")
+		}
+		err = p.ensureVirtualIPs(ctx, true)
+		if err != nil {
+			return errors.Wrap(ctx, err, "add virtual IPs")
+		}
+	}
+
+	err := p.router.Advertise(p.settings.advert)
+	if err != nil {
+		return errors.Wrap(ctx, err, "send VRRP advertisement")
+	}
+	p.startAdvertiser(ctx)
+
+	log.WithFields(logrus.Fields{
+		"vrrp_vrid":     p.settings.vrid,
+		"vrrp_priority": p.settings.advert.Priority,
+	}).Info("VRRP advertisements started")
+	return nil
+}
+
+// Deactivate stops the advertisements, sends an advertisement with the priority 0 so that the
+// backup routers take over without waiting for the master down interval, and removes the virtual MAC
+func (p *Plugin) Deactivate(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	wasAdvertising := p.stopAdvertising()
+	if wasAdvertising {
+		advert := p.settings.advert
+		advert.Priority = 0
+		err := p.router.Advertise(advert)
+		if err != nil {
+			log.WithError(err).Warn("Fail to send the VRRP advertisement with priority 0")
+		}
+	}
+
+	if p.settings.virtualMAC {
+		err := p.router.RemoveVirtualMAC()
+		if err != nil {
+			return errors.Wrap(ctx, err, "remove virtual MAC")
+		}
+	}
+
+	log.WithField("vrrp_vrid", p.settings.vrid).Info("VRRP advertisements stopped")
+	return nil
+}
+
+// Ensure restarts the advertisements if they are stopped and adds the virtual MAC and the virtual
+// IPs if they are missing
+func (p *Plugin) Ensure(ctx context.Context) error {
+	log := logger.Get(ctx)
+
+	if p.settings.virtualMAC {
+		has, err := p.router.HasVirtualMAC()
+		if err != nil {
+			return errors.Wrap(ctx, err, "check virtual MAC")
+		}
+		if !has {
+			log.Warn("Virtual MAC missing, adding it again")
+			err = p.router.EnsureVirtualMAC()
+			if err != nil {
+				return errors.Wrap(ctx, err, "add virtual MAC")
+			}
+		}
+		err = p.ensureVirtualIPs(ctx, false)
+		if err != nil {
+			return errors.Wrap(ctx, err, "add virtual IPs")
+		}
+	}
+
+	if !p.isAdvertising() {
+		log.Info("VRRP advertisements not running, starting them")
+		p.startAdvertiser(ctx)
+	}
+	return nil
+}
+
+// EnsureDeactivated stops the advertisements and removes the virtual MAC if they are still present
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	p.stopAdvertising()
+
+	if p.settings.virtualMAC {
+		err := p.router.RemoveVirtualMAC()
+		if err != nil {
+			return errors.Wrap(ctx, err, "remove virtual MAC")
+		}
+	}
+	return nil
+}
+
+// ensureVirtualIPs adds the virtual IPs to the macvlan interface owning the virtual MAC, so that
+// they are resolved to the virtual MAC address. They are announced from this interface if announce
+// is true or if they were missing. They are removed with the interface.
+func (p *Plugin) ensureVirtualIPs(ctx context.Context, announce bool) error {
+	log := logger.Get(ctx)
+
+	name := network.VirtualMACInterfaceName(p.settings.vrid, p.settings.ipv6)
+	netInterface, err := p.newNetInterface(name, p.netns)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "get network interface %s", name)
+	}
+
+	for _, address := range p.settings.advert.Addresses {
+		ip := virtualIPCIDR(address)
+		has, err := netInterface.HasIP(ip)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "check IP %s", ip)
+		}
+		if has && !announce {
+			continue
+		}
+		if !has {
+			log.WithField("ip", ip).Info("Adding virtual IP to the virtual MAC interface")
+			err = netInterface.EnsureIP(ip)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "add IP %s", ip)
+			}
+		}
+		err = netInterface.Announce(ip, network.ARPModeRequest)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "announce IP %s", ip)
+		}
+	}
+	return nil
+}
+
+// virtualIPCIDR returns the virtual IP as a single address network
+func virtualIPCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+func (p *Plugin) isAdvertising() bool {
+	p.advertiserMutex.Lock()
+	defer p.advertiserMutex.Unlock()
+	return p.stopAdvertiser != nil
+}
+
+// startAdvertiser starts the goroutine sending the advertisements if it is not running. It is not
+// bound to the context of the activation which is canceled when the activation ends.
+func (p *Plugin) startAdvertiser(ctx context.Context) {
+	p.advertiserMutex.Lock()
+	defer p.advertiserMutex.Unlock()
+	if p.stopAdvertiser != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	p.stopAdvertiser = cancel
+	p.advertiserDone = done
+
+	go func() {
+		defer close(done)
+		log := logger.Get(ctx)
+		ticker := time.NewTicker(p.settings.advert.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := p.router.Advertise(p.settings.advert)
+				if err != nil {
+					log.WithError(err).Error("Fail to send VRRP advertisement")
+				}
+			}
+		}
+	}()
+}
+
+// stopAdvertising stops the goroutine sending the advertisements and waits for it to exit. It
+// returns true if the goroutine was running.
+func (p *Plugin) stopAdvertising() bool {
+	p.advertiserMutex.Lock()
+	defer p.advertiserMutex.Unlock()
+	if p.stopAdvertiser == nil {
+		return false
+	}
+
+	p.stopAdvertiser()
+	<-p.advertiserDone
+	p.stopAdvertiser = nil
+	p.advertiserDone = nil
+	return true
+}
+
+// ElectionKey is based on the interface, the IP family and the VRID: a VRID is unique per
+// interface and IP family
+func (p *Plugin) ElectionKey(_ context.Context) string {
+	family := "ipv4"
+	if p.settings.ipv6 {
+		family = "ipv6"
+	}
+	key := fmt.Sprintf("%s_%s_%d", p.interfaceName, family, p.settings.vrid)
+	if p.netns != "" {
+		netns := strings.TrimPrefix(p.netns, network.NetnsDir+"/")
+		key = strings.ReplaceAll(netns, "/", "_") + ":" + key
+	}
+	return "vrrp:" + key
+}
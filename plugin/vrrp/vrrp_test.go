@@ -0,0 +1,234 @@
+package vrrp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/Scalingo/link/v3/network"
+	"github.com/Scalingo/link/v3/network/networkmock"
+)
+
+func newTestPlugin(router network.VRRPRouter, virtualMAC bool) *Plugin {
+	return &Plugin{
+		settings: settings{
+			vrid: 51,
+			advert: network.VRRPAdvertisement{
+				Priority:  100,
+				Interval:  time.Hour,
+				Addresses: []net.IP{net.ParseIP("192.0.2.10")},
+			},
+			virtualMAC: virtualMAC,
+		},
+		interfaceName: "eth0",
+		router:        router,
+	}
+}
+
+// withVirtualMACInterface makes the plugin open the given interface, and records the name of the
+// interface it opens
+func withVirtualMACInterface(p *Plugin, netInterface network.Interface) *string {
+	var opened string
+	p.newNetInterface = func(name, _ string) (network.Interface, error) {
+		opened = name
+		return netInterface, nil
+	}
+	return &opened
+}
+
+func TestPlugin_Activate(t *testing.T) {
+	t.Run("it adds the virtual MAC and starts the advertisements", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		p := newTestPlugin(router, true)
+		opened := withVirtualMACInterface(p, netInterface)
+
+		gomock.InOrder(
+			router.EXPECT().EnsureVirtualMAC().Return(nil),
+			netInterface.EXPECT().HasIP("192.0.2.10/32").Return(false, nil),
+			netInterface.EXPECT().EnsureIP("192.0.2.10/32").Return(nil),
+			netInterface.EXPECT().Announce("192.0.2.10/32", network.ARPModeRequest).Return(nil),
+			router.EXPECT().Advertise(p.settings.advert).Return(nil),
+		)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+		// The virtual IPs are on the macvlan interface, so that they are resolved to the virtual MAC
+		assert.Equal(t, "vrrp.51", *opened)
+		assert.True(t, p.isAdvertising())
+		p.stopAdvertising()
+	})
+
+	t.Run("it announces the virtual IPs already present on the macvlan interface", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		p := newTestPlugin(router, true)
+		p.settings.ipv6 = true
+		p.settings.advert.Addresses = []net.IP{net.ParseIP("2001:db8::10")}
+		opened := withVirtualMACInterface(p, netInterface)
+
+		router.EXPECT().EnsureVirtualMAC().Return(nil)
+		netInterface.EXPECT().HasIP("2001:db8::10/128").Return(true, nil)
+		netInterface.EXPECT().Announce("2001:db8::10/128", network.ARPModeRequest).Return(nil)
+		router.EXPECT().Advertise(p.settings.advert).Return(nil)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "vrrp6.51", *opened)
+		p.stopAdvertising()
+	})
+
+	t.Run("without virtual MAC, the virtual IPs are not added", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		p := newTestPlugin(router, false)
+		p.newNetInterface = func(string, string) (network.Interface, error) {
+			t.Fatal("no interface should be opened")
+			return nil, nil
+		}
+
+		router.EXPECT().Advertise(p.settings.advert).Return(nil)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+		p.stopAdvertising()
+	})
+
+	t.Run("it sends the advertisements at the advertisement interval", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		p := newTestPlugin(router, false)
+		p.settings.advert.Interval = 10 * time.Millisecond
+
+		sent := make(chan struct{}, 10)
+		router.EXPECT().Advertise(p.settings.advert).DoAndReturn(func(network.VRRPAdvertisement) error {
+			select {
+			case sent <- struct{}{}:
+			default:
+			}
+			return nil
+		}).MinTimes(3)
+
+		err := p.Activate(context.Background())
+		require.NoError(t, err)
+		for range 3 {
+			<-sent
+		}
+		p.stopAdvertising()
+	})
+
+	t.Run("if the advertisement cannot be sent, the activation fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		p := newTestPlugin(router, false)
+
+		router.EXPECT().Advertise(gomock.Any()).Return(errors.New("no IPv4 address on interface eth0"))
+
+		err := p.Activate(context.Background())
+		require.ErrorContains(t, err, "no IPv4 address")
+		assert.False(t, p.isAdvertising())
+	})
+}
+
+func TestPlugin_Deactivate(t *testing.T) {
+	t.Run("it sends an advertisement with the priority 0 and removes the virtual MAC", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		p := newTestPlugin(router, true)
+		withVirtualMACInterface(p, netInterface)
+		router.EXPECT().EnsureVirtualMAC().Return(nil)
+		netInterface.EXPECT().HasIP(gomock.Any()).Return(true, nil)
+		netInterface.EXPECT().Announce(gomock.Any(), gomock.Any()).Return(nil)
+		router.EXPECT().Advertise(p.settings.advert).Return(nil)
+		require.NoError(t, p.Activate(context.Background()))
+
+		resignation := p.settings.advert
+		resignation.Priority = 0
+		gomock.InOrder(
+			router.EXPECT().Advertise(resignation).Return(nil),
+			router.EXPECT().RemoveVirtualMAC().Return(nil),
+		)
+
+		err := p.Deactivate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, p.isAdvertising())
+	})
+
+	t.Run("it does not send an advertisement if the advertisements were not running", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		p := newTestPlugin(router, false)
+
+		err := p.Deactivate(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestPlugin_Ensure(t *testing.T) {
+	t.Run("it adds the virtual MAC and the virtual IPs again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		p := newTestPlugin(router, true)
+		opened := withVirtualMACInterface(p, netInterface)
+
+		gomock.InOrder(
+			router.EXPECT().HasVirtualMAC().Return(false, nil),
+			router.EXPECT().EnsureVirtualMAC().Return(nil),
+			netInterface.EXPECT().HasIP("192.0.2.10/32").Return(false, nil),
+			netInterface.EXPECT().EnsureIP("192.0.2.10/32").Return(nil),
+			netInterface.EXPECT().Announce("192.0.2.10/32", network.ARPModeRequest).Return(nil),
+		)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "vrrp.51", *opened)
+		// The advertisements are started, e.g. after a restart of LinK
+		assert.True(t, p.isAdvertising())
+		p.stopAdvertising()
+	})
+
+	t.Run("it does not announce the virtual IPs already present", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		router := networkmock.NewMockVRRPRouter(ctrl)
+		netInterface := networkmock.NewMockInterface(ctrl)
+		p := newTestPlugin(router, true)
+		withVirtualMACInterface(p, netInterface)
+
+		router.EXPECT().HasVirtualMAC().Return(true, nil)
+		netInterface.EXPECT().HasIP("192.0.2.10/32").Return(true, nil)
+
+		err := p.Ensure(context.Background())
+		require.NoError(t, err)
+		p.stopAdvertising()
+	})
+}
+
+func TestPlugin_EnsureDeactivated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	router := networkmock.NewMockVRRPRouter(ctrl)
+	p := newTestPlugin(router, true)
+
+	router.EXPECT().RemoveVirtualMAC().Return(nil)
+
+	err := p.EnsureDeactivated(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlugin_ElectionKey(t *testing.T) {
+	p := newTestPlugin(nil, false)
+	assert.Equal(t, "vrrp:eth0_ipv4_51", p.ElectionKey(context.Background()))
+
+	p.settings.ipv6 = true
+	p.netns = "/var/run/netns/blue"
+	assert.Equal(t, "vrrp:blue:eth0_ipv6_51", p.ElectionKey(context.Background()))
+}
@@ -57,6 +57,8 @@ func (p *Plugin) Deactivate(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(ctx, err, "send webhook")
 	}
+
+	p.lastRefreshedAt = time.Now()
 	log.Info("Deactivation webhook sent successfully")
 
 	return nil
@@ -77,6 +79,22 @@ func (p *Plugin) Ensure(ctx context.Context) error {
 	return nil
 }
 
+// EnsureDeactivated sends the deactivation webhook again at regular interval
+func (p *Plugin) EnsureDeactivated(ctx context.Context) error {
+	log := logger.Get(ctx)
+	if p.lastRefreshedAt.Add(p.refreshEvery).After(time.Now()) {
+		log.Debug("No need to refresh webhook yet")
+		return nil
+	}
+
+	err := p.Deactivate(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "deactivate webhook")
+	}
+
+	return nil
+}
+
 func (p *Plugin) ElectionKey(_ context.Context) string {
 	return fmt.Sprintf("%s/%s", Name, p.cfg.ResourceID)
 }
@@ -196,3 +196,34 @@ func TestPluginEnsure(t *testing.T) {
 		assert.Equal(t, int32(2), calls.Load())
 	})
 }
+
+func TestPluginEnsureDeactivated(t *testing.T) {
+	t.Run("sends the standby status once in refresh window", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+
+			var body api.WebhookPluginStatusChangePayload
+			err := json.NewDecoder(r.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, api.Standby, body.Status)
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		p := &Plugin{
+			endpoint:     models.Endpoint{ID: "vip-ensure-deactivated-1", Plugin: Name},
+			cfg:          PluginConfig{URL: server.URL, ResourceID: "resource-default"},
+			httpClient:   server.Client(),
+			refreshEvery: 30 * time.Minute,
+		}
+
+		err := p.EnsureDeactivated(t.Context())
+		require.NoError(t, err)
+		err = p.EnsureDeactivated(t.Context())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
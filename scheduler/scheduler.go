@@ -2,7 +2,9 @@ package scheduler
 
 import (
 	"context"
+	"maps"
 	"sync"
+	"sync/atomic"
 
 	stderrors "github.com/pkg/errors"
 	etcdv3 "go.etcd.io/etcd/client/v3"
@@ -29,11 +31,13 @@ var (
 type Scheduler interface {
 	Start(ctx context.Context, endpoint models.Endpoint) (models.Endpoint, error)
 	Stop(ctx context.Context, id string) error
+	Shutdown(ctx context.Context, opts ShutdownOpts) error
 	Failover(ctx context.Context, id string) error
 	Status(id string) string
 	ConfiguredEndpoints(ctx context.Context) EndpointsWithStatus
 	GetEndpoint(ctx context.Context, id string) *EndpointWithStatus
 	EndpointCount() int
+	IsOwned(ctx context.Context, id string) bool
 	UpdateEndpoint(ctx context.Context, endpoint models.Endpoint) error
 }
 
@@ -41,6 +45,8 @@ type Scheduler interface {
 type EndpointScheduler struct {
 	mapMutex         sync.RWMutex
 	endpointManagers map[string]ip.Manager
+	// restoredManagers have been added by Restore and are not started yet
+	restoredManagers []restoredManager
 	etcd             *etcdv3.Client
 	config           config.Config
 	storage          models.Storage
@@ -48,6 +54,11 @@ type EndpointScheduler struct {
 	pluginRegistry   plugin.Registry
 }
 
+type restoredManager struct {
+	ctx     context.Context
+	manager ip.Manager
+}
+
 // NewEndpointScheduler creates and configures a Scheduler
 func NewEndpointScheduler(config config.Config, etcd *etcdv3.Client, storage models.Storage, leaseManager locker.EtcdLeaseManager, registry plugin.Registry) *EndpointScheduler {
 	return &EndpointScheduler{
@@ -74,13 +85,52 @@ func (s *EndpointScheduler) Status(id string) string {
 
 // Start schedules a new endpoint on the host. It launches a new manager for the endpoint and add it to the tracked endpoint on this host.
 func (s *EndpointScheduler) Start(ctx context.Context, endpoint models.Endpoint) (models.Endpoint, error) {
+	manager, err := s.addManager(ctx, endpoint)
+	if err != nil {
+		return endpoint, err
+	}
+	go manager.Start(ctx)
+
+	return endpoint, nil
+}
+
+// Restore adds the manager of an endpoint configured on the host by a previous run of LinK, without
+// starting it. The restored managers are started by StartRestored, so that the leftovers of the
+// previous run can be cleaned up before any endpoint gets activated.
+func (s *EndpointScheduler) Restore(ctx context.Context, endpoint models.Endpoint) (models.Endpoint, error) {
+	manager, err := s.addManager(ctx, endpoint)
+	if err != nil {
+		return endpoint, err
+	}
+
+	s.mapMutex.Lock()
+	s.restoredManagers = append(s.restoredManagers, restoredManager{ctx: ctx, manager: manager})
+	s.mapMutex.Unlock()
+
+	return endpoint, nil
+}
+
+// StartRestored starts the managers added by Restore
+func (s *EndpointScheduler) StartRestored() {
+	s.mapMutex.Lock()
+	restored := s.restoredManagers
+	s.restoredManagers = nil
+	s.mapMutex.Unlock()
+
+	for _, r := range restored {
+		go r.manager.Start(r.ctx)
+	}
+}
+
+// addManager initializes the manager of the endpoint and adds it to the tracked endpoints
+func (s *EndpointScheduler) addManager(ctx context.Context, endpoint models.Endpoint) (ip.Manager, error) {
 	log := logger.Get(ctx)
 
 	log.Info("Initialize Endpoint Plugin")
 
 	plugin, err := s.pluginRegistry.Create(ctx, endpoint)
 	if err != nil {
-		return endpoint, errors.Wrap(ctx, err, "initialize plugin")
+		return nil, errors.Wrap(ctx, err, "initialize plugin")
 	}
 
 	ctx, log = logger.WithFieldToCtx(ctx, "election_key", plugin.ElectionKey(ctx))
@@ -89,7 +139,7 @@ func (s *EndpointScheduler) Start(ctx context.Context, endpoint models.Endpoint)
 	for _, manager := range s.endpointManagers {
 		if manager.ElectionKey(ctx) == plugin.ElectionKey(ctx) {
 			s.mapMutex.RUnlock()
-			return endpoint, errors.Wrap(ctx, ErrEndpointAlreadyAssigned, "endpoint already assigned")
+			return nil, errors.Wrap(ctx, ErrEndpointAlreadyAssigned, "endpoint already assigned")
 		}
 	}
 	s.mapMutex.RUnlock()
@@ -98,15 +148,14 @@ func (s *EndpointScheduler) Start(ctx context.Context, endpoint models.Endpoint)
 
 	manager, err := ip.NewManager(ctx, s.config, endpoint, s.etcd, s.storage, s.leaseManager, plugin)
 	if err != nil {
-		return endpoint, errors.Wrap(ctx, err, "fail to initialize manager")
+		return nil, errors.Wrap(ctx, err, "fail to initialize manager")
 	}
 
 	s.mapMutex.Lock()
 	s.endpointManagers[endpoint.ID] = manager
 	s.mapMutex.Unlock()
-	go manager.Start(ctx)
 
-	return endpoint, nil
+	return manager, nil
 }
 
 // Stop the manager of the specified endpoint and remove it from the tracked endpoints
@@ -118,7 +167,7 @@ func (s *EndpointScheduler) Stop(ctx context.Context, id string) error {
 		return ErrEndpointNotFound
 	}
 
-	err := manager.Stop(ctx)
+	err := manager.Stop(ctx, ip.StopOpts{})
 	if err != nil {
 		return errors.Wrap(ctx, err, "fail to stop manager")
 	}
@@ -129,6 +178,49 @@ func (s *EndpointScheduler) Stop(ctx context.Context, id string) error {
 	return nil
 }
 
+// ShutdownOpts are the options used when LinK is shutting down
+type ShutdownOpts struct {
+	// KeepPluginsActive does not hand over the endpoints and leaves their plugins in their current state
+	KeepPluginsActive bool
+}
+
+// Shutdown stops the managers of all the endpoints in parallel. It is called when LinK is stopping.
+// Contrary to Stop, the endpoints are not removed from the storage and they will be restarted with LinK.
+func (s *EndpointScheduler) Shutdown(ctx context.Context, opts ShutdownOpts) error {
+	log := logger.Get(ctx)
+
+	s.mapMutex.RLock()
+	managers := make(map[string]ip.Manager, len(s.endpointManagers))
+	maps.Copy(managers, s.endpointManagers)
+	s.mapMutex.RUnlock()
+
+	log.WithField("endpoints_count", len(managers)).Info("Stopping all the endpoint managers")
+
+	var wg sync.WaitGroup
+	var failures atomic.Int32
+	for id, manager := range managers {
+		wg.Go(func() {
+			ctx, log := logger.WithFieldToCtx(ctx, "endpoint_id", id)
+			err := manager.Stop(ctx, ip.StopOpts{KeepPluginActive: opts.KeepPluginsActive})
+			if err != nil {
+				log.WithError(err).Error("Fail to stop the endpoint manager")
+				failures.Add(1)
+				return
+			}
+
+			s.mapMutex.Lock()
+			delete(s.endpointManagers, id)
+			s.mapMutex.Unlock()
+		})
+	}
+	wg.Wait()
+
+	if failures.Load() > 0 {
+		return errors.Newf(ctx, "fail to stop %d endpoint managers", failures.Load())
+	}
+	return nil
+}
+
 // Failover triggers a failover on a specific endpoint
 func (s *EndpointScheduler) Failover(ctx context.Context, id string) error {
 	s.mapMutex.RLock()
@@ -158,11 +250,35 @@ func (s *EndpointScheduler) ConfiguredEndpoints(ctx context.Context) EndpointsWi
 			Endpoint:    manager.Endpoint(),
 			Status:      manager.Status(),
 			ElectionKey: manager.ElectionKey(ctx),
+			Conditions:  manager.Conditions(ctx),
 		})
 	}
 	return res
 }
 
+// IsOwned returns true if this host currently owns the lock of the endpoint. If the lock cannot be
+// checked (e.g. etcd is not reachable), the endpoint is considered as owned so that it is never
+// cleaned up by mistake.
+func (s *EndpointScheduler) IsOwned(ctx context.Context, id string) bool {
+	s.mapMutex.RLock()
+	manager, ok := s.endpointManagers[id]
+	s.mapMutex.RUnlock()
+	if !ok {
+		return false
+	}
+
+	isMaster, err := manager.IsMaster(ctx)
+	if errors.Is(err, locker.ErrInvalidEtcdState) {
+		// Nobody owns the lock
+		return false
+	}
+	if err != nil {
+		logger.Get(ctx).WithError(err).WithField("endpoint_id", id).Info("Fail to check if the endpoint is owned by this host")
+		return true
+	}
+	return isMaster
+}
+
 // GetEndpoint fetches basic information about a tracked endpoint
 func (s *EndpointScheduler) GetEndpoint(ctx context.Context, id string) *EndpointWithStatus {
 	s.mapMutex.RLock()
@@ -177,6 +293,7 @@ func (s *EndpointScheduler) GetEndpoint(ctx context.Context, id string) *Endpoin
 		Endpoint:    manager.Endpoint(),
 		Status:      manager.Status(),
 		ElectionKey: manager.ElectionKey(ctx),
+		Conditions:  manager.Conditions(ctx),
 	}
 }
 
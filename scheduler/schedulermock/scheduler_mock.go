@@ -93,6 +93,34 @@ func (mr *MockSchedulerMockRecorder) GetEndpoint(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndpoint", reflect.TypeOf((*MockScheduler)(nil).GetEndpoint), ctx, id)
 }
 
+// IsOwned mocks base method.
+func (m *MockScheduler) IsOwned(ctx context.Context, id string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsOwned", ctx, id)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsOwned indicates an expected call of IsOwned.
+func (mr *MockSchedulerMockRecorder) IsOwned(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsOwned", reflect.TypeOf((*MockScheduler)(nil).IsOwned), ctx, id)
+}
+
+// Shutdown mocks base method.
+func (m *MockScheduler) Shutdown(ctx context.Context, opts scheduler.ShutdownOpts) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shutdown", ctx, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockSchedulerMockRecorder) Shutdown(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockScheduler)(nil).Shutdown), ctx, opts)
+}
+
 // Start mocks base method.
 func (m *MockScheduler) Start(ctx context.Context, endpoint models.Endpoint) (models.Endpoint, error) {
 	m.ctrl.T.Helper()
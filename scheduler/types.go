@@ -10,12 +10,14 @@ type EndpointWithStatus struct {
 
 	Status      string
 	ElectionKey string
+	Conditions  []api.EndpointCondition
 }
 
 func (e EndpointWithStatus) ToAPIType() api.Endpoint {
 	res := e.Endpoint.ToAPIType()
 	res.Status = e.Status
 	res.ElectionKey = e.ElectionKey
+	res.Conditions = e.Conditions
 	return res
 }
 
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// The aws package provides a minimal client of the EC2 Query API, limited to the actions needed by
+// the plugins. The requests are signed with the AWS Signature Version 4.
+
+// ec2APIVersion is the version of the EC2 Query API used by the client
+const ec2APIVersion = "2016-11-15"
+
+type APIClient struct {
+	httpClient *http.Client
+	signer     *v4.Signer
+
+	// Client Configuration
+	region      string
+	endpointURL string
+}
+
+// NewClient returns a client of the EC2 API of the region. If endpointURL is empty, the public
+// endpoint of the region is used.
+func NewClient(accessKey, secretKey, region, endpointURL string) *APIClient {
+	if endpointURL == "" {
+		endpointURL = fmt.Sprintf("https://ec2.%s.amazonaws.com/", region)
+	}
+
+	return &APIClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		signer:      v4.NewSigner(credentials.NewStaticCredentials(accessKey, secretKey, "")),
+		region:      region,
+		endpointURL: endpointURL,
+	}
+}
+
+// APIError is an error returned by the EC2 API
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+type errorResponse struct {
+	Errors []struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Errors>Error"`
+}
+
+// do sends the action with its parameters and decodes the XML response in res
+func (c *APIClient) do(ctx context.Context, action string, params url.Values, res any) error {
+	params.Set("Action", action)
+	params.Set("Version", ec2APIVersion)
+	body := []byte(params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(ctx, err, "create request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	_, err = c.signer.Sign(req, bytes.NewReader(body), "ec2", c.region, time.Now())
+	if err != nil {
+		return errors.Wrap(ctx, err, "sign request")
+	}
+
+	httpRes, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(ctx, err, "send request")
+	}
+	defer httpRes.Body.Close()
+
+	resBody, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read response")
+	}
+
+	if httpRes.StatusCode != http.StatusOK {
+		apiErr := APIError{StatusCode: httpRes.StatusCode, Code: http.StatusText(httpRes.StatusCode)}
+		var errRes errorResponse
+		if xml.Unmarshal(resBody, &errRes) == nil && len(errRes.Errors) > 0 {
+			apiErr.Code = errRes.Errors[0].Code
+			apiErr.Message = errRes.Errors[0].Message
+		}
+		return apiErr
+	}
+
+	if res == nil {
+		return nil
+	}
+	err = xml.Unmarshal(resBody, res)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "decode %s response", action)
+	}
+	return nil
+}
+
+// setList sets the parameters of a list, using the numbered format of the EC2 Query API
+// (e.g. PrivateIpAddress.1, PrivateIpAddress.2)
+func setList(params url.Values, name string, values []string) {
+	for i, value := range values {
+		params.Set(fmt.Sprintf("%s.%d", name, i+1), value)
+	}
+}
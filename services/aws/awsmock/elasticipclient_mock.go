@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/aws (interfaces: ElasticIPClient)
+
+// Package awsmock is a generated GoMock package.
+package awsmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	aws "github.com/Scalingo/link/v3/services/aws"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockElasticIPClient is a mock of ElasticIPClient interface.
+type MockElasticIPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockElasticIPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockElasticIPClientMockRecorder is the mock recorder for MockElasticIPClient.
+type MockElasticIPClientMockRecorder struct {
+	mock *MockElasticIPClient
+}
+
+// NewMockElasticIPClient creates a new mock instance.
+func NewMockElasticIPClient(ctrl *gomock.Controller) *MockElasticIPClient {
+	mock := &MockElasticIPClient{ctrl: ctrl}
+	mock.recorder = &MockElasticIPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElasticIPClient) EXPECT() *MockElasticIPClientMockRecorder {
+	return m.recorder
+}
+
+// AssociateAddress mocks base method.
+func (m *MockElasticIPClient) AssociateAddress(ctx context.Context, params aws.AssociateAddressParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateAddress", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateAddress indicates an expected call of AssociateAddress.
+func (mr *MockElasticIPClientMockRecorder) AssociateAddress(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateAddress", reflect.TypeOf((*MockElasticIPClient)(nil).AssociateAddress), ctx, params)
+}
+
+// DescribeAddress mocks base method.
+func (m *MockElasticIPClient) DescribeAddress(ctx context.Context, allocationID string) (aws.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAddress", ctx, allocationID)
+	ret0, _ := ret[0].(aws.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAddress indicates an expected call of DescribeAddress.
+func (mr *MockElasticIPClientMockRecorder) DescribeAddress(ctx, allocationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAddress", reflect.TypeOf((*MockElasticIPClient)(nil).DescribeAddress), ctx, allocationID)
+}
+
+// DisassociateAddress mocks base method.
+func (m *MockElasticIPClient) DisassociateAddress(ctx context.Context, associationID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisassociateAddress", ctx, associationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisassociateAddress indicates an expected call of DisassociateAddress.
+func (mr *MockElasticIPClientMockRecorder) DisassociateAddress(ctx, associationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateAddress", reflect.TypeOf((*MockElasticIPClient)(nil).DisassociateAddress), ctx, associationID)
+}
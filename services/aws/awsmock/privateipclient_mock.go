@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/aws (interfaces: PrivateIPClient)
+
+// Package awsmock is a generated GoMock package.
+package awsmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	aws "github.com/Scalingo/link/v3/services/aws"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPrivateIPClient is a mock of PrivateIPClient interface.
+type MockPrivateIPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivateIPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockPrivateIPClientMockRecorder is the mock recorder for MockPrivateIPClient.
+type MockPrivateIPClientMockRecorder struct {
+	mock *MockPrivateIPClient
+}
+
+// NewMockPrivateIPClient creates a new mock instance.
+func NewMockPrivateIPClient(ctrl *gomock.Controller) *MockPrivateIPClient {
+	mock := &MockPrivateIPClient{ctrl: ctrl}
+	mock.recorder = &MockPrivateIPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivateIPClient) EXPECT() *MockPrivateIPClientMockRecorder {
+	return m.recorder
+}
+
+// AssignPrivateIPAddresses mocks base method.
+func (m *MockPrivateIPClient) AssignPrivateIPAddresses(ctx context.Context, params aws.AssignPrivateIPAddressesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignPrivateIPAddresses", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignPrivateIPAddresses indicates an expected call of AssignPrivateIPAddresses.
+func (mr *MockPrivateIPClientMockRecorder) AssignPrivateIPAddresses(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignPrivateIPAddresses", reflect.TypeOf((*MockPrivateIPClient)(nil).AssignPrivateIPAddresses), ctx, params)
+}
+
+// DescribeNetworkInterface mocks base method.
+func (m *MockPrivateIPClient) DescribeNetworkInterface(ctx context.Context, networkInterfaceID string) (aws.NetworkInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeNetworkInterface", ctx, networkInterfaceID)
+	ret0, _ := ret[0].(aws.NetworkInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNetworkInterface indicates an expected call of DescribeNetworkInterface.
+func (mr *MockPrivateIPClientMockRecorder) DescribeNetworkInterface(ctx, networkInterfaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNetworkInterface", reflect.TypeOf((*MockPrivateIPClient)(nil).DescribeNetworkInterface), ctx, networkInterfaceID)
+}
+
+// UnassignPrivateIPAddresses mocks base method.
+func (m *MockPrivateIPClient) UnassignPrivateIPAddresses(ctx context.Context, networkInterfaceID string, privateIPAddresses []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignPrivateIPAddresses", ctx, networkInterfaceID, privateIPAddresses)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignPrivateIPAddresses indicates an expected call of UnassignPrivateIPAddresses.
+func (mr *MockPrivateIPClientMockRecorder) UnassignPrivateIPAddresses(ctx, networkInterfaceID, privateIPAddresses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignPrivateIPAddresses", reflect.TypeOf((*MockPrivateIPClient)(nil).UnassignPrivateIPAddresses), ctx, networkInterfaceID, privateIPAddresses)
+}
@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/models"
+)
+
+var (
+	accessKeyRegex = regexp.MustCompile(`^[A-Z0-9]{16,128}$`)
+	secretKeyRegex = regexp.MustCompile(`^[A-Za-z0-9/+=]{16,128}$`)
+	regionRegex    = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]$`)
+)
+
+// IDRegex matches the IDs of the EC2 resources (e.g. eni-0123456789abcdef0)
+var IDRegex = regexp.MustCompile(`^[a-z]+-[a-f0-9]{8,17}$`)
+
+// ValidateCredentials checks the format of the credentials sent in the configuration of an
+// endpoint
+func ValidateCredentials(validations *errors.ValidationErrorsBuilder, accessKey, secretKey, region string) {
+	if accessKey == "" {
+		validations.Set("plugin_config.access_key", "missing access key")
+	}
+	if accessKey != "" && !accessKeyRegex.MatchString(accessKey) {
+		validations.Set("plugin_config.access_key", "invalid access key format")
+	}
+
+	if secretKey == "" {
+		validations.Set("plugin_config.secret_key", "missing secret key")
+	}
+	if secretKey != "" && !secretKeyRegex.MatchString(secretKey) {
+		validations.Set("plugin_config.secret_key", "invalid secret key format")
+	}
+
+	if region == "" {
+		validations.Set("plugin_config.region", "missing region")
+	}
+	if region != "" && !regionRegex.MatchString(region) {
+		validations.Set("plugin_config.region", "invalid region: "+region)
+	}
+}
+
+// StorableCredentials are the credentials of an endpoint as saved in the storage, the keys being
+// encrypted
+type StorableCredentials struct {
+	AccessKey models.EncryptedDataLink `json:"access_key"`
+	SecretKey models.EncryptedDataLink `json:"secret_key"`
+	Region    string                   `json:"region"`
+}
+
+// EncryptCredentials encrypts the keys of an endpoint before saving its configuration
+func EncryptCredentials(ctx context.Context, encryptedStorage models.EncryptedStorage, endpointID, accessKey, secretKey, region string) (StorableCredentials, error) {
+	credentials := StorableCredentials{Region: region}
+
+	var err error
+	credentials.AccessKey, err = encryptedStorage.Encrypt(ctx, endpointID, accessKey)
+	if err != nil {
+		return StorableCredentials{}, errors.Wrap(ctx, err, "encrypt access key")
+	}
+	credentials.SecretKey, err = encryptedStorage.Encrypt(ctx, endpointID, secretKey)
+	if err != nil {
+		return StorableCredentials{}, errors.Wrap(ctx, err, "encrypt secret key")
+	}
+	return credentials, nil
+}
+
+// NewClientFromCredentials decrypts the keys of an endpoint and returns a client using them
+func NewClientFromCredentials(ctx context.Context, encryptedStorage models.EncryptedStorage, credentials StorableCredentials, endpointURL string) (*APIClient, error) {
+	var accessKey, secretKey string
+	err := encryptedStorage.Decrypt(ctx, credentials.AccessKey, &accessKey)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt access key")
+	}
+	err = encryptedStorage.Decrypt(ctx, credentials.SecretKey, &secretKey)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt secret key")
+	}
+	return NewClient(accessKey, secretKey, credentials.Region, endpointURL), nil
+}
@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ ElasticIPClient = (*APIClient)(nil)
+
+type ElasticIPClient interface {
+	AssociateAddress(ctx context.Context, params AssociateAddressParams) (string, error)
+	DisassociateAddress(ctx context.Context, associationID string) error
+	DescribeAddress(ctx context.Context, allocationID string) (Address, error)
+}
+
+type AssociateAddressParams struct {
+	AllocationID       string
+	NetworkInterfaceID string
+	// PrivateIPAddress is the private IP of the ENI associated with the Elastic IP, the primary
+	// private IP if it is empty
+	PrivateIPAddress   string
+	AllowReassociation bool
+}
+
+// Address is an Elastic IP, the association fields are empty if it is not associated
+type Address struct {
+	AllocationID       string `xml:"allocationId"`
+	PublicIP           string `xml:"publicIp"`
+	AssociationID      string `xml:"associationId"`
+	NetworkInterfaceID string `xml:"networkInterfaceId"`
+	PrivateIPAddress   string `xml:"privateIpAddress"`
+}
+
+// AssociateAddress associates an Elastic IP with an ENI and returns the ID of the association
+func (c *APIClient) AssociateAddress(ctx context.Context, params AssociateAddressParams) (string, error) {
+	values := url.Values{}
+	values.Set("AllocationId", params.AllocationID)
+	values.Set("NetworkInterfaceId", params.NetworkInterfaceID)
+	if params.PrivateIPAddress != "" {
+		values.Set("PrivateIpAddress", params.PrivateIPAddress)
+	}
+	values.Set("AllowReassociation", strconv.FormatBool(params.AllowReassociation))
+
+	var res struct {
+		AssociationID string `xml:"associationId"`
+	}
+	err := c.do(ctx, "AssociateAddress", values, &res)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "associate address")
+	}
+	return res.AssociationID, nil
+}
+
+func (c *APIClient) DisassociateAddress(ctx context.Context, associationID string) error {
+	values := url.Values{}
+	values.Set("AssociationId", associationID)
+
+	err := c.do(ctx, "DisassociateAddress", values, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "disassociate address")
+	}
+	return nil
+}
+
+func (c *APIClient) DescribeAddress(ctx context.Context, allocationID string) (Address, error) {
+	values := url.Values{}
+	setList(values, "AllocationId", []string{allocationID})
+
+	var res struct {
+		Addresses []Address `xml:"addressesSet>item"`
+	}
+	err := c.do(ctx, "DescribeAddresses", values, &res)
+	if err != nil {
+		return Address{}, errors.Wrap(ctx, err, "describe address")
+	}
+
+	if len(res.Addresses) != 1 {
+		return Address{}, errors.Newf(ctx, "invalid number of addresses returned: %d", len(res.Addresses))
+	}
+
+	return res.Addresses[0], nil
+}
@@ -0,0 +1,78 @@
+package aws
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ PrivateIPClient = (*APIClient)(nil)
+
+type PrivateIPClient interface {
+	AssignPrivateIPAddresses(ctx context.Context, params AssignPrivateIPAddressesParams) error
+	UnassignPrivateIPAddresses(ctx context.Context, networkInterfaceID string, privateIPAddresses []string) error
+	DescribeNetworkInterface(ctx context.Context, networkInterfaceID string) (NetworkInterface, error)
+}
+
+type AssignPrivateIPAddressesParams struct {
+	NetworkInterfaceID string
+	PrivateIPAddresses []string
+	// AllowReassignment moves the private IPs already assigned to another ENI
+	AllowReassignment bool
+}
+
+type NetworkInterface struct {
+	NetworkInterfaceID string             `xml:"networkInterfaceId"`
+	PrivateIPAddresses []PrivateIPAddress `xml:"privateIpAddressesSet>item"`
+}
+
+type PrivateIPAddress struct {
+	PrivateIPAddress string `xml:"privateIpAddress"`
+	Primary          bool   `xml:"primary"`
+}
+
+func (c *APIClient) AssignPrivateIPAddresses(ctx context.Context, params AssignPrivateIPAddressesParams) error {
+	values := url.Values{}
+	values.Set("NetworkInterfaceId", params.NetworkInterfaceID)
+	setList(values, "PrivateIpAddress", params.PrivateIPAddresses)
+	values.Set("AllowReassignment", strconv.FormatBool(params.AllowReassignment))
+
+	err := c.do(ctx, "AssignPrivateIpAddresses", values, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "assign private IP addresses")
+	}
+	return nil
+}
+
+func (c *APIClient) UnassignPrivateIPAddresses(ctx context.Context, networkInterfaceID string, privateIPAddresses []string) error {
+	values := url.Values{}
+	values.Set("NetworkInterfaceId", networkInterfaceID)
+	setList(values, "PrivateIpAddress", privateIPAddresses)
+
+	err := c.do(ctx, "UnassignPrivateIpAddresses", values, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unassign private IP addresses")
+	}
+	return nil
+}
+
+func (c *APIClient) DescribeNetworkInterface(ctx context.Context, networkInterfaceID string) (NetworkInterface, error) {
+	values := url.Values{}
+	setList(values, "NetworkInterfaceId", []string{networkInterfaceID})
+
+	var res struct {
+		NetworkInterfaces []NetworkInterface `xml:"networkInterfaceSet>item"`
+	}
+	err := c.do(ctx, "DescribeNetworkInterfaces", values, &res)
+	if err != nil {
+		return NetworkInterface{}, errors.Wrap(ctx, err, "describe network interface")
+	}
+
+	if len(res.NetworkInterfaces) != 1 {
+		return NetworkInterface{}, errors.Newf(ctx, "invalid number of network interfaces returned: %d", len(res.NetworkInterfaces))
+	}
+
+	return res.NetworkInterfaces[0], nil
+}
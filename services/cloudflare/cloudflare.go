@@ -0,0 +1,141 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// DefaultAPIURL is the base URL of the Cloudflare API
+const DefaultAPIURL = "https://api.cloudflare.com/client/v4"
+
+type APIClient struct {
+	httpClient *http.Client
+
+	// Client Configuration
+	apiToken string
+	apiURL   string
+	zone     string // Name of the zone, without the trailing dot
+
+	// zoneID is looked up with the name of the zone on the first request
+	zoneIDMutex sync.Mutex
+	zoneID      string
+}
+
+// NewClient returns a client of the Cloudflare API managing the records of the zone, authenticated
+// with the API token. If apiURL is empty, DefaultAPIURL is used.
+func NewClient(apiToken, zone, apiURL string) *APIClient {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	return &APIClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		apiToken:   apiToken,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		zone:       strings.TrimSuffix(zone, "."),
+	}
+}
+
+// APIError is an error returned by the Cloudflare API
+type APIError struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%d (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// response is the envelope of all the responses of the Cloudflare API
+type response struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+// do sends the request with the JSON encoded body, if any, and decodes the result of the response
+// in res
+func (c *APIClient) do(ctx context.Context, method, path string, body, res any) error {
+	var reqBody io.Reader
+	if body != nil {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(ctx, err, "encode request")
+		}
+		reqBody = bytes.NewReader(rawBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, reqBody)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create request")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpRes, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(ctx, err, "send request")
+	}
+	defer httpRes.Body.Close()
+
+	var envelope response
+	decodeErr := json.NewDecoder(httpRes.Body).Decode(&envelope)
+	if httpRes.StatusCode < 200 || httpRes.StatusCode >= 300 || (decodeErr == nil && !envelope.Success) {
+		apiErr := APIError{StatusCode: httpRes.StatusCode, Message: http.StatusText(httpRes.StatusCode)}
+		if decodeErr == nil && len(envelope.Errors) > 0 {
+			apiErr.Code = envelope.Errors[0].Code
+			apiErr.Message = envelope.Errors[0].Message
+		}
+		return apiErr
+	}
+	if decodeErr != nil {
+		return errors.Wrapf(ctx, decodeErr, "decode %s %s response", method, path)
+	}
+
+	if res == nil {
+		return nil
+	}
+	err = json.Unmarshal(envelope.Result, res)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "decode %s %s result", method, path)
+	}
+	return nil
+}
+
+// getZoneID returns the ID of the zone, looked up once with its name
+func (c *APIClient) getZoneID(ctx context.Context) (string, error) {
+	c.zoneIDMutex.Lock()
+	defer c.zoneIDMutex.Unlock()
+	if c.zoneID != "" {
+		return c.zoneID, nil
+	}
+
+	var zones []struct {
+		ID string `json:"id"`
+	}
+	err := c.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(c.zone), nil, &zones)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "list zones")
+	}
+	if len(zones) != 1 {
+		return "", errors.Newf(ctx, "zone %s not found", c.zone)
+	}
+	c.zoneID = zones[0].ID
+	return c.zoneID, nil
+}
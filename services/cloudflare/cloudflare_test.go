@@ -0,0 +1,92 @@
+package cloudflare
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+const (
+	testAPIToken = "YQSn-xWAQiiEh9qM58wZNnyQS7FUdoqGIUAbrh7T"
+	testZoneID   = "023e105f4ecef8ad9ca31a8372d0c353"
+)
+
+func TestAPIClient_Records(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer "+testAPIToken, r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+
+		switch r.Method + " " + r.URL.RequestURI() {
+		case "GET /client/v4/zones?name=example.com":
+			_, _ = w.Write([]byte(`{"success": true, "errors": [], "result": [{"id": "` + testZoneID + `", "name": "example.com"}]}`))
+		case "GET /client/v4/zones/" + testZoneID + "/dns_records?name=www.example.com&type=A":
+			_, _ = w.Write([]byte(`{"success": true, "errors": [], "result": [
+				{"id": "record-1", "type": "A", "name": "www.example.com", "content": "192.0.2.2", "ttl": 1, "proxied": true},
+				{"id": "record-2", "type": "A", "name": "www.example.com", "content": "192.0.2.3", "ttl": 300, "proxied": false}
+			]}`))
+		case "GET /client/v4/zones/" + testZoneID + "/dns_records?name=api.example.com&type=AAAA":
+			_, _ = w.Write([]byte(`{"success": true, "errors": [], "result": []}`))
+		case "PATCH /client/v4/zones/" + testZoneID + "/dns_records/record-1",
+			"DELETE /client/v4/zones/" + testZoneID + "/dns_records/record-2",
+			"POST /client/v4/zones/" + testZoneID + "/dns_records":
+			_, _ = w.Write([]byte(`{"success": true, "errors": [], "result": {}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"success": false, "errors": [{"code": 7003, "message": "Could not route to /zones, perhaps your object identifier is invalid?"}], "result": null}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testAPIToken, "example.com.", server.URL+"/client/v4/")
+
+	records, err := client.GetRecords(ctx, "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Equal(t, []dnsprovider.Record{
+		{Name: "www.example.com.", Type: "A", TTL: 0, Value: "192.0.2.2"},
+		{Name: "www.example.com.", Type: "A", TTL: 300, Value: "192.0.2.3"},
+	}, records, "the TTL of a proxied record should be reported as managed by Cloudflare")
+
+	// The first record is updated, keeping its proxy status, and the other one is deleted
+	requests = nil
+	err = client.ReplaceRecord(ctx, dnsprovider.Record{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /client/v4/zones/" + testZoneID + "/dns_records?name=www.example.com&type=A ",
+		"PATCH /client/v4/zones/" + testZoneID + `/dns_records/record-1 {"type":"A","name":"www.example.com","content":"192.0.2.1","ttl":60}`,
+		"DELETE /client/v4/zones/" + testZoneID + "/dns_records/record-2 ",
+	}, requests, "the zone ID should only be looked up once")
+
+	// A missing record is created
+	requests = nil
+	err = client.ReplaceRecord(ctx, dnsprovider.Record{Name: "api.example.com.", Type: "AAAA", TTL: 60, Value: "2001:db8::1"})
+	require.NoError(t, err)
+	assert.Equal(t, "POST /client/v4/zones/"+testZoneID+`/dns_records {"type":"AAAA","name":"api.example.com","content":"2001:db8::1","ttl":60}`, requests[1])
+
+	_, err = client.GetRecords(ctx, "other.example.com.", "A")
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 7003, apiErr.Code)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestAPIClient_UnknownZone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"success": true, "errors": [], "result": []}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testAPIToken, "example.org", server.URL)
+
+	_, err := client.GetRecords(context.Background(), "www.example.org.", "A")
+	require.ErrorContains(t, err, "zone example.org not found")
+}
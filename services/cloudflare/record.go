@@ -0,0 +1,104 @@
+package cloudflare
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+var _ dnsprovider.Provider = (*APIClient)(nil)
+
+// dnsRecord is a DNS record of the Cloudflare API, its name has no trailing dot
+type dnsRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     uint32 `json:"ttl"`
+	// Proxied is omitted from the updates, so that the proxy status of the record is kept
+	Proxied bool `json:"proxied,omitempty"`
+}
+
+// listRecords returns the records with the name and type
+func (c *APIClient) listRecords(ctx context.Context, zoneID, name, recordType string) ([]dnsRecord, error) {
+	query := url.Values{}
+	query.Set("name", strings.TrimSuffix(name, "."))
+	query.Set("type", recordType)
+
+	var records []dnsRecord
+	err := c.do(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+query.Encode(), nil, &records)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "list DNS records")
+	}
+	return records, nil
+}
+
+// ReplaceRecord updates the first record with the name and type of the record, or creates it, and
+// deletes the other ones. The other settings of an updated record (e.g. the proxy status) are kept.
+func (c *APIClient) ReplaceRecord(ctx context.Context, record dnsprovider.Record) error {
+	zoneID, err := c.getZoneID(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get zone ID")
+	}
+	records, err := c.listRecords(ctx, zoneID, record.Name, record.Type)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get current records")
+	}
+
+	body := dnsRecord{
+		Type:    record.Type,
+		Name:    strings.TrimSuffix(record.Name, "."),
+		Content: record.Value,
+		TTL:     record.TTL,
+	}
+	if len(records) == 0 {
+		err = c.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", body, nil)
+		if err != nil {
+			return errors.Wrap(ctx, err, "create DNS record")
+		}
+		return nil
+	}
+
+	err = c.do(ctx, http.MethodPatch, "/zones/"+zoneID+"/dns_records/"+records[0].ID, body, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "update DNS record")
+	}
+	for _, other := range records[1:] {
+		err = c.do(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+other.ID, nil, nil)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "delete DNS record %s", other.Content)
+		}
+	}
+	return nil
+}
+
+func (c *APIClient) GetRecords(ctx context.Context, name, recordType string) ([]dnsprovider.Record, error) {
+	zoneID, err := c.getZoneID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get zone ID")
+	}
+	records, err := c.listRecords(ctx, zoneID, name, recordType)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get records")
+	}
+
+	res := make([]dnsprovider.Record, 0, len(records))
+	for _, record := range records {
+		ttl := record.TTL
+		if record.Proxied {
+			// The TTL of a proxied record is always automatic, Cloudflare reports 1
+			ttl = 0
+		}
+		res = append(res, dnsprovider.Record{
+			Name:  record.Name + ".",
+			Type:  record.Type,
+			TTL:   ttl,
+			Value: record.Content,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/dnsprovider (interfaces: Provider)
+
+// Package dnsprovidermock is a generated GoMock package.
+package dnsprovidermock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dnsprovider "github.com/Scalingo/link/v3/services/dnsprovider"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProvider is a mock of Provider interface.
+type MockProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockProviderMockRecorder is the mock recorder for MockProvider.
+type MockProviderMockRecorder struct {
+	mock *MockProvider
+}
+
+// NewMockProvider creates a new mock instance.
+func NewMockProvider(ctrl *gomock.Controller) *MockProvider {
+	mock := &MockProvider{ctrl: ctrl}
+	mock.recorder = &MockProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProvider) EXPECT() *MockProviderMockRecorder {
+	return m.recorder
+}
+
+// GetRecords mocks base method.
+func (m *MockProvider) GetRecords(ctx context.Context, name, recordType string) ([]dnsprovider.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecords", ctx, name, recordType)
+	ret0, _ := ret[0].([]dnsprovider.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecords indicates an expected call of GetRecords.
+func (mr *MockProviderMockRecorder) GetRecords(ctx, name, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecords", reflect.TypeOf((*MockProvider)(nil).GetRecords), ctx, name, recordType)
+}
+
+// ReplaceRecord mocks base method.
+func (m *MockProvider) ReplaceRecord(ctx context.Context, record dnsprovider.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecord indicates an expected call of ReplaceRecord.
+func (mr *MockProviderMockRecorder) ReplaceRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecord", reflect.TypeOf((*MockProvider)(nil).ReplaceRecord), ctx, record)
+}
@@ -0,0 +1,22 @@
+package dnsprovider
+
+import "context"
+
+// Provider manages the records of a zone hosted by a DNS provider with its HTTP API
+type Provider interface {
+	// ReplaceRecord replaces all the records with the name and type of the record by the record
+	ReplaceRecord(ctx context.Context, record Record) error
+	// GetRecords returns the records with the name and type
+	GetRecords(ctx context.Context, name, recordType string) ([]Record, error)
+}
+
+// Record is an A or AAAA record, or a CNAME record for the providers supporting it
+type Record struct {
+	// Name is the fully qualified name of the record, with the trailing dot
+	Name string
+	Type string
+	// TTL is 0 if it is managed by the provider, in this case it can not be configured
+	TTL uint32
+	// Value is the IP of an A or AAAA record, or the fully qualified target of a CNAME record
+	Value string
+}
@@ -0,0 +1,19 @@
+package hetzner
+
+import (
+	"regexp"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var apiTokenRegex = regexp.MustCompile(`^[A-Za-z0-9]{64}$`)
+
+// ValidateAPIToken checks the format of the API token sent in the configuration of an endpoint
+func ValidateAPIToken(validations *errors.ValidationErrorsBuilder, apiToken string) {
+	if apiToken == "" {
+		validations.Set("plugin_config.api_token", "missing API token")
+	}
+	if apiToken != "" && !apiTokenRegex.MatchString(apiToken) {
+		validations.Set("plugin_config.api_token", "invalid API token format")
+	}
+}
@@ -0,0 +1,62 @@
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ FloatingIPClient = (*APIClient)(nil)
+
+type FloatingIPClient interface {
+	AssignFloatingIP(ctx context.Context, floatingIPID, serverID int64) error
+	UnassignFloatingIP(ctx context.Context, floatingIPID int64) error
+	GetFloatingIP(ctx context.Context, floatingIPID int64) (FloatingIP, error)
+}
+
+// FloatingIP is a Hetzner Cloud floating IP, Server is nil if it is not assigned
+type FloatingIP struct {
+	ID     int64  `json:"id"`
+	IP     string `json:"ip"`
+	Server *int64 `json:"server"`
+}
+
+// ServerID returns the ID of the server the floating IP is assigned to, 0 if it is not assigned
+func (ip FloatingIP) ServerID() int64 {
+	if ip.Server == nil {
+		return 0
+	}
+	return *ip.Server
+}
+
+// AssignFloatingIP assigns the floating IP to the server, unassigning it from the server it was
+// assigned to. The assignment is done asynchronously by the API.
+func (c *APIClient) AssignFloatingIP(ctx context.Context, floatingIPID, serverID int64) error {
+	body := map[string]int64{"server": serverID}
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/floating_ips/%d/actions/assign", floatingIPID), body, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "assign floating IP")
+	}
+	return nil
+}
+
+func (c *APIClient) UnassignFloatingIP(ctx context.Context, floatingIPID int64) error {
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/floating_ips/%d/actions/unassign", floatingIPID), nil, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "unassign floating IP")
+	}
+	return nil
+}
+
+func (c *APIClient) GetFloatingIP(ctx context.Context, floatingIPID int64) (FloatingIP, error) {
+	var res struct {
+		FloatingIP FloatingIP `json:"floating_ip"`
+	}
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/floating_ips/%d", floatingIPID), nil, &res)
+	if err != nil {
+		return FloatingIP{}, errors.Wrap(ctx, err, "get floating IP")
+	}
+	return res.FloatingIP, nil
+}
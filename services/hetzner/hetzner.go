@@ -0,0 +1,103 @@
+package hetzner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// DefaultAPIURL is the base URL of the Hetzner Cloud API
+const DefaultAPIURL = "https://api.hetzner.cloud/v1"
+
+type APIClient struct {
+	httpClient *http.Client
+
+	// Client Configuration
+	apiToken string
+	apiURL   string
+}
+
+// NewClient returns a client of the Hetzner Cloud API authenticated with the API token. If apiURL
+// is empty, DefaultAPIURL is used.
+func NewClient(apiToken, apiURL string) *APIClient {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	return &APIClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		apiToken:   apiToken,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+	}
+}
+
+// APIError is an error returned by the Hetzner Cloud API
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// do sends the request with the JSON encoded body, if any, and decodes the JSON response in res
+func (c *APIClient) do(ctx context.Context, method, path string, body, res any) error {
+	var reqBody io.Reader
+	if body != nil {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(ctx, err, "encode request")
+		}
+		reqBody = bytes.NewReader(rawBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, reqBody)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create request")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpRes, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(ctx, err, "send request")
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode < 200 || httpRes.StatusCode >= 300 {
+		apiErr := APIError{StatusCode: httpRes.StatusCode, Code: http.StatusText(httpRes.StatusCode)}
+		var errRes errorResponse
+		if json.NewDecoder(httpRes.Body).Decode(&errRes) == nil && errRes.Error.Code != "" {
+			apiErr.Code = errRes.Error.Code
+			apiErr.Message = errRes.Error.Message
+		}
+		return apiErr
+	}
+
+	if res == nil {
+		return nil
+	}
+	err = json.NewDecoder(httpRes.Body).Decode(res)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "decode %s %s response", method, path)
+	}
+	return nil
+}
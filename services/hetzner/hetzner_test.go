@@ -0,0 +1,60 @@
+package hetzner
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIToken = "jEheVytlAoFl7F8MqUQ7jAo2hOXASztX1S2e4wJVQ6SrTDzZmMi2d2Q0ZyJyxMXW"
+
+func TestAPIClient_FloatingIP(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer "+testAPIToken, r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/floating_ips/4711/actions/assign":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"action": {"id": 13, "command": "assign_floating_ip", "status": "running"}}`))
+		case "GET /v1/floating_ips/4711":
+			_, _ = w.Write([]byte(`{"floating_ip": {"id": 4711, "ip": "203.0.113.1", "type": "ipv4", "server": 42}}`))
+		case "GET /v1/floating_ips/4712":
+			_, _ = w.Write([]byte(`{"floating_ip": {"id": 4712, "ip": "203.0.113.2", "type": "ipv4", "server": null}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "not_found", "message": "floating_ip with ID '4713' not found"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testAPIToken, server.URL+"/v1/")
+
+	err := client.AssignFloatingIP(ctx, 4711, 42)
+	require.NoError(t, err)
+	assert.Equal(t, `POST /v1/floating_ips/4711/actions/assign {"server":42}`, requests[0])
+
+	floatingIP, err := client.GetFloatingIP(ctx, 4711)
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.1", floatingIP.IP)
+	assert.Equal(t, int64(42), floatingIP.ServerID())
+
+	floatingIP, err = client.GetFloatingIP(ctx, 4712)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), floatingIP.ServerID())
+
+	err = client.UnassignFloatingIP(ctx, 4713)
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "not_found", apiErr.Code)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/hetzner (interfaces: FloatingIPClient)
+
+// Package hetznermock is a generated GoMock package.
+package hetznermock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	hetzner "github.com/Scalingo/link/v3/services/hetzner"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFloatingIPClient is a mock of FloatingIPClient interface.
+type MockFloatingIPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFloatingIPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockFloatingIPClientMockRecorder is the mock recorder for MockFloatingIPClient.
+type MockFloatingIPClientMockRecorder struct {
+	mock *MockFloatingIPClient
+}
+
+// NewMockFloatingIPClient creates a new mock instance.
+func NewMockFloatingIPClient(ctrl *gomock.Controller) *MockFloatingIPClient {
+	mock := &MockFloatingIPClient{ctrl: ctrl}
+	mock.recorder = &MockFloatingIPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFloatingIPClient) EXPECT() *MockFloatingIPClientMockRecorder {
+	return m.recorder
+}
+
+// AssignFloatingIP mocks base method.
+func (m *MockFloatingIPClient) AssignFloatingIP(ctx context.Context, floatingIPID, serverID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignFloatingIP", ctx, floatingIPID, serverID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignFloatingIP indicates an expected call of AssignFloatingIP.
+func (mr *MockFloatingIPClientMockRecorder) AssignFloatingIP(ctx, floatingIPID, serverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignFloatingIP", reflect.TypeOf((*MockFloatingIPClient)(nil).AssignFloatingIP), ctx, floatingIPID, serverID)
+}
+
+// GetFloatingIP mocks base method.
+func (m *MockFloatingIPClient) GetFloatingIP(ctx context.Context, floatingIPID int64) (hetzner.FloatingIP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFloatingIP", ctx, floatingIPID)
+	ret0, _ := ret[0].(hetzner.FloatingIP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFloatingIP indicates an expected call of GetFloatingIP.
+func (mr *MockFloatingIPClientMockRecorder) GetFloatingIP(ctx, floatingIPID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloatingIP", reflect.TypeOf((*MockFloatingIPClient)(nil).GetFloatingIP), ctx, floatingIPID)
+}
+
+// UnassignFloatingIP mocks base method.
+func (m *MockFloatingIPClient) UnassignFloatingIP(ctx context.Context, floatingIPID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignFloatingIP", ctx, floatingIPID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignFloatingIP indicates an expected call of UnassignFloatingIP.
+func (mr *MockFloatingIPClientMockRecorder) UnassignFloatingIP(ctx, floatingIPID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignFloatingIP", reflect.TypeOf((*MockFloatingIPClient)(nil).UnassignFloatingIP), ctx, floatingIPID)
+}
@@ -0,0 +1,81 @@
+package openstack
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/models"
+)
+
+var applicationCredentialIDRegex = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// IDRegex matches the IDs of the Neutron resources, which are UUIDs
+var IDRegex = regexp.MustCompile(`^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$`)
+
+// ValidateCredentials checks the Keystone URL and the format of the application credential sent in
+// the configuration of an endpoint
+func ValidateCredentials(validations *errors.ValidationErrorsBuilder, authURL, applicationCredentialID, applicationCredentialSecret string) {
+	if authURL == "" {
+		validations.Set("plugin_config.auth_url", "missing auth URL")
+	}
+	if authURL != "" {
+		u, err := url.Parse(authURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			validations.Set("plugin_config.auth_url", "invalid auth URL, must be the HTTP(S) URL of the Keystone v3 API")
+		}
+	}
+
+	if applicationCredentialID == "" {
+		validations.Set("plugin_config.application_credential_id", "missing application credential ID")
+	}
+	if applicationCredentialID != "" && !applicationCredentialIDRegex.MatchString(applicationCredentialID) {
+		validations.Set("plugin_config.application_credential_id", "invalid application credential ID format")
+	}
+
+	if applicationCredentialSecret == "" {
+		validations.Set("plugin_config.application_credential_secret", "missing application credential secret")
+	}
+}
+
+// StorableCredentials are the credentials of an endpoint as saved in the storage, the application
+// credential being encrypted
+type StorableCredentials struct {
+	AuthURL                     string                   `json:"auth_url"`
+	ApplicationCredentialID     models.EncryptedDataLink `json:"application_credential_id"`
+	ApplicationCredentialSecret models.EncryptedDataLink `json:"application_credential_secret"`
+	Region                      string                   `json:"region,omitempty"`
+}
+
+// EncryptCredentials encrypts the application credential of an endpoint before saving its
+// configuration
+func EncryptCredentials(ctx context.Context, encryptedStorage models.EncryptedStorage, endpointID, authURL, applicationCredentialID, applicationCredentialSecret, region string) (StorableCredentials, error) {
+	credentials := StorableCredentials{AuthURL: authURL, Region: region}
+
+	var err error
+	credentials.ApplicationCredentialID, err = encryptedStorage.Encrypt(ctx, endpointID, applicationCredentialID)
+	if err != nil {
+		return StorableCredentials{}, errors.Wrap(ctx, err, "encrypt application credential ID")
+	}
+	credentials.ApplicationCredentialSecret, err = encryptedStorage.Encrypt(ctx, endpointID, applicationCredentialSecret)
+	if err != nil {
+		return StorableCredentials{}, errors.Wrap(ctx, err, "encrypt application credential secret")
+	}
+	return credentials, nil
+}
+
+// NewClientFromCredentials decrypts the application credential of an endpoint and returns a client
+// using it
+func NewClientFromCredentials(ctx context.Context, encryptedStorage models.EncryptedStorage, credentials StorableCredentials, networkURL string) (*APIClient, error) {
+	var applicationCredentialID, applicationCredentialSecret string
+	err := encryptedStorage.Decrypt(ctx, credentials.ApplicationCredentialID, &applicationCredentialID)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt application credential ID")
+	}
+	err = encryptedStorage.Decrypt(ctx, credentials.ApplicationCredentialSecret, &applicationCredentialSecret)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt application credential secret")
+	}
+	return NewClient(credentials.AuthURL, applicationCredentialID, applicationCredentialSecret, credentials.Region, networkURL), nil
+}
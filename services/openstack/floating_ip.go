@@ -0,0 +1,67 @@
+package openstack
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ FloatingIPClient = (*APIClient)(nil)
+
+type FloatingIPClient interface {
+	AssociateFloatingIP(ctx context.Context, floatingIPID, portID, fixedIP string) error
+	DisassociateFloatingIP(ctx context.Context, floatingIPID string) error
+	GetFloatingIP(ctx context.Context, floatingIPID string) (FloatingIP, error)
+}
+
+// FloatingIP is a Neutron floating IP, the port fields are empty if it is not associated
+type FloatingIP struct {
+	ID                string `json:"id"`
+	FloatingIPAddress string `json:"floating_ip_address"`
+	PortID            string `json:"port_id"`
+	FixedIPAddress    string `json:"fixed_ip_address"`
+}
+
+type floatingIPUpdate struct {
+	// PortID is the port the floating IP is associated with, the floating IP is disassociated if it
+	// is nil
+	PortID         *string `json:"port_id"`
+	FixedIPAddress string  `json:"fixed_ip_address,omitempty"`
+}
+
+// AssociateFloatingIP associates the floating IP with the port, moving it from the port it was
+// associated with. If fixedIP is empty, Neutron uses the IPv4 of the port, which
+// must then be unique.
+func (c *APIClient) AssociateFloatingIP(ctx context.Context, floatingIPID, portID, fixedIP string) error {
+	body := map[string]floatingIPUpdate{
+		"floatingip": {PortID: &portID, FixedIPAddress: fixedIP},
+	}
+	err := c.do(ctx, http.MethodPut, "/v2.0/floatingips/"+floatingIPID, body, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "associate floating IP")
+	}
+	return nil
+}
+
+func (c *APIClient) DisassociateFloatingIP(ctx context.Context, floatingIPID string) error {
+	body := map[string]floatingIPUpdate{
+		"floatingip": {},
+	}
+	err := c.do(ctx, http.MethodPut, "/v2.0/floatingips/"+floatingIPID, body, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "disassociate floating IP")
+	}
+	return nil
+}
+
+func (c *APIClient) GetFloatingIP(ctx context.Context, floatingIPID string) (FloatingIP, error) {
+	var res struct {
+		FloatingIP FloatingIP `json:"floatingip"`
+	}
+	err := c.do(ctx, http.MethodGet, "/v2.0/floatingips/"+floatingIPID, nil, &res)
+	if err != nil {
+		return FloatingIP{}, errors.Wrap(ctx, err, "get floating IP")
+	}
+	return res.FloatingIP, nil
+}
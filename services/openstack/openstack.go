@@ -0,0 +1,225 @@
+package openstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// The openstack package provides a minimal client of the Neutron API, limited to the actions needed
+// by the plugins. The client authenticates with Keystone application credentials.
+
+// tokenRenewBefore is the delay before the expiration of the token when a new one is requested
+const tokenRenewBefore = 5 * time.Minute
+
+type APIClient struct {
+	httpClient *http.Client
+
+	// Client Configuration
+	authURL                     string
+	applicationCredentialID     string
+	applicationCredentialSecret string
+	region                      string
+
+	// tokenMutex protects the token and the network URL, which is read from the catalog of the token
+	// if it has not been configured
+	tokenMutex     sync.Mutex
+	token          string
+	tokenExpiresAt time.Time
+	networkURL     string
+}
+
+// NewClient returns a client of the Neutron API authenticated with the application credential on
+// the Keystone v3 API at authURL. If networkURL is empty, the public network endpoint of the region
+// is read from the service catalog.
+func NewClient(authURL, applicationCredentialID, applicationCredentialSecret, region, networkURL string) *APIClient {
+	return &APIClient{
+		httpClient:                  &http.Client{Timeout: 30 * time.Second},
+		authURL:                     strings.TrimSuffix(authURL, "/"),
+		applicationCredentialID:     applicationCredentialID,
+		applicationCredentialSecret: applicationCredentialSecret,
+		region:                      region,
+		networkURL:                  strings.TrimSuffix(networkURL, "/"),
+	}
+}
+
+// APIError is an error returned by the Keystone or Neutron API
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Type, e.StatusCode, e.Message)
+}
+
+// errorResponse is the format of the errors of Neutron, Keystone wraps the code and the message in
+// the error field
+type errorResponse struct {
+	NeutronError struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"NeutronError"`
+	Error struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type authRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods               []string `json:"methods"`
+			ApplicationCredential struct {
+				ID     string `json:"id"`
+				Secret string `json:"secret"`
+			} `json:"application_credential"`
+		} `json:"identity"`
+	} `json:"auth"`
+}
+
+type authResponse struct {
+	Token struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Catalog   []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				RegionID  string `json:"region_id"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// authenticate returns a valid token and the network URL, requesting a new token to Keystone if
+// needed
+func (c *APIClient) authenticate(ctx context.Context) (string, string, error) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+
+	if c.token != "" && time.Now().Add(tokenRenewBefore).Before(c.tokenExpiresAt) {
+		return c.token, c.networkURL, nil
+	}
+
+	var req authRequest
+	req.Auth.Identity.Methods = []string{"application_credential"}
+	req.Auth.Identity.ApplicationCredential.ID = c.applicationCredentialID
+	req.Auth.Identity.ApplicationCredential.Secret = c.applicationCredentialSecret
+
+	var res authResponse
+	header, err := c.send(ctx, http.MethodPost, c.authURL+"/auth/tokens", "", req, &res)
+	if err != nil {
+		return "", "", errors.Wrap(ctx, err, "request token")
+	}
+	token := header.Get("X-Subject-Token")
+	if token == "" {
+		return "", "", errors.New(ctx, "no token in the Keystone response")
+	}
+
+	if c.networkURL == "" {
+		for _, service := range res.Token.Catalog {
+			if service.Type != "network" {
+				continue
+			}
+			for _, endpoint := range service.Endpoints {
+				if endpoint.Interface == "public" && (c.region == "" || endpoint.RegionID == c.region) {
+					c.networkURL = strings.TrimSuffix(endpoint.URL, "/")
+					break
+				}
+			}
+		}
+		if c.networkURL == "" {
+			return "", "", errors.Newf(ctx, "no public network endpoint in the catalog for region '%s'", c.region)
+		}
+	}
+
+	c.token = token
+	c.tokenExpiresAt = res.Token.ExpiresAt
+	return c.token, c.networkURL, nil
+}
+
+// do sends the request to the Neutron API. The token is renewed once if it has been rejected.
+func (c *APIClient) do(ctx context.Context, method, path string, body, res any) error {
+	for attempt := 0; ; attempt++ {
+		token, networkURL, err := c.authenticate(ctx)
+		if err != nil {
+			return errors.Wrap(ctx, err, "authenticate")
+		}
+
+		_, err = c.send(ctx, method, networkURL+path, token, body, res)
+		var apiErr APIError
+		if attempt == 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			c.tokenMutex.Lock()
+			if c.token == token {
+				c.token = ""
+			}
+			c.tokenMutex.Unlock()
+			continue
+		}
+		return err
+	}
+}
+
+// send sends the request with the JSON encoded body, if any, and decodes the JSON response in res
+func (c *APIClient) send(ctx context.Context, method, url, token string, body, res any) (http.Header, error) {
+	var reqBody io.Reader
+	if body != nil {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "encode request")
+		}
+		reqBody = bytes.NewReader(rawBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("X-Auth-Token", token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpRes, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "send request")
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode < 200 || httpRes.StatusCode >= 300 {
+		apiErr := APIError{StatusCode: httpRes.StatusCode, Type: http.StatusText(httpRes.StatusCode)}
+		var errRes errorResponse
+		if json.NewDecoder(httpRes.Body).Decode(&errRes) == nil {
+			if errRes.NeutronError.Type != "" {
+				apiErr.Type = errRes.NeutronError.Type
+				apiErr.Message = errRes.NeutronError.Message
+			} else if errRes.Error.Title != "" {
+				apiErr.Type = errRes.Error.Title
+				apiErr.Message = errRes.Error.Message
+			}
+		}
+		return nil, apiErr
+	}
+
+	if res == nil {
+		return httpRes.Header, nil
+	}
+	err = json.NewDecoder(httpRes.Body).Decode(res)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "decode %s response", method)
+	}
+	return httpRes.Header, nil
+}
@@ -0,0 +1,164 @@
+package openstack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testApplicationCredentialID     = "0123456789abcdef0123456789abcdef"
+	testApplicationCredentialSecret = "my-secret"
+	testFloatingIPID                = "11111111-1111-4111-8111-111111111111"
+	testPortID                      = "22222222-2222-4222-8222-222222222222"
+)
+
+// openStackServer is a stand-in of Keystone and Neutron, the network endpoint of the catalog being
+// served under /network
+type openStackServer struct {
+	*httptest.Server
+	tokens   int
+	requests []string
+}
+
+func newOpenStackServer(t *testing.T) *openStackServer {
+	t.Helper()
+
+	s := &openStackServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if r.URL.Path == "/identity/v3/auth/tokens" {
+			var req authRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			assert.Equal(t, []string{"application_credential"}, req.Auth.Identity.Methods)
+			assert.Equal(t, testApplicationCredentialID, req.Auth.Identity.ApplicationCredential.ID)
+			assert.Equal(t, testApplicationCredentialSecret, req.Auth.Identity.ApplicationCredential.Secret)
+
+			s.tokens++
+			w.Header().Set("X-Subject-Token", "token-"+strconv.Itoa(s.tokens))
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"token": map[string]any{
+					"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+					"catalog": []any{
+						map[string]any{"type": "compute", "endpoints": []any{
+							map[string]any{"interface": "public", "region_id": "RegionOne", "url": s.URL + "/compute"},
+						}},
+						map[string]any{"type": "network", "endpoints": []any{
+							map[string]any{"interface": "internal", "region_id": "RegionOne", "url": s.URL + "/internal"},
+							map[string]any{"interface": "public", "region_id": "RegionTwo", "url": s.URL + "/region-two"},
+							map[string]any{"interface": "public", "region_id": "RegionOne", "url": s.URL + "/network/"},
+						}},
+					},
+				},
+			})
+			return
+		}
+
+		s.requests = append(s.requests, r.Header.Get("X-Auth-Token")+" "+r.Method+" "+r.URL.Path+" "+string(body))
+		// The first token is rejected, as if it had been revoked
+		if r.Header.Get("X-Auth-Token") == "token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": {"code": 401, "title": "Unauthorized", "message": "The request you have made requires authentication."}}`))
+			return
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /network/v2.0/floatingips/" + testFloatingIPID:
+			_, _ = w.Write([]byte(`{"floatingip": {"id": "` + testFloatingIPID + `"}}`))
+		case "GET /network/v2.0/floatingips/" + testFloatingIPID:
+			_, _ = w.Write([]byte(`{"floatingip": {"id": "` + testFloatingIPID + `", "floating_ip_address": "203.0.113.10", "port_id": "` + testPortID + `", "fixed_ip_address": "10.0.0.5"}}`))
+		case "GET /network/v2.0/ports/" + testPortID:
+			_, _ = w.Write([]byte(`{"port": {"id": "` + testPortID + `", "mac_address": "fa:16:3e:00:00:01", "allowed_address_pairs": [{"ip_address": "10.0.0.100", "mac_address": "fa:16:3e:00:00:01"}]}}`))
+		case "PUT /network/v2.0/ports/" + testPortID:
+			_, _ = w.Write([]byte(`{"port": {"id": "` + testPortID + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"NeutronError": {"type": "FloatingIPNotFound", "message": "Floating IP could not be found", "detail": ""}}`))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestAPIClient_FloatingIP(t *testing.T) {
+	ctx := context.Background()
+	server := newOpenStackServer(t)
+	client := NewClient(server.URL+"/identity/v3/", testApplicationCredentialID, testApplicationCredentialSecret, "RegionOne", "")
+
+	err := client.AssociateFloatingIP(ctx, testFloatingIPID, testPortID, "10.0.0.5")
+	require.NoError(t, err)
+	err = client.DisassociateFloatingIP(ctx, testFloatingIPID)
+	require.NoError(t, err)
+	floatingIP, err := client.GetFloatingIP(ctx, testFloatingIPID)
+	require.NoError(t, err)
+	assert.Equal(t, FloatingIP{
+		ID:                testFloatingIPID,
+		FloatingIPAddress: "203.0.113.10",
+		PortID:            testPortID,
+		FixedIPAddress:    "10.0.0.5",
+	}, floatingIP)
+
+	// The rejected token is renewed once, the new token is then reused
+	assert.Equal(t, 2, server.tokens)
+	assert.Equal(t, []string{
+		`token-1 PUT /network/v2.0/floatingips/` + testFloatingIPID + ` {"floatingip":{"port_id":"` + testPortID + `","fixed_ip_address":"10.0.0.5"}}`,
+		`token-2 PUT /network/v2.0/floatingips/` + testFloatingIPID + ` {"floatingip":{"port_id":"` + testPortID + `","fixed_ip_address":"10.0.0.5"}}`,
+		`token-2 PUT /network/v2.0/floatingips/` + testFloatingIPID + ` {"floatingip":{"port_id":null}}`,
+		`token-2 GET /network/v2.0/floatingips/` + testFloatingIPID + ` `,
+	}, server.requests)
+
+	_, err = client.GetFloatingIP(ctx, "33333333-3333-4333-8333-333333333333")
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "FloatingIPNotFound", apiErr.Type)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestAPIClient_Port(t *testing.T) {
+	ctx := context.Background()
+	server := newOpenStackServer(t)
+	// The network endpoint is configured, the catalog is not used
+	client := NewClient(server.URL+"/identity/v3", testApplicationCredentialID, testApplicationCredentialSecret, "", server.URL+"/network")
+	// Skip the rejected token
+	server.tokens = 1
+
+	port, err := client.GetPort(ctx, testPortID)
+	require.NoError(t, err)
+	assert.Equal(t, []AddressPair{{IPAddress: "10.0.0.100", MACAddress: "fa:16:3e:00:00:01"}}, port.AllowedAddressPairs)
+
+	err = client.UpdateAllowedAddressPairs(ctx, testPortID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `token-2 PUT /network/v2.0/ports/`+testPortID+` {"port":{"allowed_address_pairs":[]}}`, server.requests[1])
+}
+
+func TestAPIClient_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	server := newOpenStackServer(t)
+
+	t.Run("without network endpoint in the region", func(t *testing.T) {
+		client := NewClient(server.URL+"/identity/v3", testApplicationCredentialID, testApplicationCredentialSecret, "RegionThree", "")
+
+		_, err := client.GetPort(ctx, testPortID)
+		require.ErrorContains(t, err, "no public network endpoint in the catalog for region 'RegionThree'")
+	})
+
+	t.Run("with invalid credentials", func(t *testing.T) {
+		client := NewClient(server.URL+"/invalid", testApplicationCredentialID, testApplicationCredentialSecret, "", "")
+
+		_, err := client.GetPort(ctx, testPortID)
+		var apiErr APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	})
+}
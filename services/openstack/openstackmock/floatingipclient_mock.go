@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/openstack (interfaces: FloatingIPClient)
+
+// Package openstackmock is a generated GoMock package.
+package openstackmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	openstack "github.com/Scalingo/link/v3/services/openstack"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFloatingIPClient is a mock of FloatingIPClient interface.
+type MockFloatingIPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFloatingIPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockFloatingIPClientMockRecorder is the mock recorder for MockFloatingIPClient.
+type MockFloatingIPClientMockRecorder struct {
+	mock *MockFloatingIPClient
+}
+
+// NewMockFloatingIPClient creates a new mock instance.
+func NewMockFloatingIPClient(ctrl *gomock.Controller) *MockFloatingIPClient {
+	mock := &MockFloatingIPClient{ctrl: ctrl}
+	mock.recorder = &MockFloatingIPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFloatingIPClient) EXPECT() *MockFloatingIPClientMockRecorder {
+	return m.recorder
+}
+
+// AssociateFloatingIP mocks base method.
+func (m *MockFloatingIPClient) AssociateFloatingIP(ctx context.Context, floatingIPID, portID, fixedIP string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateFloatingIP", ctx, floatingIPID, portID, fixedIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssociateFloatingIP indicates an expected call of AssociateFloatingIP.
+func (mr *MockFloatingIPClientMockRecorder) AssociateFloatingIP(ctx, floatingIPID, portID, fixedIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateFloatingIP", reflect.TypeOf((*MockFloatingIPClient)(nil).AssociateFloatingIP), ctx, floatingIPID, portID, fixedIP)
+}
+
+// DisassociateFloatingIP mocks base method.
+func (m *MockFloatingIPClient) DisassociateFloatingIP(ctx context.Context, floatingIPID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisassociateFloatingIP", ctx, floatingIPID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisassociateFloatingIP indicates an expected call of DisassociateFloatingIP.
+func (mr *MockFloatingIPClientMockRecorder) DisassociateFloatingIP(ctx, floatingIPID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateFloatingIP", reflect.TypeOf((*MockFloatingIPClient)(nil).DisassociateFloatingIP), ctx, floatingIPID)
+}
+
+// GetFloatingIP mocks base method.
+func (m *MockFloatingIPClient) GetFloatingIP(ctx context.Context, floatingIPID string) (openstack.FloatingIP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFloatingIP", ctx, floatingIPID)
+	ret0, _ := ret[0].(openstack.FloatingIP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFloatingIP indicates an expected call of GetFloatingIP.
+func (mr *MockFloatingIPClientMockRecorder) GetFloatingIP(ctx, floatingIPID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFloatingIP", reflect.TypeOf((*MockFloatingIPClient)(nil).GetFloatingIP), ctx, floatingIPID)
+}
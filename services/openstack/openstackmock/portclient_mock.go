@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/openstack (interfaces: PortClient)
+
+// Package openstackmock is a generated GoMock package.
+package openstackmock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	openstack "github.com/Scalingo/link/v3/services/openstack"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPortClient is a mock of PortClient interface.
+type MockPortClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortClientMockRecorder
+	isgomock struct{}
+}
+
+// MockPortClientMockRecorder is the mock recorder for MockPortClient.
+type MockPortClientMockRecorder struct {
+	mock *MockPortClient
+}
+
+// NewMockPortClient creates a new mock instance.
+func NewMockPortClient(ctrl *gomock.Controller) *MockPortClient {
+	mock := &MockPortClient{ctrl: ctrl}
+	mock.recorder = &MockPortClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortClient) EXPECT() *MockPortClientMockRecorder {
+	return m.recorder
+}
+
+// GetPort mocks base method.
+func (m *MockPortClient) GetPort(ctx context.Context, portID string) (openstack.Port, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPort", ctx, portID)
+	ret0, _ := ret[0].(openstack.Port)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPort indicates an expected call of GetPort.
+func (mr *MockPortClientMockRecorder) GetPort(ctx, portID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPort", reflect.TypeOf((*MockPortClient)(nil).GetPort), ctx, portID)
+}
+
+// UpdateAllowedAddressPairs mocks base method.
+func (m *MockPortClient) UpdateAllowedAddressPairs(ctx context.Context, portID string, pairs []openstack.AddressPair) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAllowedAddressPairs", ctx, portID, pairs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAllowedAddressPairs indicates an expected call of UpdateAllowedAddressPairs.
+func (mr *MockPortClientMockRecorder) UpdateAllowedAddressPairs(ctx, portID, pairs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAllowedAddressPairs", reflect.TypeOf((*MockPortClient)(nil).UpdateAllowedAddressPairs), ctx, portID, pairs)
+}
@@ -0,0 +1,54 @@
+package openstack
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ PortClient = (*APIClient)(nil)
+
+type PortClient interface {
+	GetPort(ctx context.Context, portID string) (Port, error)
+	UpdateAllowedAddressPairs(ctx context.Context, portID string, pairs []AddressPair) error
+}
+
+type Port struct {
+	ID                  string        `json:"id"`
+	MACAddress          string        `json:"mac_address"`
+	AllowedAddressPairs []AddressPair `json:"allowed_address_pairs"`
+}
+
+// AddressPair is an IP, or a prefix, allowed to be sent and received by a port in addition to its
+// fixed IPs. The MAC address of the port is used if MACAddress is empty.
+type AddressPair struct {
+	IPAddress  string `json:"ip_address"`
+	MACAddress string `json:"mac_address,omitempty"`
+}
+
+func (c *APIClient) GetPort(ctx context.Context, portID string) (Port, error) {
+	var res struct {
+		Port Port `json:"port"`
+	}
+	err := c.do(ctx, http.MethodGet, "/v2.0/ports/"+portID, nil, &res)
+	if err != nil {
+		return Port{}, errors.Wrap(ctx, err, "get port")
+	}
+	return res.Port, nil
+}
+
+// UpdateAllowedAddressPairs replaces the allowed address pairs of the port
+func (c *APIClient) UpdateAllowedAddressPairs(ctx context.Context, portID string, pairs []AddressPair) error {
+	if pairs == nil {
+		pairs = []AddressPair{}
+	}
+	body := map[string]any{
+		"port": map[string]any{"allowed_address_pairs": pairs},
+	}
+	err := c.do(ctx, http.MethodPut, "/v2.0/ports/"+portID, body, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "update allowed address pairs")
+	}
+	return nil
+}
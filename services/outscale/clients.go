@@ -0,0 +1,59 @@
+package outscale
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/models"
+)
+
+// Clients shares the API clients between the endpoints: the endpoints using the same credentials
+// and region use the same client, and share its rate limiter.
+type Clients struct {
+	config ClientConfig
+
+	mutex   sync.Mutex
+	clients map[clientKey]*APIClient
+}
+
+type clientKey struct {
+	accessKey string
+	secretKey string
+	region    string
+}
+
+func NewClients(config ClientConfig) *Clients {
+	return &Clients{
+		config:  config,
+		clients: make(map[clientKey]*APIClient),
+	}
+}
+
+// Get returns the client of the credentials, it is created on the first call
+func (c *Clients) Get(accessKey, secretKey, region string) *APIClient {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := clientKey{accessKey: accessKey, secretKey: secretKey, region: region}
+	client, ok := c.clients[key]
+	if !ok {
+		client = NewClient(accessKey, secretKey, region, c.config)
+		c.clients[key] = client
+	}
+	return client
+}
+
+// FromCredentials decrypts the keys of an endpoint and returns the client using them
+func (c *Clients) FromCredentials(ctx context.Context, encryptedStorage models.EncryptedStorage, credentials StorableCredentials) (*APIClient, error) {
+	var accessKey, secretKey string
+	err := encryptedStorage.Decrypt(ctx, credentials.AccessKey, &accessKey)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt access key")
+	}
+	err = encryptedStorage.Decrypt(ctx, credentials.SecretKey, &secretKey)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "decrypt secret key")
+	}
+	return c.Get(accessKey, secretKey, credentials.Region), nil
+}
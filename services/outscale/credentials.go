@@ -0,0 +1,83 @@
+package outscale
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/models"
+)
+
+// Regions are the Outscale regions accepted in the configuration of the endpoints
+var Regions = []string{
+	"ap-northeast-1",
+	"cloudgouv-eu-west-1",
+	"eu-west-2",
+	"us-east-2",
+	"us-west-1",
+}
+
+// testRegion is only accepted in the test environment
+const testRegion = "test-region"
+
+var tokenRegex = regexp.MustCompile(`^[A-Z0-9]{3,64}$`)
+
+// IDRegex matches the IDs of the Outscale resources (e.g. nic-12345678)
+var IDRegex = regexp.MustCompile(`^[a-z]+-[a-f0-9]{3,64}$`)
+
+// ValidateCredentials checks the format of the credentials sent in the configuration of an
+// endpoint. The test region is accepted if goEnv is "test".
+func ValidateCredentials(validations *errors.ValidationErrorsBuilder, accessKey, secretKey, region, goEnv string) {
+	if accessKey == "" {
+		validations.Set("plugin_config.access_key", "missing access key")
+	}
+	if accessKey != "" && !tokenRegex.MatchString(accessKey) {
+		validations.Set("plugin_config.access_key", "invalid access key format")
+	}
+
+	if secretKey == "" {
+		validations.Set("plugin_config.secret_key", "missing secret key")
+	}
+	if secretKey != "" && !tokenRegex.MatchString(secretKey) {
+		validations.Set("plugin_config.secret_key", "invalid secret key format")
+	}
+
+	if region == "" {
+		validations.Set("plugin_config.region", "missing region")
+	}
+
+	validRegions := Regions
+	if goEnv == "test" {
+		validRegions = append(slices.Clone(validRegions), testRegion)
+	}
+
+	if region != "" && !slices.Contains(validRegions, strings.ToLower(region)) {
+		validations.Set("plugin_config.region", "invalid region: "+region)
+	}
+}
+
+// StorableCredentials are the credentials of an endpoint as saved in the storage, the keys being
+// encrypted
+type StorableCredentials struct {
+	AccessKey models.EncryptedDataLink `json:"access_key"`
+	SecretKey models.EncryptedDataLink `json:"secret_key"`
+	Region    string                   `json:"region"`
+}
+
+// EncryptCredentials encrypts the keys of an endpoint before saving its configuration
+func EncryptCredentials(ctx context.Context, encryptedStorage models.EncryptedStorage, endpointID, accessKey, secretKey, region string) (StorableCredentials, error) {
+	credentials := StorableCredentials{Region: region}
+
+	var err error
+	credentials.AccessKey, err = encryptedStorage.Encrypt(ctx, endpointID, accessKey)
+	if err != nil {
+		return StorableCredentials{}, errors.Wrap(ctx, err, "encrypt access key")
+	}
+	credentials.SecretKey, err = encryptedStorage.Encrypt(ctx, endpointID, secretKey)
+	if err != nil {
+		return StorableCredentials{}, errors.Wrap(ctx, err, "encrypt secret key")
+	}
+	return credentials, nil
+}
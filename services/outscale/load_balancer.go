@@ -0,0 +1,57 @@
+package outscale
+
+import (
+	"context"
+
+	"github.com/outscale/osc-sdk-go/v2"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ LoadBalancerClient = (*APIClient)(nil)
+
+type LoadBalancerClient interface {
+	RegisterVMsInLoadBalancer(ctx context.Context, params osc.RegisterVmsInLoadBalancerRequest) (osc.RegisterVmsInLoadBalancerResponse, error)
+	DeregisterVMsInLoadBalancer(ctx context.Context, params osc.DeregisterVmsInLoadBalancerRequest) (osc.DeregisterVmsInLoadBalancerResponse, error)
+	ReadLoadBalancer(ctx context.Context, name string) (osc.LoadBalancer, error)
+}
+
+func (c *APIClient) RegisterVMsInLoadBalancer(ctx context.Context, params osc.RegisterVmsInLoadBalancerRequest) (osc.RegisterVmsInLoadBalancerResponse, error) {
+	authCtx := c.authenticatedContext(ctx)
+	resp, err := execute(ctx, c, c.oscClient.LoadBalancerApi.RegisterVmsInLoadBalancer(authCtx).RegisterVmsInLoadBalancerRequest(params))
+	if err != nil {
+		return osc.RegisterVmsInLoadBalancerResponse{}, errors.Wrap(ctx, err, "register VMs in load balancer")
+	}
+	return resp, nil
+}
+
+func (c *APIClient) DeregisterVMsInLoadBalancer(ctx context.Context, params osc.DeregisterVmsInLoadBalancerRequest) (osc.DeregisterVmsInLoadBalancerResponse, error) {
+	authCtx := c.authenticatedContext(ctx)
+	resp, err := execute(ctx, c, c.oscClient.LoadBalancerApi.DeregisterVmsInLoadBalancer(authCtx).DeregisterVmsInLoadBalancerRequest(params))
+	if err != nil {
+		return osc.DeregisterVmsInLoadBalancerResponse{}, errors.Wrap(ctx, err, "deregister VMs from load balancer")
+	}
+	return resp, nil
+}
+
+func (c *APIClient) ReadLoadBalancer(ctx context.Context, name string) (osc.LoadBalancer, error) {
+	authCtx := c.authenticatedContext(ctx)
+	req := osc.ReadLoadBalancersRequest{
+		Filters: &osc.FiltersLoadBalancer{
+			LoadBalancerNames: &[]string{name},
+		},
+	}
+	resp, err := execute(ctx, c, c.oscClient.LoadBalancerApi.ReadLoadBalancers(authCtx).ReadLoadBalancersRequest(req))
+	if err != nil {
+		return osc.LoadBalancer{}, errors.Wrap(ctx, err, "read load balancer")
+	}
+	if resp.LoadBalancers == nil {
+		return osc.LoadBalancer{}, errors.New(ctx, "load balancer not found")
+	}
+
+	if len(*resp.LoadBalancers) != 1 {
+		return osc.LoadBalancer{}, errors.Newf(ctx, "invalid number of load balancers returned: %d", len(*resp.LoadBalancers))
+	}
+
+	return (*resp.LoadBalancers)[0], nil
+}
@@ -0,0 +1,89 @@
+package outscale
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// DefaultMetadataURL is the URL of the metadata service of the Outscale VMs
+const DefaultMetadataURL = "http://169.254.169.254/latest/meta-data"
+
+// PrimaryNICDeviceIndex is the device index of the primary NIC of a VM
+const PrimaryNICDeviceIndex = 0
+
+// Metadata gives information about the VM of the current host
+type Metadata interface {
+	// NICID returns the ID of the NIC of the VM attached with the given device index
+	NICID(ctx context.Context, deviceIndex int) (string, error)
+}
+
+var _ Metadata = MetadataClient{}
+
+// MetadataClient queries the metadata service of the VM
+type MetadataClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewMetadataClient(url string) MetadataClient {
+	return MetadataClient{
+		url:        strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (c MetadataClient) NICID(ctx context.Context, deviceIndex int) (string, error) {
+	macs, err := c.get(ctx, "network/interfaces/macs/")
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "list the NICs")
+	}
+
+	for _, mac := range strings.Fields(macs) {
+		mac = strings.TrimSuffix(mac, "/")
+		rawIndex, err := c.get(ctx, "network/interfaces/macs/"+mac+"/device-number")
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "get the device index of the NIC %s", mac)
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(rawIndex))
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "invalid device index of the NIC %s", mac)
+		}
+		if index != deviceIndex {
+			continue
+		}
+
+		nicID, err := c.get(ctx, "network/interfaces/macs/"+mac+"/interface-id")
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "get the ID of the NIC %s", mac)
+		}
+		return strings.TrimSpace(nicID), nil
+	}
+	return "", errors.Newf(ctx, "no NIC with device index %d", deviceIndex)
+}
+
+func (c MetadataClient) get(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/"+path, nil)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "create request")
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "send request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Newf(ctx, "invalid status code %d for %s", res.StatusCode, path)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "read response")
+	}
+	return string(body), nil
+}
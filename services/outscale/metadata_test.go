@@ -0,0 +1,63 @@
+package outscale
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMetadataServer starts a stand-in of the metadata service of a VM with two NICs
+func newMetadataServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	files := map[string]string{
+		"/latest/meta-data/network/interfaces/macs/":                                "aa:fa:00:00:00:02/\naa:fa:00:00:00:01/\n",
+		"/latest/meta-data/network/interfaces/macs/aa:fa:00:00:00:01/device-number": "0",
+		"/latest/meta-data/network/interfaces/macs/aa:fa:00:00:00:01/interface-id":  "eni-12345678",
+		"/latest/meta-data/network/interfaces/macs/aa:fa:00:00:00:02/device-number": "1",
+		"/latest/meta-data/network/interfaces/macs/aa:fa:00:00:00:02/interface-id":  "eni-87654321",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(file))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMetadataClient_NICID(t *testing.T) {
+	ctx := context.Background()
+	server := newMetadataServer(t)
+	client := NewMetadataClient(server.URL + "/latest/meta-data/")
+
+	t.Run("the primary NIC", func(t *testing.T) {
+		nicID, err := client.NICID(ctx, PrimaryNICDeviceIndex)
+		require.NoError(t, err)
+		assert.Equal(t, "eni-12345678", nicID)
+	})
+
+	t.Run("a secondary NIC", func(t *testing.T) {
+		nicID, err := client.NICID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "eni-87654321", nicID)
+	})
+
+	t.Run("a device index without NIC", func(t *testing.T) {
+		_, err := client.NICID(ctx, 2)
+		require.ErrorContains(t, err, "no NIC with device index 2")
+	})
+
+	t.Run("the metadata service is not reachable", func(t *testing.T) {
+		client := NewMetadataClient(server.URL + "/invalid")
+		_, err := client.NICID(ctx, PrimaryNICDeviceIndex)
+		require.ErrorContains(t, err, "invalid status code 404")
+	})
+}
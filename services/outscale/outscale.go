@@ -2,16 +2,33 @@ package outscale
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/outscale/osc-sdk-go/v2"
+	"golang.org/x/time/rate"
+
+	"github.com/Scalingo/go-utils/retry"
 )
 
 // The outscale package provides a generic interface for interacting with the Outscale API.
 // This is useful for generating mocks and testing purposes.
 // It also helps with abstracting some of the complexities of the Outscale API.
 
+type ClientConfig struct {
+	// RateLimit is the maximum number of requests per second sent by a client
+	RateLimit float64
+	RateBurst int
+	// MaxAttempts is the maximum number of attempts of a request throttled by the API, the delay
+	// between two attempts starts at RetryWait and is doubled at each attempt
+	MaxAttempts int
+	RetryWait   time.Duration
+}
+
 type APIClient struct {
 	oscClient *osc.APIClient
+	limiter   *rate.Limiter
+	retryer   retry.Retry
 
 	// Client Configuration
 	accessKey string
@@ -19,11 +36,17 @@ type APIClient struct {
 	region    string
 }
 
-func NewClient(accessKey, secretKey, region string) *APIClient {
+func NewClient(accessKey, secretKey, region string, config ClientConfig) *APIClient {
 	oscClient := osc.NewAPIClient(osc.NewConfiguration())
 
 	return &APIClient{
 		oscClient: oscClient,
+		limiter:   rate.NewLimiter(rate.Limit(config.RateLimit), config.RateBurst),
+		retryer: retry.New(
+			retry.WithWaitDuration(config.RetryWait),
+			retry.WithExponentialBackoff(2),
+			retry.WithMaxAttempts(config.MaxAttempts),
+		),
 		accessKey: accessKey,
 		secretKey: secretKey,
 		region:    region,
@@ -42,3 +65,33 @@ func (c *APIClient) authenticatedContext(ctx context.Context) context.Context {
 	})
 	return ctx
 }
+
+// executer is a request of the Outscale SDK, ready to be sent
+type executer[T any] interface {
+	Execute() (T, *http.Response, error)
+}
+
+// execute sends a request once allowed by the rate limiter of the client. The request is sent
+// again if it is throttled by the API, the other errors are returned directly.
+func execute[T any](ctx context.Context, c *APIClient, request executer[T]) (T, error) {
+	var res T
+	err := c.retryer.Do(ctx, func(ctx context.Context) error {
+		err := c.limiter.Wait(ctx)
+		if err != nil {
+			return retry.NewRetryCancelError(err)
+		}
+
+		var httpRes *http.Response
+		res, httpRes, err = request.Execute()
+		if err != nil && !isThrottled(httpRes) {
+			return retry.NewRetryCancelError(err)
+		}
+		return err
+	})
+	return res, err
+}
+
+// isThrottled returns true if the API refused the request because too many requests have been sent
+func isThrottled(res *http.Response) bool {
+	return res != nil && (res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable)
+}
@@ -0,0 +1,75 @@
+package outscale
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testClientConfig = ClientConfig{
+	RateLimit:   1000,
+	RateBurst:   1,
+	MaxAttempts: 3,
+	RetryWait:   time.Millisecond,
+}
+
+// fakeRequest returns the given status codes, one per call
+type fakeRequest struct {
+	statusCodes []int
+	calls       int
+}
+
+func (r *fakeRequest) Execute() (string, *http.Response, error) {
+	statusCode := r.statusCodes[r.calls]
+	r.calls++
+	if statusCode != http.StatusOK {
+		return "", &http.Response{StatusCode: statusCode}, errors.New(http.StatusText(statusCode))
+	}
+	return "response", &http.Response{StatusCode: statusCode}, nil
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("a throttled request is sent again", func(t *testing.T) {
+		client := NewClient("access", "secret", "eu-west-2", testClientConfig)
+		request := &fakeRequest{statusCodes: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}}
+
+		res, err := execute(ctx, client, request)
+		require.NoError(t, err)
+		assert.Equal(t, "response", res)
+		assert.Equal(t, 3, request.calls)
+	})
+
+	t.Run("a request throttled too many times", func(t *testing.T) {
+		client := NewClient("access", "secret", "eu-west-2", testClientConfig)
+		request := &fakeRequest{statusCodes: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}}
+
+		_, err := execute(ctx, client, request)
+		require.ErrorContains(t, err, "Service Unavailable")
+		assert.Equal(t, 3, request.calls)
+	})
+
+	t.Run("the other errors are not retried", func(t *testing.T) {
+		client := NewClient("access", "secret", "eu-west-2", testClientConfig)
+		request := &fakeRequest{statusCodes: []int{http.StatusBadRequest}}
+
+		_, err := execute(ctx, client, request)
+		require.ErrorContains(t, err, "Bad Request")
+		assert.Equal(t, 1, request.calls)
+	})
+}
+
+func TestClients_Get(t *testing.T) {
+	clients := NewClients(testClientConfig)
+
+	client := clients.Get("access", "secret", "eu-west-2")
+	assert.Same(t, client, clients.Get("access", "secret", "eu-west-2"))
+	assert.NotSame(t, client, clients.Get("access", "secret", "us-east-2"))
+	assert.NotSame(t, client, clients.Get("access", "other-secret", "eu-west-2"))
+}
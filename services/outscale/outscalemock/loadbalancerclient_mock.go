@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/outscale (interfaces: LoadBalancerClient)
+
+// Package outscalemock is a generated GoMock package.
+package outscalemock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoadBalancerClient is a mock of LoadBalancerClient interface.
+type MockLoadBalancerClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoadBalancerClientMockRecorder
+	isgomock struct{}
+}
+
+// MockLoadBalancerClientMockRecorder is the mock recorder for MockLoadBalancerClient.
+type MockLoadBalancerClientMockRecorder struct {
+	mock *MockLoadBalancerClient
+}
+
+// NewMockLoadBalancerClient creates a new mock instance.
+func NewMockLoadBalancerClient(ctrl *gomock.Controller) *MockLoadBalancerClient {
+	mock := &MockLoadBalancerClient{ctrl: ctrl}
+	mock.recorder = &MockLoadBalancerClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoadBalancerClient) EXPECT() *MockLoadBalancerClientMockRecorder {
+	return m.recorder
+}
+
+// DeregisterVMsInLoadBalancer mocks base method.
+func (m *MockLoadBalancerClient) DeregisterVMsInLoadBalancer(ctx context.Context, params osc.DeregisterVmsInLoadBalancerRequest) (osc.DeregisterVmsInLoadBalancerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeregisterVMsInLoadBalancer", ctx, params)
+	ret0, _ := ret[0].(osc.DeregisterVmsInLoadBalancerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeregisterVMsInLoadBalancer indicates an expected call of DeregisterVMsInLoadBalancer.
+func (mr *MockLoadBalancerClientMockRecorder) DeregisterVMsInLoadBalancer(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterVMsInLoadBalancer", reflect.TypeOf((*MockLoadBalancerClient)(nil).DeregisterVMsInLoadBalancer), ctx, params)
+}
+
+// ReadLoadBalancer mocks base method.
+func (m *MockLoadBalancerClient) ReadLoadBalancer(ctx context.Context, name string) (osc.LoadBalancer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLoadBalancer", ctx, name)
+	ret0, _ := ret[0].(osc.LoadBalancer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLoadBalancer indicates an expected call of ReadLoadBalancer.
+func (mr *MockLoadBalancerClientMockRecorder) ReadLoadBalancer(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLoadBalancer", reflect.TypeOf((*MockLoadBalancerClient)(nil).ReadLoadBalancer), ctx, name)
+}
+
+// RegisterVMsInLoadBalancer mocks base method.
+func (m *MockLoadBalancerClient) RegisterVMsInLoadBalancer(ctx context.Context, params osc.RegisterVmsInLoadBalancerRequest) (osc.RegisterVmsInLoadBalancerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterVMsInLoadBalancer", ctx, params)
+	ret0, _ := ret[0].(osc.RegisterVmsInLoadBalancerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterVMsInLoadBalancer indicates an expected call of RegisterVMsInLoadBalancer.
+func (mr *MockLoadBalancerClientMockRecorder) RegisterVMsInLoadBalancer(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterVMsInLoadBalancer", reflect.TypeOf((*MockLoadBalancerClient)(nil).RegisterVMsInLoadBalancer), ctx, params)
+}
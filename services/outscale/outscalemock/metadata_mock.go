@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/outscale (interfaces: Metadata)
+
+// Package outscalemock is a generated GoMock package.
+package outscalemock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMetadata is a mock of Metadata interface.
+type MockMetadata struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetadataMockRecorder
+	isgomock struct{}
+}
+
+// MockMetadataMockRecorder is the mock recorder for MockMetadata.
+type MockMetadataMockRecorder struct {
+	mock *MockMetadata
+}
+
+// NewMockMetadata creates a new mock instance.
+func NewMockMetadata(ctrl *gomock.Controller) *MockMetadata {
+	mock := &MockMetadata{ctrl: ctrl}
+	mock.recorder = &MockMetadataMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetadata) EXPECT() *MockMetadataMockRecorder {
+	return m.recorder
+}
+
+// NICID mocks base method.
+func (m *MockMetadata) NICID(ctx context.Context, deviceIndex int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NICID", ctx, deviceIndex)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NICID indicates an expected call of NICID.
+func (mr *MockMetadataMockRecorder) NICID(ctx, deviceIndex any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NICID", reflect.TypeOf((*MockMetadata)(nil).NICID), ctx, deviceIndex)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/outscale (interfaces: PrivateIPClient)
+
+// Package outscalemock is a generated GoMock package.
+package outscalemock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPrivateIPClient is a mock of PrivateIPClient interface.
+type MockPrivateIPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrivateIPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockPrivateIPClientMockRecorder is the mock recorder for MockPrivateIPClient.
+type MockPrivateIPClientMockRecorder struct {
+	mock *MockPrivateIPClient
+}
+
+// NewMockPrivateIPClient creates a new mock instance.
+func NewMockPrivateIPClient(ctrl *gomock.Controller) *MockPrivateIPClient {
+	mock := &MockPrivateIPClient{ctrl: ctrl}
+	mock.recorder = &MockPrivateIPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrivateIPClient) EXPECT() *MockPrivateIPClientMockRecorder {
+	return m.recorder
+}
+
+// LinkPrivateIPs mocks base method.
+func (m *MockPrivateIPClient) LinkPrivateIPs(ctx context.Context, params osc.LinkPrivateIpsRequest) (osc.LinkPrivateIpsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkPrivateIPs", ctx, params)
+	ret0, _ := ret[0].(osc.LinkPrivateIpsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkPrivateIPs indicates an expected call of LinkPrivateIPs.
+func (mr *MockPrivateIPClientMockRecorder) LinkPrivateIPs(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkPrivateIPs", reflect.TypeOf((*MockPrivateIPClient)(nil).LinkPrivateIPs), ctx, params)
+}
+
+// ReadNIC mocks base method.
+func (m *MockPrivateIPClient) ReadNIC(ctx context.Context, nicID string) (osc.Nic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadNIC", ctx, nicID)
+	ret0, _ := ret[0].(osc.Nic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadNIC indicates an expected call of ReadNIC.
+func (mr *MockPrivateIPClientMockRecorder) ReadNIC(ctx, nicID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadNIC", reflect.TypeOf((*MockPrivateIPClient)(nil).ReadNIC), ctx, nicID)
+}
+
+// UnlinkPrivateIPs mocks base method.
+func (m *MockPrivateIPClient) UnlinkPrivateIPs(ctx context.Context, params osc.UnlinkPrivateIpsRequest) (osc.UnlinkPrivateIpsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkPrivateIPs", ctx, params)
+	ret0, _ := ret[0].(osc.UnlinkPrivateIpsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlinkPrivateIPs indicates an expected call of UnlinkPrivateIPs.
+func (mr *MockPrivateIPClientMockRecorder) UnlinkPrivateIPs(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkPrivateIPs", reflect.TypeOf((*MockPrivateIPClient)(nil).UnlinkPrivateIPs), ctx, params)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/outscale (interfaces: RouteTableClient)
+
+// Package outscalemock is a generated GoMock package.
+package outscalemock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	osc "github.com/outscale/osc-sdk-go/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRouteTableClient is a mock of RouteTableClient interface.
+type MockRouteTableClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRouteTableClientMockRecorder
+	isgomock struct{}
+}
+
+// MockRouteTableClientMockRecorder is the mock recorder for MockRouteTableClient.
+type MockRouteTableClientMockRecorder struct {
+	mock *MockRouteTableClient
+}
+
+// NewMockRouteTableClient creates a new mock instance.
+func NewMockRouteTableClient(ctrl *gomock.Controller) *MockRouteTableClient {
+	mock := &MockRouteTableClient{ctrl: ctrl}
+	mock.recorder = &MockRouteTableClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouteTableClient) EXPECT() *MockRouteTableClientMockRecorder {
+	return m.recorder
+}
+
+// ReadRouteTable mocks base method.
+func (m *MockRouteTableClient) ReadRouteTable(ctx context.Context, routeTableID string) (osc.RouteTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRouteTable", ctx, routeTableID)
+	ret0, _ := ret[0].(osc.RouteTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRouteTable indicates an expected call of ReadRouteTable.
+func (mr *MockRouteTableClientMockRecorder) ReadRouteTable(ctx, routeTableID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRouteTable", reflect.TypeOf((*MockRouteTableClient)(nil).ReadRouteTable), ctx, routeTableID)
+}
+
+// UpdateRoute mocks base method.
+func (m *MockRouteTableClient) UpdateRoute(ctx context.Context, params osc.UpdateRouteRequest) (osc.UpdateRouteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoute", ctx, params)
+	ret0, _ := ret[0].(osc.UpdateRouteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRoute indicates an expected call of UpdateRoute.
+func (mr *MockRouteTableClientMockRecorder) UpdateRoute(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoute", reflect.TypeOf((*MockRouteTableClient)(nil).UpdateRoute), ctx, params)
+}
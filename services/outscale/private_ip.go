@@ -0,0 +1,57 @@
+package outscale
+
+import (
+	"context"
+
+	"github.com/outscale/osc-sdk-go/v2"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ PrivateIPClient = (*APIClient)(nil)
+
+type PrivateIPClient interface {
+	LinkPrivateIPs(ctx context.Context, params osc.LinkPrivateIpsRequest) (osc.LinkPrivateIpsResponse, error)
+	UnlinkPrivateIPs(ctx context.Context, params osc.UnlinkPrivateIpsRequest) (osc.UnlinkPrivateIpsResponse, error)
+	ReadNIC(ctx context.Context, nicID string) (osc.Nic, error)
+}
+
+func (c *APIClient) LinkPrivateIPs(ctx context.Context, params osc.LinkPrivateIpsRequest) (osc.LinkPrivateIpsResponse, error) {
+	authCtx := c.authenticatedContext(ctx)
+	resp, err := execute(ctx, c, c.oscClient.NicApi.LinkPrivateIps(authCtx).LinkPrivateIpsRequest(params))
+	if err != nil {
+		return osc.LinkPrivateIpsResponse{}, errors.Wrap(ctx, err, "link private IPs")
+	}
+	return resp, nil
+}
+
+func (c *APIClient) UnlinkPrivateIPs(ctx context.Context, params osc.UnlinkPrivateIpsRequest) (osc.UnlinkPrivateIpsResponse, error) {
+	authCtx := c.authenticatedContext(ctx)
+	resp, err := execute(ctx, c, c.oscClient.NicApi.UnlinkPrivateIps(authCtx).UnlinkPrivateIpsRequest(params))
+	if err != nil {
+		return osc.UnlinkPrivateIpsResponse{}, errors.Wrap(ctx, err, "unlink private IPs")
+	}
+	return resp, nil
+}
+
+func (c *APIClient) ReadNIC(ctx context.Context, nicID string) (osc.Nic, error) {
+	authCtx := c.authenticatedContext(ctx)
+	req := osc.ReadNicsRequest{
+		Filters: &osc.FiltersNic{
+			NicIds: &[]string{nicID},
+		},
+	}
+	resp, err := execute(ctx, c, c.oscClient.NicApi.ReadNics(authCtx).ReadNicsRequest(req))
+	if err != nil {
+		return osc.Nic{}, errors.Wrap(ctx, err, "read NIC")
+	}
+	if resp.Nics == nil {
+		return osc.Nic{}, errors.New(ctx, "NIC not found")
+	}
+
+	if len(*resp.Nics) != 1 {
+		return osc.Nic{}, errors.Newf(ctx, "invalid number of NICs returned: %d", len(*resp.Nics))
+	}
+
+	return (*resp.Nics)[0], nil
+}
@@ -18,7 +18,7 @@ type PublicIPClient interface {
 
 func (c *APIClient) LinkPublicIP(ctx context.Context, params osc.LinkPublicIpRequest) (osc.LinkPublicIpResponse, error) {
 	authCtx := c.authenticatedContext(ctx)
-	resp, _, err := c.oscClient.PublicIpApi.LinkPublicIp(authCtx).LinkPublicIpRequest(params).Execute()
+	resp, err := execute(ctx, c, c.oscClient.PublicIpApi.LinkPublicIp(authCtx).LinkPublicIpRequest(params))
 	if err != nil {
 		return osc.LinkPublicIpResponse{}, errors.Wrap(ctx, err, "link public IP")
 	}
@@ -27,7 +27,7 @@ func (c *APIClient) LinkPublicIP(ctx context.Context, params osc.LinkPublicIpReq
 
 func (c *APIClient) UnlinkPublicIP(ctx context.Context, params osc.UnlinkPublicIpRequest) (osc.UnlinkPublicIpResponse, error) {
 	authCtx := c.authenticatedContext(ctx)
-	resp, _, err := c.oscClient.PublicIpApi.UnlinkPublicIp(authCtx).UnlinkPublicIpRequest(params).Execute()
+	resp, err := execute(ctx, c, c.oscClient.PublicIpApi.UnlinkPublicIp(authCtx).UnlinkPublicIpRequest(params))
 	if err != nil {
 		return osc.UnlinkPublicIpResponse{}, errors.Wrap(ctx, err, "unlink public IP")
 	}
@@ -41,7 +41,7 @@ func (c *APIClient) ReadPublicIP(ctx context.Context, publicIPID string) (osc.Pu
 			PublicIpIds: &[]string{publicIPID},
 		},
 	}
-	resp, _, err := c.oscClient.PublicIpApi.ReadPublicIps(authCtx).ReadPublicIpsRequest(req).Execute()
+	resp, err := execute(ctx, c, c.oscClient.PublicIpApi.ReadPublicIps(authCtx).ReadPublicIpsRequest(req))
 	if err != nil {
 		return osc.PublicIp{}, errors.Wrap(ctx, err, "read public IP")
 	}
@@ -0,0 +1,47 @@
+package outscale
+
+import (
+	"context"
+
+	"github.com/outscale/osc-sdk-go/v2"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ RouteTableClient = (*APIClient)(nil)
+
+type RouteTableClient interface {
+	UpdateRoute(ctx context.Context, params osc.UpdateRouteRequest) (osc.UpdateRouteResponse, error)
+	ReadRouteTable(ctx context.Context, routeTableID string) (osc.RouteTable, error)
+}
+
+func (c *APIClient) UpdateRoute(ctx context.Context, params osc.UpdateRouteRequest) (osc.UpdateRouteResponse, error) {
+	authCtx := c.authenticatedContext(ctx)
+	resp, err := execute(ctx, c, c.oscClient.RouteApi.UpdateRoute(authCtx).UpdateRouteRequest(params))
+	if err != nil {
+		return osc.UpdateRouteResponse{}, errors.Wrap(ctx, err, "update route")
+	}
+	return resp, nil
+}
+
+func (c *APIClient) ReadRouteTable(ctx context.Context, routeTableID string) (osc.RouteTable, error) {
+	authCtx := c.authenticatedContext(ctx)
+	req := osc.ReadRouteTablesRequest{
+		Filters: &osc.FiltersRouteTable{
+			RouteTableIds: &[]string{routeTableID},
+		},
+	}
+	resp, err := execute(ctx, c, c.oscClient.RouteTableApi.ReadRouteTables(authCtx).ReadRouteTablesRequest(req))
+	if err != nil {
+		return osc.RouteTable{}, errors.Wrap(ctx, err, "read route table")
+	}
+	if resp.RouteTables == nil {
+		return osc.RouteTable{}, errors.New(ctx, "route table not found")
+	}
+
+	if len(*resp.RouteTables) != 1 {
+		return osc.RouteTable{}, errors.Newf(ctx, "invalid number of route tables returned: %d", len(*resp.RouteTables))
+	}
+
+	return (*resp.RouteTables)[0], nil
+}
@@ -0,0 +1,106 @@
+package powerdns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// DefaultServerID is the ID of the server of the PowerDNS API if none is configured
+const DefaultServerID = "localhost"
+
+type APIClient struct {
+	httpClient *http.Client
+
+	// Client Configuration
+	apiKey string
+	apiURL string
+	// zonePath is the path of the zone in the API, e.g. /api/v1/servers/localhost/zones/example.com.
+	zonePath string
+}
+
+// NewClient returns a client of the PowerDNS Authoritative Server API managing the records of the
+// zone, authenticated with the API key. apiURL is the URL of the webserver of PowerDNS (e.g.
+// http://127.0.0.1:8081). If serverID is empty, DefaultServerID is used.
+func NewClient(apiKey, apiURL, serverID, zone string) *APIClient {
+	if serverID == "" {
+		serverID = DefaultServerID
+	}
+	if !strings.HasSuffix(zone, ".") {
+		zone += "."
+	}
+
+	return &APIClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		apiKey:     apiKey,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		zonePath:   "/api/v1/servers/" + url.PathEscape(serverID) + "/zones/" + url.PathEscape(zone),
+	}
+}
+
+// APIError is an error returned by the PowerDNS API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// do sends the request with the JSON encoded body, if any, and decodes the JSON response in res
+func (c *APIClient) do(ctx context.Context, method, path string, body, res any) error {
+	var reqBody io.Reader
+	if body != nil {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(ctx, err, "encode request")
+		}
+		reqBody = bytes.NewReader(rawBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, reqBody)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create request")
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpRes, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(ctx, err, "send request")
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode < 200 || httpRes.StatusCode >= 300 {
+		apiErr := APIError{StatusCode: httpRes.StatusCode, Message: http.StatusText(httpRes.StatusCode)}
+		var errRes errorResponse
+		if json.NewDecoder(httpRes.Body).Decode(&errRes) == nil && errRes.Error != "" {
+			apiErr.Message = errRes.Error
+		}
+		return apiErr
+	}
+
+	if res == nil {
+		return nil
+	}
+	err = json.NewDecoder(httpRes.Body).Decode(res)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "decode %s %s response", method, path)
+	}
+	return nil
+}
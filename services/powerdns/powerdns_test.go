@@ -0,0 +1,65 @@
+package powerdns
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+const testAPIKey = "my-api-key"
+
+func TestAPIClient_Records(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testAPIKey, r.Header.Get("X-API-Key"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		switch r.Method + " " + r.URL.Path {
+		case "PATCH /api/v1/servers/localhost/zones/example.com.":
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /api/v1/servers/localhost/zones/example.com.":
+			assert.Equal(t, "www.example.com.", r.URL.Query().Get("rrset_name"))
+			assert.Equal(t, "A", r.URL.Query().Get("rrset_type"))
+			// The older versions of PowerDNS return all the RRsets of the zone
+			_, _ = w.Write([]byte(`{"name": "example.com.", "rrsets": [
+				{"name": "example.com.", "type": "A", "ttl": 3600, "records": [{"content": "192.0.2.100", "disabled": false}]},
+				{"name": "www.example.com.", "type": "AAAA", "ttl": 60, "records": [{"content": "2001:db8::1", "disabled": false}]},
+				{"name": "www.example.com.", "type": "A", "ttl": 60, "records": [
+					{"content": "192.0.2.1", "disabled": false},
+					{"content": "192.0.2.2", "disabled": true}
+				]}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error": "Could not find domain 'example.org.'"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(testAPIKey, server.URL+"/", "", "example.com")
+	err := client.ReplaceRecord(ctx, dnsprovider.Record{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.1"})
+	require.NoError(t, err)
+	assert.Equal(t, `PATCH /api/v1/servers/localhost/zones/example.com. {"rrsets":[{"name":"www.example.com.","type":"A","ttl":60,"changetype":"REPLACE","records":[{"content":"192.0.2.1","disabled":false}]}]}`, requests[0])
+
+	records, err := client.GetRecords(ctx, "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Equal(t, []dnsprovider.Record{{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.1"}}, records)
+
+	client = NewClient(testAPIKey, server.URL, "localhost", "example.org.")
+	_, err = client.GetRecords(ctx, "www.example.org.", "A")
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "Could not find domain 'example.org.'", apiErr.Message)
+}
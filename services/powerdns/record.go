@@ -0,0 +1,80 @@
+package powerdns
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+var _ dnsprovider.Provider = (*APIClient)(nil)
+
+type rrset struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	TTL        uint32        `json:"ttl"`
+	ChangeType string        `json:"changetype,omitempty"`
+	Records    []rrsetRecord `json:"records"`
+}
+
+type rrsetRecord struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// ReplaceRecord replaces the RRset with the name and type of the record by the record
+func (c *APIClient) ReplaceRecord(ctx context.Context, record dnsprovider.Record) error {
+	body := map[string][]rrset{
+		"rrsets": {{
+			Name:       record.Name,
+			Type:       record.Type,
+			TTL:        record.TTL,
+			ChangeType: "REPLACE",
+			Records:    []rrsetRecord{{Content: record.Value}},
+		}},
+	}
+	err := c.do(ctx, http.MethodPatch, c.zonePath, body, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "replace RRset")
+	}
+	return nil
+}
+
+// GetRecords returns the records of the RRset with the name and type, the disabled records are
+// ignored. The RRsets are filtered by the API of the recent versions of PowerDNS, the older ones
+// return all the RRsets of the zone.
+func (c *APIClient) GetRecords(ctx context.Context, name, recordType string) ([]dnsprovider.Record, error) {
+	query := url.Values{}
+	query.Set("rrset_name", name)
+	query.Set("rrset_type", recordType)
+
+	var zone struct {
+		RRsets []rrset `json:"rrsets"`
+	}
+	err := c.do(ctx, http.MethodGet, c.zonePath+"?"+query.Encode(), nil, &zone)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get zone")
+	}
+
+	var records []dnsprovider.Record
+	for _, rrset := range zone.RRsets {
+		if !strings.EqualFold(rrset.Name, name) || rrset.Type != recordType {
+			continue
+		}
+		for _, record := range rrset.Records {
+			if record.Disabled {
+				continue
+			}
+			records = append(records, dnsprovider.Record{
+				Name:  rrset.Name,
+				Type:  rrset.Type,
+				TTL:   rrset.TTL,
+				Value: record.Content,
+			})
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,130 @@
+package rfc2136
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/miekg/dns"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+var _ RecordClient = (*Client)(nil)
+
+// RecordClient manages the records with dynamic updates. The records are of type A, AAAA or CNAME,
+// the value of a CNAME record being its fully qualified target.
+type RecordClient interface {
+	dnsprovider.Provider
+	RemoveRecord(ctx context.Context, record dnsprovider.Record) error
+}
+
+// resourceRecord returns the resource record of the record
+func resourceRecord(r dnsprovider.Record) (dns.RR, error) {
+	header := dns.RR_Header{
+		Name:   dns.Fqdn(r.Name),
+		Rrtype: dns.StringToType[r.Type],
+		Class:  dns.ClassINET,
+		Ttl:    r.TTL,
+	}
+	switch r.Type {
+	case "A":
+		ip := net.ParseIP(r.Value).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IPv4 %s", r.Value)
+		}
+		return &dns.A{Hdr: header, A: ip}, nil
+	case "AAAA":
+		ip := net.ParseIP(r.Value)
+		if ip == nil || ip.To4() != nil {
+			return nil, fmt.Errorf("invalid IPv6 %s", r.Value)
+		}
+		return &dns.AAAA{Hdr: header, AAAA: ip}, nil
+	case "CNAME":
+		return &dns.CNAME{Hdr: header, Target: dns.Fqdn(r.Value)}, nil
+	}
+	return nil, fmt.Errorf("unsupported record type %s", r.Type)
+}
+
+// ReplaceRecord replaces all the records with the name and type of the record by the record
+func (c *Client) ReplaceRecord(ctx context.Context, record dnsprovider.Record) error {
+	rr, err := resourceRecord(record)
+	if err != nil {
+		return errors.Wrap(ctx, err, "invalid record")
+	}
+
+	msg := new(dns.Msg)
+	msg.SetUpdate(c.zone)
+	// RemoveRRset only uses the name and the type of the record
+	msg.RemoveRRset([]dns.RR{rr})
+	msg.Insert([]dns.RR{rr})
+	err = c.update(ctx, msg)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "replace %s record %s", record.Type, record.Name)
+	}
+	return nil
+}
+
+// RemoveRecord removes the record, the records with the same name and type but another value are
+// kept
+func (c *Client) RemoveRecord(ctx context.Context, record dnsprovider.Record) error {
+	rr, err := resourceRecord(record)
+	if err != nil {
+		return errors.Wrap(ctx, err, "invalid record")
+	}
+
+	msg := new(dns.Msg)
+	msg.SetUpdate(c.zone)
+	msg.Remove([]dns.RR{rr})
+	err = c.update(ctx, msg)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "remove %s record %s", record.Type, record.Name)
+	}
+	return nil
+}
+
+// GetRecords queries the server for the records with the name and type. The server being
+// authoritative for the zone, the response does not depend on any cache.
+func (c *Client) GetRecords(ctx context.Context, name, recordType string) ([]dnsprovider.Record, error) {
+	rrtype, ok := dns.StringToType[recordType]
+	if !ok {
+		return nil, errors.Newf(ctx, "unsupported record type %s", recordType)
+	}
+	name = dns.Fqdn(name)
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, rrtype)
+	msg.RecursionDesired = false
+	res, err := c.exchange(ctx, msg)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "query %s records %s", recordType, name)
+	}
+	if res.Rcode == dns.RcodeNameError {
+		return nil, nil
+	}
+	if res.Rcode != dns.RcodeSuccess {
+		return nil, errors.Wrapf(ctx, ResponseError{Rcode: res.Rcode}, "query %s records %s", recordType, name)
+	}
+
+	var records []dnsprovider.Record
+	for _, rr := range res.Answer {
+		header := rr.Header()
+		if header.Rrtype != rrtype || !dns.IsSubDomain(name, header.Name) || !dns.IsSubDomain(header.Name, name) {
+			continue
+		}
+		record := dnsprovider.Record{Name: name, Type: recordType, TTL: header.Ttl}
+		switch rr := rr.(type) {
+		case *dns.A:
+			record.Value = rr.A.String()
+		case *dns.AAAA:
+			record.Value = rr.AAAA.String()
+		case *dns.CNAME:
+			record.Value = rr.Target
+		default:
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
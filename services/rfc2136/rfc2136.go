@@ -0,0 +1,95 @@
+package rfc2136
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// DefaultPort is the port of the DNS server if the address of the server has none
+const DefaultPort = "53"
+
+// tsigFudge is the time difference allowed between LinK and the DNS server to check the TSIG
+// signatures
+const tsigFudge = 300
+
+type Client struct {
+	dnsClient *dns.Client
+
+	// Client Configuration
+	server        string // host:port of the primary server of the zone
+	zone          string
+	tsigKeyName   string
+	tsigAlgorithm string
+}
+
+// NewClient returns a client sending the dynamic updates of the zone to its primary server, signed
+// with the TSIG key. The algorithm is one of Algorithms.
+func NewClient(server, zone, tsigKeyName, tsigAlgorithm, tsigSecret string) *Client {
+	tsigKeyName = dns.CanonicalName(tsigKeyName)
+	return &Client{
+		dnsClient: &dns.Client{
+			Net:        "udp",
+			Timeout:    10 * time.Second,
+			TsigSecret: map[string]string{tsigKeyName: tsigSecret},
+		},
+		server:        ServerAddress(server),
+		zone:          dns.CanonicalName(zone),
+		tsigKeyName:   tsigKeyName,
+		tsigAlgorithm: Algorithms[tsigAlgorithm],
+	}
+}
+
+// ServerAddress adds the default port to the address of a DNS server if it has none
+func ServerAddress(server string) string {
+	_, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return net.JoinHostPort(strings.Trim(server, "[]"), DefaultPort)
+	}
+	return server
+}
+
+// ResponseError is returned when the DNS server answers with an error code
+type ResponseError struct {
+	Rcode int
+}
+
+func (e ResponseError) Error() string {
+	return fmt.Sprintf("DNS server answered %s", dns.RcodeToString[e.Rcode])
+}
+
+// exchange sends the message to the server, over TCP if the UDP response is truncated
+func (c *Client) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	res, _, err := c.dnsClient.ExchangeContext(ctx, msg, c.server)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "send DNS message to %s", c.server)
+	}
+	if res.Truncated {
+		tcpClient := *c.dnsClient
+		tcpClient.Net = "tcp"
+		res, _, err = tcpClient.ExchangeContext(ctx, msg, c.server)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "send DNS message to %s over TCP", c.server)
+		}
+	}
+	return res, nil
+}
+
+// update sends the dynamic update signed with the TSIG key
+func (c *Client) update(ctx context.Context, msg *dns.Msg) error {
+	msg.SetTsig(c.tsigKeyName, c.tsigAlgorithm, tsigFudge, time.Now().Unix())
+	res, err := c.exchange(ctx, msg)
+	if err != nil {
+		return errors.Wrap(ctx, err, "send update")
+	}
+	if res.Rcode != dns.RcodeSuccess {
+		return errors.Wrap(ctx, ResponseError{Rcode: res.Rcode}, "update refused")
+	}
+	return nil
+}
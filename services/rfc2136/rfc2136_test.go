@@ -0,0 +1,171 @@
+package rfc2136
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/link/v3/services/dnsprovider"
+)
+
+const (
+	testKeyName = "link-key."
+	testSecret  = "c2VjcmV0LWtleS1vZi1saW5rLWZvci10aGUtdGVzdHM="
+)
+
+// testServer is an authoritative server of the example.com. zone applying the dynamic updates
+// signed with the test key
+type testServer struct {
+	mutex   sync.Mutex
+	records []dns.RR
+}
+
+func (s *testServer) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	res := new(dns.Msg)
+	res.SetReply(req)
+	res.Authoritative = true
+
+	switch req.Opcode {
+	case dns.OpcodeUpdate:
+		if req.IsTsig() == nil || w.TsigStatus() != nil {
+			res.Rcode = dns.RcodeNotAuth
+			break
+		}
+		for _, rr := range req.Ns {
+			s.apply(rr)
+		}
+		res.SetTsig(testKeyName, dns.HmacSHA256, 300, int64(req.IsTsig().TimeSigned))
+	default:
+		question := req.Question[0]
+		for _, rr := range s.records {
+			if rr.Header().Name == question.Name && rr.Header().Rrtype == question.Qtype {
+				res.Answer = append(res.Answer, rr)
+			}
+		}
+	}
+	_ = w.WriteMsg(res)
+}
+
+// apply applies an update RR as described in RFC 2136 section 3.4.2
+func (s *testServer) apply(update dns.RR) {
+	header := update.Header()
+	switch header.Class {
+	case dns.ClassANY:
+		// Delete the RRset
+		s.records = filter(s.records, func(rr dns.RR) bool {
+			return rr.Header().Name == header.Name && rr.Header().Rrtype == header.Rrtype
+		})
+	case dns.ClassNONE:
+		// Delete the RR
+		s.records = filter(s.records, func(rr dns.RR) bool {
+			other := dns.Copy(update)
+			other.Header().Class = dns.ClassINET
+			other.Header().Ttl = rr.Header().Ttl
+			return dns.IsDuplicate(rr, other)
+		})
+	default:
+		s.records = append(s.records, update)
+	}
+}
+
+// zoneRecords returns a copy of the records of the zone, the server may update them concurrently
+func (s *testServer) zoneRecords() []dns.RR {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Clone(s.records)
+}
+
+func filter(records []dns.RR, remove func(rr dns.RR) bool) []dns.RR {
+	var res []dns.RR
+	for _, rr := range records {
+		if !remove(rr) {
+			res = append(res, rr)
+		}
+	}
+	return res
+}
+
+func startTestServer(t *testing.T) (*testServer, string) {
+	handler := &testServer{}
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        conn,
+		Handler:           handler,
+		TsigSecret:        map[string]string{testKeyName: testSecret},
+		NotifyStartedFunc: func() { close(started) },
+		// The default function rejects the updates
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+	}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+	return handler, conn.LocalAddr().String()
+}
+
+func TestClient_Records(t *testing.T) {
+	ctx := context.Background()
+	server, addr := startTestServer(t)
+	client := NewClient(addr, "example.com", "link-key", DefaultAlgorithm, testSecret)
+
+	record := dnsprovider.Record{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.1"}
+	err := client.ReplaceRecord(ctx, record)
+	require.NoError(t, err)
+
+	// The other record of the RRset is replaced
+	err = client.ReplaceRecord(ctx, dnsprovider.Record{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.2"})
+	require.NoError(t, err)
+	err = client.ReplaceRecord(ctx, dnsprovider.Record{Name: "www.example.com.", Type: "AAAA", TTL: 60, Value: "2001:db8::1"})
+	require.NoError(t, err)
+
+	records, err := client.GetRecords(ctx, "www.example.com", "A")
+	require.NoError(t, err)
+	assert.Equal(t, []dnsprovider.Record{{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.2"}}, records)
+
+	// Only the record with the same value is removed
+	err = client.RemoveRecord(ctx, record)
+	require.NoError(t, err)
+	records, err = client.GetRecords(ctx, "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	err = client.RemoveRecord(ctx, dnsprovider.Record{Name: "www.example.com.", Type: "A", TTL: 60, Value: "192.0.2.2"})
+	require.NoError(t, err)
+	records, err = client.GetRecords(ctx, "www.example.com.", "A")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Len(t, server.zoneRecords(), 1, "the AAAA record should be kept")
+}
+
+func TestClient_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	_, addr := startTestServer(t)
+	client := NewClient(addr, "example.com.", "other-key.", DefaultAlgorithm, testSecret)
+
+	err := client.ReplaceRecord(ctx, dnsprovider.Record{Name: "www.example.com.", Type: "CNAME", TTL: 60, Value: "host.example.com"})
+	var responseErr ResponseError
+	require.ErrorAs(t, err, &responseErr)
+	assert.Equal(t, dns.RcodeNotAuth, responseErr.Rcode)
+}
+
+func TestServerAddress(t *testing.T) {
+	assert.Equal(t, "ns1.example.com:53", ServerAddress("ns1.example.com"))
+	assert.Equal(t, "192.0.2.53:5353", ServerAddress("192.0.2.53:5353"))
+	assert.Equal(t, "[2001:db8::53]:53", ServerAddress("2001:db8::53"))
+	assert.Equal(t, "[2001:db8::53]:53", ServerAddress("[2001:db8::53]"))
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/rfc2136 (interfaces: RecordClient)
+
+// Package rfc2136mock is a generated GoMock package.
+package rfc2136mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dnsprovider "github.com/Scalingo/link/v3/services/dnsprovider"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecordClient is a mock of RecordClient interface.
+type MockRecordClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecordClientMockRecorder
+	isgomock struct{}
+}
+
+// MockRecordClientMockRecorder is the mock recorder for MockRecordClient.
+type MockRecordClientMockRecorder struct {
+	mock *MockRecordClient
+}
+
+// NewMockRecordClient creates a new mock instance.
+func NewMockRecordClient(ctrl *gomock.Controller) *MockRecordClient {
+	mock := &MockRecordClient{ctrl: ctrl}
+	mock.recorder = &MockRecordClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecordClient) EXPECT() *MockRecordClientMockRecorder {
+	return m.recorder
+}
+
+// GetRecords mocks base method.
+func (m *MockRecordClient) GetRecords(ctx context.Context, name, recordType string) ([]dnsprovider.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecords", ctx, name, recordType)
+	ret0, _ := ret[0].([]dnsprovider.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecords indicates an expected call of GetRecords.
+func (mr *MockRecordClientMockRecorder) GetRecords(ctx, name, recordType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecords", reflect.TypeOf((*MockRecordClient)(nil).GetRecords), ctx, name, recordType)
+}
+
+// RemoveRecord mocks base method.
+func (m *MockRecordClient) RemoveRecord(ctx context.Context, record dnsprovider.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveRecord indicates an expected call of RemoveRecord.
+func (mr *MockRecordClientMockRecorder) RemoveRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRecord", reflect.TypeOf((*MockRecordClient)(nil).RemoveRecord), ctx, record)
+}
+
+// ReplaceRecord mocks base method.
+func (m *MockRecordClient) ReplaceRecord(ctx context.Context, record dnsprovider.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecord indicates an expected call of ReplaceRecord.
+func (mr *MockRecordClientMockRecorder) ReplaceRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecord", reflect.TypeOf((*MockRecordClient)(nil).ReplaceRecord), ctx, record)
+}
@@ -0,0 +1,43 @@
+package rfc2136
+
+import (
+	"encoding/base64"
+
+	"github.com/miekg/dns"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+// DefaultAlgorithm is the TSIG algorithm used if the configuration of an endpoint has none
+const DefaultAlgorithm = "hmac-sha256"
+
+// Algorithms are the TSIG algorithms accepted in the configuration of the endpoints
+var Algorithms = map[string]string{
+	"hmac-sha1":   dns.HmacSHA1,
+	"hmac-sha224": dns.HmacSHA224,
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha384": dns.HmacSHA384,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+// ValidateTSIG checks the TSIG key sent in the configuration of an endpoint, the secret being
+// base64 encoded
+func ValidateTSIG(validations *errors.ValidationErrorsBuilder, keyName, algorithm, secret string) {
+	if keyName == "" {
+		validations.Set("plugin_config.tsig_key_name", "missing TSIG key name")
+	}
+	if _, ok := dns.IsDomainName(keyName); keyName != "" && !ok {
+		validations.Set("plugin_config.tsig_key_name", "invalid TSIG key name")
+	}
+
+	if _, ok := Algorithms[algorithm]; algorithm != "" && !ok {
+		validations.Set("plugin_config.tsig_algorithm", "invalid TSIG algorithm, must be one of hmac-sha1, hmac-sha224, hmac-sha256, hmac-sha384 or hmac-sha512")
+	}
+
+	if secret == "" {
+		validations.Set("plugin_config.tsig_secret", "missing TSIG secret")
+	}
+	if _, err := base64.StdEncoding.DecodeString(secret); secret != "" && err != nil {
+		validations.Set("plugin_config.tsig_secret", "invalid TSIG secret, must be base64 encoded")
+	}
+}
@@ -0,0 +1,30 @@
+package scaleway
+
+import (
+	"regexp"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var zoneRegex = regexp.MustCompile(`^[a-z]{2}-[a-z]{3}-[0-9]$`)
+
+// IDRegex matches the IDs of the Scaleway resources, which are UUIDs
+var IDRegex = regexp.MustCompile(`^[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}$`)
+
+// ValidateCredentials checks the format of the secret key and the zone sent in the configuration
+// of an endpoint
+func ValidateCredentials(validations *errors.ValidationErrorsBuilder, secretKey, zone string) {
+	if secretKey == "" {
+		validations.Set("plugin_config.secret_key", "missing secret key")
+	}
+	if secretKey != "" && !IDRegex.MatchString(secretKey) {
+		validations.Set("plugin_config.secret_key", "invalid secret key format")
+	}
+
+	if zone == "" {
+		validations.Set("plugin_config.zone", "missing zone")
+	}
+	if zone != "" && !zoneRegex.MatchString(zone) {
+		validations.Set("plugin_config.zone", "invalid zone: "+zone)
+	}
+}
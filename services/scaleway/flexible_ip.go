@@ -0,0 +1,74 @@
+package scaleway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Scalingo/go-utils/errors/v2"
+)
+
+var _ FlexibleIPClient = (*APIClient)(nil)
+
+type FlexibleIPClient interface {
+	AttachIP(ctx context.Context, ipID, serverID string) error
+	DetachIP(ctx context.Context, ipID string) error
+	GetIP(ctx context.Context, ipID string) (IP, error)
+}
+
+// IP is a flexible IP of the Instance API, Server is nil if it is not attached
+type IP struct {
+	ID      string    `json:"id"`
+	Address string    `json:"address"`
+	Server  *IPServer `json:"server"`
+}
+
+type IPServer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ServerID returns the ID of the server the flexible IP is attached to, empty if it is not attached
+func (ip IP) ServerID() string {
+	if ip.Server == nil {
+		return ""
+	}
+	return ip.Server.ID
+}
+
+type updateIPRequest struct {
+	// Server is the server the IP is attached to, the IP is detached if it is nil
+	Server *string `json:"server"`
+}
+
+// AttachIP attaches the flexible IP to the server, detaching it from the server it was attached to
+func (c *APIClient) AttachIP(ctx context.Context, ipID, serverID string) error {
+	err := c.do(ctx, http.MethodPatch, c.ipPath(ipID), updateIPRequest{Server: &serverID}, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "attach flexible IP")
+	}
+	return nil
+}
+
+func (c *APIClient) DetachIP(ctx context.Context, ipID string) error {
+	err := c.do(ctx, http.MethodPatch, c.ipPath(ipID), updateIPRequest{}, nil)
+	if err != nil {
+		return errors.Wrap(ctx, err, "detach flexible IP")
+	}
+	return nil
+}
+
+func (c *APIClient) GetIP(ctx context.Context, ipID string) (IP, error) {
+	var res struct {
+		IP IP `json:"ip"`
+	}
+	err := c.do(ctx, http.MethodGet, c.ipPath(ipID), nil, &res)
+	if err != nil {
+		return IP{}, errors.Wrap(ctx, err, "get flexible IP")
+	}
+	return res.IP, nil
+}
+
+func (c *APIClient) ipPath(ipID string) string {
+	return fmt.Sprintf("/instance/v1/zones/%s/ips/%s", c.zone, ipID)
+}
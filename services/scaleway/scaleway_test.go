@@ -0,0 +1,59 @@
+package scaleway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecretKey = "11111111-1111-4111-8111-111111111111"
+	testIPID      = "22222222-2222-4222-8222-222222222222"
+	testServerID  = "33333333-3333-4333-8333-333333333333"
+)
+
+func TestAPIClient_FlexibleIP(t *testing.T) {
+	ctx := context.Background()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testSecretKey, r.Header.Get("X-Auth-Token"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		if r.URL.Path != "/instance/v1/zones/fr-par-1/ips/"+testIPID {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type": "unknown_resource", "message": "\"ip\" not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ip": {"id": "` + testIPID + `", "address": "51.15.0.1", "server": {"id": "` + testServerID + `", "name": "node-1"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testSecretKey, "fr-par-1", server.URL)
+
+	err := client.AttachIP(ctx, testIPID, testServerID)
+	require.NoError(t, err)
+	assert.Equal(t, `PATCH /instance/v1/zones/fr-par-1/ips/`+testIPID+` {"server":"`+testServerID+`"}`, requests[0])
+
+	err = client.DetachIP(ctx, testIPID)
+	require.NoError(t, err)
+	assert.Equal(t, `PATCH /instance/v1/zones/fr-par-1/ips/`+testIPID+` {"server":null}`, requests[1])
+
+	ip, err := client.GetIP(ctx, testIPID)
+	require.NoError(t, err)
+	assert.Equal(t, "51.15.0.1", ip.Address)
+	assert.Equal(t, testServerID, ip.ServerID())
+	assert.Empty(t, IP{}.ServerID())
+
+	_, err = client.GetIP(ctx, "44444444-4444-4444-8444-444444444444")
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "unknown_resource", apiErr.Type)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Scalingo/link/v3/services/scaleway (interfaces: FlexibleIPClient)
+
+// Package scalewaymock is a generated GoMock package.
+package scalewaymock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	scaleway "github.com/Scalingo/link/v3/services/scaleway"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFlexibleIPClient is a mock of FlexibleIPClient interface.
+type MockFlexibleIPClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlexibleIPClientMockRecorder
+	isgomock struct{}
+}
+
+// MockFlexibleIPClientMockRecorder is the mock recorder for MockFlexibleIPClient.
+type MockFlexibleIPClientMockRecorder struct {
+	mock *MockFlexibleIPClient
+}
+
+// NewMockFlexibleIPClient creates a new mock instance.
+func NewMockFlexibleIPClient(ctrl *gomock.Controller) *MockFlexibleIPClient {
+	mock := &MockFlexibleIPClient{ctrl: ctrl}
+	mock.recorder = &MockFlexibleIPClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlexibleIPClient) EXPECT() *MockFlexibleIPClientMockRecorder {
+	return m.recorder
+}
+
+// AttachIP mocks base method.
+func (m *MockFlexibleIPClient) AttachIP(ctx context.Context, ipID, serverID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachIP", ctx, ipID, serverID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachIP indicates an expected call of AttachIP.
+func (mr *MockFlexibleIPClientMockRecorder) AttachIP(ctx, ipID, serverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachIP", reflect.TypeOf((*MockFlexibleIPClient)(nil).AttachIP), ctx, ipID, serverID)
+}
+
+// DetachIP mocks base method.
+func (m *MockFlexibleIPClient) DetachIP(ctx context.Context, ipID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachIP", ctx, ipID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachIP indicates an expected call of DetachIP.
+func (mr *MockFlexibleIPClientMockRecorder) DetachIP(ctx, ipID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachIP", reflect.TypeOf((*MockFlexibleIPClient)(nil).DetachIP), ctx, ipID)
+}
+
+// GetIP mocks base method.
+func (m *MockFlexibleIPClient) GetIP(ctx context.Context, ipID string) (scaleway.IP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIP", ctx, ipID)
+	ret0, _ := ret[0].(scaleway.IP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIP indicates an expected call of GetIP.
+func (mr *MockFlexibleIPClientMockRecorder) GetIP(ctx, ipID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIP", reflect.TypeOf((*MockFlexibleIPClient)(nil).GetIP), ctx, ipID)
+}